@@ -45,7 +45,16 @@ func main() {
 	validate := config.NewValidator()
 	email := config.NewEmailWorker(viperConfig)
 	authConfig := config.NewAuthConfig(viperConfig)
+	if authConfig.AccessTokenSecret == "" {
+		log.Fatalf("AUTH_ACCESS_TOKEN_SECRET is not configured!")
+	}
 	frontEndConfig := config.NewFrontEndConfig(viperConfig)
+	walletConfig := config.NewWalletConfig(viperConfig)
+	midtransConfig := config.NewMidtransConfig(viperConfig)
+	paymentReconciliationConfig := config.NewPaymentReconciliationConfig(viperConfig)
+	rateLimitConfig := config.NewRateLimitConfig(viperConfig)
+	oidcConfig := config.NewOIDCConfig(viperConfig)
+	auditLogConfig := config.NewAuditLogConfig(viperConfig)
 	pdf := config.NewPDFGenerator(log)
 	pusherClient := config.NewPusherClient(viperConfig)
 
@@ -66,23 +75,29 @@ func main() {
 			return allowed[origin]
 		},
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Requested-With,X-CSRF-Token",
-		ExposeHeaders:    "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Authorization,Set-Cookie",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Requested-With,X-CSRF-Token,X-API-Key,X-Request-Id",
+		ExposeHeaders:    "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Authorization,Set-Cookie,RateLimit-Policy,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After,X-Request-Id",
 		AllowCredentials: true,
 	}))
 
 	config.Bootstrap(&config.BootstrapConfig{
-		DB:             db,
-		App:            app,
-		Log:            log,
-		Validate:       validate,
-		Config:         viperConfig,
-		XenditClient:   xenditClient,
-		Email:          email,
-		PDF:            pdf,
-		AuthConfig:     authConfig,
-		FrontEndConfig: frontEndConfig,
-		PusherClient:   pusherClient,
+		DB:                          db,
+		App:                         app,
+		Log:                         log,
+		Validate:                    validate,
+		Config:                      viperConfig,
+		XenditClient:                xenditClient,
+		Email:                       email,
+		PDF:                         pdf,
+		AuthConfig:                  authConfig,
+		FrontEndConfig:              frontEndConfig,
+		WalletConfig:                walletConfig,
+		MidtransConfig:              midtransConfig,
+		PusherClient:                pusherClient,
+		PaymentReconciliationConfig: paymentReconciliationConfig,
+		RateLimitConfig:             rateLimitConfig,
+		OIDCConfig:                  oidcConfig,
+		AuditLogConfig:              auditLogConfig,
 	})
 
 	webPort := viperConfig.GetInt("WEB_PORT")
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS wallet_adjustment_requests;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE wallet_adjustment_requests (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    -- positif = kredit, negatif = debit
+    reason TEXT NOT NULL,
+    status ENUM ('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+    requested_by INTEGER NOT NULL,
+    -- admin yang mengajukan penyesuaian
+    processed_by INTEGER NULL,
+    -- admin yang menyetujui / menolak
+    processed_at TIMESTAMP NULL,
+    rejection_notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (requested_by) REFERENCES users (id),
+    FOREIGN KEY (processed_by) REFERENCES users (id),
+    INDEX idx_status (status)
+) ENGINE = InnoDB;
@@ -0,0 +1,5 @@
+ALTER TABLE wallets
+    DROP FOREIGN KEY fk_wallets_status_updated_by,
+    DROP COLUMN status_updated_at,
+    DROP COLUMN status_updated_by,
+    DROP COLUMN status_reason;
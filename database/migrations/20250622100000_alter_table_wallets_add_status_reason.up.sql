@@ -0,0 +1,7 @@
+ALTER TABLE wallets
+    ADD COLUMN status_reason TEXT NULL AFTER status,
+    -- alasan wallet dibekukan / diaktifkan kembali
+    ADD COLUMN status_updated_by INTEGER NULL AFTER status_reason,
+    -- admin yang terakhir mengubah status wallet
+    ADD COLUMN status_updated_at TIMESTAMP NULL AFTER status_updated_by,
+    ADD CONSTRAINT fk_wallets_status_updated_by FOREIGN KEY (status_updated_by) REFERENCES users (id);
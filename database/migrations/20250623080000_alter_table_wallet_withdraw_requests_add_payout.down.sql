@@ -0,0 +1,5 @@
+ALTER TABLE wallet_withdraw_requests
+    DROP INDEX idx_wallet_withdraw_requests_xendit_payout_id,
+    DROP COLUMN xendit_payout_id,
+    DROP COLUMN channel_code,
+    MODIFY COLUMN status ENUM ('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending';
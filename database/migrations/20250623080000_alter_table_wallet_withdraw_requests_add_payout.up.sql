@@ -0,0 +1,7 @@
+ALTER TABLE wallet_withdraw_requests
+    MODIFY COLUMN status ENUM ('pending', 'approved', 'rejected', 'cancelled', 'completed', 'failed') NOT NULL DEFAULT 'pending',
+    ADD COLUMN channel_code VARCHAR(50) NULL AFTER method,
+    -- kode channel xendit untuk bank_transfer, misal ID_BCA
+    ADD COLUMN xendit_payout_id VARCHAR(100) NULL AFTER processed_at,
+    -- payout xendit yang dibuat otomatis saat request disetujui
+    ADD INDEX idx_wallet_withdraw_requests_xendit_payout_id (xendit_payout_id);
@@ -0,0 +1,9 @@
+ALTER TABLE payouts
+    DROP FOREIGN KEY fk_payouts_bank_account_id,
+    DROP COLUMN bank_account_id;
+
+ALTER TABLE wallet_withdraw_requests
+    DROP FOREIGN KEY fk_wallet_withdraw_requests_bank_account_id,
+    DROP COLUMN bank_account_id;
+
+DROP TABLE IF EXISTS bank_accounts;
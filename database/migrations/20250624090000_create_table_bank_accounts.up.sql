@@ -0,0 +1,27 @@
+CREATE TABLE bank_accounts (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    channel_code VARCHAR(50) NOT NULL,
+    -- kode channel payout xendit, misal ID_BCA / ID_OVO
+    account_number VARCHAR(50) NOT NULL,
+    account_holder_name VARCHAR(100) NOT NULL,
+    verification_status ENUM ('unverified', 'verified', 'rejected') NOT NULL DEFAULT 'unverified',
+    verification_notes TEXT NULL,
+    verified_by INTEGER NULL,
+    -- admin yang memverifikasi
+    verified_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (verified_by) REFERENCES users (id),
+    INDEX idx_bank_accounts_user_id (user_id)
+) ENGINE = InnoDB;
+
+ALTER TABLE wallet_withdraw_requests
+    ADD COLUMN bank_account_id INTEGER NULL AFTER method,
+    ADD CONSTRAINT fk_wallet_withdraw_requests_bank_account_id FOREIGN KEY (bank_account_id) REFERENCES bank_accounts (id);
+
+ALTER TABLE payouts
+    ADD COLUMN bank_account_id INTEGER NULL AFTER xendit_payout_id,
+    ADD CONSTRAINT fk_payouts_bank_account_id FOREIGN KEY (bank_account_id) REFERENCES bank_accounts (id);
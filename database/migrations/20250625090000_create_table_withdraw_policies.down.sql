@@ -0,0 +1,15 @@
+ALTER TABLE wallet_transactions
+    MODIFY COLUMN transaction_type ENUM (
+        'top_up',
+        'order_payment',
+        'order_refund',
+        'withdraw',
+        'admin_adjustment',
+        'cashback',
+        'transfer_in',
+        'transfer_out'
+    ) NOT NULL;
+
+ALTER TABLE wallet_withdraw_requests DROP COLUMN fee;
+
+DROP TABLE IF EXISTS withdraw_policies;
@@ -0,0 +1,36 @@
+CREATE TABLE withdraw_policies (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    method ENUM ('cash', 'bank_transfer') NOT NULL,
+    min_amount DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    fee_type ENUM ('flat', 'percent') NOT NULL DEFAULT 'flat',
+    fee_value DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    -- flat = rupiah, percent = persen dari nominal withdraw
+    daily_limit DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    -- total withdraw per user per hari, 0 = tanpa batas
+    monthly_limit DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    -- total withdraw per user per bulan, 0 = tanpa batas
+    cooling_off_hours INTEGER NOT NULL DEFAULT 0,
+    -- jeda setelah top up sebelum boleh withdraw, 0 = tanpa jeda
+    updated_by INTEGER NULL,
+    -- admin yang terakhir mengubah policy
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (updated_by) REFERENCES users (id),
+    UNIQUE KEY uq_withdraw_policies_method (method)
+) ENGINE = InnoDB;
+
+ALTER TABLE wallet_withdraw_requests
+    ADD COLUMN fee DECIMAL(15, 2) NOT NULL DEFAULT 0 AFTER amount;
+
+ALTER TABLE wallet_transactions
+    MODIFY COLUMN transaction_type ENUM (
+        'top_up',
+        'order_payment',
+        'order_refund',
+        'withdraw',
+        'withdraw_fee',
+        'admin_adjustment',
+        'cashback',
+        'transfer_in',
+        'transfer_out'
+    ) NOT NULL;
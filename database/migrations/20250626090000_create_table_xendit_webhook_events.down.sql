@@ -0,0 +1 @@
+DROP TABLE IF EXISTS xendit_webhook_events;
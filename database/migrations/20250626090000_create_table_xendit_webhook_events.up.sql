@@ -0,0 +1,17 @@
+CREATE TABLE xendit_webhook_events (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    event_id VARCHAR(255) NOT NULL,
+    -- header webhook-id dari xendit, atau hash raw body jika header tidak ada
+    webhook_type ENUM ('payment_request', 'payout') NOT NULL,
+    event_type VARCHAR(100) NULL,
+    -- contoh: payment.succeeded, payout.failed
+    raw_body LONGTEXT NOT NULL,
+    status ENUM ('received', 'processing', 'processed', 'failed') NOT NULL DEFAULT 'received',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_xendit_webhook_events_event_id (event_id),
+    INDEX idx_xendit_webhook_events_status (status)
+) ENGINE = InnoDB;
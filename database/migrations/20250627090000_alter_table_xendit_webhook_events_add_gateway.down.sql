@@ -0,0 +1 @@
+ALTER TABLE xendit_webhook_events DROP COLUMN gateway;
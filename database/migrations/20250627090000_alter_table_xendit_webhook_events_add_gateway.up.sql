@@ -0,0 +1,3 @@
+ALTER TABLE xendit_webhook_events
+    ADD COLUMN gateway VARCHAR(20) NOT NULL DEFAULT 'XENDIT' AFTER event_id;
+    -- XENDIT atau SIMULATOR, menentukan parser webhook yang dipakai
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS midtrans_transactions;
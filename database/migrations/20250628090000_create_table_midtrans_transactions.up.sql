@@ -0,0 +1,25 @@
+CREATE TABLE midtrans_transactions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    midtrans_order_id VARCHAR(50) NOT NULL UNIQUE,
+    transaction_id VARCHAR(50),
+    api_type VARCHAR(10) NOT NULL,
+    payment_type VARCHAR(30) NOT NULL,
+    channel_code VARCHAR(20) NOT NULL,
+    gross_amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    transaction_status VARCHAR(20) NOT NULL,
+    fraud_status VARCHAR(20),
+    status_code VARCHAR(5),
+    va_bank VARCHAR(20),
+    va_number VARCHAR(50),
+    qr_string TEXT,
+    deeplink_url TEXT,
+    snap_token VARCHAR(100),
+    redirect_url TEXT,
+    metadata JSON,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders (id)
+) ENGINE = InnoDB;
@@ -0,0 +1,4 @@
+ALTER TABLE xendit_transactions
+    DROP COLUMN deeplink_url,
+    DROP COLUMN checkout_url,
+    DROP COLUMN mobile_number;
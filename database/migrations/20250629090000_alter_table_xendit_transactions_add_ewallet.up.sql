@@ -0,0 +1,6 @@
+ALTER TABLE xendit_transactions
+    ADD COLUMN mobile_number VARCHAR(20) NULL AFTER qr_string,
+    -- nomor OVO customer, hanya terisi untuk channel EWALLET_OVO
+    ADD COLUMN checkout_url TEXT NULL AFTER mobile_number,
+    -- url halaman pembayaran e-wallet, deeplink_url membuka aplikasi e-wallet secara langsung
+    ADD COLUMN deeplink_url TEXT NULL AFTER checkout_url;
@@ -0,0 +1,2 @@
+ALTER TABLE xendit_transactions
+    DROP COLUMN virtual_account_number;
@@ -0,0 +1,3 @@
+-- nomor virtual account xendit, bank mengikuti kolom channel_code
+ALTER TABLE xendit_transactions
+    ADD COLUMN virtual_account_number VARCHAR(50) NULL AFTER deeplink_url;
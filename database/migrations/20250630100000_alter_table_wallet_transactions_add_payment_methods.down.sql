@@ -0,0 +1,2 @@
+ALTER TABLE wallet_transactions
+    MODIFY COLUMN payment_method ENUM ('CASH', 'QR_CODE', 'WALLET', 'EWALLET') NULL;
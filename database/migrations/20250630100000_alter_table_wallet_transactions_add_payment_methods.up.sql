@@ -0,0 +1,14 @@
+ALTER TABLE wallet_transactions
+    MODIFY COLUMN payment_method ENUM (
+        'CASH',
+        -- top up/withdraw tunai
+        'QR_CODE',
+        -- top up via payment gateway
+        'WALLET',
+        -- transaksi menggunakan wallet
+        'EWALLET',
+        -- transaksi sistem (refund, cashback, dll)
+        'VIRTUAL_ACCOUNT',
+        -- pembayaran order lewat transfer virtual account
+        'CHECKOUT' -- pembayaran order lewat halaman checkout payment gateway (midtrans snap)
+    ) NULL;
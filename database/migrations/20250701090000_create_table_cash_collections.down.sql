@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS cash_collections;
+
+ALTER TABLE users
+    MODIFY COLUMN role ENUM ('admin', 'customer') NOT NULL;
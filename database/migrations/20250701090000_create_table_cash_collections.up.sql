@@ -0,0 +1,26 @@
+CREATE TABLE cash_collections (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    collected_by INTEGER NOT NULL,
+    -- admin / kurir yang menerima uang tunai dari customer
+    amount DECIMAL(15, 2) NOT NULL,
+    -- total tagihan order
+    received_amount DECIMAL(15, 2) NOT NULL,
+    change_amount DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    -- kembalian = received_amount - amount
+    note TEXT NULL,
+    collected_at TIMESTAMP NOT NULL,
+    reconciled_by INTEGER NULL,
+    -- admin yang menerima setoran kas di akhir hari
+    reconciled_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (collected_by) REFERENCES users (id),
+    FOREIGN KEY (reconciled_by) REFERENCES users (id),
+    UNIQUE KEY uq_cash_collections_order_id (order_id),
+    INDEX idx_cash_collections_collected_by_collected_at (collected_by, collected_at)
+) ENGINE = InnoDB;
+
+ALTER TABLE users
+    MODIFY COLUMN role ENUM ('admin', 'customer', 'courier') NOT NULL;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_refund_items;
+
+DROP TABLE IF EXISTS order_refunds;
+
+ALTER TABLE xendit_webhook_events
+    MODIFY COLUMN webhook_type ENUM ('payment_request', 'payout') NOT NULL;
@@ -0,0 +1,40 @@
+CREATE TABLE order_refunds (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    refunded_by INTEGER NOT NULL,
+    -- admin yang melakukan refund, atau customer jika refund otomatis dari pembatalan pesanan
+    reference_id VARCHAR(100) NOT NULL,
+    -- dipakai sebagai reference id dan idempotency key ke payment gateway
+    type ENUM ('full', 'partial') NOT NULL,
+    destination ENUM ('wallet', 'original_method') NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    reason TEXT NULL,
+    status ENUM ('pending', 'succeeded', 'failed') NOT NULL DEFAULT 'pending',
+    payment_gateway VARCHAR(20) NULL,
+    -- terisi jika refund dikembalikan ke metode pembayaran asal
+    gateway_refund_id VARCHAR(255) NULL,
+    failure_code VARCHAR(255) NULL,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (refunded_by) REFERENCES users (id),
+    UNIQUE KEY uq_order_refunds_reference_id (reference_id),
+    INDEX idx_order_refunds_gateway_refund_id (gateway_refund_id)
+) ENGINE = InnoDB;
+
+CREATE TABLE order_refund_items (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    order_refund_id INTEGER NOT NULL,
+    order_product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    -- harga produk saat dipesan x quantity
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_refund_id) REFERENCES order_refunds (id) ON DELETE CASCADE,
+    FOREIGN KEY (order_product_id) REFERENCES order_products (id)
+) ENGINE = InnoDB;
+
+ALTER TABLE xendit_webhook_events
+    MODIFY COLUMN webhook_type ENUM ('payment_request', 'payout', 'refund') NOT NULL;
@@ -0,0 +1,18 @@
+CREATE TABLE order_payment_legs (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    source ENUM ('wallet', 'gateway') NOT NULL,
+    -- wallet = saldo customer yang ditahan, gateway = sisa tagihan yang dibayar lewat payment gateway
+    payment_gateway VARCHAR(20) NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,
+    channel_code VARCHAR(50) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    status ENUM ('pending', 'held', 'paid', 'released', 'failed') NOT NULL,
+    wallet_transaction_id INTEGER NULL,
+    xendit_transaction_id VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (wallet_transaction_id) REFERENCES wallet_transactions (id),
+    INDEX idx_order_payment_legs_order_source (order_id, source)
+) ENGINE = InnoDB;
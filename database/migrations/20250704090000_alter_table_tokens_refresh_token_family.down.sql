@@ -0,0 +1,8 @@
+DELETE FROM tokens;
+
+ALTER TABLE tokens
+    DROP INDEX idx_tokens_family_id,
+    DROP INDEX idx_tokens_token,
+    DROP COLUMN revoked_at,
+    DROP COLUMN rotated_at,
+    DROP COLUMN family_id;
@@ -0,0 +1,10 @@
+-- tokens sekarang menyimpan hash refresh token, access token berupa JWT yang tidak disimpan
+-- token UUID lama dihapus sehingga semua user perlu login ulang
+DELETE FROM tokens;
+
+ALTER TABLE tokens
+    ADD COLUMN family_id CHAR(36) NOT NULL AFTER token,
+    ADD COLUMN rotated_at TIMESTAMP NULL DEFAULT NULL AFTER expiry_date,
+    ADD COLUMN revoked_at TIMESTAMP NULL DEFAULT NULL AFTER rotated_at,
+    ADD UNIQUE INDEX idx_tokens_token (token),
+    ADD INDEX idx_tokens_family_id (family_id);
@@ -0,0 +1,20 @@
+-- satu sesi login = satu family refresh token di tabel tokens (id sesi = tokens.family_id)
+CREATE TABLE user_sessions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    INDEX idx_user_sessions_user_id (user_id, revoked_at)
+) ENGINE = InnoDB;
+
+-- sesi yang sudah berjalan tetap tercatat, device nya belum diketahui
+INSERT INTO user_sessions (id, user_id, last_seen_at, expires_at, revoked_at, created_at)
+SELECT family_id, user_id, MAX(updated_at), MAX(expiry_date), MAX(revoked_at), MIN(created_at)
+FROM tokens
+GROUP BY family_id, user_id;
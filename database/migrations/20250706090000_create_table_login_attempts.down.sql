@@ -0,0 +1,4 @@
+ALTER TABLE password_resets
+    DROP COLUMN failed_attempts;
+
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,16 @@
+-- penghitung login gagal per akun (email) dan per alamat IP untuk backoff dan penguncian sementara
+CREATE TABLE login_attempts (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    scope ENUM('account', 'ip') NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NULL DEFAULT NULL,
+    locked_until TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_login_attempts_scope_identifier (scope, identifier)
+) ENGINE = InnoDB;
+
+-- kode verifikasi lupa password dibatasi jumlah percobaannya
+ALTER TABLE password_resets
+    ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0 AFTER verification_code;
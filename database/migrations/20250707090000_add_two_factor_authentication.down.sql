@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE user_sessions
+    DROP COLUMN two_factor_verified_at;
+
+ALTER TABLE users
+    DROP COLUMN two_factor_last_used_step,
+    DROP COLUMN two_factor_enabled_at,
+    DROP COLUMN two_factor_secret;
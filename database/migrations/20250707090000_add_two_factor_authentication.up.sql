@@ -0,0 +1,20 @@
+-- TOTP 2FA, two_factor_last_used_step mencegah kode yang sama dipakai dua kali
+ALTER TABLE users
+    ADD COLUMN two_factor_secret VARCHAR(64) NULL DEFAULT NULL AFTER password,
+    ADD COLUMN two_factor_enabled_at TIMESTAMP NULL DEFAULT NULL AFTER two_factor_secret,
+    ADD COLUMN two_factor_last_used_step BIGINT NOT NULL DEFAULT 0 AFTER two_factor_enabled_at;
+
+-- waktu konfirmasi 2FA terakhir pada sesi login, dipakai untuk endpoint yang butuh konfirmasi 2FA baru
+ALTER TABLE user_sessions
+    ADD COLUMN two_factor_verified_at TIMESTAMP NULL DEFAULT NULL AFTER last_seen_at;
+
+-- kode pemulihan sekali pakai jika device authenticator hilang, disimpan sebagai hash sha256
+CREATE TABLE user_recovery_codes (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    INDEX idx_user_recovery_codes_user_id (user_id, code_hash)
+) ENGINE = InnoDB;
@@ -0,0 +1,9 @@
+UPDATE users SET role = 'customer' WHERE role = 'staff';
+
+ALTER TABLE users
+    DROP FOREIGN KEY fk_users_role_id,
+    DROP COLUMN role_id,
+    MODIFY COLUMN role ENUM ('admin', 'customer', 'courier') NOT NULL;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
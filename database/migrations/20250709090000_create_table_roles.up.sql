@@ -0,0 +1,49 @@
+-- role staff (kasir, dapur, kurir) dengan permission per fitur, admin selalu memiliki semua permission
+CREATE TABLE roles (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_roles_name (name)
+) ENGINE = InnoDB;
+
+CREATE TABLE role_permissions (
+    id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    role_id INTEGER NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_role_permissions_role_permission (role_id, permission)
+) ENGINE = InnoDB;
+
+ALTER TABLE users
+    MODIFY COLUMN role ENUM ('admin', 'customer', 'courier', 'staff') NOT NULL,
+    ADD COLUMN role_id INTEGER NULL DEFAULT NULL AFTER role,
+    ADD CONSTRAINT fk_users_role_id FOREIGN KEY (role_id) REFERENCES roles (id);
+
+-- role bawaan, bisa diubah admin
+INSERT INTO roles (name, description) VALUES
+    ('cashier', 'Kasir: mengelola pesanan dan pembayaran tunai di toko'),
+    ('kitchen', 'Dapur: melihat pesanan dan memperbarui status pesanan'),
+    ('courier', 'Kurir: mengantar pesanan dan menerima pembayaran tunai');
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, permission FROM roles
+CROSS JOIN (
+    SELECT 'orders.read' AS permission
+    UNION ALL SELECT 'orders.update_status'
+    UNION ALL SELECT 'cash_payments.confirm'
+) AS permissions
+WHERE roles.name IN ('cashier', 'courier');
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, permission FROM roles
+CROSS JOIN (
+    SELECT 'orders.read' AS permission
+    UNION ALL SELECT 'orders.update_status'
+) AS permissions
+WHERE roles.name = 'kitchen';
+
+-- akun kurir yang sudah ada tetap bisa memperbarui status dan menerima pembayaran tunai
+UPDATE users SET role_id = (SELECT id FROM roles WHERE name = 'courier') WHERE role = 'courier';
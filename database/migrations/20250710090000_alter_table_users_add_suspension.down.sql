@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP INDEX idx_users_suspended_at,
+    DROP COLUMN suspended_reason,
+    DROP COLUMN suspended_at;
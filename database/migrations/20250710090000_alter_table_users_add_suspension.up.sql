@@ -0,0 +1,5 @@
+ALTER TABLE users
+    -- akun yang disuspend admin tidak bisa login dan seluruh sesinya dicabut
+    ADD COLUMN suspended_at TIMESTAMP NULL AFTER role_id,
+    ADD COLUMN suspended_reason TEXT NULL AFTER suspended_at,
+    ADD INDEX idx_users_suspended_at (suspended_at);
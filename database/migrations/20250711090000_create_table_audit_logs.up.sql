@@ -0,0 +1,20 @@
+-- audit log hanya ditambah (append-only), data lama dihapus oleh job retensi
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INTEGER NULL,
+    -- tanpa foreign key agar log tetap utuh walaupun akun pelaku dihapus
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    actor_role VARCHAR(20) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    changes JSON NULL,
+    -- {"kolom": {"before": ..., "after": ...}}, hanya kolom yang berubah
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_logs_entity (entity_type, entity_id),
+    INDEX idx_audit_logs_actor_id (actor_id),
+    INDEX idx_audit_logs_action (action),
+    INDEX idx_audit_logs_created_at (created_at)
+) ENGINE = InnoDB;
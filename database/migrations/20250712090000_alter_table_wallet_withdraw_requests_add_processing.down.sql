@@ -0,0 +1,4 @@
+UPDATE wallet_withdraw_requests SET status = 'approved' WHERE status = 'processing';
+
+ALTER TABLE wallet_withdraw_requests
+    MODIFY COLUMN status ENUM ('pending', 'approved', 'rejected', 'cancelled', 'completed', 'failed') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,3 @@
+ALTER TABLE wallet_withdraw_requests
+    -- processing = sudah disetujui admin, payout xendit sedang dikirim / menunggu callback
+    MODIFY COLUMN status ENUM ('pending', 'approved', 'processing', 'rejected', 'cancelled', 'completed', 'failed') NOT NULL DEFAULT 'pending';
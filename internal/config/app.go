@@ -2,20 +2,25 @@ package config
 
 import (
 	"seblak-bombom-restful-api/internal/delivery/http"
+	midtransController "seblak-bombom-restful-api/internal/delivery/http/midtrans"
 	xenditController "seblak-bombom-restful-api/internal/delivery/http/xendit"
 	"seblak-bombom-restful-api/internal/delivery/middleware"
 	"seblak-bombom-restful-api/internal/delivery/route"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/mailer"
+	"seblak-bombom-restful-api/internal/interfaces"
 	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/oidc_provider"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	"seblak-bombom-restful-api/internal/rate_limiter"
 	"seblak-bombom-restful-api/internal/repository"
 	"seblak-bombom-restful-api/internal/usecase"
+	midtransUseCase "seblak-bombom-restful-api/internal/usecase/midtrans"
 	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
 
 	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/midtrans/midtrans-go/coreapi"
-	"github.com/midtrans/midtrans-go/snap"
 	"github.com/pusher/pusher-http-go/v5"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -29,20 +34,32 @@ type BootstrapConfig struct {
 	Log            *logrus.Logger
 	Validate       *validator.Validate
 	Config         *viper.Viper
-	SnapClient     *snap.Client
-	CoreAPIClient  *coreapi.Client
 	XenditClient   *xendit.APIClient
 	Email          *mailer.EmailWorker
 	PDF            *wkhtmltopdf.PDFGenerator
 	AuthConfig     *model.AuthConfig
 	FrontEndConfig *model.FrontEndConfig
+	WalletConfig   *model.WalletConfig
+	MidtransConfig *model.MidtransConfig
 	PusherClient   pusher.Client
+	// nil = job rekonsiliasi pembayaran tidak dijalankan
+	PaymentReconciliationConfig *model.PaymentReconciliationConfig
+	// nil = tanpa rate limit
+	RateLimitConfig *model.RateLimitConfig
+	// nil = login OpenID Connect tidak aktif
+	OIDCConfig *model.OIDCConfig
+	// nil = audit log disimpan selamanya tanpa job retensi
+	AuditLogConfig *model.AuditLogConfig
 }
 
 func Bootstrap(config *BootstrapConfig) {
 	// setup repositories
 	userRepository := repository.NewUserRepository(config.Log)
 	tokenRepository := repository.NewTokenRepository(config.Log)
+	userSessionRepository := repository.NewUserSessionRepository(config.Log)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(config.Log)
+	userRecoveryCodeRepository := repository.NewUserRecoveryCodeRepository(config.Log)
+	userIdentityRepository := repository.NewUserIdentityRepository(config.Log)
 	addressRepository := repository.NewAddressRepository(config.Log)
 	categoryRepository := repository.NewCategoryRepository(config.Log)
 	productRepository := repository.NewProductRepository(config.Log)
@@ -54,6 +71,7 @@ func Bootstrap(config *BootstrapConfig) {
 	productReviewRepository := repository.NewProductReviewRepository(config.Log)
 	orderProductRepository := repository.NewOrderProductRepository(config.Log)
 	xenditTransactionRepository := repository.NewXenditTransactionRepository(config.Log)
+	midtransTransactionRepository := repository.NewMidtransTransactionRepository(config.Log)
 	applicationRepository := repository.NewApplicationRepository(config.Log)
 	cartRepository := repository.NewCartRepository(config.Log)
 	cartItemRepository := repository.NewCartItemRepository(config.Log)
@@ -63,26 +81,78 @@ func Bootstrap(config *BootstrapConfig) {
 	notificationRepository := repository.NewNotificationRepository(config.Log)
 	passwordResetRepository := repository.NewPasswordResetRepository(config.Log)
 	walletWithdrawRepository := repository.NewWalletWithdrawRequestRepository(config.Log)
+	bankAccountRepository := repository.NewBankAccountRepository(config.Log)
+	withdrawPolicyRepository := repository.NewWithdrawPolicyRepository(config.Log)
+	xenditWebhookEventRepository := repository.NewXenditWebhookEventRepository(config.Log)
+	walletAdjustmentRepository := repository.NewWalletAdjustmentRequestRepository(config.Log)
+	walletTransactionRepository := repository.NewWalletTransactionRepository(config.Log)
+	cashCollectionRepository := repository.NewCashCollectionRepository(config.Log)
+	orderRefundRepository := repository.NewOrderRefundRepository(config.Log)
+	orderPaymentLegRepository := repository.NewOrderPaymentLegRepository(config.Log)
+	roleRepository := repository.NewRoleRepository(config.Log)
+	rolePermissionRepository := repository.NewRolePermissionRepository(config.Log)
+	auditLogRepository := repository.NewAuditLogRepository(config.Log)
+
+	// setup payment gateway, simulator hanya aktif di luar production
+	paymentGateways := []interfaces.PaymentGateway{payment_gateway.NewXenditGateway(config.XenditClient)}
+	var simulatorGateway *payment_gateway.SimulatorGateway
+	if config.Config.GetString("ENV") != "prod" {
+		simulatorGateway = payment_gateway.NewSimulatorGateway()
+		paymentGateways = append(paymentGateways, simulatorGateway)
+	}
+	// midtrans hanya aktif jika server key sudah diatur
+	if config.MidtransConfig != nil && config.MidtransConfig.ServerKey != "" {
+		paymentGateways = append(paymentGateways, payment_gateway.NewMidtransGateway(config.MidtransConfig))
+	}
+	paymentGatewayRegistry := payment_gateway.NewRegistry(paymentGateways...)
+
+	// setup provider login OpenID Connect
+	oidcProviders := []interfaces.OIDCProvider{}
+	if config.OIDCConfig != nil {
+		for _, providerConfig := range config.OIDCConfig.Providers {
+			oidcProviders = append(oidcProviders, oidc_provider.NewGenericProvider(providerConfig))
+		}
+	}
+	oidcProviderRegistry := oidc_provider.NewRegistry(oidcProviders...)
 
 	// setup use case
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, tokenRepository, addressRepository, walletRepository, cartRepository, notificationRepository, config.Email, applicationRepository, passwordResetRepository)
+	auditLogConfig := config.AuditLogConfig
+	if auditLogConfig == nil {
+		auditLogConfig = new(model.AuditLogConfig)
+	}
+	auditLogUseCase := usecase.NewAuditLogUseCase(config.DB, config.Log, auditLogRepository, auditLogConfig)
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(config.DB, config.Log, config.Validate, userRepository, userRecoveryCodeRepository, userSessionRepository, config.AuthConfig)
+	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, tokenRepository, addressRepository, walletRepository, cartRepository, notificationRepository, config.Email, applicationRepository, passwordResetRepository, config.AuthConfig, userSessionRepository, loginAttemptRepository, twoFactorUseCase, userIdentityRepository, oidcProviderRegistry, orderRepository, auditLogUseCase)
 	addressUseCase := usecase.NewAddressUseCase(config.DB, config.Log, config.Validate, userRepository, addressRepository, deliveryRepository, userUseCase)
 	categoryUseCase := usecase.NewCategoryUseCase(config.DB, config.Log, config.Validate, categoryRepository)
-	productUseCase := usecase.NewProductUseCase(config.DB, config.Log, config.Validate, categoryRepository, productRepository, imageRepository)
-	discountCouponUseCase := usecase.NewDiscountCouponUseCase(config.DB, config.Log, config.Validate, discountCouponRepository)
+	productUseCase := usecase.NewProductUseCase(config.DB, config.Log, config.Validate, categoryRepository, productRepository, imageRepository, auditLogUseCase)
+	discountCouponUseCase := usecase.NewDiscountCouponUseCase(config.DB, config.Log, config.Validate, discountCouponRepository, auditLogUseCase)
 	deliveryUseCase := usecase.NewDeliveryUseCase(config.DB, config.Log, config.Validate, deliveryRepository)
 	productReviewUseCase := usecase.NewProductReviewUseCase(config.DB, config.Log, config.Validate, productReviewRepository)
-	xenditTransactionQRCodeUseCase := xenditUseCase.NewXenditTransactionQRCodeUseCase(config.DB, config.Log, config.Validate, orderRepository, xenditTransactionRepository, config.XenditClient)
-	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validate, orderRepository, productRepository, categoryRepository, addressRepository, discountCouponRepository, discountUsageRepository, deliveryRepository, orderProductRepository, walletRepository, xenditTransactionRepository, xenditTransactionQRCodeUseCase, config.XenditClient, applicationRepository, config.Email, notificationRepository)
-	applicationUseCase := usecase.NewApplicationUseCase(config.DB, config.Log, config.Validate, applicationRepository)
+	xenditCallbackUseCase := xenditUseCase.NewXenditCallbackUseCase(config.DB, config.Log, config.Validate, orderRepository, xenditTransactionRepository, config.XenditClient, xenditPayoutRepository, userRepository, walletRepository, payoutRepository, applicationRepository, notificationRepository, config.Email, config.WalletConfig, walletWithdrawRepository, orderRefundRepository, orderPaymentLegRepository, walletTransactionRepository)
+	xenditTransactionQRCodeUseCase := xenditUseCase.NewXenditTransactionQRCodeUseCase(config.DB, config.Log, config.Validate, orderRepository, xenditTransactionRepository, paymentGatewayRegistry, xenditCallbackUseCase, config.FrontEndConfig)
+	midtransTransactionUseCase := midtransUseCase.NewMidtransTransactionUseCase(config.DB, config.Log, config.Validate, paymentGatewayRegistry, orderRepository, midtransTransactionRepository, xenditCallbackUseCase, config.FrontEndConfig)
+	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validate, orderRepository, productRepository, categoryRepository, addressRepository, discountCouponRepository, discountUsageRepository, deliveryRepository, orderProductRepository, walletRepository, xenditTransactionRepository, xenditTransactionQRCodeUseCase, midtransTransactionUseCase, paymentGatewayRegistry, applicationRepository, config.Email, notificationRepository, config.WalletConfig, orderRefundRepository, orderPaymentLegRepository, walletTransactionRepository)
+	applicationUseCase := usecase.NewApplicationUseCase(config.DB, config.Log, config.Validate, applicationRepository, auditLogUseCase)
 	cartUseCase := usecase.NewCartUseCase(config.DB, config.Log, config.Validate, cartRepository, productRepository, cartItemRepository)
-	xenditCallbackUseCase := xenditUseCase.NewXenditCallbackUseCase(config.DB, config.Log, config.Validate, orderRepository, xenditTransactionRepository, config.XenditClient, xenditPayoutRepository, userRepository, walletRepository, payoutRepository, applicationRepository, notificationRepository, config.Email)
-	xenditPayoutUseCase := xenditUseCase.NewXenditPayoutUseCase(config.DB, config.Log, config.Validate, xenditPayoutRepository, config.XenditClient, walletRepository, userRepository)
-	payoutUseCase := usecase.NewPayoutUseCase(config.DB, config.Log, config.Validate, payoutRepository, xenditPayoutUseCase, walletRepository, userRepository)
-	walletUseCase := usecase.NewWalletUseCase(config.DB, config.Log, config.Validate, userRepository, walletRepository, walletWithdrawRepository)
+	xenditWebhookEventUseCase := xenditUseCase.NewXenditWebhookEventUseCase(config.DB, config.Log, config.Validate, xenditWebhookEventRepository, xenditCallbackUseCase, paymentGatewayRegistry, config.FrontEndConfig)
+	xenditPayoutUseCase := xenditUseCase.NewXenditPayoutUseCase(config.DB, config.Log, config.Validate, xenditPayoutRepository, config.XenditClient, walletRepository, userRepository, auditLogUseCase)
+	bankAccountUseCase := usecase.NewBankAccountUseCase(config.DB, config.Log, config.Validate, bankAccountRepository, config.WalletConfig, auditLogUseCase)
+	withdrawPolicyUseCase := usecase.NewWithdrawPolicyUseCase(config.DB, config.Log, config.Validate, withdrawPolicyRepository, walletWithdrawRepository, walletTransactionRepository, auditLogUseCase, config.WalletConfig)
+	payoutUseCase := usecase.NewPayoutUseCase(config.DB, config.Log, config.Validate, payoutRepository, xenditPayoutUseCase, walletRepository, userRepository, bankAccountUseCase, auditLogUseCase)
+	reconciliationConfig := config.PaymentReconciliationConfig
+	if reconciliationConfig == nil {
+		reconciliationConfig = new(model.PaymentReconciliationConfig)
+	}
+	xenditReconciliationUseCase := xenditUseCase.NewXenditReconciliationUseCase(config.DB, config.Log, xenditTransactionRepository, paymentGatewayRegistry, xenditCallbackUseCase, config.FrontEndConfig, reconciliationConfig)
+	cashPaymentUseCase := usecase.NewCashPaymentUseCase(config.DB, config.Log, config.Validate, orderRepository, cashCollectionRepository, xenditCallbackUseCase, auditLogUseCase)
+	orderRefundUseCase := usecase.NewOrderRefundUseCase(config.DB, config.Log, config.Validate, orderRepository, orderRefundRepository, walletRepository, paymentGatewayRegistry, config.WalletConfig, orderPaymentLegRepository, auditLogUseCase)
+	roleUseCase := usecase.NewRoleUseCase(config.DB, config.Log, config.Validate, roleRepository, rolePermissionRepository, userRepository, auditLogUseCase)
+	walletUseCase := usecase.NewWalletUseCase(config.DB, config.Log, config.Validate, userRepository, walletRepository, walletWithdrawRepository, walletAdjustmentRepository, walletTransactionRepository, applicationRepository, config.Email, config.WalletConfig, xenditPayoutUseCase, bankAccountUseCase, withdrawPolicyUseCase, auditLogUseCase)
 
 	// setup controller
 	userController := http.NewUserController(userUseCase, config.Log, config.AuthConfig, config.FrontEndConfig, applicationUseCase, config.Config)
+	twoFactorController := http.NewTwoFactorController(twoFactorUseCase, config.Log)
 	addressController := http.NewAddressController(addressUseCase, config.Log)
 	categoryController := http.NewCategoryController(categoryUseCase, config.Log)
 	productController := http.NewProductController(productUseCase, config.Log)
@@ -91,22 +161,41 @@ func Bootstrap(config *BootstrapConfig) {
 	deliveryController := http.NewDeliveryController(deliveryUseCase, config.Log)
 	productReviewController := http.NewProductReviewController(productReviewUseCase, config.Log)
 	xenditQRCodeTransactionController := xenditController.NewXenditQRCodeTransctionController(xenditTransactionQRCodeUseCase, config.Log, config.DB)
-	xenditCallbackController := xenditController.NewXenditCallbackController(xenditCallbackUseCase, config.Log, config.FrontEndConfig)
+	xenditCallbackController := xenditController.NewXenditCallbackController(xenditWebhookEventUseCase, config.Log)
+	xenditWebhookEventController := xenditController.NewXenditWebhookEventController(xenditWebhookEventUseCase, config.Log)
 	applicationController := http.NewApplicationController(applicationUseCase, config.Log)
 	cartController := http.NewCartController(cartUseCase, config.Log)
 	xenditPayoutController := xenditController.NewXenditPayoutController(xenditPayoutUseCase, config.Log, config.DB)
 	payoutController := http.NewPayoutController(payoutUseCase, config.Log)
 	walletController := http.NewWalletController(walletUseCase, config.Log)
+	bankAccountController := http.NewBankAccountController(bankAccountUseCase, config.Log)
+	withdrawPolicyController := http.NewWithdrawPolicyController(withdrawPolicyUseCase, config.Log)
+	xenditReconciliationController := xenditController.NewXenditReconciliationController(xenditReconciliationUseCase, config.Log)
+	cashPaymentController := http.NewCashPaymentController(cashPaymentUseCase, config.Log, config.FrontEndConfig)
+	orderRefundController := http.NewOrderRefundController(orderRefundUseCase, config.Log)
+	roleController := http.NewRoleController(roleUseCase, config.Log)
+	auditLogController := http.NewAuditLogController(auditLogUseCase, config.Log)
+	var paymentSimulatorController *http.PaymentSimulatorController
+	if simulatorGateway != nil {
+		paymentSimulatorUseCase := usecase.NewPaymentSimulatorUseCase(config.Log, config.Validate, simulatorGateway, xenditWebhookEventUseCase)
+		paymentSimulatorController = http.NewPaymentSimulatorController(paymentSimulatorUseCase, config.Log)
+	}
+	var midtransTransactionController *midtransController.MidtransTransactionController
+	if paymentGatewayRegistry.Has(enum_state.PAYMENT_GATEWAY_MIDTRANS) {
+		midtransTransactionController = midtransController.NewMidtransTransactionController(midtransTransactionUseCase, config.Log)
+	}
 
 	// setup middleware
 	authMiddleware := middleware.NewAuth(userUseCase)
 	roleMiddleware := middleware.NewRole(userUseCase)
+	twoFactorFreshMiddleware := middleware.NewTwoFactorFresh(twoFactorUseCase)
 	authXenditMiddleware := middleware.NewAuthXenditCallback(config.Config, config.Log)
 	authAdminCreationMiddleware := middleware.NewAuthUseAdminKey(config.AuthConfig, config.Log)
 
 	routeConfig := route.RouteConfig{
 		App:                               config.App,
 		UserController:                    userController,
+		TwoFactorController:               twoFactorController,
 		AddressController:                 addressController,
 		CategoryController:                categoryController,
 		ProductController:                 productController,
@@ -118,14 +207,34 @@ func Bootstrap(config *BootstrapConfig) {
 		PayoutController:                  payoutController,
 		XenditCallbackController:          xenditCallbackController,
 		XenditPayoutController:            xenditPayoutController,
+		XenditWebhookEventController:      xenditWebhookEventController,
+		XenditReconciliationController:    xenditReconciliationController,
 		ApplicationController:             applicationController,
 		CartController:                    cartController,
 		WalletController:                  walletController,
+		BankAccountController:             bankAccountController,
+		WithdrawPolicyController:          withdrawPolicyController,
+		CashPaymentController:             cashPaymentController,
+		OrderRefundController:             orderRefundController,
+		PaymentSimulatorController:        paymentSimulatorController,
+		MidtransTransactionController:     midtransTransactionController,
+		RoleController:                    roleController,
+		AuditLogController:                auditLogController,
 		AuthMiddleware:                    authMiddleware,
 		RoleMiddleware:                    roleMiddleware,
+		TwoFactorFreshMiddleware:          twoFactorFreshMiddleware,
 		AuthXenditMiddleware:              authXenditMiddleware,
 		AuthAdminCreationMiddleware:       authAdminCreationMiddleware,
 		PusherClient:                      config.PusherClient,
+		RateLimitConfig:                   config.RateLimitConfig,
+		RateLimitStore:                    rate_limiter.NewMemoryStore(),
+		Log:                               config.Log,
 	}
 	routeConfig.Setup()
+
+	// cek berkala transaksi yang masih pending untuk menangani callback payment gateway yang hilang
+	StartPaymentReconciliationJob(config.App, xenditReconciliationUseCase, reconciliationConfig, config.Log)
+
+	// hapus audit log yang sudah melewati masa retensi
+	StartAuditLogRetentionJob(auditLogUseCase, auditLogConfig, config.Log)
 }
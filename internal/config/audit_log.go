@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewAuditLogConfig(viper *viper.Viper) *model.AuditLogConfig {
+	newAuditLogConfig := new(model.AuditLogConfig)
+	// lama audit log disimpan, 0 = disimpan selamanya
+	newAuditLogConfig.RetentionDays = 365
+	if viper.IsSet("AUDIT_LOG_RETENTION_DAYS") {
+		newAuditLogConfig.RetentionDays = viper.GetInt("AUDIT_LOG_RETENTION_DAYS")
+	}
+	newAuditLogConfig.CleanupInterval = 24 * time.Hour
+	return newAuditLogConfig
+}
+
+// StartAuditLogRetentionJob menghapus audit log yang melewati masa retensi secara berkala di background
+func StartAuditLogRetentionJob(useCase *usecase.AuditLogUseCase, auditLogConfig *model.AuditLogConfig, log *logrus.Logger) {
+	if auditLogConfig == nil || auditLogConfig.RetentionDays <= 0 || auditLogConfig.CleanupInterval <= 0 {
+		log.Info("audit log retention job is disabled")
+		return
+	}
+
+	go func() {
+		runAuditLogRetention(useCase, log)
+		ticker := time.NewTicker(auditLogConfig.CleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runAuditLogRetention(useCase, log)
+		}
+	}()
+}
+
+func runAuditLogRetention(useCase *usecase.AuditLogUseCase, log *logrus.Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("audit log retention job panic : %+v", r)
+		}
+	}()
+
+	deleted, err := useCase.PruneExpired(context.Background())
+	if err != nil {
+		log.Warnf("failed to prune expired audit logs : %+v", err)
+		return
+	}
+
+	log.Infof("audit log retention deleted %d expired audit logs", deleted)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"seblak-bombom-restful-api/internal/model"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,5 +11,64 @@ func NewAuthConfig(viper *viper.Viper) *model.AuthConfig {
 	adminCreationKey := viper.GetString("ADMIN_CREATION_KEY")
 	newAuthConfig := new(model.AuthConfig)
 	newAuthConfig.AdminCreationKey = adminCreationKey
+	// secret untuk menandatangani JWT access token
+	newAuthConfig.AccessTokenSecret = viper.GetString("AUTH_ACCESS_TOKEN_SECRET")
+	// access token dibuat singkat karena tidak dicek ke database, default 15 menit
+	accessTokenMinutes := viper.GetInt("AUTH_ACCESS_TOKEN_TTL_MINUTES")
+	if accessTokenMinutes <= 0 {
+		accessTokenMinutes = 15
+	}
+	newAuthConfig.AccessTokenTTL = time.Duration(accessTokenMinutes) * time.Minute
+	// masa berlaku satu sesi login (family refresh token), dikali 3 jika remember me, default 24 jam
+	refreshTokenHours := viper.GetInt("AUTH_REFRESH_TOKEN_TTL_HOURS")
+	if refreshTokenHours <= 0 {
+		refreshTokenHours = 24
+	}
+	newAuthConfig.RefreshTokenTTL = time.Duration(refreshTokenHours) * time.Hour
+	// akun dikunci sementara setelah sekian kali login gagal berturut-turut, default 5
+	newAuthConfig.LoginMaxFailedAttempts = viper.GetInt("AUTH_LOGIN_MAX_FAILED_ATTEMPTS")
+	if newAuthConfig.LoginMaxFailedAttempts <= 0 {
+		newAuthConfig.LoginMaxFailedAttempts = 5
+	}
+	// satu alamat IP bisa dipakai banyak user (wifi toko, NAT) sehingga batasnya lebih longgar, default 20
+	newAuthConfig.LoginIPMaxFailedAttempts = viper.GetInt("AUTH_LOGIN_IP_MAX_FAILED_ATTEMPTS")
+	if newAuthConfig.LoginIPMaxFailedAttempts <= 0 {
+		newAuthConfig.LoginIPMaxFailedAttempts = 20
+	}
+	lockoutMinutes := viper.GetInt("AUTH_LOGIN_LOCKOUT_MINUTES")
+	if lockoutMinutes <= 0 {
+		lockoutMinutes = 15
+	}
+	newAuthConfig.LoginLockoutDuration = time.Duration(lockoutMinutes) * time.Minute
+	// kode verifikasi lupa password hangus setelah sekian kali salah, default 5
+	newAuthConfig.PasswordResetMaxAttempts = viper.GetInt("AUTH_PASSWORD_RESET_MAX_ATTEMPTS")
+	if newAuthConfig.PasswordResetMaxAttempts <= 0 {
+		newAuthConfig.PasswordResetMaxAttempts = 5
+	}
+	// nama yang tampil di aplikasi authenticator
+	newAuthConfig.TwoFactorIssuer = viper.GetString("AUTH_TWO_FACTOR_ISSUER")
+	if newAuthConfig.TwoFactorIssuer == "" {
+		newAuthConfig.TwoFactorIssuer = "Seblak Bombom"
+	}
+	// jika true, admin tanpa 2FA tidak bisa mengakses route admin sampai 2FA diaktifkan
+	newAuthConfig.TwoFactorRequiredForAdmin = viper.GetBool("AUTH_TWO_FACTOR_REQUIRED_FOR_ADMIN")
+	// batas waktu memasukkan kode 2FA setelah password benar, default 5 menit
+	challengeMinutes := viper.GetInt("AUTH_TWO_FACTOR_CHALLENGE_MINUTES")
+	if challengeMinutes <= 0 {
+		challengeMinutes = 5
+	}
+	newAuthConfig.TwoFactorChallengeTTL = time.Duration(challengeMinutes) * time.Minute
+	// endpoint berisiko tinggi (approval penarikan, payout, saldo xendit) butuh konfirmasi 2FA dalam rentang ini, default 10 menit
+	freshMinutes := viper.GetInt("AUTH_TWO_FACTOR_FRESH_MINUTES")
+	if freshMinutes <= 0 {
+		freshMinutes = 10
+	}
+	newAuthConfig.TwoFactorFreshDuration = time.Duration(freshMinutes) * time.Minute
+	// batas waktu dari membuka halaman login provider OpenID Connect sampai callback, default 10 menit
+	oidcStateMinutes := viper.GetInt("AUTH_OIDC_STATE_MINUTES")
+	if oidcStateMinutes <= 0 {
+		oidcStateMinutes = 10
+	}
+	newAuthConfig.OIDCStateTTL = time.Duration(oidcStateMinutes) * time.Minute
 	return newAuthConfig
 }
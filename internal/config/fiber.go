@@ -13,6 +13,8 @@ func NewFiber(config *viper.Viper) *fiber.App {
 		ErrorHandler: NewErrorHandler(),
 		Prefork: config.GetBool("WEB_PREFORK"),
 		BodyLimit: 100 * 1024 * 1024,
+		// header berisi IP asli client jika aplikasi berada di belakang reverse proxy, contoh: X-Forwarded-For
+		ProxyHeader: config.GetString("WEB_PROXY_HEADER"),
 	})
 
 	return app
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"seblak-bombom-restful-api/internal/model"
+
+	"github.com/spf13/viper"
+)
+
+func NewMidtransConfig(viper *viper.Viper) *model.MidtransConfig {
+	newMidtransConfig := new(model.MidtransConfig)
+	// key production hanya dipakai saat ENV=prod, selain itu memakai key sandbox
+	if viper.GetString("ENV") == "prod" {
+		newMidtransConfig.ServerKey = viper.GetString("MIDTRANS_PRODUCTION_SERVER_KEY")
+		newMidtransConfig.ClientKey = viper.GetString("MIDTRANS_PRODUCTION_CLIENT_KEY")
+		newMidtransConfig.IsProduction = true
+	} else {
+		newMidtransConfig.ServerKey = viper.GetString("MIDTRANS_SANDBOX_SERVER_KEY")
+		newMidtransConfig.ClientKey = viper.GetString("MIDTRANS_SANDBOX_CLIENT_KEY")
+	}
+	return newMidtransConfig
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// issuer bawaan untuk provider yang dikenal, provider lain wajib mengisi OIDC_<NAME>_ISSUER_URL
+var defaultOIDCIssuers = map[string]string{
+	"google": "https://accounts.google.com",
+}
+
+// NewOIDCConfig membaca OIDC_PROVIDERS (dipisah koma, misalnya "google,keycloak") lalu untuk setiap provider
+// OIDC_<NAME>_ISSUER_URL, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL dan OIDC_<NAME>_SCOPES
+func NewOIDCConfig(viper *viper.Viper) *model.OIDCConfig {
+	newOIDCConfig := new(model.OIDCConfig)
+	for _, name := range strings.Split(viper.GetString("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name)
+		newProvider := model.OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    viper.GetString(prefix + "_ISSUER_URL"),
+			ClientID:     viper.GetString(prefix + "_CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "_CLIENT_SECRET"),
+			RedirectURL:  viper.GetString(prefix + "_REDIRECT_URL"),
+			Scopes:       strings.Fields(viper.GetString(prefix + "_SCOPES")),
+		}
+
+		if newProvider.IssuerURL == "" {
+			newProvider.IssuerURL = defaultOIDCIssuers[name]
+		}
+
+		if len(newProvider.Scopes) == 0 {
+			newProvider.Scopes = []string{"openid", "email", "profile"}
+		}
+
+		// provider yang belum lengkap konfigurasinya tidak diaktifkan
+		if newProvider.IssuerURL == "" || newProvider.ClientID == "" || newProvider.RedirectURL == "" {
+			continue
+		}
+
+		newOIDCConfig.Providers = append(newOIDCConfig.Providers, newProvider)
+	}
+
+	return newOIDCConfig
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"net/url"
+	"seblak-bombom-restful-api/internal/model"
+	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+)
+
+func NewPaymentReconciliationConfig(viper *viper.Viper) *model.PaymentReconciliationConfig {
+	newPaymentReconciliationConfig := new(model.PaymentReconciliationConfig)
+	// interval pengecekan status transaksi pending ke payment gateway, 0 = job nonaktif
+	newPaymentReconciliationConfig.Interval = time.Duration(viper.GetInt("PAYMENT_RECONCILIATION_INTERVAL_MINUTES")) * time.Minute
+	// transaksi yang baru dibuat diberi waktu untuk menerima callback terlebih dahulu
+	newPaymentReconciliationConfig.MinAge = time.Duration(viper.GetInt("PAYMENT_RECONCILIATION_MIN_AGE_MINUTES")) * time.Minute
+	newPaymentReconciliationConfig.BatchSize = viper.GetInt("PAYMENT_RECONCILIATION_BATCH_SIZE")
+	if newPaymentReconciliationConfig.BatchSize <= 0 {
+		newPaymentReconciliationConfig.BatchSize = 50
+	}
+	newPaymentReconciliationConfig.BaseURL = viper.GetString("DOMAIN")
+	return newPaymentReconciliationConfig
+}
+
+// StartPaymentReconciliationJob menjalankan rekonsiliasi status pembayaran secara berkala di background
+func StartPaymentReconciliationJob(app *fiber.App, useCase *xenditUseCase.XenditReconciliationUseCase, reconciliationConfig *model.PaymentReconciliationConfig, log *logrus.Logger) {
+	if reconciliationConfig == nil || reconciliationConfig.Interval <= 0 {
+		log.Info("payment reconciliation job is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(reconciliationConfig.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runPaymentReconciliation(app, useCase, reconciliationConfig, log)
+		}
+	}()
+}
+
+func runPaymentReconciliation(app *fiber.App, useCase *xenditUseCase.XenditReconciliationUseCase, reconciliationConfig *model.PaymentReconciliationConfig, log *logrus.Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("payment reconciliation job panic : %+v", r)
+		}
+	}()
+
+	// job tidak berasal dari request http, domain api dipakai untuk link gambar pada notifikasi
+	requestCtx := new(fasthttp.RequestCtx)
+	requestCtx.Request.SetRequestURI(reconciliationConfig.BaseURL)
+	if baseURL, err := url.Parse(reconciliationConfig.BaseURL); err == nil && baseURL.Scheme == "https" {
+		requestCtx.Request.Header.Set(fiber.HeaderXForwardedProto, "https")
+	}
+	ctx := app.AcquireCtx(requestCtx)
+	defer app.ReleaseCtx(ctx)
+
+	results, err := useCase.ReconcilePending(ctx)
+	if err != nil {
+		log.Warnf("failed to reconcile pending payments : %+v", err)
+		return
+	}
+
+	updated := 0
+	for _, result := range results {
+		if result.Updated {
+			updated++
+		}
+	}
+	log.Infof("payment reconciliation checked %d pending transactions, %d updated", len(results), updated)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func NewRateLimitConfig(viper *viper.Viper) *model.RateLimitConfig {
+	newRateLimitConfig := new(model.RateLimitConfig)
+	// rate limit aktif kecuali RATE_LIMIT_ENABLED=false
+	newRateLimitConfig.Enabled = true
+	if viper.IsSet("RATE_LIMIT_ENABLED") {
+		newRateLimitConfig.Enabled = viper.GetBool("RATE_LIMIT_ENABLED")
+	}
+
+	// daftar api key dipisahkan koma, hanya api key terdaftar yang mendapat bucket api_key
+	apiKeys := []string{}
+	for _, apiKey := range strings.Split(viper.GetString("RATE_LIMIT_API_KEYS"), ",") {
+		if apiKey = strings.TrimSpace(apiKey); apiKey != "" {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+
+	// register dan lupa password mengirim email sehingga batasnya paling ketat
+	newRateLimitConfig.Register = newRateLimitPolicy(viper, "register", 5, time.Hour, enum_state.RATE_LIMIT_KEY_IP, apiKeys)
+	newRateLimitConfig.Login = newRateLimitPolicy(viper, "login", 20, time.Minute, enum_state.RATE_LIMIT_KEY_IP, apiKeys)
+	newRateLimitConfig.ForgotPassword = newRateLimitPolicy(viper, "forgot_password", 5, 15*time.Minute, enum_state.RATE_LIMIT_KEY_IP, apiKeys)
+	newRateLimitConfig.Guest = newRateLimitPolicy(viper, "guest", 120, time.Minute, enum_state.RATE_LIMIT_KEY_IP, apiKeys)
+	newRateLimitConfig.Order = newRateLimitPolicy(viper, "order", 10, time.Minute, enum_state.RATE_LIMIT_KEY_USER, apiKeys)
+	newRateLimitConfig.User = newRateLimitPolicy(viper, "user", 300, time.Minute, enum_state.RATE_LIMIT_KEY_USER, apiKeys)
+	return newRateLimitConfig
+}
+
+// newRateLimitPolicy membaca RATE_LIMIT_<NAME>_LIMIT, RATE_LIMIT_<NAME>_PERIOD_SECONDS dan RATE_LIMIT_<NAME>_KEY_BY
+func newRateLimitPolicy(viper *viper.Viper, name string, defaultLimit int, defaultPeriod time.Duration, defaultKeyBy enum_state.RateLimitKey, apiKeys []string) model.RateLimitPolicy {
+	prefix := "RATE_LIMIT_" + strings.ToUpper(name)
+	newPolicy := model.RateLimitPolicy{
+		Name:    name,
+		Limit:   viper.GetInt(prefix + "_LIMIT"),
+		Period:  time.Duration(viper.GetInt(prefix+"_PERIOD_SECONDS")) * time.Second,
+		KeyBy:   enum_state.RateLimitKey(viper.GetString(prefix + "_KEY_BY")),
+		APIKeys: apiKeys,
+	}
+
+	if newPolicy.Limit <= 0 {
+		newPolicy.Limit = defaultLimit
+	}
+
+	if newPolicy.Period <= 0 {
+		newPolicy.Period = defaultPeriod
+	}
+
+	if !enum_state.IsValidRateLimitKey(newPolicy.KeyBy) {
+		newPolicy.KeyBy = defaultKeyBy
+	}
+
+	return newPolicy
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"seblak-bombom-restful-api/internal/model"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func NewWalletConfig(viper *viper.Viper) *model.WalletConfig {
+	newWalletConfig := new(model.WalletConfig)
+	// penyesuaian saldo di atas nilai ini wajib disetujui admin lain, 0 = tanpa persetujuan
+	newWalletConfig.AdjustmentApprovalThreshold = float32(viper.GetFloat64("WALLET_ADJUSTMENT_APPROVAL_THRESHOLD"))
+	// saldo masuk ke wallet yang dibekukan ditahan sebagai transaksi pending sampai wallet diaktifkan kembali
+	newWalletConfig.HoldCreditsWhenFrozen = viper.GetBool("WALLET_HOLD_CREDITS_WHEN_FROZEN")
+	// payout ke rekening yang belum diverifikasi admin hanya boleh sampai nilai ini, 0 = wajib verifikasi
+	newWalletConfig.UnverifiedPayoutLimit = float32(viper.GetFloat64("WALLET_UNVERIFIED_PAYOUT_LIMIT"))
+	// batas harian / bulanan withdraw dihitung di zona waktu bisnis, bukan zona waktu yang dikirim client
+	newWalletConfig.BusinessTimeZone = time.Local
+	if timeZone := viper.GetString("WALLET_BUSINESS_TIMEZONE"); timeZone != "" {
+		if loc, err := time.LoadLocation(timeZone); err == nil {
+			newWalletConfig.BusinessTimeZone = loc
+		}
+	}
+	return newWalletConfig
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type AuditLogController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.AuditLogUseCase
+}
+
+func NewAuditLogController(useCase *usecase.AuditLogUseCase, logger *logrus.Logger) *AuditLogController {
+	return &AuditLogController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *AuditLogController) GetAll(ctx *fiber.Ctx) error {
+	// filter audit log, kosong berarti semua
+	action := strings.TrimSpace(ctx.Query("action", ""))
+	entityType := strings.TrimSpace(ctx.Query("entity_type", ""))
+	entityId := strings.TrimSpace(ctx.Query("entity_id", ""))
+	startDate := strings.TrimSpace(ctx.Query("start_date", ""))
+	endDate := strings.TrimSpace(ctx.Query("end_date", ""))
+
+	var actorId uint64
+	if getActorId := ctx.Query("actor_id", ""); getActorId != "" {
+		parsedActorId, err := strconv.Atoi(getActorId)
+		if err != nil {
+			c.Log.Warnf("failed to convert actor_id to integer : %+v", err)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert actor_id to integer : %+v", err))
+		}
+		actorId = uint64(parsedActorId)
+	}
+
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// ambil data sorting
+	getColumn := ctx.Query("column", "")
+	getSortBy := ctx.Query("sort_by", "desc")
+
+	// Ambil query parameter 'per_page' dengan default value 10 jika tidak disediakan
+	perPage, err := strconv.Atoi(ctx.Query("per_page", "10"))
+	if err != nil {
+		c.Log.Warnf("invalid 'per_page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'per_page' parameter : %+v", err))
+	}
+
+	// Ambil query parameter 'page' dengan default value 1 jika tidak disediakan
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		c.Log.Warnf("invalid 'page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'page' parameter : %+v", err))
+	}
+
+	response, totalCurrent, totalReal, totalActive, totalInactive, totalPages, err := c.UseCase.GetAllPaginate(ctx.Context(), page, perPage, actorId, action, entityType, entityId, startDate, endDate, loc, getColumn, getSortBy)
+	if err != nil {
+		c.Log.Warnf("failed to get all audit logs : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponsePagination[*[]model.AuditLogResponse]{
+		Code:               200,
+		Status:             "success to get all audit logs",
+		Data:               response,
+		TotalRealDatas:     totalReal,
+		TotalCurrentDatas:  totalCurrent,
+		TotalActiveDatas:   totalActive,
+		TotalInactiveDatas: totalInactive,
+		TotalPages:         totalPages,
+		CurrentPages:       page,
+		DataPerPages:       perPage,
+	})
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type BankAccountController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.BankAccountUseCase
+}
+
+func NewBankAccountController(useCase *usecase.BankAccountUseCase, logger *logrus.Logger) *BankAccountController {
+	return &BankAccountController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *BankAccountController) Add(ctx *fiber.Ctx) error {
+	request := new(model.CreateBankAccountRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = auth.ID
+	response, err := c.UseCase.Add(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to add bank account : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.ApiResponse[*model.BankAccountResponse]{
+		Code:   201,
+		Status: "success to add new bank account",
+		Data:   response,
+	})
+}
+
+func (c *BankAccountController) GetAllCurrent(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.GetAllCurrent(ctx.Context(), auth.ID)
+	if err != nil {
+		c.Log.Warnf("failed to get all bank accounts by current user : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*[]model.BankAccountResponse]{
+		Code:   200,
+		Status: "success to get all bank accounts by current user",
+		Data:   response,
+	})
+}
+
+func (c *BankAccountController) Remove(ctx *fiber.Ctx) error {
+	getId := ctx.Params("bankAccountId")
+	bankAccountId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert bank_account_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert bank_account_id to integer : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request := new(model.DeleteBankAccountRequest)
+	request.ID = uint64(bankAccountId)
+	request.UserId = auth.ID
+	response, err := c.UseCase.Delete(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to delete bank account : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to delete bank account",
+		Data:   response,
+	})
+}
+
+func (c *BankAccountController) UpdateVerification(ctx *fiber.Ctx) error {
+	getId := ctx.Params("bankAccountId")
+	bankAccountId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert bank_account_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert bank_account_id to integer : %+v", err))
+	}
+
+	request := new(model.VerifyBankAccountRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.ID = uint64(bankAccountId)
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.UpdateVerificationByAdmin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to update bank account verification : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.BankAccountResponse]{
+		Code:   200,
+		Status: "success to update bank account verification",
+		Data:   response,
+	})
+}
+
+func (c *BankAccountController) GetPayoutChannels(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[[]helper_others.PayoutChannel]{
+		Code:   200,
+		Status: "success to get all payout channels",
+		Data:   helper_others.PayoutChannels,
+	})
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CashPaymentController struct {
+	Log            *logrus.Logger
+	UseCase        *usecase.CashPaymentUseCase
+	FrontEndConfig *model.FrontEndConfig
+}
+
+func NewCashPaymentController(useCase *usecase.CashPaymentUseCase, logger *logrus.Logger, frontEndConfig *model.FrontEndConfig) *CashPaymentController {
+	return &CashPaymentController{
+		Log:            logger,
+		UseCase:        useCase,
+		FrontEndConfig: frontEndConfig,
+	}
+}
+
+func (c *CashPaymentController) Confirm(ctx *fiber.Ctx) error {
+	getId := ctx.Params("orderId")
+	orderId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert order_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert order_id to integer : %+v", err))
+	}
+
+	request := new(model.ConfirmCashPaymentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	request.OrderId = uint64(orderId)
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+	auth := middleware.GetCurrentUser(ctx)
+	request.CurrentUserId = auth.ID
+	request.CurrentUserRole = auth.Role
+	request.CurrentUserCanConfirm = auth.HasPermission(enum_state.PERMISSION_CASH_PAYMENTS_CONFIRM)
+	request.BaseFrontEndURL = c.FrontEndConfig.BaseURL
+	response, err := c.UseCase.Confirm(ctx, request)
+	if err != nil {
+		c.Log.Warnf("failed to confirm cash payment : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.CashCollectionResponse]{
+		Code:   200,
+		Status: "success to confirm cash payment",
+		Data:   response,
+	})
+}
+
+func (c *CashPaymentController) GetReconciliation(ctx *fiber.Ctx) error {
+	request := new(model.GetCashReconciliationRequest)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+	request.Date = ctx.Query("date", time.Now().In(loc).Format("2006-01-02"))
+	if getCollectedBy := ctx.Query("collected_by", ""); getCollectedBy != "" {
+		collectedBy, err := strconv.Atoi(getCollectedBy)
+		if err != nil {
+			c.Log.Warnf("failed to convert collected_by to integer : %+v", err)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert collected_by to integer : %+v", err))
+		}
+		request.CollectedBy = uint64(collectedBy)
+	}
+
+	response, err := c.UseCase.GetReconciliation(ctx, request)
+	if err != nil {
+		c.Log.Warnf("failed to get cash reconciliation : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.CashReconciliationResponse]{
+		Code:   200,
+		Status: "success to get cash reconciliation",
+		Data:   response,
+	})
+}
+
+func (c *CashPaymentController) Reconcile(ctx *fiber.Ctx) error {
+	request := new(model.ReconcileCashRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+	auth := middleware.GetCurrentUser(ctx)
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.Reconcile(ctx, request)
+	if err != nil {
+		c.Log.Warnf("failed to reconcile cash collections : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.CashReconciliationResponse]{
+		Code:   200,
+		Status: "success to reconcile cash collections",
+		Data:   response,
+	})
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase/midtrans"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type MidtransTransactionController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.MidtransTransactionUseCase
+}
+
+func NewMidtransTransactionController(useCase *usecase.MidtransTransactionUseCase, logger *logrus.Logger) *MidtransTransactionController {
+	return &MidtransTransactionController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *MidtransTransactionController) GetTransaction(ctx *fiber.Ctx) error {
+	getId := ctx.Params("orderId")
+	orderId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("Failed to convert order_id into integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, "failed to convert order_id to integer")
+	}
+
+	midtransRequest := new(model.GetMidtransTransaction)
+	midtransRequest.OrderId = uint64(orderId)
+
+	response, err := c.UseCase.GetTransaction(ctx, midtransRequest)
+	if err != nil {
+		c.Log.Warnf("Failed to get midtrans transaction : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.MidtransTransactionResponse]{
+		Code:   200,
+		Status: "Success to get midtrans transaction by order id",
+		Data:   response,
+	})
+}
+
+func (c *MidtransTransactionController) Notification(ctx *fiber.Ctx) error {
+	// keaslian notifikasi dicek lewat signature_key di dalam body
+	err := c.UseCase.Notification(ctx, ctx.Body())
+	if err != nil {
+		c.Log.Warnf("Failed to process midtrans notification : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code":   200,
+		"status": "Success to get midtrans notification",
+	})
+}
@@ -254,18 +254,38 @@ func (c *OrderController) ShowInvoiceByOrderId(ctx *fiber.Ctx) error {
 		items = append(items, item)
 	}
 
-	templatePath := "../internal/templates/pdf/orders/invoice.html"
-	tmpl, err := template.ParseFiles(templatePath)
-	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
-	}
-
 	getTimeZoneUser := ctx.Query("timezone", "UTC")
 	loc, err := time.LoadLocation(getTimeZoneUser)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
+	refunds := []map[string]any{}
+	for _, refund := range order.Refunds {
+		destination := "Saldo Wallet"
+		if refund.Destination == enum_state.REFUND_DESTINATION_ORIGINAL_METHOD {
+			destination = "Metode Pembayaran Asal"
+		}
+
+		refunds = append(refunds, map[string]any{
+			"Date":        refund.CreatedAt.ToTime().In(loc).Format("02 January 2006"),
+			"Destination": destination,
+			"Amount":      helper_others.FormatNumberFloat32(refund.Amount),
+			"IsPending":   refund.Status == enum_state.REFUND_STATUS_PENDING,
+		})
+	}
+
+	// bahasa dibatasi agar query lang tidak bisa dipakai untuk membaca file lain
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	if getLang != string(enum_state.INDONESIA) {
+		getLang = string(enum_state.ENGLISH)
+	}
+	templatePath := fmt.Sprintf("../internal/templates/%s/pdf/orders/invoice.html", getLang)
+	tmpl, err := template.ParseFiles(templatePath)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
 	timeZone := helper_others.TimeZoneMap[getTimeZoneUser]
 	logoImage := fmt.Sprintf("../uploads/images/application/%s", app.LogoFilename)
 	logoImageToBase64, err := helper_others.ImageToBase64(logoImage)
@@ -292,6 +312,8 @@ func (c *OrderController) ShowInvoiceByOrderId(ctx *fiber.Ctx) error {
 		"Discount":           helper_others.FormatNumberFloat32(order.TotalDiscount),
 		"ShippingCost":       helper_others.FormatNumberFloat32(order.DeliveryCost),
 		"TotalBilling":       helper_others.FormatNumberFloat32(order.TotalFinalPrice + app.ServiceFee),
+		"Refunds":            refunds,
+		"TotalAfterRefund":   helper_others.FormatNumberFloat32(order.TotalFinalPrice + app.ServiceFee - order.TotalRefunded),
 		"ServiceFee":         helper_others.FormatNumberFloat32(app.ServiceFee),
 		"PaymentMethod":      order.PaymentMethod,
 		"PaymentStatus":      order.PaymentStatus,
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderRefundController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.OrderRefundUseCase
+}
+
+func NewOrderRefundController(useCase *usecase.OrderRefundUseCase, logger *logrus.Logger) *OrderRefundController {
+	return &OrderRefundController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *OrderRefundController) Create(ctx *fiber.Ctx) error {
+	getId := ctx.Params("orderId")
+	orderId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert order_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert order_id to integer : %+v", err))
+	}
+
+	request := new(model.CreateOrderRefundRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	request.OrderId = uint64(orderId)
+	auth := middleware.GetCurrentUser(ctx)
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.Create(ctx, request)
+	if err != nil {
+		c.Log.Warnf("failed to create order refund : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.ApiResponse[*model.OrderRefundResponse]{
+		Code:   201,
+		Status: "success to create order refund",
+		Data:   response,
+	})
+}
+
+func (c *OrderRefundController) GetByOrderId(ctx *fiber.Ctx) error {
+	getId := ctx.Params("orderId")
+	orderId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert order_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert order_id to integer : %+v", err))
+	}
+
+	response, err := c.UseCase.GetByOrderId(ctx, uint64(orderId))
+	if err != nil {
+		c.Log.Warnf("failed to get order refunds : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.OrderRefundSummaryResponse]{
+		Code:   200,
+		Status: "success to get order refunds",
+		Data:   response,
+	})
+}
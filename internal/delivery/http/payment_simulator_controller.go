@@ -0,0 +1,41 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentSimulatorController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PaymentSimulatorUseCase
+}
+
+func NewPaymentSimulatorController(useCase *usecase.PaymentSimulatorUseCase, logger *logrus.Logger) *PaymentSimulatorController {
+	return &PaymentSimulatorController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *PaymentSimulatorController) Simulate(ctx *fiber.Ctx) error {
+	request := new(model.SimulatePaymentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	request.PaymentId = ctx.Params("paymentId")
+	if err := c.UseCase.Simulate(ctx, request); err != nil {
+		c.Log.Warnf("failed to simulate payment : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code":   200,
+		"status": "success to simulate payment",
+	})
+}
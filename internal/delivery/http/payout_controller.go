@@ -2,7 +2,6 @@ package http
 
 import (
 	"fmt"
-	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/usecase"
 	"strconv"
@@ -38,10 +37,6 @@ func (c *PayoutController) Create(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
 	}
 
-	if request.Method == enum_state.PAYOUT_METHOD_ONLINE {
-		request.XenditPayoutRequest.UserId = uint64(userId)
-	}
-
 	response, err := c.UseCase.Add(ctx, request)
 	if err != nil {
 		c.Log.Warnf("failed to create a new payout request : %+v", err)
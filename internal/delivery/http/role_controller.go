@@ -0,0 +1,240 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.RoleUseCase
+}
+
+func NewRoleController(useCase *usecase.RoleUseCase, logger *logrus.Logger) *RoleController {
+	return &RoleController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *RoleController) GetPermissions(ctx *fiber.Ctx) error {
+	response := c.UseCase.GetPermissions()
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[[]enum_state.Permission]{
+		Code:   200,
+		Status: "success to get all permissions",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) GetAll(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.GetAll(ctx.Context())
+	if err != nil {
+		c.Log.Warnf("failed to get all roles : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*[]model.RoleResponse]{
+		Code:   200,
+		Status: "success to get all roles",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) Get(ctx *fiber.Ctx) error {
+	roleId, err := c.getRoleId(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.GetRoleRequest)
+	request.ID = roleId
+	response, err := c.UseCase.Get(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to get role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.RoleResponse]{
+		Code:   200,
+		Status: "success to get role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) Create(ctx *fiber.Ctx) error {
+	request := new(model.CreateRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	response, err := c.UseCase.Create(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to create role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.ApiResponse[*model.RoleResponse]{
+		Code:   201,
+		Status: "success to create role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) Update(ctx *fiber.Ctx) error {
+	roleId, err := c.getRoleId(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.UpdateRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	request.ID = roleId
+	response, err := c.UseCase.Update(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to update role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.RoleResponse]{
+		Code:   200,
+		Status: "success to update role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) Delete(ctx *fiber.Ctx) error {
+	roleId, err := c.getRoleId(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.DeleteRoleRequest)
+	request.ID = roleId
+	response, err := c.UseCase.Delete(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to delete role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to delete role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) AssignToUser(ctx *fiber.Ctx) error {
+	userId, err := c.getUserId(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.AssignStaffRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = userId
+	request.CurrentAdminId = auth.ID
+	request.CurrentAdminRole = auth.Role
+	response, err := c.UseCase.AssignToUser(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to assign staff role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to assign staff role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) RemoveFromUser(ctx *fiber.Ctx) error {
+	userId, err := c.getUserId(ctx)
+	if err != nil {
+		return err
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request := new(model.RemoveStaffRoleRequest)
+	request.UserId = userId
+	request.CurrentAdminId = auth.ID
+	request.CurrentAdminRole = auth.Role
+	response, err := c.UseCase.RemoveFromUser(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to remove staff role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to remove staff role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) UpdateUserRole(ctx *fiber.Ctx) error {
+	userId, err := c.getUserId(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.UpdateUserRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = userId
+	request.CurrentAdminId = auth.ID
+	request.CurrentAdminRole = auth.Role
+	response, err := c.UseCase.UpdateUserRole(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to update user role : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to update user role",
+		Data:   response,
+	})
+}
+
+func (c *RoleController) getRoleId(ctx *fiber.Ctx) (uint64, error) {
+	getId := ctx.Params("roleId")
+	roleId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert role_id to integer : %+v", err)
+		return 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert role_id to integer : %+v", err))
+	}
+
+	return uint64(roleId), nil
+}
+
+func (c *RoleController) getUserId(ctx *fiber.Ctx) (uint64, error) {
+	getId := ctx.Params("userId")
+	userId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert user_id to integer : %+v", err)
+		return 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert user_id to integer : %+v", err))
+	}
+
+	return uint64(userId), nil
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.TwoFactorUseCase
+}
+
+func NewTwoFactorController(useCase *usecase.TwoFactorUseCase, logger *logrus.Logger) *TwoFactorController {
+	return &TwoFactorController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *TwoFactorController) GetStatus(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.GetStatus(ctx.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("failed to get two-factor status : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorStatusResponse]{
+		Code:   200,
+		Status: "success to get two-factor status",
+		Data:   response,
+	})
+}
+
+func (c *TwoFactorController) Setup(ctx *fiber.Ctx) error {
+	request := new(model.SetupTwoFactorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.Setup(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to setup two-factor authentication : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorSetupResponse]{
+		Code:   200,
+		Status: "success to setup two-factor authentication",
+		Data:   response,
+	})
+}
+
+func (c *TwoFactorController) Enable(ctx *fiber.Ctx) error {
+	request := new(model.TwoFactorCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.Enable(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to enable two-factor authentication : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorRecoveryCodesResponse]{
+		Code:   200,
+		Status: "success to enable two-factor authentication",
+		Data:   response,
+	})
+}
+
+func (c *TwoFactorController) Confirm(ctx *fiber.Ctx) error {
+	request := new(model.TwoFactorCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.Confirm(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to confirm two-factor code : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorConfirmResponse]{
+		Code:   200,
+		Status: "success to confirm two-factor code",
+		Data:   response,
+	})
+}
+
+func (c *TwoFactorController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	request := new(model.TwoFactorCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.RegenerateRecoveryCodes(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to regenerate recovery codes : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorRecoveryCodesResponse]{
+		Code:   200,
+		Status: "success to regenerate recovery codes",
+		Data:   response,
+	})
+}
+
+func (c *TwoFactorController) Disable(ctx *fiber.Ctx) error {
+	request := new(model.DisableTwoFactorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.Disable(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to disable two-factor authentication : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to disable two-factor authentication",
+		Data:   response,
+	})
+}
@@ -171,21 +171,222 @@ func (c *UserController) Login(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
 	}
 
-	response, userResponse, err := c.UseCase.Authenticate(ctx.Context(), request)
+	// bahasa dan zona waktu dipakai untuk email pemberitahuan saat akun dikunci
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.TimeZone = *loc
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+	response, userResponse, challenge, err := c.UseCase.Authenticate(ctx.Context(), request)
 	if err != nil {
 		c.Log.Warnf("failed to login : %+v", err)
 		return err
 	}
 
-	isProduction := c.ViperConfig.GetString("ENV") == "prod"
-	hostname := ctx.Hostname() // Misal: "seblak.fznh-dev.my.id"
-	domainParts := strings.Split(hostname, ".")
-	domain := ""
-	if len(domainParts) >= 3 {
-		// Ambil root domain dinamis (misal: fznh-dev.my.id)
-		domain = "." + strings.Join(domainParts[len(domainParts)-3:], ".")
+	// akun dengan 2FA belum mendapat token, client mengirim kode ke /api/users/login/two-factor
+	if challenge != nil {
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorChallengeResponse]{
+			Code:   200,
+			Status: "two-factor authentication is required",
+			Data:   challenge,
+		})
+	}
+
+	c.setTokenCookies(ctx, response)
+
+	// client non-browser (aplikasi mobile, POS, script) memakai token di body untuk header Authorization: Bearer
+	if request.ReturnToken {
+		response.TokenType = "Bearer"
+		response.User = userResponse
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserTokenResponse]{
+			Code:   200,
+			Status: "success to login",
+			Data:   response,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to login",
+		Data:   userResponse,
+	})
+}
+
+func (c *UserController) LoginTwoFactor(ctx *fiber.Ctx) error {
+	request := new(model.LoginTwoFactorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.TimeZone = *loc
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+	response, userResponse, err := c.UseCase.AuthenticateTwoFactor(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to login with two-factor code : %+v", err)
+		return err
+	}
+
+	c.setTokenCookies(ctx, response)
+
+	if request.ReturnToken {
+		response.TokenType = "Bearer"
+		response.User = userResponse
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserTokenResponse]{
+			Code:   200,
+			Status: "success to login",
+			Data:   response,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to login",
+		Data:   userResponse,
+	})
+}
+
+func (c *UserController) GetOIDCProviders(ctx *fiber.Ctx) error {
+	response := c.UseCase.GetOIDCProviders()
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[[]model.OIDCProviderResponse]{
+		Code:   200,
+		Status: "success to get oidc providers",
+		Data:   response,
+	})
+}
+
+func (c *UserController) OIDCAuthorize(ctx *fiber.Ctx) error {
+	request := new(model.OIDCAuthorizeRequest)
+	request.Provider = ctx.Params("provider")
+	request.Remember = ctx.QueryBool("remember", false)
+	response, err := c.UseCase.OIDCAuthorize(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to create oidc authorization url : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.OIDCAuthorizationResponse]{
+		Code:   200,
+		Status: "success to create oidc authorization url",
+		Data:   response,
+	})
+}
+
+// OIDCCallback menerima code dan state yang diteruskan frontend dari redirect provider OpenID Connect
+func (c *UserController) OIDCCallback(ctx *fiber.Ctx) error {
+	request := new(model.OIDCCallbackRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.TimeZone = *loc
+	request.Provider = ctx.Params("provider")
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+	response, userResponse, challenge, err := c.UseCase.OIDCLogin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to login with oidc : %+v", err)
+		return err
+	}
+
+	if challenge != nil {
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.TwoFactorChallengeResponse]{
+			Code:   200,
+			Status: "two-factor authentication is required",
+			Data:   challenge,
+		})
+	}
+
+	c.setTokenCookies(ctx, response)
+
+	if request.ReturnToken {
+		response.TokenType = "Bearer"
+		response.User = userResponse
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserTokenResponse]{
+			Code:   200,
+			Status: "success to login",
+			Data:   response,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to login",
+		Data:   userResponse,
+	})
+}
+
+func (c *UserController) RefreshToken(ctx *fiber.Ctx) error {
+	request := new(model.RefreshTokenRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			c.Log.Warnf("cannot parse data : %+v", err)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+		}
+	}
+
+	// browser mengirim refresh token lewat cookie
+	if request.RefreshToken == "" {
+		request.RefreshToken = ctx.Cookies("refresh_token")
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+	response, userResponse, err := c.UseCase.RefreshToken(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to refresh token : %+v", err)
+		return err
 	}
 
+	c.setTokenCookies(ctx, response)
+
+	if request.ReturnToken {
+		response.TokenType = "Bearer"
+		response.User = userResponse
+		return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserTokenResponse]{
+			Code:   200,
+			Status: "success to refresh token",
+			Data:   response,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to refresh token",
+		Data:   userResponse,
+	})
+}
+
+// setTokenCookies menyimpan access token dan refresh token untuk browser,
+// refresh token hanya dikirim ke endpoint refresh token
+func (c *UserController) setTokenCookies(ctx *fiber.Ctx, response *model.UserTokenResponse) {
+	isProduction := c.ViperConfig.GetString("ENV") == "prod"
+	domain := cookieDomain(ctx)
+
 	ctx.Cookie(&fiber.Cookie{
 		Name:     "access_token",
 		Value:    response.Token,
@@ -197,10 +398,73 @@ func (c *UserController) Login(ctx *fiber.Ctx) error {
 		Domain:   domain,
 	})
 
-	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+	ctx.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    response.RefreshToken,
+		Path:     "/api/users/token",
+		HTTPOnly: true,
+		Secure:   isProduction,
+		SameSite: fiber.CookieSameSiteLaxMode,
+		Expires:  response.RefreshTokenExpiryDate,
+		Domain:   domain,
+	})
+}
+
+func cookieDomain(ctx *fiber.Ctx) string {
+	hostname := ctx.Hostname() // Misal: "seblak.fznh-dev.my.id"
+	domainParts := strings.Split(hostname, ".")
+	domain := ""
+	if len(domainParts) >= 3 {
+		// Ambil root domain dinamis (misal: fznh-dev.my.id)
+		domain = "." + strings.Join(domainParts[len(domainParts)-3:], ".")
+	}
+	return domain
+}
+
+func (c *UserController) GetSessions(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.GetSessions(ctx.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("failed to get user sessions : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*[]model.UserSessionResponse]{
 		Code:   200,
-		Status: "success to login",
-		Data:   userResponse,
+		Status: "success to get all user sessions",
+		Data:   response,
+	})
+}
+
+func (c *UserController) RevokeSession(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+	request := new(model.RevokeUserSessionRequest)
+	request.ID = ctx.Params("sessionId")
+	response, err := c.UseCase.RevokeSession(ctx.Context(), request, auth)
+	if err != nil {
+		c.Log.Warnf("failed to revoke user session : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to revoke user session",
+		Data:   response,
+	})
+}
+
+func (c *UserController) RevokeOtherSessions(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.RevokeOtherSessions(ctx.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("failed to revoke other user sessions : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to revoke other user sessions",
+		Data:   response,
 	})
 }
 
@@ -278,9 +542,8 @@ func (c *UserController) UpdatePassword(ctx *fiber.Ctx) error {
 
 func (c *UserController) Logout(ctx *fiber.Ctx) error {
 	tokenRequest := new(model.GetUserByTokenRequest)
-	// tangkap token dari header
-	result := ctx.Cookies("access_token", "NOT_FOUND")
-	tokenRequest.Token = result
+	// tangkap token dari header Authorization / cookie
+	tokenRequest.Token = middleware.GetRequestToken(ctx)
 	response, err := c.UseCase.Logout(ctx.Context(), tokenRequest)
 	if err != nil {
 		c.Log.Warnf("failed to delete user token : %+v", err)
@@ -288,14 +551,7 @@ func (c *UserController) Logout(ctx *fiber.Ctx) error {
 	}
 
 	isProduction := c.ViperConfig.GetString("ENV") == "prod"
-	hostname := ctx.Hostname() // Misal: "seblak.fznh-dev.my.id"
-	domainParts := strings.Split(hostname, ".")
-	domain := ""
-	if len(domainParts) >= 3 {
-		// Ambil root domain dinamis (misal: fznh-dev.my.id)
-		domain = "." + strings.Join(domainParts[len(domainParts)-3:], ".")
-	}
-	fmt.Println("domain:", domain)
+	domain := cookieDomain(ctx)
 	ctx.Cookie(&fiber.Cookie{
 		Name:     "access_token",
 		Value:    "",
@@ -308,6 +564,18 @@ func (c *UserController) Logout(ctx *fiber.Ctx) error {
 		SameSite: fiber.CookieSameSiteLaxMode,
 	})
 
+	ctx.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Path:     "/api/users/token",
+		Domain:   domain,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HTTPOnly: true,
+		Secure:   isProduction,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
 	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
 		Code:   200,
 		Status: "success to logout",
@@ -431,3 +699,176 @@ func (c *UserController) ResetPassword(ctx *fiber.Ctx) error {
 		Data:   response,
 	})
 }
+
+func (c *UserController) GetAllByAdmin(ctx *fiber.Ctx) error {
+	search := strings.TrimSpace(ctx.Query("search", ""))
+	role := ctx.Query("role", "")
+	isVerified := ctx.Query("is_verified", "")
+	isActive := ctx.Query("is_active", "")
+
+	// ambil data sorting
+	getColumn := ctx.Query("column", "")
+	getSortBy := ctx.Query("sort_by", "desc")
+
+	// Ambil query parameter 'per_page' dengan default value 10 jika tidak disediakan
+	perPage, err := strconv.Atoi(ctx.Query("per_page", "10"))
+	if err != nil {
+		c.Log.Warnf("invalid 'per_page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'per_page' parameter : %+v", err))
+	}
+
+	// Ambil query parameter 'page' dengan default value 1 jika tidak disediakan
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		c.Log.Warnf("invalid 'page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'page' parameter : %+v", err))
+	}
+
+	response, totalCurrentUsers, totalRealUsers, totalActiveUsers, totalInactiveUsers, totalPages, err := c.UseCase.GetAllPaginate(ctx.Context(), page, perPage, search, role, isVerified, isActive, getColumn, getSortBy)
+	if err != nil {
+		c.Log.Warnf("failed to find all users : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponsePagination[*[]model.UserResponse]{
+		Code:               200,
+		Status:             "success to get all users",
+		Data:               response,
+		TotalRealDatas:     totalRealUsers,
+		TotalCurrentDatas:  totalCurrentUsers,
+		TotalActiveDatas:   totalActiveUsers,
+		TotalInactiveDatas: totalInactiveUsers,
+		TotalPages:         totalPages,
+		CurrentPages:       page,
+		DataPerPages:       perPage,
+	})
+}
+
+func (c *UserController) GetByAdmin(ctx *fiber.Ctx) error {
+	request, err := c.adminUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.UseCase.GetByAdmin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to get user : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.AdminUserDetailResponse]{
+		Code:   200,
+		Status: "success to get user",
+		Data:   response,
+	})
+}
+
+func (c *UserController) Suspend(ctx *fiber.Ctx) error {
+	adminRequest, err := c.adminUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := new(model.SuspendUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	request.UserId = adminRequest.UserId
+	request.CurrentAdminId = adminRequest.CurrentAdminId
+	request.CurrentAdminRole = adminRequest.CurrentAdminRole
+	response, err := c.UseCase.Suspend(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to suspend user : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to suspend user",
+		Data:   response,
+	})
+}
+
+func (c *UserController) Unsuspend(ctx *fiber.Ctx) error {
+	request, err := c.adminUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.UseCase.Unsuspend(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to unsuspend user : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to unsuspend user",
+		Data:   response,
+	})
+}
+
+func (c *UserController) ResendVerification(ctx *fiber.Ctx) error {
+	request, err := c.adminUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.UseCase.ResendVerification(ctx, request)
+	if err != nil {
+		c.Log.Warnf("failed to resend verification email : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[bool]{
+		Code:   200,
+		Status: "success to resend verification email",
+		Data:   response,
+	})
+}
+
+func (c *UserController) Restore(ctx *fiber.Ctx) error {
+	request, err := c.adminUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.UseCase.Restore(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to restore user : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.UserResponse]{
+		Code:   200,
+		Status: "success to restore user",
+		Data:   response,
+	})
+}
+
+// adminUserRequest mengambil user_id dari path beserta admin yang sedang login, bahasa dan zona waktu
+func (c *UserController) adminUserRequest(ctx *fiber.Ctx) (*model.AdminUserRequest, error) {
+	getId := ctx.Params("userId")
+	userId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert user_id to integer : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert user_id to integer : %+v", err))
+	}
+
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request := new(model.AdminUserRequest)
+	request.UserId = uint64(userId)
+	request.CurrentAdminId = auth.ID
+	request.CurrentAdminRole = auth.Role
+	request.Lang = enum_state.Languange(ctx.Query("lang", string(enum_state.ENGLISH)))
+	request.TimeZone = *loc
+	return request, nil
+}
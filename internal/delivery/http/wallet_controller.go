@@ -3,9 +3,12 @@ package http
 import (
 	"fmt"
 	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/usecase"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -30,6 +33,16 @@ func (c *WalletController) WithdrawCustRequest(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
 	}
 
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = auth.ID
 	response, err := c.UseCase.WithdrawByCustRequest(ctx.Context(), request)
 	if err != nil {
 		c.Log.Warnf("failed to withdraw wallet request : %+v", err)
@@ -60,7 +73,7 @@ func (c *WalletController) WithdrawAdminApproval(ctx *fiber.Ctx) error {
 
 	auth := middleware.GetCurrentUser(ctx)
 	request.CurrentAdminId = auth.ID
-	response, err := c.UseCase.WithdrawByAdminApproval(ctx.Context(), request)
+	response, err := c.UseCase.WithdrawByAdminApproval(ctx, request)
 	if err != nil {
 		c.Log.Warnf("failed to approval withdraw wallet balance : %+v", err)
 		return err
@@ -71,4 +84,212 @@ func (c *WalletController) WithdrawAdminApproval(ctx *fiber.Ctx) error {
 		Status: "success to approval withdraw wallet balance",
 		Data:   response,
 	})
-}
\ No newline at end of file
+}
+
+func (c *WalletController) WithdrawCustCancel(ctx *fiber.Ctx) error {
+	request := new(model.CancelWithdrawWalletRequest)
+	getId := ctx.Params("withdrawRequestId")
+	withdrawRequestId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert withdraw_request_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert withdraw_request_id to integer : %+v", err))
+	}
+	request.ID = uint64(withdrawRequestId)
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.CurrentUserId = auth.ID
+	response, err := c.UseCase.WithdrawCancelByCust(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to cancel withdraw wallet request : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.WithdrawWalletResponse]{
+		Code:   200,
+		Status: "success to cancel withdraw wallet request",
+		Data:   response,
+	})
+}
+
+func (c *WalletController) GetWithdrawRequestById(ctx *fiber.Ctx) error {
+	getId := ctx.Params("withdrawRequestId")
+	withdrawRequestId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert withdraw_request_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert withdraw_request_id to integer : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	response, err := c.UseCase.GetWithdrawRequestById(ctx.Context(), uint64(withdrawRequestId), auth)
+	if err != nil {
+		c.Log.Warnf("failed to get withdraw wallet request by id : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.WithdrawWalletResponse]{
+		Code:   200,
+		Status: "success to get withdraw wallet request by id",
+		Data:   response,
+	})
+}
+
+func (c *WalletController) GetAllWithdrawRequests(ctx *fiber.Ctx) error {
+	auth := middleware.GetCurrentUser(ctx)
+
+	// filter status, kosong berarti semua status
+	status := strings.TrimSpace(ctx.Query("status", ""))
+
+	// ambil data sorting
+	getColumn := ctx.Query("column", "")
+	getSortBy := ctx.Query("sort_by", "desc")
+
+	// Ambil query parameter 'per_page' dengan default value 10 jika tidak disediakan
+	perPage, err := strconv.Atoi(ctx.Query("per_page", "10"))
+	if err != nil {
+		c.Log.Warnf("invalid 'per_page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'per_page' parameter : %+v", err))
+	}
+
+	// Ambil query parameter 'page' dengan default value 1 jika tidak disediakan
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		c.Log.Warnf("invalid 'page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'page' parameter : %+v", err))
+	}
+
+	response, totalCurrent, totalReal, totalActive, totalInactive, totalPages, err := c.UseCase.GetAllWithdrawRequestsPaginate(ctx.Context(), page, perPage, status, getColumn, getSortBy, auth)
+	if err != nil {
+		c.Log.Warnf("failed to get all withdraw wallet requests : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponsePagination[*[]model.WithdrawWalletResponse]{
+		Code:               200,
+		Status:             "success to get all withdraw wallet requests",
+		Data:               response,
+		TotalRealDatas:     totalReal,
+		TotalCurrentDatas:  totalCurrent,
+		TotalActiveDatas:   totalActive,
+		TotalInactiveDatas: totalInactive,
+		TotalPages:         totalPages,
+		CurrentPages:       page,
+		DataPerPages:       perPage,
+	})
+}
+
+func (c *WalletController) CreateAdjustment(ctx *fiber.Ctx) error {
+	request := new(model.WalletAdjustmentRequest)
+	getId := ctx.Params("userId")
+	userId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert user_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert user_id to integer : %+v", err))
+	}
+
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = uint64(userId)
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.AdjustByAdmin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to adjust wallet balance : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.ApiResponse[*model.WalletAdjustmentResponse]{
+		Code:   201,
+		Status: "success to adjust wallet balance",
+		Data:   response,
+	})
+}
+
+func (c *WalletController) AdjustmentAdminApproval(ctx *fiber.Ctx) error {
+	request := new(model.WalletAdjustmentApprovalRequest)
+	getId := ctx.Params("adjustmentId")
+	adjustmentId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert adjustment_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert adjustment_id to integer : %+v", err))
+	}
+
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	getLang := ctx.Query("lang", string(enum_state.ENGLISH))
+	request.Lang = enum_state.Languange(getLang)
+	getTimeZoneUser := ctx.Query("timezone", "UTC")
+	loc, err := time.LoadLocation(getTimeZoneUser)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.TimeZone = *loc
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.ID = uint64(adjustmentId)
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.AdjustmentByAdminApproval(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to approval wallet adjustment : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.WalletAdjustmentResponse]{
+		Code:   200,
+		Status: "success to approval wallet adjustment",
+		Data:   response,
+	})
+}
+
+func (c *WalletController) Freeze(ctx *fiber.Ctx) error {
+	return c.updateStatus(ctx, enum_state.INACIVE_WALLET, "freeze")
+}
+
+func (c *WalletController) Unfreeze(ctx *fiber.Ctx) error {
+	return c.updateStatus(ctx, enum_state.ACTIVE_WALLET, "unfreeze")
+}
+
+func (c *WalletController) updateStatus(ctx *fiber.Ctx, status enum_state.WalletStatus, action string) error {
+	request := new(model.UpdateWalletStatusRequest)
+	getId := ctx.Params("userId")
+	userId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert user_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert user_id to integer : %+v", err))
+	}
+
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.UserId = uint64(userId)
+	request.Status = status
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.UpdateStatusByAdmin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to %s wallet : %+v", action, err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.WalletResponse]{
+		Code:   200,
+		Status: fmt.Sprintf("success to %s wallet", action),
+		Data:   response,
+	})
+}
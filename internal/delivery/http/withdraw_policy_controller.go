@@ -0,0 +1,61 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WithdrawPolicyController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.WithdrawPolicyUseCase
+}
+
+func NewWithdrawPolicyController(useCase *usecase.WithdrawPolicyUseCase, logger *logrus.Logger) *WithdrawPolicyController {
+	return &WithdrawPolicyController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *WithdrawPolicyController) GetAll(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.GetAll(ctx.Context())
+	if err != nil {
+		c.Log.Warnf("failed to get all withdraw policies : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*[]model.WithdrawPolicyResponse]{
+		Code:   200,
+		Status: "success to get all withdraw policies",
+		Data:   response,
+	})
+}
+
+func (c *WithdrawPolicyController) Upsert(ctx *fiber.Ctx) error {
+	request := new(model.UpsertWithdrawPolicyRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("cannot parse data : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("cannot parse data : %+v", err))
+	}
+
+	auth := middleware.GetCurrentUser(ctx)
+	request.Method = enum_state.WalletWithdrawRequest(ctx.Params("method"))
+	request.CurrentAdminId = auth.ID
+	response, err := c.UseCase.UpsertByAdmin(ctx.Context(), request)
+	if err != nil {
+		c.Log.Warnf("failed to save withdraw policy : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.WithdrawPolicyResponse]{
+		Code:   200,
+		Status: "success to save withdraw policy",
+		Data:   response,
+	})
+}
@@ -1,54 +1,29 @@
 package http
 
 import (
-	"encoding/json"
-	"fmt"
 	"seblak-bombom-restful-api/internal/helper/enum_state"
-	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/usecase/xendit"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
 type XenditCallbackController struct {
-	Log            *logrus.Logger
-	UseCase        *usecase.XenditCallbackUseCase
-	FrontEndConfig *model.FrontEndConfig
+	Log     *logrus.Logger
+	UseCase *usecase.XenditWebhookEventUseCase
 }
 
-func NewXenditCallbackController(useCase *usecase.XenditCallbackUseCase, logger *logrus.Logger, frontEndConfig *model.FrontEndConfig) *XenditCallbackController {
+func NewXenditCallbackController(useCase *usecase.XenditWebhookEventUseCase, logger *logrus.Logger) *XenditCallbackController {
 	return &XenditCallbackController{
-		Log:            logger,
-		UseCase:        useCase,
-		FrontEndConfig: frontEndConfig,
+		Log:     logger,
+		UseCase: useCase,
 	}
 }
 
 func (c *XenditCallbackController) GetPaymentRequestCallbacks(ctx *fiber.Ctx) error {
-	// Menangkap raw body
+	// Menangkap raw body, disimpan dulu sebelum diproses
 	rawBody := ctx.Body()
-	var requestData model.XenditGetPaymentRequestCallbackStatus
-	err := json.Unmarshal(rawBody, &requestData)
-	if err != nil {
-		c.Log.Warnf("Failed to unmarshall request body : %+v", err)
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
-	}
-
-	lang, _ := requestData.Data.Metadata["lang"].(string)
-	if lang == "" {
-		lang = "en"
-	}
-	requestData.Lang = enum_state.Languange(lang)
-	timeZone, _ := requestData.Data.Metadata["time_zone"].(string)
-	loc, err := time.LoadLocation(timeZone)
-	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
-	}
-	requestData.TimeZone = *loc
-	requestData.BaseFrontEndURL = c.FrontEndConfig.BaseURL
-	err = c.UseCase.UpdateStatusPaymentRequestCallback(ctx, &requestData)
+	err := c.UseCase.Receive(ctx, enum_state.PAYMENT_GATEWAY_XENDIT, enum_state.XENDIT_WEBHOOK_TYPE_PAYMENT_REQUEST, ctx.Get("webhook-id"), rawBody)
 	if err != nil {
 		c.Log.Warnf("Failed to process xendit payment request callback : %+v", err)
 		return err
@@ -61,23 +36,31 @@ func (c *XenditCallbackController) GetPaymentRequestCallbacks(ctx *fiber.Ctx) er
 }
 
 func (c *XenditCallbackController) GetPayoutRequestCallbacks(ctx *fiber.Ctx) error {
-	// Menangkap raw body
+	// Menangkap raw body, disimpan dulu sebelum diproses
 	rawBody := ctx.Body()
-	var requestData model.XenditGetPayoutRequestCallbackStatus
-	err := json.Unmarshal(rawBody, &requestData)
+	err := c.UseCase.Receive(ctx, enum_state.PAYMENT_GATEWAY_XENDIT, enum_state.XENDIT_WEBHOOK_TYPE_PAYOUT, ctx.Get("webhook-id"), rawBody)
 	if err != nil {
-		c.Log.Warnf("Failed to unmarshall request body : %+v", err)
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
+		c.Log.Warnf("Failed to process xendit payout request callback : %+v", err)
+		return err
 	}
 
-	err = c.UseCase.UpdateStatusPayoutRequestCallback(ctx, &requestData)
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code":   200,
+		"status": "Success to get xendit payout request callback",
+	})
+}
+
+func (c *XenditCallbackController) GetRefundCallbacks(ctx *fiber.Ctx) error {
+	// Menangkap raw body, disimpan dulu sebelum diproses
+	rawBody := ctx.Body()
+	err := c.UseCase.Receive(ctx, enum_state.PAYMENT_GATEWAY_XENDIT, enum_state.XENDIT_WEBHOOK_TYPE_REFUND, ctx.Get("webhook-id"), rawBody)
 	if err != nil {
-		c.Log.Warnf("Failed to process xendit payout request callback : %+v", err)
+		c.Log.Warnf("Failed to process xendit refund callback : %+v", err)
 		return err
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
 		"code":   200,
-		"status": "Success to get xendit payout request callback",
+		"status": "Success to get xendit refund callback",
 	})
 }
@@ -1,10 +1,8 @@
 package http
 
 import (
-	"fmt"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/usecase/xendit"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -25,41 +23,6 @@ func NewXenditPayoutController(useCase *usecase.XenditPayoutUseCase, logger *log
 	}
 }
 
-func (c *XenditPayoutController) Create(ctx *fiber.Ctx) error {
-	getId := ctx.Params("userId")
-	userId, err := strconv.Atoi(getId)
-	if err != nil {
-		c.Log.Warnf("Failed to convert user_id to integer : %+v", err)
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to convert user_id to integer : %+v", err))
-	}
-
-	xenditPayoutRequest := new(model.CreateXenditPayout)
-	xenditPayoutRequest.UserId = uint64(userId)
-	if err := ctx.BodyParser(xenditPayoutRequest); err != nil {
-		c.Log.Warnf("Cannot parse data : %+v", err)
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Cannot parse data : %+v", err))
-	}
-
-	tx := c.DB.WithContext(ctx.Context()).Begin()
-	defer tx.Rollback()
-	response, err := c.UseCase.AddPayout(ctx, xenditPayoutRequest, tx)
-	if err != nil {
-		c.Log.Warnf("Failed to create new xendit payout : %+v", err)
-		return err
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("Failed to commit transaction : %+v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("Failed to commit transaction : %+v", err))
-	}
-
-	return ctx.Status(fiber.StatusCreated).JSON(model.ApiResponse[*model.XenditPayoutResponse]{
-		Code:   201,
-		Status: "Success to create a new xendit payout",
-		Data:   response,
-	})
-}
-
 func (c *XenditPayoutController) GetAdminBalance(ctx *fiber.Ctx) error {
 	balance, err := c.UseCase.GetBalance(ctx)
 	if err != nil {
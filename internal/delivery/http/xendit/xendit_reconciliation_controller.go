@@ -0,0 +1,44 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase/xendit"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type XenditReconciliationController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.XenditReconciliationUseCase
+}
+
+func NewXenditReconciliationController(useCase *usecase.XenditReconciliationUseCase, logger *logrus.Logger) *XenditReconciliationController {
+	return &XenditReconciliationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *XenditReconciliationController) ReconcileOrder(ctx *fiber.Ctx) error {
+	getId := ctx.Params("orderId")
+	orderId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert order_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert order_id to integer : %+v", err))
+	}
+
+	response, err := c.UseCase.ReconcileOrder(ctx, uint64(orderId))
+	if err != nil {
+		c.Log.Warnf("failed to reconcile order payment status : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.PaymentReconciliationResponse]{
+		Code:   200,
+		Status: "success to reconcile order payment status",
+		Data:   response,
+	})
+}
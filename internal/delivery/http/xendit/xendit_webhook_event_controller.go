@@ -0,0 +1,88 @@
+package http
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/usecase/xendit"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type XenditWebhookEventController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.XenditWebhookEventUseCase
+}
+
+func NewXenditWebhookEventController(useCase *usecase.XenditWebhookEventUseCase, logger *logrus.Logger) *XenditWebhookEventController {
+	return &XenditWebhookEventController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *XenditWebhookEventController) GetAll(ctx *fiber.Ctx) error {
+	// filter status dan tipe webhook, kosong berarti semua
+	status := strings.TrimSpace(ctx.Query("status", ""))
+	webhookType := strings.TrimSpace(ctx.Query("webhook_type", ""))
+
+	// ambil data sorting
+	getColumn := ctx.Query("column", "")
+	getSortBy := ctx.Query("sort_by", "desc")
+
+	// Ambil query parameter 'per_page' dengan default value 10 jika tidak disediakan
+	perPage, err := strconv.Atoi(ctx.Query("per_page", "10"))
+	if err != nil {
+		c.Log.Warnf("invalid 'per_page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'per_page' parameter : %+v", err))
+	}
+
+	// Ambil query parameter 'page' dengan default value 1 jika tidak disediakan
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		c.Log.Warnf("invalid 'page' parameter : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid 'page' parameter : %+v", err))
+	}
+
+	response, totalCurrent, totalReal, totalActive, totalInactive, totalPages, err := c.UseCase.GetAllPaginate(ctx.Context(), page, perPage, status, webhookType, getColumn, getSortBy)
+	if err != nil {
+		c.Log.Warnf("failed to get all xendit webhook events : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponsePagination[*[]model.XenditWebhookEventResponse]{
+		Code:               200,
+		Status:             "success to get all xendit webhook events",
+		Data:               response,
+		TotalRealDatas:     totalReal,
+		TotalCurrentDatas:  totalCurrent,
+		TotalActiveDatas:   totalActive,
+		TotalInactiveDatas: totalInactive,
+		TotalPages:         totalPages,
+		CurrentPages:       page,
+		DataPerPages:       perPage,
+	})
+}
+
+func (c *XenditWebhookEventController) Replay(ctx *fiber.Ctx) error {
+	getId := ctx.Params("webhookEventId")
+	webhookEventId, err := strconv.Atoi(getId)
+	if err != nil {
+		c.Log.Warnf("failed to convert webhook_event_id to integer : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to convert webhook_event_id to integer : %+v", err))
+	}
+
+	response, err := c.UseCase.Replay(ctx, uint64(webhookEventId))
+	if err != nil {
+		c.Log.Warnf("failed to replay xendit webhook event : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.ApiResponse[*model.XenditWebhookEventResponse]{
+		Code:   200,
+		Status: "success to replay xendit webhook event",
+		Data:   response,
+	})
+}
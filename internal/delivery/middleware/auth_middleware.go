@@ -1,17 +1,17 @@
 package middleware
 
 import (
-	"fmt"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/usecase"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func NewAuth(userUseCase *usecase.UserUseCase) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		getToken := c.Cookies("access_token", "NOT_FOUND")
-		if getToken == "NOT_FOUND" {
+		getToken := GetRequestToken(c)
+		if getToken == "" {
 			return fiber.NewError(fiber.StatusUnauthorized, "missing token")
 		}
 
@@ -19,8 +19,7 @@ func NewAuth(userUseCase *usecase.UserUseCase) fiber.Handler {
 			Token: getToken,
 		}
 
-		userUseCase.Log.Debugf("token from cookie: %s", request.Token)
-		fmt.Println("token from cookie:", request.Token)
+		// token tidak boleh ditulis ke log / stdout
 		auth, err := userUseCase.GetUserByToken(c.Context(), request)
 		if err != nil {
 			userUseCase.Log.Warnf("invalid token: %+v", err)
@@ -32,6 +31,17 @@ func NewAuth(userUseCase *usecase.UserUseCase) fiber.Handler {
 	}
 }
 
+// GetRequestToken mengambil token dari header Authorization: Bearer <token> (aplikasi mobile, POS, script)
+// dan jika tidak ada memakai cookie access_token (browser)
+func GetRequestToken(c *fiber.Ctx) string {
+	authorization := strings.TrimSpace(c.Get(fiber.HeaderAuthorization))
+	if len(authorization) > len("Bearer ") && strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(authorization[len("Bearer "):])
+	}
+
+	return c.Cookies("access_token")
+}
+
 func GetCurrentUser(ctx *fiber.Ctx) *model.UserResponse {
 	return ctx.Locals("auth").(*model.UserResponse)
 }
@@ -1,20 +1,86 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"net"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 func NewAuthXenditCallback(config *viper.Viper, log *logrus.Logger) fiber.Handler {
+	// token callback dibedakan per environment, production memakai token akun live xendit
+	tokenKey := "XENDIT_TEST_CALLBACK_TOKEN"
+	if config.GetString("ENV") == "prod" {
+		tokenKey = "XENDIT_LIVE_CALLBACK_TOKEN"
+	}
+	xenditCallbackToken := config.GetString(tokenKey)
+	if xenditCallbackToken == "" {
+		log.Warnf("%s is not configured, all xendit callbacks will be rejected!", tokenKey)
+	}
+
+	// daftar IP / CIDR yang boleh mengirim callback, kosong berarti tidak dibatasi
+	allowedNetworks := []*net.IPNet{}
+	for _, allowed := range strings.Split(config.GetString("XENDIT_CALLBACK_ALLOWED_IPS"), ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "" {
+			continue
+		}
+
+		if !strings.Contains(allowed, "/") {
+			if strings.Contains(allowed, ":") {
+				allowed += "/128"
+			} else {
+				allowed += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(allowed)
+		if err != nil {
+			log.Warnf("invalid xendit callback allowed ip %s : %+v", allowed, err)
+			continue
+		}
+		allowedNetworks = append(allowedNetworks, network)
+	}
+
+	reject := func(c *fiber.Ctx, reason string) error {
+		log.WithFields(logrus.Fields{
+			"ip":         c.IP(),
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"user_agent": c.Get(fiber.HeaderUserAgent),
+			"webhook_id": c.Get("webhook-id"),
+		}).Warnf("xendit callback rejected : %s", reason)
+		return fiber.NewError(fiber.StatusUnauthorized, "xendit callback token isn't valid!")
+	}
+
 	return func(c *fiber.Ctx) error {
-		xenditCallbackToken := config.GetString("XENDIT_TEST_CALLBACK_TOKEN")
-		requestToken := c.Get("X-Callback-Token", "NOT_FOUND")
-		if requestToken != "NOT_FOUND" && requestToken != xenditCallbackToken {
-			log.Warnf("xendit callback token isn't valid!")
-			return fiber.NewError(fiber.StatusUnauthorized, "xendit callback token isn't valid!")
+		if len(allowedNetworks) > 0 {
+			requestIP := net.ParseIP(c.IP())
+			isAllowed := false
+			for _, network := range allowedNetworks {
+				if requestIP != nil && network.Contains(requestIP) {
+					isAllowed = true
+					break
+				}
+			}
+
+			if !isAllowed {
+				return reject(c, "source ip is not in allowlist")
+			}
 		}
-		c.Locals("xendit_callback_token", xenditCallbackToken)
+
+		requestToken := c.Get("X-Callback-Token")
+		if requestToken == "" {
+			return reject(c, "callback token is missing")
+		}
+
+		if xenditCallbackToken == "" || subtle.ConstantTimeCompare([]byte(requestToken), []byte(xenditCallbackToken)) != 1 {
+			return reject(c, "callback token doesn't match")
+		}
+
 		return c.Next()
 	}
 }
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// NewPermission dipasang per route setelah role middleware, admin selalu lolos
+// karena memiliki semua permission
+func NewPermission(permission enum_state.Permission, log *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := GetCurrentUser(c)
+		if !auth.HasPermission(permission) {
+			log.Warnf("you don't have permission %s!", permission)
+			return fiber.NewError(fiber.StatusForbidden, "you don't have permission "+string(permission)+"!")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/interfaces"
+	"seblak-bombom-restful-api/internal/model"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// NewRateLimit membatasi request dengan token bucket sesuai policy dan mengirim header RateLimit-* dan Retry-After
+func NewRateLimit(store interfaces.RateLimitStore, policy model.RateLimitPolicy, log *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := fmt.Sprintf("%s:%s", policy.Name, GetRateLimitKey(c, policy))
+		result, err := store.Take(c.Context(), key, policy)
+		if err != nil {
+			// store bermasalah tidak boleh membuat seluruh api tidak bisa dipakai
+			log.Warnf("failed to take rate limit token : %+v", err)
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Period.Seconds())))
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			log.Warnf("rate limit %s exceeded", policy.Name)
+			return fiber.NewError(fiber.StatusTooManyRequests, fmt.Sprintf("too many requests, try again in %d seconds", retryAfter))
+		}
+
+		return c.Next()
+	}
+}
+
+// GetRateLimitKey menentukan pemilik bucket, user dan api key memakai IP jika tidak tersedia.
+// api key yang tidak terdaftar memakai bucket user / IP agar tidak bisa membuat bucket baru di setiap request
+func GetRateLimitKey(c *fiber.Ctx, policy model.RateLimitPolicy) string {
+	switch policy.KeyBy {
+	case enum_state.RATE_LIMIT_KEY_USER:
+		if auth, ok := c.Locals("auth").(*model.UserResponse); ok && auth != nil {
+			return fmt.Sprintf("user:%d", auth.ID)
+		}
+	case enum_state.RATE_LIMIT_KEY_API_KEY:
+		if apiKey := c.Get(HeaderAPIKey); apiKey != "" && isRegisteredAPIKey(apiKey, policy.APIKeys) {
+			// api key tidak disimpan apa adanya di store
+			sum := sha256.Sum256([]byte(apiKey))
+			return "api_key:" + hex.EncodeToString(sum[:])
+		}
+
+		if auth, ok := c.Locals("auth").(*model.UserResponse); ok && auth != nil {
+			return fmt.Sprintf("user:%d", auth.ID)
+		}
+	}
+
+	return "ip:" + c.IP()
+}
+
+func isRegisteredAPIKey(apiKey string, apiKeys []string) bool {
+	for _, registeredAPIKey := range apiKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(registeredAPIKey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
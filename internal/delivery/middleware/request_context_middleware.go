@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+)
+
+const HeaderRequestId = "X-Request-Id"
+
+// request id dari client hanya dipakai jika formatnya aman untuk disimpan di log
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,100}$`)
+
+// NewRequestContext memberi setiap request sebuah request id dan menyimpan IP client,
+// keduanya dibaca use case lewat context untuk audit log
+func NewRequestContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestId := c.Get(HeaderRequestId)
+		if requestIdPattern.MatchString(requestId) {
+			// nilai header hanya valid selama request, disalin karena disimpan di locals
+			requestId = utils.CopyString(requestId)
+		} else {
+			requestId = uuid.NewString()
+		}
+
+		c.Set(HeaderRequestId, requestId)
+		c.Locals("request_id", requestId)
+		c.Locals("ip_address", utils.CopyString(c.IP()))
+		return c.Next()
+	}
+}
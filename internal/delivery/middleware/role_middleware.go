@@ -10,10 +10,18 @@ import (
 func NewRole(userUseCase *usecase.UserUseCase) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		auth := GetCurrentUser(c)
-		if auth.Role != enum_state.ADMIN {
+		// staff dengan role yang memiliki permission ikut masuk ke route admin,
+		// akses per route dibatasi lagi oleh permission middleware
+		if auth.Role != enum_state.ADMIN && len(auth.Permissions) == 0 {
 			userUseCase.Log.Warn("admin access only!")
 			return fiber.NewError(fiber.StatusUnauthorized, "admin access only!")
 		}
+
+		// admin tanpa 2FA hanya bisa mengakses route untuk mengaktifkan 2FA jika 2FA diwajibkan
+		if userUseCase.AuthConfig.TwoFactorRequiredForAdmin && auth.Role == enum_state.ADMIN && !auth.TwoFactorEnabled {
+			userUseCase.Log.Warn("two-factor authentication must be enabled for admin accounts!")
+			return fiber.NewError(fiber.StatusForbidden, "two-factor authentication must be enabled for admin accounts!")
+		}
 		return c.Next()
 	}
 }
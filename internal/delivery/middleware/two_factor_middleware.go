@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"seblak-bombom-restful-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewTwoFactorFresh dipasang setelah auth middleware pada endpoint berisiko tinggi,
+// sesi harus dikonfirmasi dengan kode 2FA dalam rentang AUTH_TWO_FACTOR_FRESH_MINUTES
+func NewTwoFactorFresh(twoFactorUseCase *usecase.TwoFactorUseCase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := twoFactorUseCase.RequireFreshConfirmation(c.Context(), GetCurrentUser(c)); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
@@ -5,16 +5,23 @@ import (
 	"os"
 	"path/filepath"
 	"seblak-bombom-restful-api/internal/delivery/http"
+	midtransController "seblak-bombom-restful-api/internal/delivery/http/midtrans"
 	xenditController "seblak-bombom-restful-api/internal/delivery/http/xendit"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/interfaces"
+	"seblak-bombom-restful-api/internal/model"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pusher/pusher-http-go/v5"
+	"github.com/sirupsen/logrus"
 )
 
 type RouteConfig struct {
 	App                               *fiber.App
 	UserController                    *http.UserController
+	TwoFactorController               *http.TwoFactorController
 	AddressController                 *http.AddressController
 	CategoryController                *http.CategoryController
 	ProductController                 *http.ProductController
@@ -25,30 +32,84 @@ type RouteConfig struct {
 	XenditQRCodeTransactionController *xenditController.XenditQRCodeTransctionController
 	XenditCallbackController          *xenditController.XenditCallbackController
 	XenditPayoutController            *xenditController.XenditPayoutController
+	XenditWebhookEventController      *xenditController.XenditWebhookEventController
+	XenditReconciliationController    *xenditController.XenditReconciliationController
 	PayoutController                  *http.PayoutController
 	ApplicationController             *http.ApplicationController
 	CartController                    *http.CartController
 	WalletController                  *http.WalletController
+	BankAccountController             *http.BankAccountController
+	WithdrawPolicyController          *http.WithdrawPolicyController
+	CashPaymentController             *http.CashPaymentController
+	OrderRefundController             *http.OrderRefundController
+	PaymentSimulatorController        *http.PaymentSimulatorController
+	MidtransTransactionController     *midtransController.MidtransTransactionController
+	RoleController                    *http.RoleController
+	AuditLogController                *http.AuditLogController
 	AuthMiddleware                    fiber.Handler
 	RoleMiddleware                    fiber.Handler
+	TwoFactorFreshMiddleware          fiber.Handler
 	AuthXenditMiddleware              fiber.Handler
 	AuthAdminCreationMiddleware       fiber.Handler
 	PusherClient                      pusher.Client
+	// policy rate limit per kelompok route, nil / Enabled false = tanpa rate limit
+	RateLimitConfig *model.RateLimitConfig
+	RateLimitStore  interfaces.RateLimitStore
+	Log             *logrus.Logger
 }
 
 func (c *RouteConfig) Setup() {
+	// request id dan IP client dipasang paling awal agar tersedia untuk semua route
+	c.App.Use(middleware.NewRequestContext())
 	c.SetupGuestRoute()
 	c.SetupXenditCallbacksRoute()
+	c.SetupMidtransNotificationRoute()
 	c.SetupAuthRoute()
 	c.SetupAuthAdminRoute()
 }
 
+// rateLimit membuat middleware rate limit untuk satu policy
+func (c *RouteConfig) rateLimit(policy model.RateLimitPolicy) fiber.Handler {
+	if c.RateLimitConfig == nil || !c.RateLimitConfig.Enabled || c.RateLimitStore == nil {
+		return func(ctx *fiber.Ctx) error {
+			return ctx.Next()
+		}
+	}
+
+	return middleware.NewRateLimit(c.RateLimitStore, policy, c.Log)
+}
+
+// permission membuat middleware yang mewajibkan permission tertentu pada satu route
+func (c *RouteConfig) permission(permission enum_state.Permission) fiber.Handler {
+	return middleware.NewPermission(permission, c.Log)
+}
+
+func (c *RouteConfig) rateLimitConfig() *model.RateLimitConfig {
+	if c.RateLimitConfig == nil {
+		return new(model.RateLimitConfig)
+	}
+
+	return c.RateLimitConfig
+}
+
 func (c *RouteConfig) SetupXenditCallbacksRoute() {
 	api := c.App.Group("/api")
-	authToken := api.Use(c.AuthXenditMiddleware)
+	// middleware hanya dipasang di route callback, bukan di seluruh group /api
 	// Xendit QR Code Callback
-	authToken.Post("/xendits/payment-request/notifications/callback", c.XenditCallbackController.GetPaymentRequestCallbacks)
-	authToken.Post("/xendits/payout-request/notifications/callback", c.XenditCallbackController.GetPayoutRequestCallbacks)
+	api.Post("/xendits/payment-request/notifications/callback", c.AuthXenditMiddleware, c.XenditCallbackController.GetPaymentRequestCallbacks)
+	api.Post("/xendits/payout-request/notifications/callback", c.AuthXenditMiddleware, c.XenditCallbackController.GetPayoutRequestCallbacks)
+	api.Post("/xendits/refund/notifications/callback", c.AuthXenditMiddleware, c.XenditCallbackController.GetRefundCallbacks)
+}
+
+// Midtrans hanya terdaftar jika server key midtrans sudah diatur
+func (c *RouteConfig) SetupMidtransNotificationRoute() {
+	if c.MidtransTransactionController == nil {
+		return
+	}
+
+	api := c.App.Group("/api")
+	// tidak memakai middleware, notifikasi diverifikasi lewat signature_key
+	api.Post("/midtrans/notifications", c.MidtransTransactionController.Notification)
 }
 
 // GUEST
@@ -58,30 +119,41 @@ func (c *RouteConfig) SetupGuestRoute() {
 	staticPath := filepath.Join(wd, "../internal/templates/assets")
 	api.Static("/assets", staticPath)
 
+	rateLimitConfig := c.rateLimitConfig()
+	registerRateLimit := c.rateLimit(rateLimitConfig.Register)
+	loginRateLimit := c.rateLimit(rateLimitConfig.Login)
+	forgotPasswordRateLimit := c.rateLimit(rateLimitConfig.ForgotPassword)
+	guestRateLimit := c.rateLimit(rateLimitConfig.Guest)
+
 	// User
-	api.Post("/users/register", c.UserController.Register)
-	api.Post("/users/login", c.UserController.Login)
-	api.Post("/users/forgot-password", c.UserController.CreateForgotPassword)
-	api.Post("/users/forgot-password/:passwordResetId/validate", c.UserController.ValidateForgotPassword)
-	api.Post("/users/forgot-password/:passwordResetId/reset-password", c.UserController.ResetPassword)
-	api.Get("/users/verify-email/:token", c.UserController.VerifyEmailRegistration)
+	api.Post("/users/register", registerRateLimit, c.UserController.Register)
+	api.Post("/users/login", loginRateLimit, c.UserController.Login)
+	api.Post("/users/login/two-factor", loginRateLimit, c.UserController.LoginTwoFactor)
+	api.Post("/users/token/refresh", loginRateLimit, c.UserController.RefreshToken)
+	api.Get("/users/oidc/providers", guestRateLimit, c.UserController.GetOIDCProviders)
+	api.Get("/users/oidc/:provider/authorize", loginRateLimit, c.UserController.OIDCAuthorize)
+	api.Post("/users/oidc/:provider/callback", loginRateLimit, c.UserController.OIDCCallback)
+	api.Post("/users/forgot-password", forgotPasswordRateLimit, c.UserController.CreateForgotPassword)
+	api.Post("/users/forgot-password/:passwordResetId/validate", forgotPasswordRateLimit, c.UserController.ValidateForgotPassword)
+	api.Post("/users/forgot-password/:passwordResetId/reset-password", forgotPasswordRateLimit, c.UserController.ResetPassword)
+	api.Get("/users/verify-email/:token", guestRateLimit, c.UserController.VerifyEmailRegistration)
 	c.App.Get("/verified-success/:token", c.UserController.ShowVerifiedSuccess)
 	c.App.Get("/verified-failed/:token", c.UserController.ShowVerifiedFailed)
 
 	// Discount Coupon
-	api.Get("/discount-coupons", c.DiscountCouponController.GetAll)
-	api.Get("/discount-coupons/:discountId", c.DiscountCouponController.Get)
+	api.Get("/discount-coupons", guestRateLimit, c.DiscountCouponController.GetAll)
+	api.Get("/discount-coupons/:discountId", guestRateLimit, c.DiscountCouponController.Get)
 
 	// Category
-	api.Get("/categories/:categoryId", c.CategoryController.Get)
-	api.Get("/categories", c.CategoryController.GetAll)
+	api.Get("/categories/:categoryId", guestRateLimit, c.CategoryController.Get)
+	api.Get("/categories", guestRateLimit, c.CategoryController.GetAll)
 
 	// Delivery
-	api.Get("/deliveries", c.DeliveryController.GetAll)
+	api.Get("/deliveries", guestRateLimit, c.DeliveryController.GetAll)
 
 	// Product
-	api.Get("/products", c.ProductController.GetAll)
-	api.Get("/products/:productId", c.ProductController.Get)
+	api.Get("/products", guestRateLimit, c.ProductController.GetAll)
+	api.Get("/products/:productId", guestRateLimit, c.ProductController.Get)
 
 	// Images
 	uploadsDir := "uploads/images"
@@ -115,7 +187,7 @@ func (c *RouteConfig) SetupGuestRoute() {
 		return c.SendFile(absCleanPath)
 	})
 	// Application
-	api.Get("/applications", c.ApplicationController.Get)
+	api.Get("/applications", guestRateLimit, c.ApplicationController.Get)
 	api.Use(c.AuthAdminCreationMiddleware).Post("/applications-use-admin-key", c.ApplicationController.Create) // add & update
 
 	api.Get("/test-pusher", func(f *fiber.Ctx) error {
@@ -132,7 +204,10 @@ func (c *RouteConfig) SetupGuestRoute() {
 // USER
 func (c *RouteConfig) SetupAuthRoute() {
 	api := c.App.Group("/api")
-	auth := api.Use(c.AuthMiddleware)
+	rateLimitConfig := c.rateLimitConfig()
+	orderRateLimit := c.rateLimit(rateLimitConfig.Order)
+	// policy user juga berlaku untuk route admin karena middleware ini dipasang lebih dulu
+	auth := api.Use(c.AuthMiddleware, c.rateLimit(rateLimitConfig.User))
 
 	// User
 	auth.Get("/users/current", c.UserController.GetCurrent)
@@ -140,6 +215,18 @@ func (c *RouteConfig) SetupAuthRoute() {
 	auth.Delete("/users/logout", c.UserController.Logout)
 	auth.Delete("/users/current", c.UserController.RemoveAccount)
 	auth.Patch("/users/current/password", c.UserController.UpdatePassword)
+	auth.Get("/users/current/sessions", c.UserController.GetSessions)
+	// log out everywhere else, sesi yang sedang dipakai tetap aktif
+	auth.Delete("/users/current/sessions", c.UserController.RevokeOtherSessions)
+	auth.Delete("/users/current/sessions/:sessionId", c.UserController.RevokeSession)
+
+	// Two-factor authentication (TOTP)
+	auth.Get("/users/current/two-factor", c.TwoFactorController.GetStatus)
+	auth.Post("/users/current/two-factor/setup", c.TwoFactorController.Setup)
+	auth.Post("/users/current/two-factor/enable", c.TwoFactorController.Enable)
+	auth.Post("/users/current/two-factor/confirm", c.TwoFactorController.Confirm)
+	auth.Post("/users/current/two-factor/recovery-codes", c.TwoFactorController.RegenerateRecoveryCodes)
+	auth.Delete("/users/current/two-factor", c.TwoFactorController.Disable)
 
 	// Address
 	auth.Post("/users/current/addresses", c.AddressController.Add)
@@ -149,21 +236,28 @@ func (c *RouteConfig) SetupAuthRoute() {
 	auth.Delete("/users/current/addresses", c.AddressController.Remove)
 
 	// Order
-	auth.Post("/orders", c.OrderController.Create)
+	auth.Post("/orders", orderRateLimit, c.OrderController.Create)
 	auth.Get("/orders/:orderId", c.OrderController.GetOrderById)
 	auth.Get("/orders/users/:userId", c.OrderController.GetAllByUserId)
 	auth.Patch("/orders/:orderId/status", c.OrderController.UpdateOrderStatus)
 	auth.Get("/orders", c.OrderController.GetAll)
 	auth.Get("/orders/:invoiceId/invoice", c.OrderController.ShowInvoiceByOrderId)
+	// konfirmasi pembayaran tunai oleh admin / staff, permission dicek di use case
+	auth.Post("/orders/:orderId/cash-payment/confirm", c.CashPaymentController.Confirm)
 
 	// Product review
 	auth.Post("/reviews", c.ProductReviewController.Create)
 
 	// Xendit
-	auth.Post("/xendit/orders/qr-code/transaction", c.XenditQRCodeTransactionController.Create)
+	auth.Post("/xendit/orders/qr-code/transaction", orderRateLimit, c.XenditQRCodeTransactionController.Create)
 	auth.Get("/xendit/orders/:orderId/qr-code/transaction", c.XenditQRCodeTransactionController.GetTransaction)
-	auth.Post("/xendit/payout-request/:payoutId/cancel", c.XenditPayoutController.Cancel)
-	auth.Get("/xendit/payout-request/:payoutId", c.XenditPayoutController.GetPayoutById)
+	auth.Post("/xendit/payout-request/:payoutId/cancel", c.permission(enum_state.PERMISSION_PAYOUTS_CREATE), c.TwoFactorFreshMiddleware, c.XenditPayoutController.Cancel)
+	auth.Get("/xendit/payout-request/:payoutId", c.permission(enum_state.PERMISSION_PAYOUTS_CREATE), c.TwoFactorFreshMiddleware, c.XenditPayoutController.GetPayoutById)
+
+	// Midtrans
+	if c.MidtransTransactionController != nil {
+		auth.Get("/midtrans/orders/:orderId/transaction", c.MidtransTransactionController.GetTransaction)
+	}
 
 	// Cart
 	auth.Post("/carts", c.CartController.Create)
@@ -171,47 +265,108 @@ func (c *RouteConfig) SetupAuthRoute() {
 	auth.Patch("/carts/cart-items/:cartItemId", c.CartController.Update)
 	auth.Delete("/carts/cart-items/:cartItemId", c.CartController.Delete)
 
-	// Xendit payout
-	auth.Post("/xendit/payouts/:userId", c.XenditPayoutController.Create)
-
 	// Payout
-	auth.Post("/payouts/:userId", c.PayoutController.Create)
+	auth.Post("/payouts/:userId", c.permission(enum_state.PERMISSION_PAYOUTS_CREATE), c.TwoFactorFreshMiddleware, c.PayoutController.Create)
 
 	// Wallet
 	auth.Post("/wallets/withdraw-cust", c.WalletController.WithdrawCustRequest)
+	auth.Get("/wallets/withdraw-requests", c.WalletController.GetAllWithdrawRequests)
+	auth.Get("/wallets/withdraw-requests/:withdrawRequestId", c.WalletController.GetWithdrawRequestById)
+	auth.Patch("/wallets/withdraw-requests/:withdrawRequestId/cancel", c.WalletController.WithdrawCustCancel)
+	auth.Get("/wallets/withdraw-policies", c.WithdrawPolicyController.GetAll)
+
+	// Bank account
+	auth.Get("/payout-channels", c.BankAccountController.GetPayoutChannels)
+	auth.Post("/users/current/bank-accounts", c.BankAccountController.Add)
+	auth.Get("/users/current/bank-accounts", c.BankAccountController.GetAllCurrent)
+	auth.Delete("/users/current/bank-accounts/:bankAccountId", c.BankAccountController.Remove)
 }
 
 // ADMIN
 func (c *RouteConfig) SetupAuthAdminRoute() {
 	api := c.App.Group("/api")
+	// role middleware meloloskan admin dan staff, akses tiap route dibatasi permission
 	auth := api.Use(c.AuthMiddleware, c.RoleMiddleware)
 
 	// Category
-	auth.Post("/categories", c.CategoryController.Create)
-	auth.Put("/categories/:categoryId", c.CategoryController.Edit)
-	auth.Delete("/categories", c.CategoryController.Remove)
+	auth.Post("/categories", c.permission(enum_state.PERMISSION_CATEGORIES_WRITE), c.CategoryController.Create)
+	auth.Put("/categories/:categoryId", c.permission(enum_state.PERMISSION_CATEGORIES_WRITE), c.CategoryController.Edit)
+	auth.Delete("/categories", c.permission(enum_state.PERMISSION_CATEGORIES_WRITE), c.CategoryController.Remove)
 
 	// Product
-	auth.Post("/products", c.ProductController.Create)
-	auth.Put("/products/:productId", c.ProductController.Edit)
-	auth.Delete("/products", c.ProductController.Remove)
+	auth.Post("/products", c.permission(enum_state.PERMISSION_PRODUCTS_WRITE), c.ProductController.Create)
+	auth.Put("/products/:productId", c.permission(enum_state.PERMISSION_PRODUCTS_WRITE), c.ProductController.Edit)
+	auth.Delete("/products", c.permission(enum_state.PERMISSION_PRODUCTS_WRITE), c.ProductController.Remove)
 
 	// Discount
-	auth.Post("/discount-coupons", c.DiscountCouponController.Create)
-	auth.Put("/discount-coupons/:discountId", c.DiscountCouponController.Update)
-	auth.Delete("/discount-coupons", c.DiscountCouponController.Delete)
+	auth.Post("/discount-coupons", c.permission(enum_state.PERMISSION_DISCOUNT_COUPONS_WRITE), c.DiscountCouponController.Create)
+	auth.Put("/discount-coupons/:discountId", c.permission(enum_state.PERMISSION_DISCOUNT_COUPONS_WRITE), c.DiscountCouponController.Update)
+	auth.Delete("/discount-coupons", c.permission(enum_state.PERMISSION_DISCOUNT_COUPONS_WRITE), c.DiscountCouponController.Delete)
 
 	// Delivery
-	auth.Post("/deliveries", c.DeliveryController.Create)
-	auth.Put("/deliveries/:deliveryId", c.DeliveryController.Update)
-	auth.Delete("/deliveries", c.DeliveryController.Remove)
+	auth.Post("/deliveries", c.permission(enum_state.PERMISSION_DELIVERIES_WRITE), c.DeliveryController.Create)
+	auth.Put("/deliveries/:deliveryId", c.permission(enum_state.PERMISSION_DELIVERIES_WRITE), c.DeliveryController.Update)
+	auth.Delete("/deliveries", c.permission(enum_state.PERMISSION_DELIVERIES_WRITE), c.DeliveryController.Remove)
 
 	// Application
-	auth.Post("/applications", c.ApplicationController.Create) // add & update
+	auth.Post("/applications", c.permission(enum_state.PERMISSION_APPLICATIONS_WRITE), c.ApplicationController.Create) // add & update
 
-	// Balance
-	auth.Get("/balance", c.XenditPayoutController.GetAdminBalance)
+	// Balance, endpoint berisiko tinggi butuh konfirmasi 2FA yang masih baru
+	auth.Get("/balance", c.permission(enum_state.PERMISSION_BALANCE_READ), c.TwoFactorFreshMiddleware, c.XenditPayoutController.GetAdminBalance)
 
 	// Wallet
-	auth.Patch("/wallets/:withdrawRequestId/withdraw-approval", c.WalletController.WithdrawAdminApproval)
+	auth.Patch("/wallets/:withdrawRequestId/withdraw-approval", c.permission(enum_state.PERMISSION_WALLETS_APPROVE_WITHDRAW), c.TwoFactorFreshMiddleware, c.WalletController.WithdrawAdminApproval)
+	auth.Get("/admin/wallets/withdraw-requests", c.permission(enum_state.PERMISSION_WALLETS_READ), c.WalletController.GetAllWithdrawRequests)
+	auth.Get("/admin/wallets/withdraw-requests/:withdrawRequestId", c.permission(enum_state.PERMISSION_WALLETS_READ), c.WalletController.GetWithdrawRequestById)
+	auth.Put("/admin/wallets/withdraw-policies/:method", c.permission(enum_state.PERMISSION_WITHDRAW_POLICIES_WRITE), c.WithdrawPolicyController.Upsert)
+	auth.Post("/admin/wallets/:userId/adjustments", c.permission(enum_state.PERMISSION_WALLETS_ADJUST), c.TwoFactorFreshMiddleware, c.WalletController.CreateAdjustment)
+	auth.Patch("/admin/wallets/adjustments/:adjustmentId/approval", c.permission(enum_state.PERMISSION_WALLETS_APPROVE_ADJUSTMENT), c.TwoFactorFreshMiddleware, c.WalletController.AdjustmentAdminApproval)
+	auth.Patch("/admin/wallets/:userId/freeze", c.permission(enum_state.PERMISSION_WALLETS_FREEZE), c.TwoFactorFreshMiddleware, c.WalletController.Freeze)
+	auth.Patch("/admin/wallets/:userId/unfreeze", c.permission(enum_state.PERMISSION_WALLETS_FREEZE), c.TwoFactorFreshMiddleware, c.WalletController.Unfreeze)
+
+	// Bank account
+	auth.Patch("/admin/bank-accounts/:bankAccountId/verification", c.permission(enum_state.PERMISSION_BANK_ACCOUNTS_VERIFY), c.BankAccountController.UpdateVerification)
+
+	// Cash reconciliation
+	auth.Get("/admin/cash-collections/reconciliation", c.permission(enum_state.PERMISSION_CASH_COLLECTIONS_RECONCILE), c.CashPaymentController.GetReconciliation)
+	auth.Patch("/admin/cash-collections/reconcile", c.permission(enum_state.PERMISSION_CASH_COLLECTIONS_RECONCILE), c.CashPaymentController.Reconcile)
+
+	// Order refunds
+	auth.Post("/admin/orders/:orderId/refunds", c.permission(enum_state.PERMISSION_ORDERS_REFUND), c.TwoFactorFreshMiddleware, c.OrderRefundController.Create)
+	auth.Get("/admin/orders/:orderId/refunds", c.permission(enum_state.PERMISSION_ORDERS_REFUND), c.OrderRefundController.GetByOrderId)
+
+	// Xendit webhook events
+	auth.Get("/admin/xendit/webhook-events", c.permission(enum_state.PERMISSION_PAYMENTS_MANAGE), c.XenditWebhookEventController.GetAll)
+	auth.Post("/admin/xendit/webhook-events/:webhookEventId/replay", c.permission(enum_state.PERMISSION_PAYMENTS_MANAGE), c.XenditWebhookEventController.Replay)
+
+	// Rekonsiliasi status pembayaran xendit untuk callback yang tidak sampai
+	auth.Post("/admin/orders/:orderId/payment-reconciliation", c.permission(enum_state.PERMISSION_PAYMENTS_MANAGE), c.XenditReconciliationController.ReconcileOrder)
+
+	// Payment simulator, hanya terdaftar di luar production
+	if c.PaymentSimulatorController != nil {
+		auth.Post("/admin/payment-simulator/payments/:paymentId/simulate", c.permission(enum_state.PERMISSION_PAYMENTS_MANAGE), c.PaymentSimulatorController.Simulate)
+	}
+
+	// Role & permission staff
+	auth.Get("/admin/permissions", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.GetPermissions)
+	auth.Get("/admin/roles", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.GetAll)
+	auth.Get("/admin/roles/:roleId", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.Get)
+	auth.Post("/admin/roles", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.Create)
+	auth.Put("/admin/roles/:roleId", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.Update)
+	auth.Delete("/admin/roles/:roleId", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.Delete)
+	auth.Put("/admin/users/:userId/staff-role", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.AssignToUser)
+	auth.Delete("/admin/users/:userId/staff-role", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.RemoveFromUser)
+	// mengganti role admin / customer / kurir / staff termasuk menaikkan user menjadi admin
+	auth.Patch("/admin/users/:userId/role", c.permission(enum_state.PERMISSION_ROLES_MANAGE), c.RoleController.UpdateUserRole)
+
+	// User management
+	auth.Get("/admin/users", c.permission(enum_state.PERMISSION_USERS_READ), c.UserController.GetAllByAdmin)
+	auth.Get("/admin/users/:userId", c.permission(enum_state.PERMISSION_USERS_READ), c.UserController.GetByAdmin)
+	auth.Patch("/admin/users/:userId/suspend", c.permission(enum_state.PERMISSION_USERS_MANAGE), c.UserController.Suspend)
+	auth.Patch("/admin/users/:userId/unsuspend", c.permission(enum_state.PERMISSION_USERS_MANAGE), c.UserController.Unsuspend)
+	auth.Post("/admin/users/:userId/verification-email", c.permission(enum_state.PERMISSION_USERS_MANAGE), c.UserController.ResendVerification)
+	auth.Patch("/admin/users/:userId/restore", c.permission(enum_state.PERMISSION_USERS_MANAGE), c.UserController.Restore)
+
+	// audit log perubahan data administratif dan keuangan
+	auth.Get("/admin/audit-logs", c.permission(enum_state.PERMISSION_AUDIT_LOGS_READ), c.AuditLogController.GetAll)
 }
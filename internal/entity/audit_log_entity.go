@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+// audit log tidak memiliki updated_at dan deleted_at karena tidak pernah diubah
+type AuditLog struct {
+	ID         uint64                     `gorm:"primary_key;column:id;autoIncrement"`
+	ActorId    *uint64                    `gorm:"column:actor_id"`
+	ActorEmail string                     `gorm:"column:actor_email"`
+	ActorRole  enum_state.Role            `gorm:"column:actor_role"`
+	Action     enum_state.AuditAction     `gorm:"column:action"`
+	EntityType enum_state.AuditEntityType `gorm:"column:entity_type"`
+	EntityId   string                     `gorm:"column:entity_id"`
+	Changes    *string                    `gorm:"column:changes"`
+	IPAddress  string                     `gorm:"column:ip_address"`
+	RequestId  string                     `gorm:"column:request_id"`
+	CreatedAt  time.Time                  `gorm:"column:created_at;autoCreateTime;<-:create"`
+}
+
+func (a *AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BankAccount struct {
+	ID                 uint64                                   `gorm:"primary_key;column:id;autoIncrement"`
+	UserId             uint64                                   `gorm:"column:user_id"`
+	ChannelCode        string                                   `gorm:"column:channel_code"`
+	AccountNumber      string                                   `gorm:"column:account_number"`
+	AccountHolderName  string                                   `gorm:"column:account_holder_name"`
+	VerificationStatus enum_state.BankAccountVerificationStatus `gorm:"column:verification_status"`
+	VerificationNotes  string                                   `gorm:"column:verification_notes"`
+	VerifiedBy         *uint64                                  `gorm:"column:verified_by"`
+	VerifiedAt         *time.Time                               `gorm:"column:verified_at"`
+	CreatedAt          time.Time                                `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt          time.Time                                `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt                           `gorm:"column:deleted_at"`
+	User               *User                                    `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (u *BankAccount) TableName() string {
+	return "bank_accounts"
+}
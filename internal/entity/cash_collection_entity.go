@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type CashCollection struct {
+	ID             uint64     `gorm:"primary_key;column:id;autoIncrement"`
+	OrderId        uint64     `gorm:"column:order_id"`
+	CollectedBy    uint64     `gorm:"column:collected_by"`
+	Amount         float32    `gorm:"column:amount"`
+	ReceivedAmount float32    `gorm:"column:received_amount"`
+	ChangeAmount   float32    `gorm:"column:change_amount"`
+	Note           string     `gorm:"column:note"`
+	CollectedAt    time.Time  `gorm:"column:collected_at"`
+	ReconciledBy   *uint64    `gorm:"column:reconciled_by"`
+	ReconciledAt   *time.Time `gorm:"column:reconciled_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Order          *Order     `gorm:"foreignKey:order_id;references:id"`
+	Collector      *User      `gorm:"foreignKey:collected_by;references:id"`
+}
+
+func (u *CashCollection) TableName() string {
+	return "cash_collections"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+// LoginAttempt menyimpan jumlah login gagal berturut-turut untuk satu akun (email) atau satu alamat IP
+type LoginAttempt struct {
+	ID           uint64                       `gorm:"primary_key;column:id;autoIncrement"`
+	Scope        enum_state.LoginAttemptScope `gorm:"column:scope"`
+	Identifier   string                       `gorm:"column:identifier"`
+	FailedCount  int                          `gorm:"column:failed_count"`
+	LastFailedAt *time.Time                   `gorm:"column:last_failed_at"`
+	LockedUntil  *time.Time                   `gorm:"column:locked_until"`
+	CreatedAt    time.Time                    `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt    time.Time                    `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (l *LoginAttempt) TableName() string {
+	return "login_attempts"
+}
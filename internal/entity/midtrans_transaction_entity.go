@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type MidtransTransaction struct {
+	ID                uint64                 `gorm:"primary_key;column:id;autoIncrement"`
+	OrderId           uint64                 `gorm:"column:order_id"`
+	MidtransOrderId   string                 `gorm:"column:midtrans_order_id"`
+	TransactionId     string                 `gorm:"column:transaction_id"`
+	ApiType           string                 `gorm:"column:api_type"`
+	PaymentType       string                 `gorm:"column:payment_type"`
+	ChannelCode       enum_state.ChannelCode `gorm:"column:channel_code"`
+	GrossAmount       float64                `gorm:"column:gross_amount"`
+	Currency          string                 `gorm:"column:currency"`
+	TransactionStatus string                 `gorm:"column:transaction_status"`
+	FraudStatus       string                 `gorm:"column:fraud_status"`
+	StatusCode        string                 `gorm:"column:status_code"`
+	VaBank            string                 `gorm:"column:va_bank"`
+	VaNumber          string                 `gorm:"column:va_number"`
+	QrString          string                 `gorm:"column:qr_string"`
+	DeeplinkUrl       string                 `gorm:"column:deeplink_url"`
+	SnapToken         string                 `gorm:"column:snap_token"`
+	RedirectUrl       string                 `gorm:"column:redirect_url"`
+	Metadata          []byte                 `gorm:"column:metadata"`
+	ExpiresAt         *time.Time             `gorm:"column:expires_at"`
+	CreatedAt         time.Time              `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt         time.Time              `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Order             *Order                 `gorm:"foreignKey:order_id;references:id"`
+}
+
+func (m *MidtransTransaction) TableName() string {
+	return "midtrans_transactions"
+}
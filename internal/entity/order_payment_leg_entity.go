@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type OrderPaymentLeg struct {
+	ID                  uint64                           `gorm:"primary_key;column:id;autoIncrement"`
+	OrderId             uint64                           `gorm:"column:order_id"`
+	Source              enum_state.OrderPaymentLegSource `gorm:"column:source"`
+	PaymentGateway      enum_state.PaymentGateway        `gorm:"column:payment_gateway"`
+	PaymentMethod       enum_state.PaymentMethod         `gorm:"column:payment_method"`
+	ChannelCode         enum_state.ChannelCode           `gorm:"column:channel_code"`
+	Amount              float32                          `gorm:"column:amount"`
+	Status              enum_state.OrderPaymentLegStatus `gorm:"column:status"`
+	WalletTransactionId *uint64                          `gorm:"column:wallet_transaction_id"`
+	XenditTransactionId *string                          `gorm:"column:xendit_transaction_id"`
+	CreatedAt           time.Time                        `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt           time.Time                        `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Order               *Order                           `gorm:"foreignKey:order_id;references:id"`
+}
+
+func (u *OrderPaymentLeg) TableName() string {
+	return "order_payment_legs"
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type OrderRefund struct {
+	ID              uint64                       `gorm:"primary_key;column:id;autoIncrement"`
+	OrderId         uint64                       `gorm:"column:order_id"`
+	RefundedBy      uint64                       `gorm:"column:refunded_by"`
+	ReferenceId     string                       `gorm:"column:reference_id"`
+	Type            enum_state.RefundType        `gorm:"column:type"`
+	Destination     enum_state.RefundDestination `gorm:"column:destination"`
+	Amount          float32                      `gorm:"column:amount"`
+	Reason          string                       `gorm:"column:reason"`
+	Status          enum_state.RefundStatus      `gorm:"column:status"`
+	PaymentGateway  enum_state.PaymentGateway    `gorm:"column:payment_gateway"`
+	GatewayRefundId *string                      `gorm:"column:gateway_refund_id"`
+	FailureCode     string                       `gorm:"column:failure_code"`
+	ProcessedAt     *time.Time                   `gorm:"column:processed_at"`
+	CreatedAt       time.Time                    `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt       time.Time                    `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Items           []OrderRefundItem            `gorm:"foreignKey:order_refund_id;references:id"`
+	Order           *Order                       `gorm:"foreignKey:order_id;references:id"`
+	Refunder        *User                        `gorm:"foreignKey:refunded_by;references:id"`
+}
+
+func (u *OrderRefund) TableName() string {
+	return "order_refunds"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type OrderRefundItem struct {
+	ID             uint64        `gorm:"primary_key;column:id;autoIncrement"`
+	OrderRefundId  uint64        `gorm:"column:order_refund_id"`
+	OrderProductId uint64        `gorm:"column:order_product_id"`
+	Quantity       int           `gorm:"column:quantity"`
+	Amount         float32       `gorm:"column:amount"`
+	CreatedAt      time.Time     `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt      time.Time     `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	OrderProduct   *OrderProduct `gorm:"foreignKey:order_product_id;references:id"`
+}
+
+func (u *OrderRefundItem) TableName() string {
+	return "order_refund_items"
+}
@@ -10,6 +10,7 @@ type Payout struct {
 	ID             uint64                  `gorm:"primary_key;column:id;autoIncrement"`
 	UserId         uint64                  `gorm:"column:user_id"`
 	XenditPayoutId sql.NullString          `gorm:"column:xendit_payout_id"`
+	BankAccountId  *uint64                 `gorm:"column:bank_account_id"`
 	Amount         float32                 `gorm:"column:amount"`
 	Currency       string                  `gorm:"column:currency"`
 	Method         enum_state.PayoutMethod `gorm:"column:method"`
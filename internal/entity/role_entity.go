@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// Role adalah role staff (misalnya kasir, dapur, kurir) beserta permission nya
+type Role struct {
+	ID          uint64           `gorm:"primary_key;column:id;autoIncrement"`
+	Name        string           `gorm:"column:name"`
+	Description string           `gorm:"column:description"`
+	CreatedAt   time.Time        `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt   time.Time        `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Permissions []RolePermission `gorm:"foreignKey:role_id;references:id"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
+
+type RolePermission struct {
+	ID         uint64    `gorm:"primary_key;column:id;autoIncrement"`
+	RoleId     uint64    `gorm:"column:role_id"`
+	Permission string    `gorm:"column:permission"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime;<-:create"`
+}
+
+func (r *RolePermission) TableName() string {
+	return "role_permissions"
+}
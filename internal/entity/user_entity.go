@@ -9,23 +9,31 @@ import (
 
 // user is a struct that represents a user entity in database table
 type User struct {
-	ID                uint64          `gorm:"primary_key;column:id;autoIncrement"`
-	Name              Name            `gorm:"embedded"`
-	Email             string          `gorm:"column:email"`
-	EmailVerified     bool            `gorm:"column:email_verified"`
-	VerificationToken string          `gorm:"column:verification_token"`
-	TokenExpiry       time.Time       `gorm:"column:token_expiry"`
-	Phone             string          `gorm:"column:phone"`
-	Password          string          `gorm:"column:password"`
-	Role              enum_state.Role `gorm:"column:role"`
-	UserProfile       string          `gorm:"column:user_profile"`
-	CreatedAt         time.Time       `gorm:"column:created_at;autoCreateTime;<-:create"`
-	UpdatedAt         time.Time       `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
-	DeletedAt         gorm.DeletedAt  `gorm:"column:deleted_at"`
-	Token             Token           `gorm:"foreignKey:user_id;references:id"`
-	Addresses         []Address       `gorm:"foreignKey:user_id;references:id"`
-	Cart              *Cart           `gorm:"foreignKey:user_id;references:id"`
-	Wallet            *Wallet         `gorm:"foreignKey:user_id;references:id"`
+	ID                uint64    `gorm:"primary_key;column:id;autoIncrement"`
+	Name              Name      `gorm:"embedded"`
+	Email             string    `gorm:"column:email"`
+	EmailVerified     bool      `gorm:"column:email_verified"`
+	VerificationToken string    `gorm:"column:verification_token"`
+	TokenExpiry       time.Time `gorm:"column:token_expiry"`
+	Phone             string    `gorm:"column:phone"`
+	Password          string    `gorm:"column:password"`
+	// secret TOTP tersimpan sejak setup, 2FA baru aktif setelah TwoFactorEnabledAt terisi
+	TwoFactorSecret       *string         `gorm:"column:two_factor_secret"`
+	TwoFactorEnabledAt    *time.Time      `gorm:"column:two_factor_enabled_at"`
+	TwoFactorLastUsedStep int64           `gorm:"column:two_factor_last_used_step"`
+	Role                  enum_state.Role `gorm:"column:role"`
+	RoleId                *uint64         `gorm:"column:role_id"`
+	SuspendedAt           *time.Time      `gorm:"column:suspended_at"`
+	SuspendedReason       string          `gorm:"column:suspended_reason"`
+	UserProfile           string          `gorm:"column:user_profile"`
+	CreatedAt             time.Time       `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt             time.Time       `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	DeletedAt             gorm.DeletedAt  `gorm:"column:deleted_at"`
+	Token                 Token           `gorm:"foreignKey:user_id;references:id"`
+	Addresses             []Address       `gorm:"foreignKey:user_id;references:id"`
+	Cart                  *Cart           `gorm:"foreignKey:user_id;references:id"`
+	Wallet                *Wallet         `gorm:"foreignKey:user_id;references:id"`
+	StaffRole             *Role           `gorm:"foreignKey:role_id;references:id"`
 }
 
 func (u *User) TableName() string {
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// UserIdentity menghubungkan user dengan akun di provider OpenID Connect (sub dari ID token)
+type UserIdentity struct {
+	ID          uint64     `gorm:"primary_key;column:id;autoIncrement"`
+	UserId      uint64     `gorm:"column:user_id"`
+	Provider    string     `gorm:"column:provider"`
+	Subject     string     `gorm:"column:subject"`
+	Email       string     `gorm:"column:email"`
+	LastLoginAt *time.Time `gorm:"column:last_login_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (u *UserIdentity) TableName() string {
+	return "user_identities"
+}
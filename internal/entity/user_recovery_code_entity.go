@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type UserRecoveryCode struct {
+	ID        uint64     `gorm:"primary_key;column:id;autoIncrement"`
+	UserId    uint64     `gorm:"column:user_id"`
+	CodeHash  string     `gorm:"column:code_hash"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime;<-:create"`
+}
+
+func (u *UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
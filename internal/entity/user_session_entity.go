@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// UserSession adalah satu sesi login (device), ID nya sama dengan family_id refresh token di tabel tokens
+type UserSession struct {
+	ID         string    `gorm:"primary_key;column:id"`
+	UserId     uint64    `gorm:"column:user_id"`
+	UserAgent  string    `gorm:"column:user_agent"`
+	IPAddress  string    `gorm:"column:ip_address"`
+	LastSeenAt time.Time `gorm:"column:last_seen_at"`
+	// konfirmasi 2FA terakhir pada sesi ini
+	TwoFactorVerifiedAt *time.Time `gorm:"column:two_factor_verified_at"`
+	ExpiresAt           time.Time  `gorm:"column:expires_at"`
+	RevokedAt           *time.Time `gorm:"column:revoked_at"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	User                *User      `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (u *UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type WalletAdjustmentRequests struct {
+	ID             uint64                            `gorm:"primary_key;column:id"`
+	UserId         uint64                            `gorm:"column:user_id"`
+	Amount         float32                           `gorm:"column:amount"`
+	Reason         string                            `gorm:"column:reason"`
+	Status         enum_state.WalletAdjustmentStatus `gorm:"column:status"`
+	RequestedBy    uint64                            `gorm:"column:requested_by"`
+	ProcessedBy    *uint64                           `gorm:"column:processed_by"`
+	ProcessedAt    *time.Time                        `gorm:"column:processed_at"`
+	RejectionNotes string                            `gorm:"column:rejection_notes"`
+	CreatedAt      time.Time                         `gorm:"column:created_at"`
+	UpdatedAt      time.Time                         `gorm:"column:updated_at"`
+	User           *User                             `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (u *WalletAdjustmentRequests) TableName() string {
+	return "wallet_adjustment_requests"
+}
@@ -10,10 +10,13 @@ type Wallet struct {
 	ID        uint64                  `gorm:"primary_key;column:id;autoIncrement"`
 	Balance   float32                 `gorm:"column:balance"`
 	UserId    uint64                  `gorm:"column:user_id"`
-	Status    enum_state.WalletStatus `gorm:"column:status"`
-	CreatedAt time.Time               `gorm:"column:created_at;autoCreateTime;<-:create"`
-	UpdatedAt time.Time               `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
-	User      *User                   `gorm:"foreignKey:user_id;references:id"`
+	Status          enum_state.WalletStatus `gorm:"column:status"`
+	StatusReason    string                  `gorm:"column:status_reason"`
+	StatusUpdatedBy *uint64                 `gorm:"column:status_updated_by"`
+	StatusUpdatedAt *time.Time              `gorm:"column:status_updated_at"`
+	CreatedAt       time.Time               `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt       time.Time               `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	User            *User                   `gorm:"foreignKey:user_id;references:id"`
 }
 
 func (u *Wallet) TableName() string {
@@ -9,7 +9,10 @@ type WalletWithdrawRequests struct {
 	ID               uint64                           `gorm:"primary_key;column:id"`
 	UserId           uint64                           `gorm:"column:user_id"`
 	Amount           float32                          `gorm:"column:amount"`
+	Fee              float32                          `gorm:"column:fee"`
 	Method           enum_state.WalletWithdrawRequest `gorm:"column:method"`
+	BankAccountId    *uint64                          `gorm:"column:bank_account_id"`
+	ChannelCode      string                           `gorm:"column:channel_code"`
 	BankName         string                           `gorm:"column:bank_name"`
 	BankAcountNumber string                           `gorm:"column:bank_account_number"`
 	BankAcountName   string                           `gorm:"column:bank_account_name"`
@@ -18,6 +21,7 @@ type WalletWithdrawRequests struct {
 	RejectionNotes   string                           `gorm:"column:rejection_notes"`
 	ProcessedBy      *uint64                          `gorm:"column:processed_by"`
 	ProcessedAt      *time.Time                       `gorm:"column:processed_at"`
+	XenditPayoutId   *string                          `gorm:"column:xendit_payout_id"`
 	CreatedAt        time.Time                        `gorm:"column:created_at"`
 	UpdatedAt        time.Time                        `gorm:"column:updated_at"`
 	User             *User                            `gorm:"foreignKey:user_id;references:id"`
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type WithdrawPolicy struct {
+	ID              uint64                           `gorm:"primary_key;column:id;autoIncrement"`
+	Method          enum_state.WalletWithdrawRequest `gorm:"column:method"`
+	MinAmount       float32                          `gorm:"column:min_amount"`
+	FeeType         enum_state.WithdrawFeeType       `gorm:"column:fee_type"`
+	FeeValue        float32                          `gorm:"column:fee_value"`
+	DailyLimit      float32                          `gorm:"column:daily_limit"`
+	MonthlyLimit    float32                          `gorm:"column:monthly_limit"`
+	CoolingOffHours int                              `gorm:"column:cooling_off_hours"`
+	UpdatedBy       *uint64                          `gorm:"column:updated_by"`
+	CreatedAt       time.Time                        `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt       time.Time                        `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (u *WithdrawPolicy) TableName() string {
+	return "withdraw_policies"
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type XenditWebhookEvent struct {
+	ID          uint64                              `gorm:"primary_key;column:id;autoIncrement"`
+	EventId     string                              `gorm:"column:event_id"`
+	Gateway     enum_state.PaymentGateway           `gorm:"column:gateway"`
+	WebhookType enum_state.XenditWebhookType        `gorm:"column:webhook_type"`
+	EventType   string                              `gorm:"column:event_type"`
+	RawBody     string                              `gorm:"column:raw_body"`
+	Status      enum_state.XenditWebhookEventStatus `gorm:"column:status"`
+	Attempts    int                                 `gorm:"column:attempts"`
+	LastError   string                              `gorm:"column:last_error"`
+	ProcessedAt *time.Time                          `gorm:"column:processed_at"`
+	CreatedAt   time.Time                           `gorm:"column:created_at;autoCreateTime;<-:create"`
+	UpdatedAt   time.Time                           `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (u *XenditWebhookEvent) TableName() string {
+	return "xendit_webhook_events"
+}
@@ -24,11 +24,29 @@ type WalletTransactionType string
 type WalletPaymentMethod string
 type WalletTransactionStatus string
 type WalletWithdrawRequest string
+type WalletAdjustmentStatus string
+type BankAccountVerificationStatus string
+type PayoutChannelCategory string
+type WithdrawFeeType string
+type XenditWebhookType string
+type XenditWebhookEventStatus string
+type RefundDestination string
+type RefundStatus string
+type RefundType string
+type OrderPaymentLegSource string
+type OrderPaymentLegStatus string
+type LoginAttemptScope string
+type RateLimitKey string
+type Permission string
+type AuditAction string
+type AuditEntityType string
 
 const (
 	// role
 	ADMIN    Role = "admin"
 	CUSTOMER Role = "customer"
+	COURIER  Role = "courier"
+	STAFF    Role = "staff" // hak akses mengikuti role staff yang dipasang (users.role_id)
 	// Payment Status
 	PAID_PAYMENT      PaymentStatus = "paid"      // Pembayaran sukses
 	PENDING_PAYMENT   PaymentStatus = "pending"   // Menunggu konfirmasi
@@ -63,9 +81,16 @@ const (
 	PAYMENT_METHOD_EWALLET PaymentMethod = "EWALLET"
 	PAYMENT_METHOD_WALLET  PaymentMethod = "WALLET"
 	PAYMENT_METHOD_CASH    PaymentMethod = "CASH"
+	// virtual account bank transfer
+	PAYMENT_METHOD_VIRTUAL_ACCOUNT PaymentMethod = "VIRTUAL_ACCOUNT"
+	// halaman checkout yang di-hosting payment gateway (midtrans snap), customer memilih sendiri channelnya
+	PAYMENT_METHOD_CHECKOUT PaymentMethod = "CHECKOUT"
 
 	PAYMENT_GATEWAY_XENDIT PaymentGateway = "XENDIT"
 	PAYMENT_GATEWAY_SYSTEM PaymentGateway = "SYSTEM"
+	// gateway lokal untuk dev/test, tidak tersedia di production
+	PAYMENT_GATEWAY_SIMULATOR PaymentGateway = "SIMULATOR"
+	PAYMENT_GATEWAY_MIDTRANS  PaymentGateway = "MIDTRANS"
 
 	GET    RequestMethod = "GET"
 	POST   RequestMethod = "POST"
@@ -86,6 +111,21 @@ const (
 	XENDIT_EWALLET_OVO_CHANNEL_CODE       ChannelCode = "EWALLET_OVO"
 	XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE ChannelCode = "EWALLET_SHOPEEPAY"
 	WALLET_CHANNEL_CODE                   ChannelCode = "WALLET"
+	CASH_CHANNEL_CODE                     ChannelCode = "CASH"
+
+	XENDIT_VA_BCA_CHANNEL_CODE     ChannelCode = "VA_BCA"
+	XENDIT_VA_BNI_CHANNEL_CODE     ChannelCode = "VA_BNI"
+	XENDIT_VA_BRI_CHANNEL_CODE     ChannelCode = "VA_BRI"
+	XENDIT_VA_MANDIRI_CHANNEL_CODE ChannelCode = "VA_MANDIRI"
+	XENDIT_VA_PERMATA_CHANNEL_CODE ChannelCode = "VA_PERMATA"
+
+	MIDTRANS_QRIS_CHANNEL_CODE       ChannelCode = "MIDTRANS_QRIS"
+	MIDTRANS_GOPAY_CHANNEL_CODE      ChannelCode = "MIDTRANS_GOPAY"
+	MIDTRANS_VA_BCA_CHANNEL_CODE     ChannelCode = "MIDTRANS_VA_BCA"
+	MIDTRANS_VA_BNI_CHANNEL_CODE     ChannelCode = "MIDTRANS_VA_BNI"
+	MIDTRANS_VA_BRI_CHANNEL_CODE     ChannelCode = "MIDTRANS_VA_BRI"
+	MIDTRANS_VA_PERMATA_CHANNEL_CODE ChannelCode = "MIDTRANS_VA_PERMATA"
+	MIDTRANS_SNAP_CHANNEL_CODE       ChannelCode = "MIDTRANS_SNAP"
 
 	ITEM_TYPE_DIGITAL_PRODUCT  ItemType = "DIGITAL_PRODUCT"
 	ITEM_TYPE_PHYSICAL_PRODUCT ItemType = "PHYSICAL_PRODUCT"
@@ -122,6 +162,7 @@ const (
 	WALLET_TRANSACTION_TYPE_ORDER_PAYMENT    WalletTransactionType = "order_payment"
 	WALLET_TRANSACTION_TYPE_ORDER_REFUND     WalletTransactionType = "order_refund"
 	WALLET_TRANSACTION_TYPE_WITHDRAW         WalletTransactionType = "withdraw"
+	WALLET_TRANSACTION_TYPE_WITHDRAW_FEE     WalletTransactionType = "withdraw_fee"
 	WALLET_TRANSACTION_TYPE_ADMIN_ADJUSTMENT WalletTransactionType = "admin_adjustment"
 	WALLET_TRANSACTION_TYPE_CASHBACK         WalletTransactionType = "cashback"
 	WALLET_TRANSACTION_TYPE_TRANSFER_IN      WalletTransactionType = "transfer_in"
@@ -130,17 +171,175 @@ const (
 	WALLET_TRANSACTION_STATUS_PENDING    WalletTransactionStatus = "pending"
 	WALLET_TRANSACTION_STATUS_COMPLETED  WalletTransactionStatus = "completed"
 	WALLET_TRANSACTION_STATUS_FAILED     WalletTransactionStatus = "failed"
+	WALLET_TRANSACTION_STATUS_CANCELLED  WalletTransactionStatus = "cancelled"
 
 	WALLET_WITHDRAW_REQUEST_METHOD_CASH          WalletWithdrawRequest = "cash"
 	WALLET_WITHDRAW_REQUEST_METHOD_BANK_TRANSFER WalletWithdrawRequest = "bank_transfer"
 	WALLET_WITHDRAW_REQUEST_STATUS_PENDING       WalletWithdrawRequest = "pending"
 	WALLET_WITHDRAW_REQUEST_STATUS_APPROVED      WalletWithdrawRequest = "approved"
+	WALLET_WITHDRAW_REQUEST_STATUS_PROCESSING    WalletWithdrawRequest = "processing"
 	WALLET_WITHDRAW_REQUEST_STATUS_REJECTED      WalletWithdrawRequest = "rejected"
+	WALLET_WITHDRAW_REQUEST_STATUS_CANCELLED     WalletWithdrawRequest = "cancelled"
+	WALLET_WITHDRAW_REQUEST_STATUS_COMPLETED     WalletWithdrawRequest = "completed"
+	WALLET_WITHDRAW_REQUEST_STATUS_FAILED        WalletWithdrawRequest = "failed"
+
+	WALLET_ADJUSTMENT_STATUS_PENDING  WalletAdjustmentStatus = "pending"
+	WALLET_ADJUSTMENT_STATUS_APPROVED WalletAdjustmentStatus = "approved"
+	WALLET_ADJUSTMENT_STATUS_REJECTED WalletAdjustmentStatus = "rejected"
+
+	BANK_ACCOUNT_UNVERIFIED BankAccountVerificationStatus = "unverified"
+	BANK_ACCOUNT_VERIFIED   BankAccountVerificationStatus = "verified"
+	BANK_ACCOUNT_REJECTED   BankAccountVerificationStatus = "rejected"
+
+	PAYOUT_CHANNEL_CATEGORY_BANK    PayoutChannelCategory = "BANK"
+	PAYOUT_CHANNEL_CATEGORY_EWALLET PayoutChannelCategory = "EWALLET"
+
+	WITHDRAW_FEE_TYPE_FLAT    WithdrawFeeType = "flat"
+	WITHDRAW_FEE_TYPE_PERCENT WithdrawFeeType = "percent"
+
+	XENDIT_WEBHOOK_TYPE_PAYMENT_REQUEST XenditWebhookType = "payment_request"
+	XENDIT_WEBHOOK_TYPE_PAYOUT          XenditWebhookType = "payout"
+	XENDIT_WEBHOOK_TYPE_REFUND          XenditWebhookType = "refund"
+
+	XENDIT_WEBHOOK_EVENT_RECEIVED   XenditWebhookEventStatus = "received"
+	XENDIT_WEBHOOK_EVENT_PROCESSING XenditWebhookEventStatus = "processing"
+	XENDIT_WEBHOOK_EVENT_PROCESSED  XenditWebhookEventStatus = "processed"
+	XENDIT_WEBHOOK_EVENT_FAILED     XenditWebhookEventStatus = "failed"
+
+	REFUND_DESTINATION_WALLET          RefundDestination = "wallet"
+	REFUND_DESTINATION_ORIGINAL_METHOD RefundDestination = "original_method"
+
+	REFUND_STATUS_PENDING   RefundStatus = "pending"
+	REFUND_STATUS_SUCCEEDED RefundStatus = "succeeded"
+	REFUND_STATUS_FAILED    RefundStatus = "failed"
+
+	REFUND_TYPE_FULL    RefundType = "full"
+	REFUND_TYPE_PARTIAL RefundType = "partial"
+
+	ORDER_PAYMENT_LEG_SOURCE_WALLET  OrderPaymentLegSource = "wallet"
+	ORDER_PAYMENT_LEG_SOURCE_GATEWAY OrderPaymentLegSource = "gateway"
+
+	ORDER_PAYMENT_LEG_PENDING  OrderPaymentLegStatus = "pending"
+	ORDER_PAYMENT_LEG_HELD     OrderPaymentLegStatus = "held" // saldo wallet sudah dipotong, menunggu pembayaran payment gateway
+	ORDER_PAYMENT_LEG_PAID     OrderPaymentLegStatus = "paid"
+	ORDER_PAYMENT_LEG_RELEASED OrderPaymentLegStatus = "released" // saldo wallet dikembalikan karena pembayaran gagal / kadaluwarsa
+	ORDER_PAYMENT_LEG_FAILED   OrderPaymentLegStatus = "failed"
+
+	LOGIN_ATTEMPT_SCOPE_ACCOUNT LoginAttemptScope = "account"
+	LOGIN_ATTEMPT_SCOPE_IP      LoginAttemptScope = "ip"
+
+	RATE_LIMIT_KEY_IP      RateLimitKey = "ip"
+	RATE_LIMIT_KEY_USER    RateLimitKey = "user"    // user yang login, memakai IP jika belum login
+	RATE_LIMIT_KEY_API_KEY RateLimitKey = "api_key" // header X-API-Key, memakai IP jika header kosong
+
+	// permission role staff, admin selalu memiliki semua permission
+	PERMISSION_CATEGORIES_WRITE           Permission = "categories.write"
+	PERMISSION_PRODUCTS_WRITE             Permission = "products.write"
+	PERMISSION_DISCOUNT_COUPONS_WRITE     Permission = "discount_coupons.write"
+	PERMISSION_DELIVERIES_WRITE           Permission = "deliveries.write"
+	PERMISSION_APPLICATIONS_WRITE         Permission = "applications.write"
+	PERMISSION_ORDERS_READ                Permission = "orders.read" // melihat pesanan milik semua user
+	PERMISSION_ORDERS_UPDATE_STATUS       Permission = "orders.update_status"
+	PERMISSION_ORDERS_REFUND              Permission = "orders.refund"
+	PERMISSION_CASH_PAYMENTS_CONFIRM      Permission = "cash_payments.confirm"
+	PERMISSION_CASH_COLLECTIONS_RECONCILE Permission = "cash_collections.reconcile"
+	PERMISSION_WALLETS_READ               Permission = "wallets.read" // melihat permintaan penarikan milik semua user
+	PERMISSION_WALLETS_APPROVE_WITHDRAW   Permission = "wallets.approve_withdraw"
+	PERMISSION_WALLETS_ADJUST             Permission = "wallets.adjust"
+	PERMISSION_WALLETS_APPROVE_ADJUSTMENT Permission = "wallets.approve_adjustment"
+	PERMISSION_WALLETS_FREEZE             Permission = "wallets.freeze"
+	PERMISSION_WITHDRAW_POLICIES_WRITE    Permission = "withdraw_policies.write"
+	PERMISSION_BANK_ACCOUNTS_VERIFY       Permission = "bank_accounts.verify"
+	PERMISSION_PAYOUTS_CREATE             Permission = "payouts.create"
+	PERMISSION_BALANCE_READ               Permission = "balance.read"
+	PERMISSION_PAYMENTS_MANAGE            Permission = "payments.manage" // webhook event, rekonsiliasi dan simulator pembayaran
+	PERMISSION_ROLES_MANAGE               Permission = "roles.manage"
+	PERMISSION_USERS_READ                 Permission = "users.read"
+	PERMISSION_USERS_MANAGE               Permission = "users.manage" // suspend, kirim ulang verifikasi dan restore akun
+	PERMISSION_AUDIT_LOGS_READ            Permission = "audit_logs.read"
+
+	// aksi yang dicatat pada audit log
+	AUDIT_ACTION_CREATE      AuditAction = "create"
+	AUDIT_ACTION_UPDATE      AuditAction = "update"
+	AUDIT_ACTION_DELETE      AuditAction = "delete"
+	AUDIT_ACTION_APPROVE     AuditAction = "approve"
+	AUDIT_ACTION_REJECT      AuditAction = "reject"
+	AUDIT_ACTION_VERIFY      AuditAction = "verify"
+	AUDIT_ACTION_FREEZE      AuditAction = "freeze"
+	AUDIT_ACTION_UNFREEZE    AuditAction = "unfreeze"
+	AUDIT_ACTION_RECONCILE   AuditAction = "reconcile"
+	AUDIT_ACTION_SUSPEND     AuditAction = "suspend"
+	AUDIT_ACTION_UNSUSPEND   AuditAction = "unsuspend"
+	AUDIT_ACTION_RESTORE     AuditAction = "restore"
+	AUDIT_ACTION_CHANGE_ROLE AuditAction = "change_role"
+
+	// jenis data yang dicatat pada audit log
+	AUDIT_ENTITY_PRODUCT                   AuditEntityType = "product"
+	AUDIT_ENTITY_CATEGORY                  AuditEntityType = "category"
+	AUDIT_ENTITY_DISCOUNT_COUPON           AuditEntityType = "discount_coupon"
+	AUDIT_ENTITY_DELIVERY                  AuditEntityType = "delivery"
+	AUDIT_ENTITY_APPLICATION               AuditEntityType = "application"
+	AUDIT_ENTITY_WALLET                    AuditEntityType = "wallet"
+	AUDIT_ENTITY_WALLET_WITHDRAW_REQUEST   AuditEntityType = "wallet_withdraw_request"
+	AUDIT_ENTITY_WALLET_ADJUSTMENT_REQUEST AuditEntityType = "wallet_adjustment_request"
+	AUDIT_ENTITY_WITHDRAW_POLICY           AuditEntityType = "withdraw_policy"
+	AUDIT_ENTITY_BANK_ACCOUNT              AuditEntityType = "bank_account"
+	AUDIT_ENTITY_PAYOUT                    AuditEntityType = "payout"
+	AUDIT_ENTITY_XENDIT_PAYOUT             AuditEntityType = "xendit_payout"
+	AUDIT_ENTITY_ORDER_REFUND              AuditEntityType = "order_refund"
+	AUDIT_ENTITY_CASH_COLLECTION           AuditEntityType = "cash_collection"
+	AUDIT_ENTITY_ROLE                      AuditEntityType = "role"
+	AUDIT_ENTITY_USER                      AuditEntityType = "user"
 )
 
+// AllPermissions dipakai untuk validasi permission role dan sebagai permission milik admin
+var AllPermissions = []Permission{
+	PERMISSION_CATEGORIES_WRITE,
+	PERMISSION_PRODUCTS_WRITE,
+	PERMISSION_DISCOUNT_COUPONS_WRITE,
+	PERMISSION_DELIVERIES_WRITE,
+	PERMISSION_APPLICATIONS_WRITE,
+	PERMISSION_ORDERS_READ,
+	PERMISSION_ORDERS_UPDATE_STATUS,
+	PERMISSION_ORDERS_REFUND,
+	PERMISSION_CASH_PAYMENTS_CONFIRM,
+	PERMISSION_CASH_COLLECTIONS_RECONCILE,
+	PERMISSION_WALLETS_READ,
+	PERMISSION_WALLETS_APPROVE_WITHDRAW,
+	PERMISSION_WALLETS_ADJUST,
+	PERMISSION_WALLETS_APPROVE_ADJUSTMENT,
+	PERMISSION_WALLETS_FREEZE,
+	PERMISSION_WITHDRAW_POLICIES_WRITE,
+	PERMISSION_BANK_ACCOUNTS_VERIFY,
+	PERMISSION_PAYOUTS_CREATE,
+	PERMISSION_BALANCE_READ,
+	PERMISSION_PAYMENTS_MANAGE,
+	PERMISSION_ROLES_MANAGE,
+	PERMISSION_USERS_READ,
+	PERMISSION_USERS_MANAGE,
+	PERMISSION_AUDIT_LOGS_READ,
+}
+
+// MoneyMovingPermissions memindahkan saldo atau uang keluar, pemegangnya wajib memakai 2FA
+var MoneyMovingPermissions = []Permission{
+	PERMISSION_ORDERS_REFUND,
+	PERMISSION_WALLETS_APPROVE_WITHDRAW,
+	PERMISSION_WALLETS_ADJUST,
+	PERMISSION_WALLETS_APPROVE_ADJUSTMENT,
+	PERMISSION_WALLETS_FREEZE,
+	PERMISSION_PAYOUTS_CREATE,
+}
+
+// AdminOnlyPermissions tidak bisa dipasang ke role staff, hanya dimiliki admin
+var AdminOnlyPermissions = []Permission{
+	PERMISSION_ROLES_MANAGE,
+}
+
 func IsValidChannelCode(pc ChannelCode) bool {
 	switch pc {
-	case XENDIT_QR_DANA_CHANNEL_CODE, XENDIT_QR_LINKAJA_CHANNEL_CODE, XENDIT_EWALLET_LINKAJA_CHANNEL_CODE, XENDIT_EWALLET_DANA_CHANNEL_CODE, XENDIT_EWALLET_OVO_CHANNEL_CODE, XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE, WALLET_CHANNEL_CODE:
+	case XENDIT_QR_DANA_CHANNEL_CODE, XENDIT_QR_LINKAJA_CHANNEL_CODE, XENDIT_EWALLET_LINKAJA_CHANNEL_CODE, XENDIT_EWALLET_DANA_CHANNEL_CODE, XENDIT_EWALLET_OVO_CHANNEL_CODE, XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE, WALLET_CHANNEL_CODE, CASH_CHANNEL_CODE,
+		XENDIT_VA_BCA_CHANNEL_CODE, XENDIT_VA_BNI_CHANNEL_CODE, XENDIT_VA_BRI_CHANNEL_CODE, XENDIT_VA_MANDIRI_CHANNEL_CODE, XENDIT_VA_PERMATA_CHANNEL_CODE,
+		MIDTRANS_QRIS_CHANNEL_CODE, MIDTRANS_GOPAY_CHANNEL_CODE, MIDTRANS_VA_BCA_CHANNEL_CODE, MIDTRANS_VA_BNI_CHANNEL_CODE, MIDTRANS_VA_BRI_CHANNEL_CODE, MIDTRANS_VA_PERMATA_CHANNEL_CODE, MIDTRANS_SNAP_CHANNEL_CODE:
 		return true
 	default:
 		return false
@@ -149,16 +348,61 @@ func IsValidChannelCode(pc ChannelCode) bool {
 
 func IsValidPaymentMethod(pm PaymentMethod) bool {
 	switch pm {
-	case PAYMENT_METHOD_QR_CODE, PAYMENT_METHOD_EWALLET, PAYMENT_METHOD_WALLET:
+	case PAYMENT_METHOD_QR_CODE, PAYMENT_METHOD_EWALLET, PAYMENT_METHOD_WALLET, PAYMENT_METHOD_CASH, PAYMENT_METHOD_VIRTUAL_ACCOUNT, PAYMENT_METHOD_CHECKOUT:
 		return true
 	default:
 		return false
 	}
 }
 
+func IsValidRateLimitKey(key RateLimitKey) bool {
+	switch key {
+	case RATE_LIMIT_KEY_IP, RATE_LIMIT_KEY_USER, RATE_LIMIT_KEY_API_KEY:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidPermission(permission Permission) bool {
+	for _, validPermission := range AllPermissions {
+		if permission == validPermission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsGrantablePermission(permission Permission) bool {
+	if !IsValidPermission(permission) {
+		return false
+	}
+
+	for _, adminOnlyPermission := range AdminOnlyPermissions {
+		if permission == adminOnlyPermission {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GrantablePermissions berisi permission yang boleh dipasang ke role staff
+func GrantablePermissions() []Permission {
+	permissions := []Permission{}
+	for _, permission := range AllPermissions {
+		if IsGrantablePermission(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
+
 func IsValidPaymentGateway(pg PaymentGateway) bool {
 	switch pg {
-	case PAYMENT_GATEWAY_XENDIT, PAYMENT_GATEWAY_SYSTEM:
+	case PAYMENT_GATEWAY_XENDIT, PAYMENT_GATEWAY_SYSTEM, PAYMENT_GATEWAY_SIMULATOR, PAYMENT_GATEWAY_MIDTRANS:
 		return true
 	default:
 		return false
@@ -18,6 +18,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NullStringToString(ns sql.NullString) string {
@@ -214,6 +215,25 @@ func FormatNumberFloat32(n float32) string {
 	return result
 }
 
+var indonesianMobileNumberRegex = regexp.MustCompile(`^\+628[0-9]{7,11}$`)
+
+// NormalizeIndonesianMobileNumber mengubah nomor HP (08xx / 628xx / +628xx) ke format E.164 +628xx
+func NormalizeIndonesianMobileNumber(number string) (string, bool) {
+	normalized := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+	switch {
+	case strings.HasPrefix(normalized, "08"):
+		normalized = "+62" + strings.TrimPrefix(normalized, "0")
+	case strings.HasPrefix(normalized, "62"):
+		normalized = "+" + normalized
+	}
+
+	if !indonesianMobileNumberRegex.MatchString(normalized) {
+		return "", false
+	}
+
+	return normalized, true
+}
+
 type SaveWalletTransactionRequest struct {
 	DB              *gorm.DB
 	UserId          uint64
@@ -252,3 +272,25 @@ func SaveWalletTransaction(walletTransaction *SaveWalletTransactionRequest) erro
 
 	return nil
 }
+
+// CreditWalletBalance menambah saldo wallet. Jika wallet sedang dibekukan dan holdWhenFrozen aktif,
+// saldo tidak ditambahkan dan transaksi harus dicatat sebagai pending sampai wallet diaktifkan kembali.
+// Wallet dibaca ulang dengan row lock dan saldo ditambah langsung di database, sehingga salinan wallet
+// milik pemanggil yang sudah usang tidak menimpa perubahan saldo dari transaksi lain
+func CreditWalletBalance(db *gorm.DB, wallet *entity.Wallet, amount float32, holdWhenFrozen bool) (enum_state.WalletTransactionStatus, error) {
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(wallet).Error; err != nil {
+		return "", err
+	}
+
+	if wallet.Status == enum_state.INACIVE_WALLET && holdWhenFrozen {
+		return enum_state.WALLET_TRANSACTION_STATUS_PENDING, nil
+	}
+
+	newBalance := wallet.Balance + amount
+	if err := db.Model(wallet).Update("balance", gorm.Expr("balance + ?", amount)).Error; err != nil {
+		return "", err
+	}
+	wallet.Balance = newBalance
+
+	return enum_state.WALLET_TRANSACTION_STATUS_COMPLETED, nil
+}
@@ -0,0 +1,42 @@
+package helper_others
+
+import "seblak-bombom-restful-api/internal/helper/enum_state"
+
+type PayoutChannel struct {
+	Code     string                           `json:"code"`
+	Name     string                           `json:"name"`
+	Category enum_state.PayoutChannelCategory `json:"category"`
+}
+
+// daftar channel payout xendit yang didukung, update jika xendit menambah / menghapus channel
+var PayoutChannels = []PayoutChannel{
+	{Code: "ID_BCA", Name: "Bank Central Asia (BCA)", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_BNI", Name: "Bank Negara Indonesia (BNI)", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_BRI", Name: "Bank Rakyat Indonesia (BRI)", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_MANDIRI", Name: "Bank Mandiri", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_PERMATA", Name: "Bank Permata", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_CIMB", Name: "Bank CIMB Niaga", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_BSI", Name: "Bank Syariah Indonesia (BSI)", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_DANAMON", Name: "Bank Danamon", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_BTN", Name: "Bank Tabungan Negara (BTN)", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_MAYBANK", Name: "Bank Maybank", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_OCBC", Name: "Bank OCBC NISP", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_PANIN", Name: "Bank Panin", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_BJB", Name: "Bank BJB", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_JAGO", Name: "Bank Jago", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_SEABANK", Name: "SeaBank", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_BANK},
+	{Code: "ID_OVO", Name: "OVO", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_EWALLET},
+	{Code: "ID_DANA", Name: "DANA", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_EWALLET},
+	{Code: "ID_GOPAY", Name: "GoPay", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_EWALLET},
+	{Code: "ID_SHOPEEPAY", Name: "ShopeePay", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_EWALLET},
+	{Code: "ID_LINKAJA", Name: "LinkAja", Category: enum_state.PAYOUT_CHANNEL_CATEGORY_EWALLET},
+}
+
+func FindPayoutChannel(code string) (*PayoutChannel, bool) {
+	for _, channel := range PayoutChannels {
+		if channel.Code == code {
+			return &channel, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,56 @@
+package helper_others
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+)
+
+type VirtualAccountBank struct {
+	Code string
+	Name string
+	// menu pada mobile banking untuk membayar virtual account
+	MobileMenuEN string
+	MobileMenuID string
+}
+
+// daftar bank virtual account xendit yang didukung, code mengikuti channel code virtual account xendit
+var VirtualAccountBanks = []VirtualAccountBank{
+	{Code: "BCA", Name: "Bank Central Asia (BCA)", MobileMenuEN: "BCA mobile > m-Transfer > BCA Virtual Account", MobileMenuID: "BCA mobile > m-Transfer > BCA Virtual Account"},
+	{Code: "BNI", Name: "Bank Negara Indonesia (BNI)", MobileMenuEN: "BNI Mobile Banking > Transfer > Virtual Account Billing", MobileMenuID: "BNI Mobile Banking > Transfer > Virtual Account Billing"},
+	{Code: "BRI", Name: "Bank Rakyat Indonesia (BRI)", MobileMenuEN: "BRImo > Payment > BRIVA", MobileMenuID: "BRImo > Pembayaran > BRIVA"},
+	{Code: "MANDIRI", Name: "Bank Mandiri", MobileMenuEN: "Livin' by Mandiri > Pay > Virtual Account", MobileMenuID: "Livin' by Mandiri > Bayar > Virtual Account"},
+	{Code: "PERMATA", Name: "Bank Permata", MobileMenuEN: "PermataMobile X > Pay Bills > Virtual Account", MobileMenuID: "PermataMobile X > Bayar Tagihan > Virtual Account"},
+}
+
+func FindVirtualAccountBank(code string) (*VirtualAccountBank, bool) {
+	for _, bank := range VirtualAccountBanks {
+		if bank.Code == code {
+			return &bank, true
+		}
+	}
+	return nil, false
+}
+
+// VirtualAccountInstructions membuat langkah pembayaran virtual account sesuai bank dan bahasa customer
+func VirtualAccountInstructions(code string, number string, lang enum_state.Languange) []string {
+	bank, ok := FindVirtualAccountBank(code)
+	if !ok || number == "" {
+		return nil
+	}
+
+	if lang == enum_state.INDONESIA {
+		return []string{
+			fmt.Sprintf("Buka menu %s atau pilih Transfer ke Virtual Account %s di ATM", bank.MobileMenuID, bank.Name),
+			fmt.Sprintf("Masukkan nomor virtual account %s", number),
+			"Pastikan nama dan nominal tagihan sesuai, nominal harus dibayar tepat tanpa dibulatkan",
+			"Konfirmasi pembayaran dengan PIN sebelum virtual account kadaluwarsa",
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("Open %s or choose Transfer to %s Virtual Account at an ATM", bank.MobileMenuEN, bank.Name),
+		fmt.Sprintf("Enter virtual account number %s", number),
+		"Make sure the name and bill amount are correct, the amount must be paid exactly without rounding",
+		"Confirm the payment with your PIN before the virtual account expires",
+	}
+}
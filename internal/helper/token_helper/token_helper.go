@@ -0,0 +1,193 @@
+package token_helper
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("token is malformed or has invalid signature")
+	ErrExpiredToken = errors.New("token is expired")
+)
+
+// AccessTokenClaims adalah isi JWT access token, sid menunjuk ke family refresh token / sesi login
+type AccessTokenClaims struct {
+	Subject   uint64 `json:"sub"`
+	SessionId string `json:"sid"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TwoFactorChallengeClaims dipakai di antara login password dan langkah kedua (kode 2FA),
+// ditandatangani dengan secret berbeda sehingga tidak bisa dipakai sebagai access token
+type TwoFactorChallengeClaims struct {
+	Subject   uint64 `json:"sub"`
+	Remember  bool   `json:"remember"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// OIDCStateClaims adalah parameter state login OpenID Connect, state ditandatangani sehingga tidak perlu
+// disimpan di server, code verifier PKCE diturunkan dari nonce dan tidak pernah dikirim ke browser
+type OIDCStateClaims struct {
+	Provider  string `json:"provider"`
+	Nonce     string `json:"nonce"`
+	Remember  bool   `json:"remember"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// GenerateAccessToken membuat JWT HS256 yang ditandatangani dengan secret
+func GenerateAccessToken(secret string, claims *AccessTokenClaims) (string, error) {
+	return encode(secret, claims)
+}
+
+// ParseAccessToken memverifikasi tanda tangan dan masa berlaku JWT tanpa query ke database
+func ParseAccessToken(secret string, token string) (*AccessTokenClaims, error) {
+	claims := new(AccessTokenClaims)
+	if err := decode(secret, token, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+func GenerateTwoFactorChallenge(secret string, claims *TwoFactorChallengeClaims) (string, error) {
+	return encode(twoFactorChallengeSecret(secret), claims)
+}
+
+func ParseTwoFactorChallenge(secret string, token string) (*TwoFactorChallengeClaims, error) {
+	claims := new(TwoFactorChallengeClaims)
+	if err := decode(twoFactorChallengeSecret(secret), token, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+func GenerateOIDCState(secret string, claims *OIDCStateClaims) (string, error) {
+	return encode(secret+":oidc-state", claims)
+}
+
+func ParseOIDCState(secret string, token string) (*OIDCStateClaims, error) {
+	claims := new(OIDCStateClaims)
+	if err := decode(secret+":oidc-state", token, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Provider == "" || claims.Nonce == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+// GenerateOIDCNonce membuat nonce acak untuk satu kali login OpenID Connect
+func GenerateOIDCNonce() (string, error) {
+	buffer := make([]byte, 24)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// OIDCCodeVerifier menurunkan code verifier PKCE (43 karakter) dari nonce state
+func OIDCCodeVerifier(secret string, nonce string) string {
+	return sign(secret+":oidc-pkce", nonce)
+}
+
+// OIDCCodeChallenge adalah code challenge PKCE metode S256
+func OIDCCodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func twoFactorChallengeSecret(secret string) string {
+	return secret + ":two-factor-challenge"
+}
+
+func encode(secret string, claims any) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + sign(secret, unsigned), nil
+}
+
+func decode(secret string, token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return ErrInvalidToken
+	}
+
+	expected := sign(secret, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// GenerateRefreshToken membuat refresh token acak, yang disimpan ke database hanya hash nya
+func GenerateRefreshToken() (string, string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buffer)
+	return token, HashRefreshToken(token), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sign(secret string, unsigned string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+type PaymentGateway interface {
+	Name() enum_state.PaymentGateway
+	CreatePayment(ctx context.Context, request *model.CreatePaymentGatewayRequest) (*model.PaymentGatewayResponse, error)
+	GetPaymentStatus(ctx context.Context, paymentId string) (*model.PaymentGatewayResponse, error)
+	CancelPayment(ctx context.Context, payment *model.PaymentGatewayResponse) (*model.PaymentGatewayResponse, error)
+	RefundPayment(ctx context.Context, request *model.RefundPaymentGatewayRequest) (*model.RefundPaymentGatewayResponse, error)
+	ParseWebhook(rawBody []byte) (*model.PaymentGatewayWebhook, error)
+}
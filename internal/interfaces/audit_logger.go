@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogger mencatat perubahan data ke audit log, dipakai use case di luar package usecase
+// (contoh: xendit) agar tidak terjadi import cycle
+type AuditLogger interface {
+	Snapshot(tx *gorm.DB, value any) map[string]any
+	Record(tx *gorm.DB, action enum_state.AuditAction, entityType enum_state.AuditEntityType, entityId any, before map[string]any, after map[string]any) error
+}
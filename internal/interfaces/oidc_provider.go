@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+type OIDCProvider interface {
+	Name() string
+	AuthorizationURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// Exchange menukar authorization code dengan ID token lalu memverifikasi tanda tangan, issuer, audience dan nonce
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*model.OIDCIdentity, error)
+}
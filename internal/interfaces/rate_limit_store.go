@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+// RateLimitStore menyimpan token bucket rate limit, implementasi in-memory hanya berlaku untuk satu instance
+// sehingga untuk beberapa instance perlu store bersama (misalnya redis) dengan interface yang sama
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy model.RateLimitPolicy) (*model.RateLimitResult, error)
+}
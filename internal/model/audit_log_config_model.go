@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type AuditLogConfig struct {
+	RetentionDays   int           `json:"retention_days"`
+	CleanupInterval time.Duration `json:"cleanup_interval"`
+}
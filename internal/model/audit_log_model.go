@@ -0,0 +1,26 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+// AuditChange nilai satu kolom sebelum dan sesudah perubahan, nil berarti belum / sudah tidak ada
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditLogResponse struct {
+	ID         uint64                     `json:"id"`
+	ActorId    *uint64                    `json:"actor_id"`
+	ActorEmail string                     `json:"actor_email"`
+	ActorRole  enum_state.Role            `json:"actor_role"`
+	Action     enum_state.AuditAction     `json:"action"`
+	EntityType enum_state.AuditEntityType `json:"entity_type"`
+	EntityId   string                     `json:"entity_id"`
+	Changes    map[string]AuditChange     `json:"changes"`
+	IPAddress  string                     `json:"ip_address"`
+	RequestId  string                     `json:"request_id"`
+	CreatedAt  helper_others.TimeRFC3339  `json:"created_at"`
+}
@@ -1,5 +1,22 @@
 package model
 
+import "time"
+
 type AuthConfig struct {
-	AdminCreationKey string `json:"admin_creation_key"`
+	AdminCreationKey  string        `json:"admin_creation_key"`
+	AccessTokenSecret string        `json:"-"`
+	AccessTokenTTL    time.Duration `json:"access_token_ttl"`
+	RefreshTokenTTL   time.Duration `json:"refresh_token_ttl"`
+	// batas login gagal sebelum akun / alamat IP dikunci sementara
+	LoginMaxFailedAttempts   int           `json:"login_max_failed_attempts"`
+	LoginIPMaxFailedAttempts int           `json:"login_ip_max_failed_attempts"`
+	LoginLockoutDuration     time.Duration `json:"login_lockout_duration"`
+	PasswordResetMaxAttempts int           `json:"password_reset_max_attempts"`
+	// TOTP 2FA
+	TwoFactorIssuer           string        `json:"two_factor_issuer"`
+	TwoFactorRequiredForAdmin bool          `json:"two_factor_required_for_admin"`
+	TwoFactorChallengeTTL     time.Duration `json:"two_factor_challenge_ttl"`
+	TwoFactorFreshDuration    time.Duration `json:"two_factor_fresh_duration"`
+	// batas waktu menyelesaikan login OpenID Connect di halaman provider
+	OIDCStateTTL time.Duration `json:"oidc_state_ttl"`
 }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type BankAccountResponse struct {
+	ID                 uint64                                   `json:"id"`
+	UserId             uint64                                   `json:"user_id"`
+	ChannelCode        string                                   `json:"channel_code"`
+	ChannelName        string                                   `json:"channel_name"`
+	ChannelCategory    enum_state.PayoutChannelCategory         `json:"channel_category"`
+	AccountNumber      string                                   `json:"account_number"`
+	AccountHolderName  string                                   `json:"account_holder_name"`
+	VerificationStatus enum_state.BankAccountVerificationStatus `json:"verification_status"`
+	VerificationNotes  string                                   `json:"verification_notes"`
+	VerifiedAt         helper_others.TimeRFC3339                `json:"verified_at"`
+	CreatedAt          helper_others.TimeRFC3339                `json:"created_at"`
+	UpdatedAt          helper_others.TimeRFC3339                `json:"updated_at"`
+}
+
+type CreateBankAccountRequest struct {
+	UserId            uint64 `json:"-" validate:"required"`
+	ChannelCode       string `json:"channel_code" validate:"required,max=50"`
+	AccountNumber     string `json:"account_number" validate:"required,numeric,max=50"`
+	AccountHolderName string `json:"account_holder_name" validate:"required,max=100"`
+}
+
+type DeleteBankAccountRequest struct {
+	ID     uint64 `json:"-" validate:"required"`
+	UserId uint64 `json:"-" validate:"required"`
+}
+
+type VerifyBankAccountRequest struct {
+	ID                uint64                                   `json:"-" validate:"required"`
+	Status            enum_state.BankAccountVerificationStatus `json:"status" validate:"required"`
+	VerificationNotes string                                   `json:"verification_notes" validate:"max=500"`
+	CurrentAdminId    uint64                                   `json:"-" validate:"required"`
+}
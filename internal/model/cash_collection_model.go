@@ -0,0 +1,68 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"time"
+)
+
+type CashCollectionResponse struct {
+	ID             uint64                     `json:"id"`
+	OrderId        uint64                     `json:"order_id"`
+	Invoice        string                     `json:"invoice,omitempty"`
+	CollectedBy    uint64                     `json:"collected_by"`
+	CollectorName  string                     `json:"collector_name,omitempty"`
+	Amount         float32                    `json:"amount"`
+	ReceivedAmount float32                    `json:"received_amount"`
+	ChangeAmount   float32                    `json:"change_amount"`
+	Note           string                     `json:"note"`
+	CollectedAt    helper_others.TimeRFC3339  `json:"collected_at"`
+	ReconciledBy   *uint64                    `json:"reconciled_by"`
+	ReconciledAt   *helper_others.TimeRFC3339 `json:"reconciled_at"`
+	CreatedAt      helper_others.TimeRFC3339  `json:"created_at"`
+	UpdatedAt      helper_others.TimeRFC3339  `json:"updated_at"`
+}
+
+type ConfirmCashPaymentRequest struct {
+	OrderId               uint64               `json:"-" validate:"required"`
+	ReceivedAmount        float32              `json:"received_amount" validate:"gte=0"` // 0 = uang pas
+	Note                  string               `json:"note" validate:"max=255"`
+	CurrentUserId         uint64               `json:"-" validate:"required"`
+	CurrentUserRole       enum_state.Role      `json:"-" validate:"required"`
+	CurrentUserCanConfirm bool                 `json:"-"`
+	Lang                  enum_state.Languange `json:"-"`
+	TimeZone              time.Location        `json:"-"`
+	BaseFrontEndURL       string               `json:"-"`
+}
+
+// rekap kas per staff untuk satu hari, dipakai saat rekonsiliasi kas akhir hari
+type CashStaffSummaryResponse struct {
+	CollectedBy        uint64  `json:"collected_by"`
+	CollectorName      string  `json:"collector_name"`
+	TotalCollections   int     `json:"total_collections"`
+	TotalAmount        float32 `json:"total_amount"`
+	ReconciledAmount   float32 `json:"reconciled_amount"`
+	UnreconciledAmount float32 `json:"unreconciled_amount"`
+}
+
+type CashReconciliationResponse struct {
+	Date               string                     `json:"date"`
+	TotalCollections   int                        `json:"total_collections"`
+	TotalAmount        float32                    `json:"total_amount"`
+	UnreconciledAmount float32                    `json:"unreconciled_amount"`
+	Staffs             []CashStaffSummaryResponse `json:"staffs"`
+	Collections        []CashCollectionResponse   `json:"collections"`
+}
+
+type GetCashReconciliationRequest struct {
+	Date        string        `json:"-" validate:"required,datetime=2006-01-02"`
+	CollectedBy uint64        `json:"-"`
+	TimeZone    time.Location `json:"-"`
+}
+
+type ReconcileCashRequest struct {
+	Date           string        `json:"date" validate:"required,datetime=2006-01-02"`
+	CollectedBy    uint64        `json:"collected_by" validate:"required"`
+	CurrentAdminId uint64        `json:"-" validate:"required"`
+	TimeZone       time.Location `json:"-"`
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"encoding/json"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func AuditLogToResponse(auditLog *entity.AuditLog) *model.AuditLogResponse {
+	response := &model.AuditLogResponse{
+		ID:         auditLog.ID,
+		ActorId:    auditLog.ActorId,
+		ActorEmail: auditLog.ActorEmail,
+		ActorRole:  auditLog.ActorRole,
+		Action:     auditLog.Action,
+		EntityType: auditLog.EntityType,
+		EntityId:   auditLog.EntityId,
+		Changes:    map[string]model.AuditChange{},
+		IPAddress:  auditLog.IPAddress,
+		RequestId:  auditLog.RequestId,
+		CreatedAt:  helper_others.TimeRFC3339(auditLog.CreatedAt),
+	}
+
+	if auditLog.Changes != nil {
+		// changes selalu ditulis oleh aplikasi, data rusak cukup ditampilkan kosong
+		_ = json.Unmarshal([]byte(*auditLog.Changes), &response.Changes)
+	}
+
+	return response
+}
+
+func AuditLogsToResponse(auditLogs *[]entity.AuditLog) *[]model.AuditLogResponse {
+	getAuditLogs := make([]model.AuditLogResponse, len(*auditLogs))
+	for i, auditLog := range *auditLogs {
+		getAuditLogs[i] = *AuditLogToResponse(&auditLog)
+	}
+	return &getAuditLogs
+}
@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func BankAccountToResponse(bankAccount *entity.BankAccount) *model.BankAccountResponse {
+	response := &model.BankAccountResponse{
+		ID:                 bankAccount.ID,
+		UserId:             bankAccount.UserId,
+		ChannelCode:        bankAccount.ChannelCode,
+		AccountNumber:      bankAccount.AccountNumber,
+		AccountHolderName:  bankAccount.AccountHolderName,
+		VerificationStatus: bankAccount.VerificationStatus,
+		VerificationNotes:  bankAccount.VerificationNotes,
+		CreatedAt:          helper_others.TimeRFC3339(bankAccount.CreatedAt),
+		UpdatedAt:          helper_others.TimeRFC3339(bankAccount.UpdatedAt),
+	}
+
+	if channel, ok := helper_others.FindPayoutChannel(bankAccount.ChannelCode); ok {
+		response.ChannelName = channel.Name
+		response.ChannelCategory = channel.Category
+	}
+
+	if bankAccount.VerifiedAt != nil {
+		response.VerifiedAt = helper_others.TimeRFC3339(*bankAccount.VerifiedAt)
+	}
+
+	return response
+}
+
+func BankAccountsToResponse(bankAccounts *[]entity.BankAccount) *[]model.BankAccountResponse {
+	getBankAccounts := make([]model.BankAccountResponse, len(*bankAccounts))
+	for i, bankAccount := range *bankAccounts {
+		getBankAccounts[i] = *BankAccountToResponse(&bankAccount)
+	}
+	return &getBankAccounts
+}
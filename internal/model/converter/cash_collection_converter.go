@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+)
+
+func CashCollectionToResponse(cashCollection *entity.CashCollection) *model.CashCollectionResponse {
+	response := &model.CashCollectionResponse{
+		ID:             cashCollection.ID,
+		OrderId:        cashCollection.OrderId,
+		CollectedBy:    cashCollection.CollectedBy,
+		Amount:         cashCollection.Amount,
+		ReceivedAmount: cashCollection.ReceivedAmount,
+		ChangeAmount:   cashCollection.ChangeAmount,
+		Note:           cashCollection.Note,
+		CollectedAt:    helper_others.TimeRFC3339(cashCollection.CollectedAt),
+		ReconciledBy:   cashCollection.ReconciledBy,
+		CreatedAt:      helper_others.TimeRFC3339(cashCollection.CreatedAt),
+		UpdatedAt:      helper_others.TimeRFC3339(cashCollection.UpdatedAt),
+	}
+
+	if cashCollection.ReconciledAt != nil {
+		reconciledAt := helper_others.TimeRFC3339(*cashCollection.ReconciledAt)
+		response.ReconciledAt = &reconciledAt
+	}
+
+	if cashCollection.Order != nil {
+		response.Invoice = cashCollection.Order.Invoice
+	}
+
+	if cashCollection.Collector != nil {
+		response.CollectorName = strings.TrimSpace(cashCollection.Collector.Name.FirstName + " " + cashCollection.Collector.Name.LastName)
+	}
+
+	return response
+}
+
+func CashCollectionsToResponse(cashCollections *[]entity.CashCollection) []model.CashCollectionResponse {
+	responses := make([]model.CashCollectionResponse, len(*cashCollections))
+	for i, cashCollection := range *cashCollections {
+		responses[i] = *CashCollectionToResponse(&cashCollection)
+	}
+
+	return responses
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+)
+
+func MidtransTransactionToResponse(midtransTransaction *entity.MidtransTransaction) *model.MidtransTransactionResponse {
+	response := &model.MidtransTransactionResponse{
+		ID:                midtransTransaction.ID,
+		OrderId:           midtransTransaction.OrderId,
+		MidtransOrderId:   midtransTransaction.MidtransOrderId,
+		TransactionId:     midtransTransaction.TransactionId,
+		ApiType:           midtransTransaction.ApiType,
+		PaymentType:       midtransTransaction.PaymentType,
+		ChannelCode:       midtransTransaction.ChannelCode,
+		GrossAmount:       midtransTransaction.GrossAmount,
+		Currency:          midtransTransaction.Currency,
+		TransactionStatus: midtransTransaction.TransactionStatus,
+		FraudStatus:       midtransTransaction.FraudStatus,
+		PaymentStatus:     payment_gateway.MidtransPaymentStatus(midtransTransaction.TransactionStatus, midtransTransaction.FraudStatus),
+		VaBank:            midtransTransaction.VaBank,
+		VaNumber:          midtransTransaction.VaNumber,
+		QrString:          midtransTransaction.QrString,
+		DeeplinkUrl:       midtransTransaction.DeeplinkUrl,
+		SnapToken:         midtransTransaction.SnapToken,
+		RedirectUrl:       midtransTransaction.RedirectUrl,
+		CreatedAt:         helper_others.TimeRFC3339(midtransTransaction.CreatedAt),
+		UpdatedAt:         helper_others.TimeRFC3339(midtransTransaction.UpdatedAt),
+	}
+
+	if midtransTransaction.ExpiresAt != nil {
+		response.ExpiresAt = helper_others.TimeRFC3339(*midtransTransaction.ExpiresAt)
+	}
+
+	return response
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
 	"seblak-bombom-restful-api/internal/model"
 )
@@ -39,6 +40,37 @@ func OrderToResponse(order *entity.Order) *model.OrderResponse {
 		response.XenditTransaction = XenditTransactionToResponse(*order.XenditTransaction)
 	}
 
+	if order.MidtransTransaction != nil {
+		response.MidtransTransaction = MidtransTransactionToResponse(order.MidtransTransaction)
+	}
+
+	for _, paymentLeg := range order.PaymentLegs {
+		paymentLegResponse := model.OrderPaymentLegResponse{
+			ID:             paymentLeg.ID,
+			Source:         paymentLeg.Source,
+			PaymentGateway: paymentLeg.PaymentGateway,
+			PaymentMethod:  paymentLeg.PaymentMethod,
+			ChannelCode:    paymentLeg.ChannelCode,
+			Amount:         paymentLeg.Amount,
+			Status:         paymentLeg.Status,
+			CreatedAt:      helper_others.TimeRFC3339(paymentLeg.CreatedAt),
+			UpdatedAt:      helper_others.TimeRFC3339(paymentLeg.UpdatedAt),
+		}
+		if paymentLeg.XenditTransactionId != nil {
+			paymentLegResponse.XenditTransactionId = *paymentLeg.XenditTransactionId
+		}
+		response.PaymentLegs = append(response.PaymentLegs, paymentLegResponse)
+	}
+
+	if len(order.Refunds) > 0 {
+		response.Refunds = OrderRefundsToResponse(&order.Refunds)
+		for _, refund := range order.Refunds {
+			if refund.Status != enum_state.REFUND_STATUS_FAILED {
+				response.TotalRefunded += refund.Amount
+			}
+		}
+	}
+
 	return response
 }
 
@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+)
+
+func OrderRefundToResponse(orderRefund *entity.OrderRefund) *model.OrderRefundResponse {
+	response := &model.OrderRefundResponse{
+		ID:             orderRefund.ID,
+		OrderId:        orderRefund.OrderId,
+		RefundedBy:     orderRefund.RefundedBy,
+		ReferenceId:    orderRefund.ReferenceId,
+		Type:           orderRefund.Type,
+		Destination:    orderRefund.Destination,
+		Amount:         orderRefund.Amount,
+		Reason:         orderRefund.Reason,
+		Status:         orderRefund.Status,
+		PaymentGateway: orderRefund.PaymentGateway,
+		FailureCode:    orderRefund.FailureCode,
+		CreatedAt:      helper_others.TimeRFC3339(orderRefund.CreatedAt),
+		UpdatedAt:      helper_others.TimeRFC3339(orderRefund.UpdatedAt),
+		Items:          []model.OrderRefundItemResponse{},
+	}
+
+	if orderRefund.GatewayRefundId != nil {
+		response.GatewayRefundId = *orderRefund.GatewayRefundId
+	}
+
+	if orderRefund.ProcessedAt != nil {
+		processedAt := helper_others.TimeRFC3339(*orderRefund.ProcessedAt)
+		response.ProcessedAt = &processedAt
+	}
+
+	if orderRefund.Order != nil {
+		response.Invoice = orderRefund.Order.Invoice
+	}
+
+	if orderRefund.Refunder != nil {
+		response.RefunderName = strings.TrimSpace(orderRefund.Refunder.Name.FirstName + " " + orderRefund.Refunder.Name.LastName)
+	}
+
+	for _, item := range orderRefund.Items {
+		response.Items = append(response.Items, model.OrderRefundItemResponse{
+			ID:             item.ID,
+			OrderProductId: item.OrderProductId,
+			Quantity:       item.Quantity,
+			Amount:         item.Amount,
+		})
+	}
+
+	return response
+}
+
+func OrderRefundsToResponse(orderRefunds *[]entity.OrderRefund) []model.OrderRefundResponse {
+	responses := make([]model.OrderRefundResponse, len(*orderRefunds))
+	for i := range *orderRefunds {
+		responses[i] = *OrderRefundToResponse(&(*orderRefunds)[i])
+	}
+	return responses
+}
@@ -10,6 +10,7 @@ func PayoutToResponse(payout *entity.Payout) *model.PayoutResponse {
 	response := &model.PayoutResponse{
 		ID:             payout.ID,
 		XenditPayoutId: helper_others.NullStringToString(payout.XenditPayoutId),
+		BankAccountId:  payout.BankAccountId,
 		Amount:         payout.Amount,
 		Currency:       payout.Currency,
 		Method:         payout.Method,
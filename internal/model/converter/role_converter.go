@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func RoleToResponse(role *entity.Role) *model.RoleResponse {
+	permissions := make([]enum_state.Permission, len(role.Permissions))
+	for i, rolePermission := range role.Permissions {
+		permissions[i] = enum_state.Permission(rolePermission.Permission)
+	}
+
+	return &model.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   helper_others.TimeRFC3339(role.CreatedAt),
+		UpdatedAt:   helper_others.TimeRFC3339(role.UpdatedAt),
+	}
+}
+
+func RolesToResponse(roles *[]entity.Role) *[]model.RoleResponse {
+	getRoles := make([]model.RoleResponse, len(*roles))
+	for i, role := range *roles {
+		getRoles[i] = *RoleToResponse(&role)
+	}
+	return &getRoles
+}
@@ -2,8 +2,10 @@ package converter
 
 import (
 	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
 	"seblak-bombom-restful-api/internal/model"
+	"time"
 )
 
 func UserToResponse(user *entity.User) *model.UserResponse {
@@ -23,6 +25,20 @@ func UserToResponse(user *entity.User) *model.UserResponse {
 		UserProfile: user.UserProfile,
 		CreatedAt:   helper_others.TimeRFC3339(user.CreatedAt),
 		UpdatedAt:   helper_others.TimeRFC3339(user.UpdatedAt),
+		// 2FA aktif hanya setelah kode pertama berhasil diverifikasi
+		TwoFactorEnabled: user.TwoFactorEnabledAt != nil,
+		EmailVerified:    user.EmailVerified,
+		SuspendedReason:  user.SuspendedReason,
+	}
+
+	if user.SuspendedAt != nil {
+		suspendedAt := helper_others.TimeRFC3339(*user.SuspendedAt)
+		response.SuspendedAt = &suspendedAt
+	}
+
+	if user.DeletedAt.Valid {
+		deletedAt := helper_others.TimeRFC3339(user.DeletedAt.Time)
+		response.DeletedAt = &deletedAt
 	}
 
 	if user.Wallet != nil {
@@ -33,14 +49,42 @@ func UserToResponse(user *entity.User) *model.UserResponse {
 		response.Cart = *CartToResponse(user.Cart)
 	}
 
+	if user.StaffRole != nil {
+		response.StaffRole = RoleToResponse(user.StaffRole)
+	}
+
+	// admin memiliki semua permission, staff / kurir mengikuti role yang dipasang
+	response.Permissions = []enum_state.Permission{}
+	if user.Role == enum_state.ADMIN {
+		response.Permissions = enum_state.AllPermissions
+	} else if response.StaffRole != nil {
+		// permission khusus admin yang terlanjur tersimpan di role staff diabaikan
+		for _, permission := range response.StaffRole.Permissions {
+			if enum_state.IsGrantablePermission(permission) {
+				response.Permissions = append(response.Permissions, permission)
+			}
+		}
+	}
+
 	return response
 }
 
-func UserTokenToResponse(token *entity.Token) *model.UserTokenResponse {
+func UsersToResponse(users *[]entity.User) *[]model.UserResponse {
+	getUsers := make([]model.UserResponse, len(*users))
+	for i, user := range *users {
+		getUsers[i] = *UserToResponse(&user)
+	}
+	return &getUsers
+}
+
+// UserTokenToResponse memakai token asli karena entity token hanya menyimpan hash refresh token
+func UserTokenToResponse(token *entity.Token, accessToken string, accessTokenExpiryDate time.Time, refreshToken string) *model.UserTokenResponse {
 	return &model.UserTokenResponse{
-		Token:      token.Token,
-		ExpiryDate: token.ExpiryDate,
-		CreatedAt:  helper_others.TimeRFC3339(token.CreatedAt),
-		UpdatedAt:  helper_others.TimeRFC3339(token.UpdatedAt),
+		Token:                  accessToken,
+		ExpiryDate:             accessTokenExpiryDate,
+		RefreshToken:           refreshToken,
+		RefreshTokenExpiryDate: token.ExpiryDate,
+		CreatedAt:              helper_others.TimeRFC3339(token.CreatedAt),
+		UpdatedAt:              helper_others.TimeRFC3339(token.UpdatedAt),
 	}
 }
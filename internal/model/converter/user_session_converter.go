@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func UserSessionToResponse(session *entity.UserSession, currentSessionId string) *model.UserSessionResponse {
+	return &model.UserSessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		IsCurrent:  session.ID == currentSessionId,
+		LastSeenAt: helper_others.TimeRFC3339(session.LastSeenAt),
+		ExpiresAt:  helper_others.TimeRFC3339(session.ExpiresAt),
+		CreatedAt:  helper_others.TimeRFC3339(session.CreatedAt),
+	}
+}
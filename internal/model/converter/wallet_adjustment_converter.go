@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func WalletAdjustmentToResponse(walletAdjustment *entity.WalletAdjustmentRequests) *model.WalletAdjustmentResponse {
+	response := &model.WalletAdjustmentResponse{
+		ID:             walletAdjustment.ID,
+		UserId:         walletAdjustment.UserId,
+		Amount:         walletAdjustment.Amount,
+		Reason:         walletAdjustment.Reason,
+		Status:         walletAdjustment.Status,
+		RequestedBy:    walletAdjustment.RequestedBy,
+		ProcessedBy:    walletAdjustment.ProcessedBy,
+		RejectionNotes: walletAdjustment.RejectionNotes,
+		CreatedAt:      helper_others.TimeRFC3339(walletAdjustment.CreatedAt),
+		UpdatedAt:      helper_others.TimeRFC3339(walletAdjustment.UpdatedAt),
+	}
+
+	if walletAdjustment.User != nil {
+		response.User = *UserToResponse(walletAdjustment.User)
+	}
+
+	if walletAdjustment.ProcessedAt != nil {
+		response.ProcessedAt = helper_others.TimeRFC3339(*walletAdjustment.ProcessedAt)
+	}
+
+	return response
+}
@@ -8,10 +8,11 @@ import (
 
 func WalletToResponse(wallet *entity.Wallet) *model.WalletResponse {
 	return &model.WalletResponse{
-		ID:        wallet.ID,
-		Balance:   wallet.Balance,
-		Status:    wallet.Status,
-		CreatedAt: helper_others.TimeRFC3339(wallet.CreatedAt),
-		UpdatedAt: helper_others.TimeRFC3339(wallet.UpdatedAt),
+		ID:           wallet.ID,
+		Balance:      wallet.Balance,
+		Status:       wallet.Status,
+		StatusReason: wallet.StatusReason,
+		CreatedAt:    helper_others.TimeRFC3339(wallet.CreatedAt),
+		UpdatedAt:    helper_others.TimeRFC3339(wallet.UpdatedAt),
 	}
 }
@@ -12,7 +12,10 @@ func WalletWithdrawToResponse(walletWithdrawRequest *entity.WalletWithdrawReques
 		UserId:            walletWithdrawRequest.UserId,
 		User:              *UserToResponse(walletWithdrawRequest.User),
 		Amount:            walletWithdrawRequest.Amount,
+		Fee:               walletWithdrawRequest.Fee,
 		Method:            walletWithdrawRequest.Method,
+		BankAccountId:     walletWithdrawRequest.BankAccountId,
+		ChannelCode:       walletWithdrawRequest.ChannelCode,
 		BankName:          walletWithdrawRequest.BankName,
 		BankAccountNumber: walletWithdrawRequest.BankAcountNumber,
 		BankAccountName:   walletWithdrawRequest.BankAcountName,
@@ -31,6 +34,10 @@ func WalletWithdrawToResponse(walletWithdrawRequest *entity.WalletWithdrawReques
 		response.ProcessedAt = helper_others.TimeRFC3339(*walletWithdrawRequest.ProcessedAt)
 	}
 
+	if walletWithdrawRequest.XenditPayoutId != nil {
+		response.XenditPayoutId = *walletWithdrawRequest.XenditPayoutId
+	}
+
 	return response
 }
 
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func WithdrawPolicyToResponse(withdrawPolicy *entity.WithdrawPolicy) *model.WithdrawPolicyResponse {
+	return &model.WithdrawPolicyResponse{
+		ID:              withdrawPolicy.ID,
+		Method:          withdrawPolicy.Method,
+		MinAmount:       withdrawPolicy.MinAmount,
+		FeeType:         withdrawPolicy.FeeType,
+		FeeValue:        withdrawPolicy.FeeValue,
+		DailyLimit:      withdrawPolicy.DailyLimit,
+		MonthlyLimit:    withdrawPolicy.MonthlyLimit,
+		CoolingOffHours: withdrawPolicy.CoolingOffHours,
+		UpdatedBy:       withdrawPolicy.UpdatedBy,
+		CreatedAt:       helper_others.TimeRFC3339(withdrawPolicy.CreatedAt),
+		UpdatedAt:       helper_others.TimeRFC3339(withdrawPolicy.UpdatedAt),
+	}
+}
+
+func WithdrawPoliciesToResponse(withdrawPolicies *[]entity.WithdrawPolicy) *[]model.WithdrawPolicyResponse {
+	getWithdrawPolicies := make([]model.WithdrawPolicyResponse, len(*withdrawPolicies))
+	for i, withdrawPolicy := range *withdrawPolicies {
+		getWithdrawPolicies[i] = *WithdrawPolicyToResponse(&withdrawPolicy)
+	}
+	return &getWithdrawPolicies
+}
@@ -0,0 +1,37 @@
+package converter
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+)
+
+func XenditWebhookEventToResponse(webhookEvent *entity.XenditWebhookEvent) *model.XenditWebhookEventResponse {
+	response := &model.XenditWebhookEventResponse{
+		ID:          webhookEvent.ID,
+		EventId:     webhookEvent.EventId,
+		Gateway:     webhookEvent.Gateway,
+		WebhookType: webhookEvent.WebhookType,
+		EventType:   webhookEvent.EventType,
+		RawBody:     webhookEvent.RawBody,
+		Status:      webhookEvent.Status,
+		Attempts:    webhookEvent.Attempts,
+		LastError:   webhookEvent.LastError,
+		CreatedAt:   helper_others.TimeRFC3339(webhookEvent.CreatedAt),
+		UpdatedAt:   helper_others.TimeRFC3339(webhookEvent.UpdatedAt),
+	}
+
+	if webhookEvent.ProcessedAt != nil {
+		response.ProcessedAt = helper_others.TimeRFC3339(*webhookEvent.ProcessedAt)
+	}
+
+	return response
+}
+
+func XenditWebhookEventsToResponse(webhookEvents *[]entity.XenditWebhookEvent) *[]model.XenditWebhookEventResponse {
+	getWebhookEvents := make([]model.XenditWebhookEventResponse, len(*webhookEvents))
+	for i, webhookEvent := range *webhookEvents {
+		getWebhookEvents[i] = *XenditWebhookEventToResponse(&webhookEvent)
+	}
+	return &getWebhookEvents
+}
@@ -0,0 +1,7 @@
+package model
+
+type MidtransConfig struct {
+	ServerKey    string `json:"-"`
+	ClientKey    string `json:"client_key"`
+	IsProduction bool   `json:"is_production"`
+}
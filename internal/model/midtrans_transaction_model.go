@@ -0,0 +1,63 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"time"
+)
+
+type CreateMidtransTransaction struct {
+	OrderId  uint64               `json:"order_id" validate:"required"`
+	Lang     enum_state.Languange `json:"-"`
+	TimeZone time.Location        `json:"-"`
+}
+
+type GetMidtransTransaction struct {
+	OrderId uint64 `json:"-" validate:"required"`
+}
+
+// MidtransNotificationRequest adalah body http notification yang dikirim midtrans
+type MidtransNotificationRequest struct {
+	TransactionTime   string `json:"transaction_time"`
+	TransactionStatus string `json:"transaction_status" validate:"required"`
+	TransactionId     string `json:"transaction_id"`
+	StatusMessage     string `json:"status_message"`
+	StatusCode        string `json:"status_code" validate:"required"`
+	SignatureKey      string `json:"signature_key" validate:"required"`
+	SettlementTime    string `json:"settlement_time"`
+	PaymentType       string `json:"payment_type"`
+	OrderId           string `json:"order_id" validate:"required"`
+	MerchantId        string `json:"merchant_id"`
+	GrossAmount       string `json:"gross_amount" validate:"required"`
+	FraudStatus       string `json:"fraud_status"`
+	Currency          string `json:"currency"`
+	PermataVaNumber   string `json:"permata_va_number"`
+	VaNumbers         []struct {
+		Bank     string `json:"bank"`
+		VaNumber string `json:"va_number"`
+	} `json:"va_numbers"`
+}
+
+type MidtransTransactionResponse struct {
+	ID                uint64                    `json:"id"`
+	OrderId           uint64                    `json:"order_id"`
+	MidtransOrderId   string                    `json:"midtrans_order_id"`
+	TransactionId     string                    `json:"transaction_id"`
+	ApiType           string                    `json:"api_type"`
+	PaymentType       string                    `json:"payment_type"`
+	ChannelCode       enum_state.ChannelCode    `json:"channel_code"`
+	GrossAmount       float64                   `json:"gross_amount"`
+	Currency          string                    `json:"currency"`
+	TransactionStatus string                    `json:"transaction_status"`
+	FraudStatus       string                    `json:"fraud_status,omitempty"`
+	PaymentStatus     enum_state.PaymentStatus  `json:"payment_status"`
+	VaBank            string                    `json:"va_bank,omitempty"`
+	VaNumber          string                    `json:"va_number,omitempty"`
+	QrString          string                    `json:"qr_string,omitempty"`
+	DeeplinkUrl       string                    `json:"deeplink_url,omitempty"`
+	SnapToken         string                    `json:"snap_token,omitempty"`
+	RedirectUrl       string                    `json:"redirect_url,omitempty"`
+	ExpiresAt         helper_others.TimeRFC3339 `json:"expires_at"`
+	CreatedAt         helper_others.TimeRFC3339 `json:"created_at"`
+	UpdatedAt         helper_others.TimeRFC3339 `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type OIDCProviderConfig struct {
+	Name         string   `json:"name"`
+	IssuerURL    string   `json:"issuer_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"-"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `json:"providers"`
+}
+
+// OIDCIdentity adalah isi ID token yang sudah diverifikasi
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+type OIDCProviderResponse struct {
+	Name string `json:"name"`
+}
+
+type OIDCAuthorizeRequest struct {
+	Provider string `json:"-" validate:"required,max=50"`
+	Remember bool   `json:"remember"`
+}
+
+type OIDCAuthorizationResponse struct {
+	Provider         string    `json:"provider"`
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackRequest dikirim frontend setelah provider redirect kembali dengan code dan state
+type OIDCCallbackRequest struct {
+	Provider    string               `json:"-" validate:"required,max=50"`
+	Code        string               `json:"code" validate:"required,max=2048"`
+	State       string               `json:"state" validate:"required,max=2048"`
+	ReturnToken bool                 `json:"return_token"`
+	UserAgent   string               `json:"-"`
+	IPAddress   string               `json:"-"`
+	Lang        enum_state.Languange `json:"-"`
+	TimeZone    time.Location        `json:"-"`
+}
@@ -7,33 +7,50 @@ import (
 )
 
 type OrderResponse struct {
-	ID                uint64                     `json:"id"`
-	Invoice           string                     `json:"invoice"`
-	DiscountType      enum_state.DiscountType    `json:"discount_type"`
-	DiscountValue     float32                    `json:"discount_value"`
-	TotalDiscount     float32                    `json:"total_discount"`
-	UserId            uint64                     `json:"user_id"`
-	FirstName         string                     `json:"first_name"`
-	LastName          string                     `json:"last_name"`
-	Email             string                     `json:"email"`
-	Phone             string                     `json:"phone"`
-	PaymentGateway    enum_state.PaymentGateway  `json:"payment_gateway"`
-	PaymentMethod     enum_state.PaymentMethod   `json:"payment_method"`
-	PaymentStatus     enum_state.PaymentStatus   `json:"payment_status"`
-	ChannelCode       enum_state.ChannelCode     `json:"channel_code"`
-	OrderStatus       enum_state.OrderStatus     `json:"order_status"`
-	IsDelivery        bool                       `json:"delivery"`
-	DeliveryCost      float32                    `json:"delivery_cost"`
-	CompleteAddress   string                     `json:"complete_address"`
-	Note              string                     `json:"note"`
-	ServiceFee        float32                    `json:"service_fee"`
-	TotalProductPrice float32                    `json:"total_product_price"`
-	TotalFinalPrice   float32                    `json:"total_final_price"`
-	CancellationNotes string                     `json:"cancellation_notes"`
-	CreatedAt         helper_others.TimeRFC3339  `json:"created_at"`
-	UpdatedAt         helper_others.TimeRFC3339  `json:"updated_at"`
-	OrderProducts     []OrderProductResponse     `json:"order_products"`
-	XenditTransaction *XenditTransactionResponse `json:"xendit_transaction_response,omitempty"`
+	ID                  uint64                       `json:"id"`
+	Invoice             string                       `json:"invoice"`
+	DiscountType        enum_state.DiscountType      `json:"discount_type"`
+	DiscountValue       float32                      `json:"discount_value"`
+	TotalDiscount       float32                      `json:"total_discount"`
+	UserId              uint64                       `json:"user_id"`
+	FirstName           string                       `json:"first_name"`
+	LastName            string                       `json:"last_name"`
+	Email               string                       `json:"email"`
+	Phone               string                       `json:"phone"`
+	PaymentGateway      enum_state.PaymentGateway    `json:"payment_gateway"`
+	PaymentMethod       enum_state.PaymentMethod     `json:"payment_method"`
+	PaymentStatus       enum_state.PaymentStatus     `json:"payment_status"`
+	ChannelCode         enum_state.ChannelCode       `json:"channel_code"`
+	OrderStatus         enum_state.OrderStatus       `json:"order_status"`
+	IsDelivery          bool                         `json:"delivery"`
+	DeliveryCost        float32                      `json:"delivery_cost"`
+	CompleteAddress     string                       `json:"complete_address"`
+	Note                string                       `json:"note"`
+	ServiceFee          float32                      `json:"service_fee"`
+	TotalProductPrice   float32                      `json:"total_product_price"`
+	TotalFinalPrice     float32                      `json:"total_final_price"`
+	CancellationNotes   string                       `json:"cancellation_notes"`
+	CreatedAt           helper_others.TimeRFC3339    `json:"created_at"`
+	UpdatedAt           helper_others.TimeRFC3339    `json:"updated_at"`
+	OrderProducts       []OrderProductResponse       `json:"order_products"`
+	XenditTransaction   *XenditTransactionResponse   `json:"xendit_transaction_response,omitempty"`
+	MidtransTransaction *MidtransTransactionResponse `json:"midtrans_transaction,omitempty"`
+	TotalRefunded       float32                      `json:"total_refunded"`
+	Refunds             []OrderRefundResponse        `json:"refunds,omitempty"`
+	PaymentLegs         []OrderPaymentLegResponse    `json:"payment_legs,omitempty"`
+}
+
+type OrderPaymentLegResponse struct {
+	ID                  uint64                           `json:"id"`
+	Source              enum_state.OrderPaymentLegSource `json:"source"`
+	PaymentGateway      enum_state.PaymentGateway        `json:"payment_gateway"`
+	PaymentMethod       enum_state.PaymentMethod         `json:"payment_method"`
+	ChannelCode         enum_state.ChannelCode           `json:"channel_code"`
+	Amount              float32                          `json:"amount"`
+	Status              enum_state.OrderPaymentLegStatus `json:"status"`
+	XenditTransactionId string                           `json:"xendit_transaction_id,omitempty"`
+	CreatedAt           helper_others.TimeRFC3339        `json:"created_at"`
+	UpdatedAt           helper_others.TimeRFC3339        `json:"updated_at"`
 }
 
 type CreateOrderRequest struct {
@@ -46,6 +63,8 @@ type CreateOrderRequest struct {
 	PaymentMethod   enum_state.PaymentMethod  `json:"payment_method" validate:"required"`
 	ChannelCode     enum_state.ChannelCode    `json:"channel_code" validate:"required"`
 	PaymentGateway  enum_state.PaymentGateway `json:"payment_gateway" validate:"required"`
+	MobileNumber    string                    `json:"mobile_number"`      // wajib untuk channel EWALLET_OVO
+	UseWallet       bool                      `json:"use_wallet_balance"` // seluruh saldo wallet dipakai, sisanya dibayar lewat payment gateway
 	IsDelivery      bool                      `json:"is_delivery"`
 	DeliveryId      uint64                    `json:"delivery_id"`
 	CompleteAddress string                    `json:"complete_address" validate:"required"`
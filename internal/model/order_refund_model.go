@@ -0,0 +1,70 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type OrderRefundResponse struct {
+	ID              uint64                       `json:"id"`
+	OrderId         uint64                       `json:"order_id"`
+	Invoice         string                       `json:"invoice,omitempty"`
+	RefundedBy      uint64                       `json:"refunded_by"`
+	RefunderName    string                       `json:"refunder_name,omitempty"`
+	ReferenceId     string                       `json:"reference_id"`
+	Type            enum_state.RefundType        `json:"type"`
+	Destination     enum_state.RefundDestination `json:"destination"`
+	Amount          float32                      `json:"amount"`
+	Reason          string                       `json:"reason"`
+	Status          enum_state.RefundStatus      `json:"status"`
+	PaymentGateway  enum_state.PaymentGateway    `json:"payment_gateway"`
+	GatewayRefundId string                       `json:"gateway_refund_id"`
+	FailureCode     string                       `json:"failure_code"`
+	ProcessedAt     *helper_others.TimeRFC3339   `json:"processed_at"`
+	CreatedAt       helper_others.TimeRFC3339    `json:"created_at"`
+	UpdatedAt       helper_others.TimeRFC3339    `json:"updated_at"`
+	Items           []OrderRefundItemResponse    `json:"items"`
+}
+
+type OrderRefundItemResponse struct {
+	ID             uint64  `json:"id"`
+	OrderProductId uint64  `json:"order_product_id"`
+	Quantity       int     `json:"quantity"`
+	Amount         float32 `json:"amount"`
+}
+
+type OrderRefundSummaryResponse struct {
+	OrderId         uint64                `json:"order_id"`
+	TotalFinalPrice float32               `json:"total_final_price"`
+	TotalRefunded   float32               `json:"total_refunded"`
+	RefundableLeft  float32               `json:"refundable_left"`
+	Refunds         []OrderRefundResponse `json:"refunds"`
+}
+
+// CreateOrderRefundRequest, amount dan items boleh kosong untuk refund seluruh sisa pembayaran.
+// jika items diisi maka nominal refund dihitung dari harga produk saat dipesan
+type CreateOrderRefundRequest struct {
+	OrderId        uint64                       `json:"-" validate:"required"`
+	Amount         float32                      `json:"amount" validate:"gte=0"`
+	Items          []CreateOrderRefundItem      `json:"items" validate:"omitempty,dive"`
+	Destination    enum_state.RefundDestination `json:"destination" validate:"required,oneof=wallet original_method"`
+	Reason         string                       `json:"reason" validate:"required,max=255"`
+	CurrentAdminId uint64                       `json:"-" validate:"required"`
+}
+
+type CreateOrderRefundItem struct {
+	OrderProductId uint64 `json:"order_product_id" validate:"required"`
+	Quantity       int    `json:"quantity" validate:"required,gt=0"`
+}
+
+type XenditGetRefundCallbackStatus struct {
+	Event string `json:"event"`
+	Data  struct {
+		RefundId         string                    `json:"id" validate:"required"`
+		PaymentRequestId string                    `json:"payment_request_id"`
+		ReferenceId      string                    `json:"reference_id"`
+		Status           string                    `json:"status" validate:"required"`
+		FailureCode      string                    `json:"failure_code"`
+		UpdatedAt        helper_others.TimeRFC3339 `json:"updated" validate:"required"`
+	} `json:"data" validate:"required"`
+}
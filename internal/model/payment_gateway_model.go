@@ -0,0 +1,111 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+// Status pada payment gateway mengikuti status payment request xendit
+// (PENDING, SUCCEEDED, FAILED, EXPIRED, CANCELED) sebagai status standar semua gateway
+
+type PaymentGatewayItem struct {
+	ReferenceId string
+	Name        string
+	Category    string
+	Type        string
+	Quantity    int
+	Price       float32
+}
+
+type CreatePaymentGatewayRequest struct {
+	OrderId        uint64
+	ReferenceId    string
+	CustomerId     string
+	Amount         float32
+	Currency       string
+	PaymentMethod  enum_state.PaymentMethod
+	ChannelCode    enum_state.ChannelCode
+	Description    string
+	Items          []PaymentGatewayItem
+	Metadata       map[string]any
+	ExpiresAt      time.Time
+	IdempotencyKey string
+	// nama pemilik virtual account yang tampil di aplikasi bank customer
+	CustomerName string
+	// dipakai e-wallet, customer diarahkan kembali ke front end setelah membayar
+	MobileNumber     string
+	SuccessReturnUrl string
+	FailureReturnUrl string
+}
+
+type PaymentGatewayResponse struct {
+	ID              string
+	ReferenceId     string
+	Amount          float64
+	Currency        string
+	PaymentMethod   string
+	PaymentMethodId string
+	ChannelCode     string
+	QrString        string
+	Status          string
+	// status asli dari payment gateway sebelum dipetakan ke status standar
+	RawStatus            string
+	FraudStatus          string
+	Description          string
+	FailureCode          string
+	CheckoutToken        string
+	CheckoutUrl          string
+	DeeplinkUrl          string
+	VirtualAccountBank   string
+	VirtualAccountNumber string
+	Metadata             map[string]any
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+type RefundPaymentGatewayRequest struct {
+	PaymentId      string
+	ReferenceId    string
+	Amount         float32
+	Currency       string
+	Reason         string
+	IdempotencyKey string
+}
+
+type RefundPaymentGatewayResponse struct {
+	ID          string
+	PaymentId   string
+	Amount      float64
+	Currency    string
+	Status      string
+	FailureCode string
+	CreatedAt   time.Time
+}
+
+type PaymentGatewayWebhook struct {
+	EventType       string
+	PaymentId       string
+	PaymentMethodId string
+	Status          string
+	RawStatus       string
+	FraudStatus     string
+	Metadata        map[string]any
+	UpdatedAt       time.Time
+}
+
+type SimulatePaymentRequest struct {
+	PaymentId string `json:"-" validate:"required"`
+	Status    string `json:"status" validate:"required,oneof=SUCCEEDED FAILED EXPIRED"`
+}
+
+// UpdateOrderPaymentStatus dipakai callback semua payment gateway untuk memperbarui payment status order
+// sekaligus mengirim email dan notifikasi ke customer
+type UpdateOrderPaymentStatus struct {
+	OrderId         uint64
+	PaymentStatus   enum_state.PaymentStatus
+	UpdatedAt       time.Time
+	Lang            enum_state.Languange
+	TimeZone        time.Location
+	BaseFrontEndURL string
+}
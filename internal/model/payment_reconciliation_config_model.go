@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type PaymentReconciliationConfig struct {
+	Interval  time.Duration `json:"interval"`
+	MinAge    time.Duration `json:"min_age"`
+	BatchSize int           `json:"batch_size"`
+	BaseURL   string        `json:"base_url"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "seblak-bombom-restful-api/internal/helper/enum_state"
+
+type PaymentReconciliationResponse struct {
+	OrderId             uint64                   `json:"order_id"`
+	XenditTransactionId string                   `json:"xendit_transaction_id"`
+	PreviousStatus      string                   `json:"previous_status"`
+	GatewayStatus       string                   `json:"gateway_status"`
+	CurrentStatus       string                   `json:"current_status"`
+	PaymentStatus       enum_state.PaymentStatus `json:"payment_status"`
+	Updated             bool                     `json:"updated"`
+}
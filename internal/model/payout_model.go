@@ -6,17 +6,18 @@ import (
 )
 
 type CreatePayoutRequest struct {
-	Amount              float32                 `json:"amount" validate:"required"`
-	Currency            string                  `json:"currency"`
-	Method              enum_state.PayoutMethod `json:"method"`
-	Notes               string                  `json:"notes"`
-	UserId              uint64                  `json:"user_id"`
-	XenditPayoutRequest *CreateXenditPayout     `json:"xendit_payout_request" validate:"required_if=Method 1"`
+	Amount        float32                 `json:"amount" validate:"required,gt=0"`
+	Currency      string                  `json:"currency"`
+	Method        enum_state.PayoutMethod `json:"method"`
+	Notes         string                  `json:"notes"`
+	UserId        uint64                  `json:"user_id"`
+	BankAccountId uint64                  `json:"bank_account_id" validate:"required_if=Method online"`
 }
 
 type PayoutResponse struct {
 	ID             uint64                    `json:"id"`
 	XenditPayoutId string                    `json:"xendit_payout_id"`
+	BankAccountId  *uint64                   `json:"bank_account_id"`
 	Amount         float32                   `json:"amount"`
 	Currency       string                    `json:"currency"`
 	Method         enum_state.PayoutMethod   `json:"method"`
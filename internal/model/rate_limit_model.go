@@ -0,0 +1,34 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+// RateLimitPolicy adalah token bucket berisi Limit token yang terisi penuh kembali dalam Period
+type RateLimitPolicy struct {
+	Name    string                  `json:"name"`
+	Limit   int                     `json:"limit"`
+	Period  time.Duration           `json:"period"`
+	KeyBy   enum_state.RateLimitKey `json:"key_by"`
+	APIKeys []string                `json:"-"` // api key terdaftar, X-API-Key di luar daftar ini tidak mendapat bucket sendiri
+}
+
+// RateLimitConfig menyimpan policy rate limit untuk setiap kelompok route
+type RateLimitConfig struct {
+	Enabled        bool            `json:"enabled"`
+	Register       RateLimitPolicy `json:"register"`
+	Login          RateLimitPolicy `json:"login"`
+	ForgotPassword RateLimitPolicy `json:"forgot_password"`
+	Guest          RateLimitPolicy `json:"guest"`
+	Order          RateLimitPolicy `json:"order"`
+	User           RateLimitPolicy `json:"user"`
+}
+
+type RateLimitResult struct {
+	Allowed    bool          `json:"allowed"`
+	Limit      int           `json:"limit"`
+	Remaining  int           `json:"remaining"`
+	ResetAfter time.Duration `json:"reset_after"` // sisa waktu sampai bucket terisi penuh
+	RetryAfter time.Duration `json:"retry_after"` // sisa waktu sampai satu token tersedia, hanya jika ditolak
+}
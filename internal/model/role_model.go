@@ -0,0 +1,51 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type RoleResponse struct {
+	ID          uint64                    `json:"id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Permissions []enum_state.Permission   `json:"permissions"`
+	CreatedAt   helper_others.TimeRFC3339 `json:"created_at"`
+	UpdatedAt   helper_others.TimeRFC3339 `json:"updated_at"`
+}
+
+type CreateRoleRequest struct {
+	Name        string                  `json:"name" validate:"required,max=50"`
+	Description string                  `json:"description" validate:"max=255"`
+	Permissions []enum_state.Permission `json:"permissions" validate:"required,min=1"`
+}
+
+type UpdateRoleRequest struct {
+	ID          uint64                  `json:"-" validate:"required"`
+	Name        string                  `json:"name" validate:"required,max=50"`
+	Description string                  `json:"description" validate:"max=255"`
+	Permissions []enum_state.Permission `json:"permissions" validate:"required,min=1"`
+}
+
+type GetRoleRequest struct {
+	ID uint64 `json:"-" validate:"required"`
+}
+
+type DeleteRoleRequest struct {
+	ID uint64 `json:"-" validate:"required"`
+}
+
+// AssignStaffRoleRequest menjadikan user sebagai staff dengan role tertentu
+type AssignStaffRoleRequest struct {
+	UserId           uint64          `json:"-" validate:"required"`
+	RoleId           uint64          `json:"role_id" validate:"required"`
+	CurrentAdminId   uint64          `json:"-" validate:"required"`
+	CurrentAdminRole enum_state.Role `json:"-"`
+}
+
+// RemoveStaffRoleRequest melepas role staff, user kembali menjadi customer
+type RemoveStaffRoleRequest struct {
+	UserId           uint64          `json:"-" validate:"required"`
+	CurrentAdminId   uint64          `json:"-" validate:"required"`
+	CurrentAdminRole enum_state.Role `json:"-"`
+}
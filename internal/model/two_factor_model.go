@@ -0,0 +1,60 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"time"
+)
+
+type SetupTwoFactorRequest struct {
+	Password string `json:"password" validate:"required,max=100"`
+}
+
+type TwoFactorSetupResponse struct {
+	Issuer          string `json:"issuer"`
+	AccountName     string `json:"account_name"`
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorCodeRequest berisi kode TOTP dari aplikasi authenticator atau kode pemulihan
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required,max=100"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RemainingRecoveryCodes int64      `json:"remaining_recovery_codes"`
+}
+
+// TwoFactorRecoveryCodesResponse hanya dikirim sekali, yang tersimpan di database hanya hash nya
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorConfirmResponse struct {
+	VerifiedAt time.Time `json:"verified_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// TwoFactorChallengeResponse dikirim saat login password benar tetapi akun memakai 2FA
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string               `json:"challenge_token" validate:"required"`
+	Code           string               `json:"code" validate:"required,max=20"`
+	ReturnToken    bool                 `json:"return_token"`
+	UserAgent      string               `json:"-"`
+	IPAddress      string               `json:"-"`
+	Lang           enum_state.Languange `json:"-"`
+	TimeZone       time.Location        `json:"-"`
+}
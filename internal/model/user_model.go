@@ -20,6 +20,38 @@ type UserResponse struct {
 	UserProfile string                    `json:"user_profile"`
 	CreatedAt   helper_others.TimeRFC3339 `json:"created_at"`
 	UpdatedAt   helper_others.TimeRFC3339 `json:"updated_at"`
+	// sesi login dari access token yang sedang dipakai
+	SessionId        string `json:"-"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	// role staff yang dipasang, permission admin selalu berisi semua permission
+	StaffRole     *RoleResponse           `json:"staff_role,omitempty"`
+	Permissions   []enum_state.Permission `json:"permissions"`
+	EmailVerified bool                    `json:"email_verified"`
+	// hanya terisi untuk akun yang disuspend / dihapus, dipakai di endpoint admin
+	SuspendedAt     *helper_others.TimeRFC3339 `json:"suspended_at,omitempty"`
+	SuspendedReason string                     `json:"suspended_reason,omitempty"`
+	DeletedAt       *helper_others.TimeRFC3339 `json:"deleted_at,omitempty"`
+}
+
+func (u *UserResponse) HasPermission(permission enum_state.Permission) bool {
+	for _, userPermission := range u.Permissions {
+		if userPermission == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasMoneyMovingPermission true jika user memegang salah satu permission yang memindahkan uang
+func (u *UserResponse) HasMoneyMovingPermission() bool {
+	for _, permission := range enum_state.MoneyMovingPermissions {
+		if u.HasPermission(permission) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type RegisterUserRequest struct {
@@ -62,13 +94,31 @@ type LoginUserRequest struct {
 	Email    string `json:"email" validate:"required,max=100"`
 	Password string `json:"password" validate:"required,max=100"`
 	Remember bool   `json:"remember"`
+	// true untuk client non-browser agar token dikembalikan di body response
+	ReturnToken bool                 `json:"return_token"`
+	UserAgent   string               `json:"-"`
+	IPAddress   string               `json:"-"`
+	Lang        enum_state.Languange `json:"-"`
+	TimeZone    time.Location        `json:"-"`
 }
 
 type UserTokenResponse struct {
-	Token      string                    `json:"token"`
-	ExpiryDate time.Time                 `json:"expiry_date"`
-	CreatedAt  helper_others.TimeRFC3339 `json:"created_at"`
-	UpdatedAt  helper_others.TimeRFC3339 `json:"updated_at"`
+	Token                  string                    `json:"token"`
+	TokenType              string                    `json:"token_type,omitempty"`
+	ExpiryDate             time.Time                 `json:"expiry_date"`
+	RefreshToken           string                    `json:"refresh_token"`
+	RefreshTokenExpiryDate time.Time                 `json:"refresh_token_expiry_date"`
+	CreatedAt              helper_others.TimeRFC3339 `json:"created_at"`
+	UpdatedAt              helper_others.TimeRFC3339 `json:"updated_at"`
+	User                   *UserResponse             `json:"user,omitempty"`
+}
+
+// RefreshTokenRequest diisi dari body (client non-browser) atau cookie refresh_token (browser)
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+	ReturnToken  bool   `json:"return_token"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
 }
 
 type GetUserByTokenRequest struct {
@@ -107,3 +157,34 @@ type PasswordResetResponse struct {
 	ExpiresAt        helper_others.TimeRFC3339 `json:"expires_at"`
 	CreatedAt        helper_others.TimeRFC3339 `json:"created_at"`
 }
+
+// AdminUserDetailResponse adalah detail user untuk admin beserta ringkasan aktivitasnya
+type AdminUserDetailResponse struct {
+	UserResponse
+	TotalOrders   int64   `json:"total_orders"`
+	WalletBalance float32 `json:"wallet_balance"`
+}
+
+type AdminUserRequest struct {
+	UserId           uint64               `json:"-" validate:"required"`
+	CurrentAdminId   uint64               `json:"-" validate:"required"`
+	CurrentAdminRole enum_state.Role      `json:"-"`
+	Lang             enum_state.Languange `json:"-"`
+	TimeZone         time.Location        `json:"-"`
+}
+
+type SuspendUserRequest struct {
+	UserId           uint64          `json:"-" validate:"required"`
+	Reason           string          `json:"reason" validate:"required,max=500"`
+	CurrentAdminId   uint64          `json:"-" validate:"required"`
+	CurrentAdminRole enum_state.Role `json:"-"`
+}
+
+// UpdateUserRoleRequest mengganti role akun, role_id wajib untuk staff dan kurir
+type UpdateUserRoleRequest struct {
+	UserId           uint64          `json:"-" validate:"required"`
+	Role             enum_state.Role `json:"role" validate:"required,oneof=admin customer courier staff"`
+	RoleId           *uint64         `json:"role_id"`
+	CurrentAdminId   uint64          `json:"-" validate:"required"`
+	CurrentAdminRole enum_state.Role `json:"-"`
+}
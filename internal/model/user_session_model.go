@@ -0,0 +1,19 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type UserSessionResponse struct {
+	ID         string                    `json:"id"`
+	UserAgent  string                    `json:"user_agent"`
+	IPAddress  string                    `json:"ip_address"`
+	IsCurrent  bool                      `json:"is_current"`
+	LastSeenAt helper_others.TimeRFC3339 `json:"last_seen_at"`
+	ExpiresAt  helper_others.TimeRFC3339 `json:"expires_at"`
+	CreatedAt  helper_others.TimeRFC3339 `json:"created_at"`
+}
+
+type RevokeUserSessionRequest struct {
+	ID string `json:"-" validate:"required,max=36"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type WalletConfig struct {
+	AdjustmentApprovalThreshold float32        `json:"adjustment_approval_threshold"`
+	HoldCreditsWhenFrozen       bool           `json:"hold_credits_when_frozen"`
+	UnverifiedPayoutLimit       float32        `json:"unverified_payout_limit"`
+	BusinessTimeZone            *time.Location `json:"-"`
+}
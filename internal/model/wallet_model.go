@@ -3,14 +3,16 @@ package model
 import (
 	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"time"
 )
 
 type WalletResponse struct {
-	ID        uint64                    `json:"id"`
-	Balance   float32                   `json:"balance"`
-	Status    enum_state.WalletStatus   `json:"status"`
-	CreatedAt helper_others.TimeRFC3339 `json:"created_at"`
-	UpdatedAt helper_others.TimeRFC3339 `json:"updated_at"`
+	ID           uint64                    `json:"id"`
+	Balance      float32                   `json:"balance"`
+	Status       enum_state.WalletStatus   `json:"status"`
+	StatusReason string                    `json:"status_reason"`
+	CreatedAt    helper_others.TimeRFC3339 `json:"created_at"`
+	UpdatedAt    helper_others.TimeRFC3339 `json:"updated_at"`
 }
 
 type GetWalletBalance struct {
@@ -18,14 +20,18 @@ type GetWalletBalance struct {
 }
 
 type WithdrawWalletRequest struct {
-	UserId            uint64                           `json:"user_id" validate:"required"`
-	Amount            float32                          `json:"amount" validate:"required"`
-	Method            enum_state.WalletWithdrawRequest `json:"method" validate:"required"`
-	BankName          string                           `json:"bank_name"`
-	BankAccountNumber string                           `json:"bank_account_number"`
-	BankAccountName   string                           `json:"bank_account_name"`
-	Status            enum_state.WalletWithdrawRequest `json:"status" validate:"required"`
-	Note              string                           `json:"note"`
+	UserId        uint64                           `json:"-" validate:"required"`
+	Amount        float32                          `json:"amount" validate:"required,gt=0"`
+	Method        enum_state.WalletWithdrawRequest `json:"method" validate:"required"`
+	BankAccountId uint64                           `json:"bank_account_id" validate:"required_if=Method bank_transfer"`
+	Note          string                           `json:"note"`
+	Lang          enum_state.Languange             `json:"-"`
+	TimeZone      time.Location                    `json:"-"`
+}
+
+type CancelWithdrawWalletRequest struct {
+	ID            uint64 `json:"-" validate:"required"`
+	CurrentUserId uint64 `json:"-" validate:"required"`
 }
 
 type WithdrawWalletApprovalRequest struct {
@@ -40,7 +46,10 @@ type WithdrawWalletResponse struct {
 	UserId            uint64                           `json:"user_id"`
 	User              UserResponse                     `json:"user"`
 	Amount            float32                          `json:"amount"`
+	Fee               float32                          `json:"fee"`
 	Method            enum_state.WalletWithdrawRequest `json:"method"`
+	BankAccountId     *uint64                          `json:"bank_account_id"`
+	ChannelCode       string                           `json:"channel_code"`
 	BankName          string                           `json:"bank_name"`
 	BankAccountNumber string                           `json:"bank_account_number"`
 	BankAccountName   string                           `json:"bank_account_name"`
@@ -49,6 +58,7 @@ type WithdrawWalletResponse struct {
 	RejectionNotes    string                           `json:"rejection_notes"`
 	ProcessedBy       UserResponse                     `json:"processed_by"`
 	ProcessedAt       helper_others.TimeRFC3339        `json:"processed_at"`
+	XenditPayoutId    string                           `json:"xendit_payout_id"`
 	CreatedAt         helper_others.TimeRFC3339        `json:"created_at"`
 	UpdatedAt         helper_others.TimeRFC3339        `json:"updated_at"`
 }
@@ -58,6 +68,46 @@ type UpdateWalletBalance struct {
 	Balance float32 `json:"balance" validate:"required"`
 }
 
+type UpdateWalletStatusRequest struct {
+	UserId         uint64                  `json:"-" validate:"required"`
+	Status         enum_state.WalletStatus `json:"-" validate:"required"`
+	Reason         string                  `json:"reason" validate:"required,max=500"`
+	CurrentAdminId uint64                  `json:"-" validate:"required"`
+}
+
 type SuspendWallet struct {
 	IDs []uint64 `json:"-" validate:"required"`
 }
+
+type WalletAdjustmentRequest struct {
+	UserId         uint64               `json:"-" validate:"required"`
+	Amount         float32              `json:"amount" validate:"required"`
+	Reason         string               `json:"reason" validate:"required,max=500"`
+	CurrentAdminId uint64               `json:"-" validate:"required"`
+	Lang           enum_state.Languange `json:"-"`
+	TimeZone       time.Location        `json:"-"`
+}
+
+type WalletAdjustmentApprovalRequest struct {
+	ID             uint64                            `json:"-" validate:"required"`
+	Status         enum_state.WalletAdjustmentStatus `json:"status" validate:"required"`
+	RejectionNotes string                            `json:"rejection_notes"`
+	CurrentAdminId uint64                            `json:"-" validate:"required"`
+	Lang           enum_state.Languange              `json:"-"`
+	TimeZone       time.Location                     `json:"-"`
+}
+
+type WalletAdjustmentResponse struct {
+	ID             uint64                            `json:"id"`
+	UserId         uint64                            `json:"user_id"`
+	User           UserResponse                      `json:"user"`
+	Amount         float32                           `json:"amount"`
+	Reason         string                            `json:"reason"`
+	Status         enum_state.WalletAdjustmentStatus `json:"status"`
+	RequestedBy    uint64                            `json:"requested_by"`
+	ProcessedBy    *uint64                           `json:"processed_by"`
+	ProcessedAt    helper_others.TimeRFC3339         `json:"processed_at"`
+	RejectionNotes string                            `json:"rejection_notes"`
+	CreatedAt      helper_others.TimeRFC3339         `json:"created_at"`
+	UpdatedAt      helper_others.TimeRFC3339         `json:"updated_at"`
+}
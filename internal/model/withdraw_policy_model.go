@@ -0,0 +1,31 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type WithdrawPolicyResponse struct {
+	ID              uint64                           `json:"id"`
+	Method          enum_state.WalletWithdrawRequest `json:"method"`
+	MinAmount       float32                          `json:"min_amount"`
+	FeeType         enum_state.WithdrawFeeType       `json:"fee_type"`
+	FeeValue        float32                          `json:"fee_value"`
+	DailyLimit      float32                          `json:"daily_limit"`
+	MonthlyLimit    float32                          `json:"monthly_limit"`
+	CoolingOffHours int                              `json:"cooling_off_hours"`
+	UpdatedBy       *uint64                          `json:"updated_by"`
+	CreatedAt       helper_others.TimeRFC3339        `json:"created_at"`
+	UpdatedAt       helper_others.TimeRFC3339        `json:"updated_at"`
+}
+
+type UpsertWithdrawPolicyRequest struct {
+	Method          enum_state.WalletWithdrawRequest `json:"-" validate:"required"`
+	MinAmount       float32                          `json:"min_amount" validate:"gte=0"`
+	FeeType         enum_state.WithdrawFeeType       `json:"fee_type" validate:"required"`
+	FeeValue        float32                          `json:"fee_value" validate:"gte=0"`
+	DailyLimit      float32                          `json:"daily_limit" validate:"gte=0"`
+	MonthlyLimit    float32                          `json:"monthly_limit" validate:"gte=0"`
+	CoolingOffHours int                              `json:"cooling_off_hours" validate:"gte=0"`
+	CurrentAdminId  uint64                           `json:"-" validate:"required"`
+}
@@ -12,6 +12,11 @@ type CreateXenditPayout struct {
 	Amount            float32 `json:"amount" validate:"required"`
 	Description       string  `json:"description" validate:"max=100"`
 	Currency          string  `json:"currency" validate:"required,max=10"`
+	// saldo sudah dipotong saat withdraw request dibuat
+	IsBalanceReserved bool `json:"-"`
+	// kunci tetap dari pemanggil agar payout yang dikirim ulang tidak dicairkan dua kali oleh xendit
+	IdempotencyKey string `json:"-"`
+	ReferenceId    string `json:"-"`
 }
 
 type GetWithdrawableBalanceResponse struct {
@@ -7,9 +7,11 @@ import (
 )
 
 type CreateXenditTransaction struct {
-	OrderId  uint64               `json:"order_id" validate:"required"`
-	Lang     enum_state.Languange `json:"-"`
-	TimeZone time.Location        `json:"-"`
+	OrderId         uint64               `json:"order_id" validate:"required"`
+	MobileNumber    string               `json:"mobile_number"`
+	Lang            enum_state.Languange `json:"-"`
+	TimeZone        time.Location        `json:"-"`
+	BaseFrontEndURL string               `json:"-"`
 }
 
 type CreateXenditQRCode struct {
@@ -25,26 +27,32 @@ type GetXenditQRCodeTransaction struct {
 }
 
 type XenditTransactionResponse struct {
-	ID              string                    `json:"id"`
-	ReferenceId     string                    `json:"reference_id"`
-	OrderId         uint64                    `json:"order_id"`
-	Amount          float64                   `json:"amount"`
-	Currency        string                    `json:"currency"`
-	PaymentMethod   string                    `json:"payment_method"`
-	PaymentMethodId string                    `json:"payment_method_id"`
-	ChannelCode     string                    `json:"channel_code"`
-	QrString        string                    `json:"qr_string,omitempty"`
-	Status          string                    `json:"status"`
-	Description     string                    `json:"description"`
-	FailureCode     string                    `json:"failure_code"`
-	Metadata        []byte                    `json:"metadata"`
-	ExpiresAt       helper_others.TimeRFC3339 `json:"expires_at"`
-	CreatedAt       helper_others.TimeRFC3339 `json:"created_at"`
-	UpdatedAt       helper_others.TimeRFC3339 `json:"updated_at"`
+	ID                         string                    `json:"id"`
+	ReferenceId                string                    `json:"reference_id"`
+	OrderId                    uint64                    `json:"order_id"`
+	Amount                     float64                   `json:"amount"`
+	Currency                   string                    `json:"currency"`
+	PaymentMethod              string                    `json:"payment_method"`
+	PaymentMethodId            string                    `json:"payment_method_id"`
+	ChannelCode                string                    `json:"channel_code"`
+	QrString                   string                    `json:"qr_string,omitempty"`
+	MobileNumber               string                    `json:"mobile_number,omitempty"`
+	CheckoutUrl                string                    `json:"checkout_url,omitempty"`
+	DeeplinkUrl                string                    `json:"deeplink_url,omitempty"`
+	VirtualAccountNumber       string                    `json:"virtual_account_number,omitempty"`
+	VirtualAccountInstructions []string                  `json:"virtual_account_instructions,omitempty"`
+	Status                     string                    `json:"status"`
+	Description                string                    `json:"description"`
+	FailureCode                string                    `json:"failure_code"`
+	Metadata                   []byte                    `json:"metadata"`
+	ExpiresAt                  helper_others.TimeRFC3339 `json:"expires_at"`
+	CreatedAt                  helper_others.TimeRFC3339 `json:"created_at"`
+	UpdatedAt                  helper_others.TimeRFC3339 `json:"updated_at"`
 }
 
 type XenditGetPaymentRequestCallbackStatus struct {
-	Data struct {
+	Event string `json:"event"`
+	Data  struct {
 		PaymentMethod struct {
 			ID string `json:"id"`
 		} `json:"payment_method" validate:"required"`
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+)
+
+type XenditWebhookEventResponse struct {
+	ID          uint64                              `json:"id"`
+	EventId     string                              `json:"event_id"`
+	Gateway     enum_state.PaymentGateway           `json:"gateway"`
+	WebhookType enum_state.XenditWebhookType        `json:"webhook_type"`
+	EventType   string                              `json:"event_type"`
+	RawBody     string                              `json:"raw_body"`
+	Status      enum_state.XenditWebhookEventStatus `json:"status"`
+	Attempts    int                                 `json:"attempts"`
+	LastError   string                              `json:"last_error"`
+	ProcessedAt helper_others.TimeRFC3339           `json:"processed_at"`
+	CreatedAt   helper_others.TimeRFC3339           `json:"created_at"`
+	UpdatedAt   helper_others.TimeRFC3339           `json:"updated_at"`
+}
@@ -0,0 +1,399 @@
+package oidc_provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryCacheDuration = time.Hour
+	// toleransi selisih jam dengan server provider
+	clockSkew = time.Minute
+)
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// flexibleBool menerima email_verified dalam bentuk boolean maupun string "true"
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	*b = flexibleBool(value == "true")
+	return nil
+}
+
+// audience bisa berupa string atau array string
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = multiple
+	return nil
+}
+
+type idTokenClaims struct {
+	Issuer        string       `json:"iss"`
+	Subject       string       `json:"sub"`
+	Audience      audience     `json:"aud"`
+	ExpiresAt     int64        `json:"exp"`
+	IssuedAt      int64        `json:"iat"`
+	Nonce         string       `json:"nonce"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+	GivenName     string       `json:"given_name"`
+	FamilyName    string       `json:"family_name"`
+}
+
+// GenericProvider adalah client OpenID Connect (authorization code + PKCE) untuk provider apa pun
+// yang menyediakan discovery document, misalnya Google, Keycloak atau Auth0
+type GenericProvider struct {
+	config     model.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu                 sync.Mutex
+	discovery          *discoveryDocument
+	discoveryFetchedAt time.Time
+	keys               map[string]*rsa.PublicKey
+}
+
+func NewGenericProvider(config model.OIDCProviderConfig) *GenericProvider {
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	return &GenericProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       map[string]*rsa.PublicKey{},
+	}
+}
+
+func (p *GenericProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *GenericProvider) AuthorizationURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (p *GenericProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*model.OIDCIdentity, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call token endpoint : %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response : %w", err)
+	}
+
+	newTokenResponse := new(tokenResponse)
+	if err := json.Unmarshal(body, newTokenResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse token response : %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d : %s %s", response.StatusCode, newTokenResponse.Error, newTokenResponse.ErrorDescription)
+	}
+
+	if newTokenResponse.IDToken == "" {
+		return nil, errors.New("token response does not contain id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, newTokenResponse.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	newIdentity := &model.OIDCIdentity{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}
+
+	// sebagian provider hanya mengirim name tanpa given_name / family_name
+	if newIdentity.FirstName == "" && claims.Name != "" {
+		names := strings.SplitN(claims.Name, " ", 2)
+		newIdentity.FirstName = names[0]
+		if len(names) > 1 {
+			newIdentity.LastName = names[1]
+		}
+	}
+
+	return newIdentity, nil
+}
+
+func (p *GenericProvider) verifyIDToken(ctx context.Context, idToken string, nonce string) (*idTokenClaims, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("id_token is malformed")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("id_token header is malformed")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, errors.New("id_token header is malformed")
+	}
+
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("id_token algorithm %s is not supported", header.Alg)
+	}
+
+	key, err := p.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("id_token signature is malformed")
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("id_token signature is not valid")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("id_token payload is malformed")
+	}
+
+	claims := new(idTokenClaims)
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, errors.New("id_token payload is malformed")
+	}
+
+	// google mengirim iss dengan atau tanpa skema https://
+	if claims.Issuer != p.config.IssuerURL && "https://"+claims.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("id_token issuer %s is not valid", claims.Issuer)
+	}
+
+	validAudience := false
+	for _, aud := range claims.Audience {
+		if aud == p.config.ClientID {
+			validAudience = true
+			break
+		}
+	}
+	if !validAudience {
+		return nil, errors.New("id_token audience is not valid")
+	}
+
+	now := time.Now()
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, errors.New("id_token is expired")
+	}
+
+	if claims.IssuedAt > 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return nil, errors.New("id_token is issued in the future")
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("id_token nonce is not valid")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("id_token does not contain subject")
+	}
+
+	return claims, nil
+}
+
+func (p *GenericProvider) getDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil && time.Since(p.discoveryFetchedAt) < discoveryCacheDuration {
+		return p.discovery, nil
+	}
+
+	newDiscovery := new(discoveryDocument)
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", newDiscovery); err != nil {
+		return nil, fmt.Errorf("failed to get discovery document : %w", err)
+	}
+
+	if strings.TrimSuffix(newDiscovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("discovery issuer %s does not match %s", newDiscovery.Issuer, p.config.IssuerURL)
+	}
+
+	if newDiscovery.AuthorizationEndpoint == "" || newDiscovery.TokenEndpoint == "" || newDiscovery.JwksURI == "" {
+		return nil, errors.New("discovery document is incomplete")
+	}
+
+	p.discovery = newDiscovery
+	p.discoveryFetchedAt = time.Now()
+	return newDiscovery, nil
+}
+
+// getKey mengambil public key berdasarkan kid, JWKS diambil ulang jika kid belum dikenal (rotasi key)
+func (p *GenericProvider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	keySet := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := p.getJSON(ctx, discovery.JwksURI, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to get jwks : %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := parseRSAKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not found", kid)
+	}
+
+	return key, nil
+}
+
+func (p *GenericProvider) getJSON(ctx context.Context, target string, result any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", target, response.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(result)
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
@@ -0,0 +1,43 @@
+package oidc_provider
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/interfaces"
+	"sort"
+)
+
+// Registry menyimpan semua provider OpenID Connect yang aktif, dipilih berdasarkan nama di URL
+type Registry struct {
+	providers map[string]interfaces.OIDCProvider
+}
+
+func NewRegistry(providers ...interfaces.OIDCProvider) *Registry {
+	registry := &Registry{
+		providers: map[string]interfaces.OIDCProvider{},
+	}
+
+	for _, provider := range providers {
+		registry.providers[provider.Name()] = provider
+	}
+
+	return registry
+}
+
+func (r *Registry) Get(name string) (interfaces.OIDCProvider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("oidc provider %s is not available", name)
+	}
+
+	return provider, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
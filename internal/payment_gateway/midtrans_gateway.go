@@ -0,0 +1,448 @@
+package payment_gateway
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+const (
+	MIDTRANS_API_TYPE_SNAP     = "SNAP"
+	MIDTRANS_API_TYPE_CORE_API = "CORE_API"
+
+	// format waktu dari midtrans, selalu dalam zona waktu WIB
+	midtransTimeLayout = "2006-01-02 15:04:05"
+)
+
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+type MidtransGateway struct {
+	SnapClient    *snap.Client
+	CoreAPIClient *coreapi.Client
+	ServerKey     string
+}
+
+func NewMidtransGateway(midtransConfig *model.MidtransConfig) *MidtransGateway {
+	env := midtrans.Sandbox
+	if midtransConfig.IsProduction {
+		env = midtrans.Production
+	}
+
+	snapClient := new(snap.Client)
+	snapClient.New(midtransConfig.ServerKey, env)
+	coreAPIClient := new(coreapi.Client)
+	coreAPIClient.New(midtransConfig.ServerKey, env)
+
+	return &MidtransGateway{
+		SnapClient:    snapClient,
+		CoreAPIClient: coreAPIClient,
+		ServerKey:     midtransConfig.ServerKey,
+	}
+}
+
+func (g *MidtransGateway) Name() enum_state.PaymentGateway {
+	return enum_state.PAYMENT_GATEWAY_MIDTRANS
+}
+
+// CreatePayment membuat transaksi snap untuk channel MIDTRANS_SNAP, selain itu memakai charge core api.
+// ReferenceId dipakai sebagai order_id midtrans sehingga harus unik untuk setiap transaksi.
+func (g *MidtransGateway) CreatePayment(ctx context.Context, request *model.CreatePaymentGatewayRequest) (*model.PaymentGatewayResponse, error) {
+	transactionDetails := midtrans.TransactionDetails{
+		OrderID:  request.ReferenceId,
+		GrossAmt: int64(math.Round(float64(request.Amount))),
+	}
+
+	items := midtransItems(request.Items, transactionDetails.GrossAmt)
+	expiryDuration := int(math.Ceil(time.Until(request.ExpiresAt).Minutes()))
+	if expiryDuration < 1 {
+		expiryDuration = 1
+	}
+
+	if request.ChannelCode == enum_state.MIDTRANS_SNAP_CHANNEL_CODE {
+		if request.PaymentMethod != enum_state.PAYMENT_METHOD_CHECKOUT {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available for channel code %s!", request.PaymentMethod, request.ChannelCode))
+		}
+
+		snapRequest := &snap.Request{
+			TransactionDetails: transactionDetails,
+			Items:              items,
+			EnabledPayments: []snap.SnapPaymentType{
+				snap.PaymentTypeGopay,
+				snap.PaymentTypeBCAVA,
+				snap.PaymentTypeBNIVA,
+				snap.PaymentTypeBRIVA,
+				snap.PaymentTypePermataVA,
+				snap.SnapPaymentType("other_qris"),
+			},
+			Expiry: &snap.ExpiryDetails{
+				Unit:     "minute",
+				Duration: int64(expiryDuration),
+			},
+			UserId:   request.CustomerId,
+			Metadata: request.Metadata,
+		}
+
+		resp, resErr := g.SnapClient.CreateTransaction(snapRequest)
+		if resErr != nil {
+			return nil, fiber.NewError(midtransStatusCode(resErr), fmt.Sprintf("failed to create new midtrans snap transaction : %+v", resErr.GetMessage()))
+		}
+
+		// snap belum punya transaction_id sampai customer memilih channel pembayaran
+		now := time.Now().UTC()
+		return &model.PaymentGatewayResponse{
+			ID:            request.ReferenceId,
+			ReferenceId:   request.ReferenceId,
+			Amount:        float64(transactionDetails.GrossAmt),
+			Currency:      "IDR",
+			PaymentMethod: MIDTRANS_API_TYPE_SNAP,
+			ChannelCode:   string(request.ChannelCode),
+			Status:        "PENDING",
+			RawStatus:     "pending",
+			Description:   request.Description,
+			CheckoutToken: resp.Token,
+			CheckoutUrl:   resp.RedirectURL,
+			Metadata:      request.Metadata,
+			ExpiresAt:     request.ExpiresAt.UTC(),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}, nil
+	}
+
+	chargeRequest := &coreapi.ChargeReq{
+		TransactionDetails: transactionDetails,
+		Items:              items,
+		CustomExpiry: &coreapi.CustomExpiry{
+			ExpiryDuration: expiryDuration,
+			Unit:           "minute",
+		},
+		Metadata: request.Metadata,
+	}
+
+	switch request.ChannelCode {
+	case enum_state.MIDTRANS_QRIS_CHANNEL_CODE:
+		if request.PaymentMethod != enum_state.PAYMENT_METHOD_QR_CODE {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available for channel code %s!", request.PaymentMethod, request.ChannelCode))
+		}
+		chargeRequest.PaymentType = coreapi.PaymentTypeQris
+		chargeRequest.Qris = &coreapi.QrisDetails{Acquirer: "gopay"}
+	case enum_state.MIDTRANS_GOPAY_CHANNEL_CODE:
+		if request.PaymentMethod != enum_state.PAYMENT_METHOD_EWALLET {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available for channel code %s!", request.PaymentMethod, request.ChannelCode))
+		}
+		chargeRequest.PaymentType = coreapi.PaymentTypeGopay
+		chargeRequest.Gopay = &coreapi.GopayDetails{}
+	case enum_state.MIDTRANS_VA_BCA_CHANNEL_CODE, enum_state.MIDTRANS_VA_BNI_CHANNEL_CODE, enum_state.MIDTRANS_VA_BRI_CHANNEL_CODE, enum_state.MIDTRANS_VA_PERMATA_CHANNEL_CODE:
+		if request.PaymentMethod != enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available for channel code %s!", request.PaymentMethod, request.ChannelCode))
+		}
+		vaBanks := map[enum_state.ChannelCode]midtrans.Bank{
+			enum_state.MIDTRANS_VA_BCA_CHANNEL_CODE:     midtrans.BankBca,
+			enum_state.MIDTRANS_VA_BNI_CHANNEL_CODE:     midtrans.BankBni,
+			enum_state.MIDTRANS_VA_BRI_CHANNEL_CODE:     midtrans.BankBri,
+			enum_state.MIDTRANS_VA_PERMATA_CHANNEL_CODE: midtrans.BankPermata,
+		}
+		chargeRequest.PaymentType = coreapi.PaymentTypeBankTransfer
+		chargeRequest.BankTransfer = &coreapi.BankTransferDetails{Bank: vaBanks[request.ChannelCode]}
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway %s!", request.ChannelCode, g.Name()))
+	}
+
+	resp, resErr := g.CoreAPIClient.ChargeTransaction(chargeRequest)
+	if resErr != nil {
+		return nil, fiber.NewError(midtransStatusCode(resErr), fmt.Sprintf("failed to create new midtrans transaction : %+v", resErr.GetMessage()))
+	}
+
+	response := &model.PaymentGatewayResponse{
+		ID:              resp.OrderID,
+		ReferenceId:     resp.OrderID,
+		Currency:        resp.Currency,
+		PaymentMethod:   resp.PaymentType,
+		PaymentMethodId: resp.TransactionID,
+		ChannelCode:     string(request.ChannelCode),
+		QrString:        resp.QRString,
+		Status:          MidtransStatus(resp.TransactionStatus, resp.FraudStatus),
+		RawStatus:       resp.TransactionStatus,
+		FraudStatus:     resp.FraudStatus,
+		Description:     request.Description,
+		Metadata:        request.Metadata,
+		ExpiresAt:       request.ExpiresAt.UTC(),
+	}
+
+	if response.Currency == "" {
+		response.Currency = "IDR"
+	}
+
+	if amount, err := strconv.ParseFloat(resp.GrossAmount, 64); err == nil {
+		response.Amount = amount
+	}
+
+	for _, action := range resp.Actions {
+		if action.Name == "deeplink-redirect" {
+			response.DeeplinkUrl = action.URL
+		}
+	}
+
+	if len(resp.VaNumbers) > 0 {
+		response.VirtualAccountBank = resp.VaNumbers[0].Bank
+		response.VirtualAccountNumber = resp.VaNumbers[0].VANumber
+	}
+
+	if resp.PermataVaNumber != "" {
+		response.VirtualAccountBank = string(midtrans.BankPermata)
+		response.VirtualAccountNumber = resp.PermataVaNumber
+	}
+
+	if expiresAt, err := parseMidtransTime(resp.ExpiryTime); err == nil {
+		response.ExpiresAt = expiresAt
+	}
+
+	transactionTime, err := parseMidtransTime(resp.TransactionTime)
+	if err != nil {
+		transactionTime = time.Now().UTC()
+	}
+	response.CreatedAt = transactionTime
+	response.UpdatedAt = transactionTime
+
+	return response, nil
+}
+
+// GetPaymentStatus menerima order_id atau transaction_id midtrans
+func (g *MidtransGateway) GetPaymentStatus(ctx context.Context, paymentId string) (*model.PaymentGatewayResponse, error) {
+	resp, resErr := g.CoreAPIClient.CheckTransaction(paymentId)
+	if resErr != nil {
+		return nil, fiber.NewError(midtransStatusCode(resErr), fmt.Sprintf("failed to find midtrans transaction : %+v", resErr.GetMessage()))
+	}
+
+	response := &model.PaymentGatewayResponse{
+		ID:              resp.OrderID,
+		ReferenceId:     resp.OrderID,
+		Currency:        resp.Currency,
+		PaymentMethod:   resp.PaymentType,
+		PaymentMethodId: resp.TransactionID,
+		Status:          MidtransStatus(resp.TransactionStatus, resp.FraudStatus),
+		RawStatus:       resp.TransactionStatus,
+		FraudStatus:     resp.FraudStatus,
+		FailureCode:     resp.ChannelResponseCode,
+	}
+
+	if amount, err := strconv.ParseFloat(resp.GrossAmount, 64); err == nil {
+		response.Amount = amount
+	}
+
+	if len(resp.VaNumbers) > 0 {
+		response.VirtualAccountBank = resp.VaNumbers[0].Bank
+		response.VirtualAccountNumber = resp.VaNumbers[0].VANumber
+	}
+
+	if resp.PermataVaNumber != "" {
+		response.VirtualAccountBank = string(midtrans.BankPermata)
+		response.VirtualAccountNumber = resp.PermataVaNumber
+	}
+
+	if expiresAt, err := parseMidtransTime(resp.ExpiryTime); err == nil {
+		response.ExpiresAt = expiresAt
+	}
+
+	if createdAt, err := parseMidtransTime(resp.TransactionTime); err == nil {
+		response.CreatedAt = createdAt
+		response.UpdatedAt = createdAt
+	}
+
+	if settledAt, err := parseMidtransTime(resp.SettlementTime); err == nil {
+		response.UpdatedAt = settledAt
+	}
+
+	return response, nil
+}
+
+// CancelPayment meng-expire transaksi yang masih pending sehingga tidak bisa dibayar lagi
+func (g *MidtransGateway) CancelPayment(ctx context.Context, payment *model.PaymentGatewayResponse) (*model.PaymentGatewayResponse, error) {
+	_, resErr := g.CoreAPIClient.ExpireTransaction(payment.ID)
+	if resErr != nil {
+		return nil, fiber.NewError(midtransStatusCode(resErr), fmt.Sprintf("failed to cancel midtrans transaction : %+v", resErr.GetMessage()))
+	}
+
+	return g.GetPaymentStatus(ctx, payment.ID)
+}
+
+func (g *MidtransGateway) RefundPayment(ctx context.Context, request *model.RefundPaymentGatewayRequest) (*model.RefundPaymentGatewayResponse, error) {
+	refundRequest := &coreapi.RefundReq{
+		RefundKey: request.IdempotencyKey,
+		Amount:    int64(math.Round(float64(request.Amount))),
+		Reason:    request.Reason,
+	}
+
+	resp, resErr := g.CoreAPIClient.RefundTransaction(request.PaymentId, refundRequest)
+	if resErr != nil {
+		return nil, fiber.NewError(midtransStatusCode(resErr), fmt.Sprintf("failed to refund midtrans transaction : %+v", resErr.GetMessage()))
+	}
+
+	response := &model.RefundPaymentGatewayResponse{
+		ID:        resp.RefundKey,
+		PaymentId: resp.TransactionID,
+		Currency:  resp.Currency,
+		Status:    "SUCCEEDED",
+	}
+
+	if amount, err := strconv.ParseFloat(resp.RefundAmount, 64); err == nil {
+		response.Amount = amount
+	}
+
+	// refund midtrans langsung diproses, status code selain 200 berarti masih diproses / ditolak
+	if resp.StatusCode != "200" {
+		response.Status = "PENDING"
+		response.FailureCode = resp.StatusCode
+	}
+
+	if createdAt, err := parseMidtransTime(resp.TransactionTime); err == nil {
+		response.CreatedAt = createdAt
+	}
+
+	return response, nil
+}
+
+// ParseWebhook memverifikasi signature_key notifikasi midtrans lalu memetakan statusnya ke status standar
+func (g *MidtransGateway) ParseWebhook(rawBody []byte) (*model.PaymentGatewayWebhook, error) {
+	notification := new(model.MidtransNotificationRequest)
+	if err := json.Unmarshal(rawBody, notification); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
+	}
+
+	if notification.OrderId == "" || notification.TransactionStatus == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid midtrans notification body : order id and transaction status are required")
+	}
+
+	if !g.VerifySignature(notification.OrderId, notification.StatusCode, notification.GrossAmount, notification.SignatureKey) {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid midtrans signature key!")
+	}
+
+	webhook := &model.PaymentGatewayWebhook{
+		EventType:       fmt.Sprintf("midtrans.%s", notification.TransactionStatus),
+		PaymentId:       notification.OrderId,
+		PaymentMethodId: notification.TransactionId,
+		Status:          MidtransStatus(notification.TransactionStatus, notification.FraudStatus),
+		RawStatus:       notification.TransactionStatus,
+		FraudStatus:     notification.FraudStatus,
+		UpdatedAt:       time.Now().UTC(),
+	}
+
+	if updatedAt, err := parseMidtransTime(notification.SettlementTime); err == nil {
+		webhook.UpdatedAt = updatedAt
+	} else if updatedAt, err := parseMidtransTime(notification.TransactionTime); err == nil {
+		webhook.UpdatedAt = updatedAt
+	}
+
+	return webhook, nil
+}
+
+// VerifySignature mencocokkan signature_key = SHA512(order_id + status_code + gross_amount + server_key)
+func (g *MidtransGateway) VerifySignature(orderId string, statusCode string, grossAmount string, signatureKey string) bool {
+	if g.ServerKey == "" || signatureKey == "" {
+		return false
+	}
+
+	hash := sha512.Sum512([]byte(orderId + statusCode + grossAmount + g.ServerKey))
+	expected := hex.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signatureKey)) == 1
+}
+
+// MidtransStatus memetakan transaction_status midtrans ke status standar payment gateway
+func MidtransStatus(transactionStatus string, fraudStatus string) string {
+	switch transactionStatus {
+	case "capture":
+		// transaksi kartu dengan fraud challenge masih harus di-review di dashboard midtrans
+		if fraudStatus == "challenge" {
+			return "PENDING"
+		}
+		if fraudStatus == "deny" {
+			return "FAILED"
+		}
+		return "SUCCEEDED"
+	case "settlement":
+		return "SUCCEEDED"
+	case "pending", "authorize":
+		return "PENDING"
+	case "deny", "failure":
+		return "FAILED"
+	case "cancel":
+		return "CANCELED"
+	case "expire":
+		return "EXPIRED"
+	default:
+		return "PENDING"
+	}
+}
+
+// MidtransPaymentStatus memetakan transaction_status midtrans ke payment status order
+func MidtransPaymentStatus(transactionStatus string, fraudStatus string) enum_state.PaymentStatus {
+	switch MidtransStatus(transactionStatus, fraudStatus) {
+	case "SUCCEEDED":
+		return enum_state.PAID_PAYMENT
+	case "FAILED":
+		return enum_state.FAILED_PAYMENT
+	case "CANCELED":
+		return enum_state.CANCELLED_PAYMENT
+	case "EXPIRED":
+		return enum_state.EXPIRED_PAYMENT
+	default:
+		return enum_state.PENDING_PAYMENT
+	}
+}
+
+// midtransItems mengirim item_details hanya jika totalnya sama dengan gross_amount,
+// midtrans menolak transaksi yang total item-nya berbeda
+func midtransItems(paymentItems []model.PaymentGatewayItem, grossAmount int64) *[]midtrans.ItemDetails {
+	items := []midtrans.ItemDetails{}
+	var total int64
+	for _, item := range paymentItems {
+		price := int64(math.Round(float64(item.Price)))
+		if item.Type == string(enum_state.ITEM_TYPE_DISCOUNT) {
+			price = -price
+		}
+
+		items = append(items, midtrans.ItemDetails{
+			ID:       item.ReferenceId,
+			Name:     item.Name,
+			Price:    price,
+			Qty:      int32(item.Quantity),
+			Category: item.Category,
+		})
+		total += price * int64(item.Quantity)
+	}
+
+	if len(items) == 0 || total != grossAmount {
+		return nil
+	}
+
+	return &items
+}
+
+func midtransStatusCode(resErr *midtrans.Error) int {
+	if resErr.StatusCode >= 400 && resErr.StatusCode < 600 {
+		return resErr.StatusCode
+	}
+
+	return fiber.StatusInternalServerError
+}
+
+func parseMidtransTime(value string) (time.Time, error) {
+	parsedTime, err := time.ParseInLocation(midtransTimeLayout, value, midtransLocation)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsedTime.UTC(), nil
+}
@@ -0,0 +1,38 @@
+package payment_gateway
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/interfaces"
+)
+
+// Registry menyimpan semua payment gateway yang aktif, dipilih berdasarkan enum_state.PaymentGateway
+type Registry struct {
+	gateways map[enum_state.PaymentGateway]interfaces.PaymentGateway
+}
+
+func NewRegistry(gateways ...interfaces.PaymentGateway) *Registry {
+	registry := &Registry{
+		gateways: map[enum_state.PaymentGateway]interfaces.PaymentGateway{},
+	}
+
+	for _, gateway := range gateways {
+		registry.gateways[gateway.Name()] = gateway
+	}
+
+	return registry
+}
+
+func (r *Registry) Get(name enum_state.PaymentGateway) (interfaces.PaymentGateway, error) {
+	gateway, ok := r.gateways[name]
+	if !ok {
+		return nil, fmt.Errorf("payment gateway %s is not available", name)
+	}
+
+	return gateway, nil
+}
+
+func (r *Registry) Has(name enum_state.PaymentGateway) bool {
+	_, ok := r.gateways[name]
+	return ok
+}
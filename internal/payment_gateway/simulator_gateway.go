@@ -0,0 +1,204 @@
+package payment_gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SimulatorGateway adalah payment gateway lokal untuk dev/test, tidak memanggil API luar.
+// Data pembayaran hanya disimpan di memory sehingga hilang saat aplikasi restart.
+type SimulatorGateway struct {
+	mu       sync.Mutex
+	payments map[string]*model.PaymentGatewayResponse
+}
+
+func NewSimulatorGateway() *SimulatorGateway {
+	return &SimulatorGateway{
+		payments: map[string]*model.PaymentGatewayResponse{},
+	}
+}
+
+func (g *SimulatorGateway) Name() enum_state.PaymentGateway {
+	return enum_state.PAYMENT_GATEWAY_SIMULATOR
+}
+
+func (g *SimulatorGateway) CreatePayment(ctx context.Context, request *model.CreatePaymentGatewayRequest) (*model.PaymentGatewayResponse, error) {
+	// aturan payment method dan channel code sama dengan xendit
+	if _, err := xenditPaymentMethodParameters(request); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// idempotency key yang sama mengembalikan pembayaran yang sama seperti xendit
+	for _, payment := range g.payments {
+		if payment.ReferenceId == request.IdempotencyKey {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+	currency := request.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	payment := &model.PaymentGatewayResponse{
+		ID:              fmt.Sprintf("pr-sim-%s", id),
+		ReferenceId:     request.IdempotencyKey,
+		Amount:          float64(request.Amount),
+		Currency:        currency,
+		PaymentMethod:   string(request.PaymentMethod),
+		PaymentMethodId: fmt.Sprintf("pm-sim-%s", id),
+		Status:          "PENDING",
+		Description:     request.Description,
+		Metadata:        request.Metadata,
+		ExpiresAt:       request.ExpiresAt.UTC(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	switch request.PaymentMethod {
+	case enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT:
+		payment.ChannelCode = strings.TrimPrefix(string(request.ChannelCode), "VA_")
+		payment.VirtualAccountBank = payment.ChannelCode
+		// nomor virtual account simulator diawali 8808 seperti prefix merchant xendit
+		payment.VirtualAccountNumber = fmt.Sprintf("8808%012d", now.UnixNano()%1000000000000)
+	case enum_state.PAYMENT_METHOD_EWALLET:
+		payment.ChannelCode = strings.TrimPrefix(string(request.ChannelCode), "EWALLET_")
+		// OVO dibayar lewat push notification sehingga tidak punya url checkout
+		if request.ChannelCode != enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE {
+			payment.CheckoutUrl = fmt.Sprintf("https://simulator.local/ewallets/%s/checkout", id)
+			payment.DeeplinkUrl = fmt.Sprintf("simulator://ewallets/%s", id)
+		}
+	default:
+		payment.ChannelCode = strings.TrimPrefix(string(request.ChannelCode), "QR_")
+		payment.QrString = fmt.Sprintf("SIMULATOR.QR.%s.%.0f", id, request.Amount)
+	}
+
+	g.payments[payment.ID] = payment
+	copied := *payment
+	return &copied, nil
+}
+
+func (g *SimulatorGateway) GetPaymentStatus(ctx context.Context, paymentId string) (*model.PaymentGatewayResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, err := g.find(paymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	// pembayaran yang belum dibayar sampai batas waktu dianggap expired
+	if payment.Status == "PENDING" && !payment.ExpiresAt.IsZero() && time.Now().After(payment.ExpiresAt) {
+		payment.Status = "EXPIRED"
+		payment.UpdatedAt = time.Now().UTC()
+	}
+
+	copied := *payment
+	return &copied, nil
+}
+
+func (g *SimulatorGateway) CancelPayment(ctx context.Context, payment *model.PaymentGatewayResponse) (*model.PaymentGatewayResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	storedPayment, err := g.find(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if storedPayment.Status != "PENDING" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment with status %s can't be cancelled!", storedPayment.Status))
+	}
+
+	storedPayment.Status = "CANCELED"
+	storedPayment.UpdatedAt = time.Now().UTC()
+	copied := *storedPayment
+	return &copied, nil
+}
+
+func (g *SimulatorGateway) RefundPayment(ctx context.Context, request *model.RefundPaymentGatewayRequest) (*model.RefundPaymentGatewayResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, err := g.find(request.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != "SUCCEEDED" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment with status %s can't be refunded!", payment.Status))
+	}
+
+	if float64(request.Amount) > payment.Amount {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "refund amount can't be more than payment amount!")
+	}
+
+	return &model.RefundPaymentGatewayResponse{
+		ID:        fmt.Sprintf("rfd-sim-%s", uuid.NewString()),
+		PaymentId: payment.ID,
+		Amount:    float64(request.Amount),
+		Currency:  payment.Currency,
+		Status:    "SUCCEEDED",
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (g *SimulatorGateway) ParseWebhook(rawBody []byte) (*model.PaymentGatewayWebhook, error) {
+	return parsePaymentRequestWebhook(rawBody)
+}
+
+// Simulate mengubah status pembayaran lalu membuat body webhook dengan format payment request xendit
+func (g *SimulatorGateway) Simulate(paymentId string, status string) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, err := g.find(paymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != "PENDING" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment with status %s can't be simulated anymore!", payment.Status))
+	}
+
+	payment.Status = status
+	payment.UpdatedAt = time.Now().UTC()
+	return json.Marshal(map[string]any{
+		"event": fmt.Sprintf("payment.%s", strings.ToLower(status)),
+		"data": map[string]any{
+			"id":             payment.ID,
+			"reference_id":   payment.ReferenceId,
+			"amount":         payment.Amount,
+			"currency":       payment.Currency,
+			"payment_method": map[string]any{"id": payment.PaymentMethodId},
+			"status":         payment.Status,
+			"metadata":       payment.Metadata,
+			"updated":        payment.UpdatedAt.Format(time.RFC3339),
+		},
+	})
+}
+
+// find harus dipanggil saat mu sudah di-lock
+func (g *SimulatorGateway) find(paymentId string) (*model.PaymentGatewayResponse, error) {
+	payment, ok := g.payments[paymentId]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("simulator payment %s not found!", paymentId))
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,368 @@
+package payment_gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xendit/xendit-go/v6"
+	"github.com/xendit/xendit-go/v6/payment_request"
+	"github.com/xendit/xendit-go/v6/refund"
+)
+
+type XenditGateway struct {
+	Client *xendit.APIClient
+}
+
+func NewXenditGateway(client *xendit.APIClient) *XenditGateway {
+	return &XenditGateway{
+		Client: client,
+	}
+}
+
+func (g *XenditGateway) Name() enum_state.PaymentGateway {
+	return enum_state.PAYMENT_GATEWAY_XENDIT
+}
+
+func (g *XenditGateway) CreatePayment(ctx context.Context, request *model.CreatePaymentGatewayRequest) (*model.PaymentGatewayResponse, error) {
+	paymentMethodParameters, err := xenditPaymentMethodParameters(request)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentRequestBasketItems := []payment_request.PaymentRequestBasketItem{}
+	for _, item := range request.Items {
+		refId := item.ReferenceId
+		itemType := item.Type
+		paymentRequestBasketItems = append(paymentRequestBasketItems, payment_request.PaymentRequestBasketItem{
+			ReferenceId: &refId,
+			Name:        item.Name,
+			Currency:    string(payment_request.PAYMENTREQUESTCURRENCY_IDR),
+			Quantity:    float64(item.Quantity),
+			Price:       float64(item.Price),
+			Category:    item.Category,
+			Type:        &itemType,
+		})
+	}
+
+	amountFloat64 := float64(request.Amount)
+	desc := request.Description
+	custId := request.CustomerId
+	paymentRequestParameters := &payment_request.PaymentRequestParameters{
+		Amount:        &amountFloat64,
+		Currency:      payment_request.PAYMENTREQUESTCURRENCY_IDR,
+		Description:   *payment_request.NewNullableString(&desc),
+		PaymentMethod: paymentMethodParameters,
+		Items:         paymentRequestBasketItems,
+		CustomerId:    *payment_request.NewNullableString(&custId),
+		Metadata:      request.Metadata,
+	}
+
+	resp, _, resErr := g.Client.PaymentRequestApi.CreatePaymentRequest(ctx).
+		PaymentRequestParameters(*paymentRequestParameters).IdempotencyKey(request.IdempotencyKey).
+		Execute()
+
+	if resErr != nil {
+		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to create new xendit transaction : %+v", resErr.FullError()))
+	}
+
+	return paymentRequestToResponse(resp)
+}
+
+func (g *XenditGateway) GetPaymentStatus(ctx context.Context, paymentId string) (*model.PaymentGatewayResponse, error) {
+	resp, _, resErr := g.Client.PaymentRequestApi.GetPaymentRequestByID(ctx, paymentId).Execute()
+	if resErr != nil {
+		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to find xendit transaction : %+v", resErr.FullError()))
+	}
+
+	return paymentRequestToResponse(resp)
+}
+
+// CancelPayment meng-expire payment method dari payment request sehingga QR tidak bisa dibayar lagi
+func (g *XenditGateway) CancelPayment(ctx context.Context, payment *model.PaymentGatewayResponse) (*model.PaymentGatewayResponse, error) {
+	_, _, resErr := g.Client.PaymentMethodApi.ExpirePaymentMethod(ctx, payment.PaymentMethodId).Execute()
+	if resErr != nil {
+		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to cancel xendit transaction : %+v", resErr.FullError()))
+	}
+
+	return g.GetPaymentStatus(ctx, payment.ID)
+}
+
+func (g *XenditGateway) RefundPayment(ctx context.Context, request *model.RefundPaymentGatewayRequest) (*model.RefundPaymentGatewayResponse, error) {
+	amount := float64(request.Amount)
+	currency := request.Currency
+	if currency == "" {
+		currency = string(payment_request.PAYMENTREQUESTCURRENCY_IDR)
+	}
+
+	createRefund := refund.CreateRefund{
+		PaymentRequestId: &request.PaymentId,
+		ReferenceId:      &request.ReferenceId,
+		Amount:           &amount,
+		Currency:         &currency,
+		Reason:           &request.Reason,
+	}
+
+	resp, _, resErr := g.Client.RefundApi.CreateRefund(ctx).CreateRefund(createRefund).IdempotencyKey(request.IdempotencyKey).Execute()
+	if resErr != nil {
+		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to refund xendit transaction : %+v", resErr.FullError()))
+	}
+
+	response := &model.RefundPaymentGatewayResponse{
+		ID:          resp.GetId(),
+		PaymentId:   resp.GetPaymentRequestId(),
+		Amount:      resp.GetAmount(),
+		Currency:    resp.GetCurrency(),
+		Status:      "PENDING",
+		FailureCode: resp.GetFailureCode(),
+	}
+
+	// status refund xendit dikirim lewat webhook, failure code menandakan refund langsung ditolak
+	if response.FailureCode != "" {
+		response.Status = "FAILED"
+	}
+
+	if createdAt, err := time.Parse(time.RFC3339Nano, resp.GetCreated()); err == nil {
+		response.CreatedAt = createdAt.UTC()
+	}
+
+	return response, nil
+}
+
+func (g *XenditGateway) ParseWebhook(rawBody []byte) (*model.PaymentGatewayWebhook, error) {
+	return parsePaymentRequestWebhook(rawBody)
+}
+
+// xenditEWalletChannelCodes memetakan channel code e-wallet aplikasi ke channel code xendit
+var xenditEWalletChannelCodes = map[enum_state.ChannelCode]payment_request.EWalletChannelCode{
+	enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE:       payment_request.EWALLETCHANNELCODE_OVO,
+	enum_state.XENDIT_EWALLET_DANA_CHANNEL_CODE:      payment_request.EWALLETCHANNELCODE_DANA,
+	enum_state.XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE: payment_request.EWALLETCHANNELCODE_SHOPEEPAY,
+	enum_state.XENDIT_EWALLET_LINKAJA_CHANNEL_CODE:   payment_request.EWALLETCHANNELCODE_LINKAJA,
+}
+
+// xenditVirtualAccountChannelCodes memetakan channel code virtual account aplikasi ke channel code xendit
+var xenditVirtualAccountChannelCodes = map[enum_state.ChannelCode]payment_request.VirtualAccountChannelCode{
+	enum_state.XENDIT_VA_BCA_CHANNEL_CODE:     payment_request.VIRTUALACCOUNTCHANNELCODE_BCA,
+	enum_state.XENDIT_VA_BNI_CHANNEL_CODE:     payment_request.VIRTUALACCOUNTCHANNELCODE_BNI,
+	enum_state.XENDIT_VA_BRI_CHANNEL_CODE:     payment_request.VIRTUALACCOUNTCHANNELCODE_BRI,
+	enum_state.XENDIT_VA_MANDIRI_CHANNEL_CODE: payment_request.VIRTUALACCOUNTCHANNELCODE_MANDIRI,
+	enum_state.XENDIT_VA_PERMATA_CHANNEL_CODE: payment_request.VIRTUALACCOUNTCHANNELCODE_PERMATA,
+}
+
+func xenditPaymentMethodParameters(request *model.CreatePaymentGatewayRequest) (*payment_request.PaymentMethodParameters, error) {
+	switch request.PaymentMethod {
+	case enum_state.PAYMENT_METHOD_QR_CODE:
+		qrisCode := payment_request.QRCODECHANNELCODE_DANA
+		if request.ChannelCode == enum_state.XENDIT_QR_LINKAJA_CHANNEL_CODE {
+			qrisCode = payment_request.QRCODECHANNELCODE_LINKAJA
+		}
+
+		qrCodeParam := new(payment_request.QRCodeParameters)
+		qrCodeParam.ChannelCode = *payment_request.NewNullableQRCodeChannelCode(&qrisCode)
+		qrCodeParam.ChannelProperties = payment_request.NewQRCodeChannelProperties()
+		expiresAt := request.ExpiresAt
+		qrCodeParam.ChannelProperties.ExpiresAt = &expiresAt
+
+		return &payment_request.PaymentMethodParameters{
+			Type:        payment_request.PAYMENTMETHODTYPE_QR_CODE,
+			Reusability: payment_request.PAYMENTMETHODREUSABILITY_ONE_TIME_USE,
+			QrCode:      *payment_request.NewNullableQRCodeParameters(qrCodeParam),
+		}, nil
+	case enum_state.PAYMENT_METHOD_EWALLET:
+		ewalletCode, ok := xenditEWalletChannelCodes[request.ChannelCode]
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway %s!", request.ChannelCode, enum_state.PAYMENT_GATEWAY_XENDIT))
+		}
+
+		channelProperties := payment_request.NewEWalletChannelProperties()
+		if ewalletCode == payment_request.EWALLETCHANNELCODE_OVO {
+			// OVO tidak memakai redirect, customer menyetujui pembayaran lewat push notification di aplikasi
+			if request.MobileNumber == "" {
+				return nil, fiber.NewError(fiber.StatusBadRequest, "mobile number is required for OVO payment!")
+			}
+			mobileNumber := request.MobileNumber
+			channelProperties.MobileNumber = &mobileNumber
+		} else {
+			successReturnUrl := request.SuccessReturnUrl
+			failureReturnUrl := request.FailureReturnUrl
+			channelProperties.SuccessReturnUrl = &successReturnUrl
+			channelProperties.FailureReturnUrl = &failureReturnUrl
+		}
+
+		ewalletParam := payment_request.NewEWalletParameters()
+		ewalletParam.ChannelCode = &ewalletCode
+		ewalletParam.ChannelProperties = channelProperties
+
+		return &payment_request.PaymentMethodParameters{
+			Type:        payment_request.PAYMENTMETHODTYPE_EWALLET,
+			Reusability: payment_request.PAYMENTMETHODREUSABILITY_ONE_TIME_USE,
+			Ewallet:     *payment_request.NewNullableEWalletParameters(ewalletParam),
+		}, nil
+	case enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT:
+		vaCode, ok := xenditVirtualAccountChannelCodes[request.ChannelCode]
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway %s!", request.ChannelCode, enum_state.PAYMENT_GATEWAY_XENDIT))
+		}
+
+		if request.CustomerName == "" {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "customer name is required for virtual account payment!")
+		}
+
+		// virtual account dibuat dengan nominal tetap sehingga customer tidak bisa membayar kurang / lebih
+		amount := float64(request.Amount)
+		expiresAt := request.ExpiresAt
+		vaParam := payment_request.NewVirtualAccountParameters(vaCode, payment_request.VirtualAccountChannelProperties{
+			CustomerName: request.CustomerName,
+			ExpiresAt:    &expiresAt,
+		})
+		vaParam.Amount = *payment_request.NewNullableFloat64(&amount)
+
+		return &payment_request.PaymentMethodParameters{
+			Type:           payment_request.PAYMENTMETHODTYPE_VIRTUAL_ACCOUNT,
+			Reusability:    payment_request.PAYMENTMETHODREUSABILITY_ONE_TIME_USE,
+			VirtualAccount: *payment_request.NewNullableVirtualAccountParameters(vaParam),
+		}, nil
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available on payment gateway %s!", request.PaymentMethod, enum_state.PAYMENT_GATEWAY_XENDIT))
+	}
+}
+
+func paymentRequestToResponse(resp *payment_request.PaymentRequest) (*model.PaymentGatewayResponse, error) {
+	response := &model.PaymentGatewayResponse{
+		ID:              resp.Id,
+		ReferenceId:     resp.ReferenceId,
+		Currency:        resp.Currency.String(),
+		PaymentMethod:   resp.PaymentMethod.Type.String(),
+		PaymentMethodId: resp.PaymentMethod.Id,
+		Status:          string(resp.Status),
+		Description:     resp.GetDescription(),
+		FailureCode:     resp.GetFailureCode(),
+		Metadata:        resp.GetMetadata(),
+	}
+
+	if resp.Amount != nil {
+		response.Amount = *resp.Amount
+	}
+
+	if qrCode := resp.PaymentMethod.QrCode.Get(); qrCode != nil {
+		if qrCode.ChannelCode.Get() != nil {
+			response.ChannelCode = qrCode.ChannelCode.Get().String()
+		}
+
+		if qrCode.ChannelProperties != nil {
+			if qrCode.ChannelProperties.QrString != nil {
+				response.QrString = *qrCode.ChannelProperties.QrString
+			}
+
+			if qrCode.ChannelProperties.ExpiresAt != nil {
+				response.ExpiresAt = *qrCode.ChannelProperties.ExpiresAt
+			}
+		}
+	}
+
+	if ewallet := resp.PaymentMethod.Ewallet.Get(); ewallet != nil {
+		if ewallet.ChannelCode != nil {
+			response.ChannelCode = ewallet.ChannelCode.String()
+		}
+	}
+
+	if virtualAccount := resp.PaymentMethod.VirtualAccount.Get(); virtualAccount != nil {
+		response.ChannelCode = virtualAccount.ChannelCode.String()
+		response.VirtualAccountBank = virtualAccount.ChannelCode.String()
+		if virtualAccount.ChannelProperties.VirtualAccountNumber != nil {
+			response.VirtualAccountNumber = *virtualAccount.ChannelProperties.VirtualAccountNumber
+		}
+
+		if virtualAccount.ChannelProperties.ExpiresAt != nil {
+			response.ExpiresAt = *virtualAccount.ChannelProperties.ExpiresAt
+		}
+	}
+
+	// url pembayaran e-wallet dikirim lewat actions, WEB untuk browser dan DEEPLINK untuk membuka aplikasi
+	for _, action := range resp.GetActions() {
+		url := action.GetUrl()
+		if url == "" {
+			continue
+		}
+
+		switch action.UrlType {
+		case "WEB":
+			response.CheckoutUrl = url
+		case "DEEPLINK":
+			response.DeeplinkUrl = url
+		case "MOBILE":
+			if response.DeeplinkUrl == "" {
+				response.DeeplinkUrl = url
+			}
+		}
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, resp.Created)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse created_at into UTC : %+v", err))
+	}
+	response.CreatedAt = createdAt.UTC()
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, resp.Updated)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse updated_at into UTC : %+v", err))
+	}
+	response.UpdatedAt = updatedAt.UTC()
+
+	return response, nil
+}
+
+// parsePaymentRequestWebhook membaca body webhook dengan format payment request xendit,
+// format yang sama juga dipakai oleh simulator. Selain event payment_request.* dan payment.*,
+// e-wallet juga mengirim event payment_method.* yang datanya berupa payment method itu sendiri
+func parsePaymentRequestWebhook(rawBody []byte) (*model.PaymentGatewayWebhook, error) {
+	payload := struct {
+		Event string `json:"event"`
+		Data  struct {
+			ID               string `json:"id"`
+			PaymentRequestId string `json:"payment_request_id"`
+			PaymentMethod    struct {
+				ID string `json:"id"`
+			} `json:"payment_method"`
+			Status    string                    `json:"status"`
+			Metadata  map[string]any            `json:"metadata"`
+			UpdatedAt helper_others.TimeRFC3339 `json:"updated"`
+		} `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(rawBody, &payload); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
+	}
+
+	paymentId := payload.Data.ID
+	paymentMethodId := payload.Data.PaymentMethod.ID
+	switch {
+	case strings.HasPrefix(payload.Event, "payment_method."):
+		// data.id adalah id payment method, payment request id tidak dikirim
+		paymentId = ""
+		paymentMethodId = payload.Data.ID
+	case payload.Data.PaymentRequestId != "":
+		// event payment.* memakai id payment (py-xxx), id payment request ada di payment_request_id
+		paymentId = payload.Data.PaymentRequestId
+	}
+
+	if paymentMethodId == "" || payload.Data.Status == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid payment webhook body : payment method id and status are required")
+	}
+
+	return &model.PaymentGatewayWebhook{
+		EventType:       payload.Event,
+		PaymentId:       paymentId,
+		PaymentMethodId: paymentMethodId,
+		Status:          payload.Data.Status,
+		Metadata:        payload.Data.Metadata,
+		UpdatedAt:       payload.Data.UpdatedAt.ToTime(),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package rate_limiter
+
+import (
+	"context"
+	"math"
+	"seblak-bombom-restful-api/internal/model"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens     float64
+	updatedAt  time.Time
+	fullPeriod time.Duration
+}
+
+// MemoryStore menyimpan token bucket di memory proses, bucket yang sudah terisi penuh dibersihkan berkala
+type MemoryStore struct {
+	mu            sync.Mutex
+	buckets       map[string]*bucket
+	sweepInterval time.Duration
+	lastSweepAt   time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       map[string]*bucket{},
+		sweepInterval: time.Minute,
+		lastSweepAt:   time.Now(),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy model.RateLimitPolicy) (*model.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(policy.Limit)
+	// jumlah token yang bertambah setiap detik
+	refillRate := capacity / policy.Period.Seconds()
+
+	current, ok := s.buckets[key]
+	if !ok {
+		current = &bucket{
+			tokens:    capacity,
+			updatedAt: now,
+		}
+		s.buckets[key] = current
+	}
+
+	elapsed := now.Sub(current.updatedAt).Seconds()
+	if elapsed > 0 {
+		current.tokens = math.Min(capacity, current.tokens+elapsed*refillRate)
+	}
+	current.updatedAt = now
+	current.fullPeriod = policy.Period
+
+	result := new(model.RateLimitResult)
+	result.Limit = policy.Limit
+	if current.tokens >= 1 {
+		current.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - current.tokens) / refillRate)
+	}
+
+	result.Remaining = int(math.Floor(current.tokens))
+	result.ResetAfter = secondsToDuration((capacity - current.tokens) / refillRate)
+	return result, nil
+}
+
+// sweep menghapus bucket yang tidak dipakai lebih lama dari period nya, bucket tersebut sudah penuh kembali
+// sehingga menghapusnya tidak mengubah hasil rate limit
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweepAt) < s.sweepInterval {
+		return
+	}
+
+	for key, current := range s.buckets {
+		if now.Sub(current.updatedAt) >= current.fullPeriod {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweepAt = now
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
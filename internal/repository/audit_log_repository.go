@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type AuditLogRepository struct {
+	Repository[entity.AuditLog]
+	Log *logrus.Logger
+}
+
+func NewAuditLogRepository(log *logrus.Logger) *AuditLogRepository {
+	return &AuditLogRepository{
+		Log: log,
+	}
+}
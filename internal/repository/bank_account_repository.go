@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type BankAccountRepository struct {
+	Repository[entity.BankAccount]
+	Log *logrus.Logger
+}
+
+func NewBankAccountRepository(log *logrus.Logger) *BankAccountRepository {
+	return &BankAccountRepository{
+		Log: log,
+	}
+}
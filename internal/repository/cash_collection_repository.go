@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type CashCollectionRepository struct {
+	Repository[entity.CashCollection]
+	Log *logrus.Logger
+}
+
+func NewCashCollectionRepository(log *logrus.Logger) *CashCollectionRepository {
+	return &CashCollectionRepository{
+		Log: log,
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type LoginAttemptRepository struct {
+	Repository[entity.LoginAttempt]
+	Log *logrus.Logger
+}
+
+func NewLoginAttemptRepository(log *logrus.Logger) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		Log: log,
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type MidtransTransactionRepository struct {
+	Repository[entity.MidtransTransaction]
+	Log *logrus.Logger
+}
+
+func NewMidtransTransactionRepository(log *logrus.Logger) *MidtransTransactionRepository {
+	return &MidtransTransactionRepository{
+		Log: log,
+	}
+}
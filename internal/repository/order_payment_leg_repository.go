@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type OrderPaymentLegRepository struct {
+	Repository[entity.OrderPaymentLeg]
+	Log *logrus.Logger
+}
+
+func NewOrderPaymentLegRepository(log *logrus.Logger) *OrderPaymentLegRepository {
+	return &OrderPaymentLegRepository{
+		Log: log,
+	}
+}
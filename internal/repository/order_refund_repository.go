@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type OrderRefundRepository struct {
+	Repository[entity.OrderRefund]
+	Log *logrus.Logger
+}
+
+func NewOrderRefundRepository(log *logrus.Logger) *OrderRefundRepository {
+	return &OrderRefundRepository{
+		Log: log,
+	}
+}
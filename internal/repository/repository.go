@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"seblak-bombom-restful-api/internal/entity"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Pagination struct {
@@ -35,6 +37,11 @@ func (r *Repository[T]) Update(db *gorm.DB, entity *T) error {
 	return db.Save(&entity).Error
 }
 
+// CreateIfNotExists menyimpan data baru, diabaikan jika data dengan unique key yang sama sudah ada
+func (r *Repository[T]) CreateIfNotExists(db *gorm.DB, entity *T) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(entity).Error
+}
+
 func (r *Repository[T]) UpdateCustomColumns(db *gorm.DB, entity *T, updateFields map[string]any) error {
 	return db.Model(entity).Updates(updateFields).Error
 }
@@ -94,6 +101,217 @@ func (r *Repository[T]) FindAndUpdateAddressToNonPrimary(db *gorm.DB, entity *T)
 	}
 }
 
+func (r *Repository[T]) FindFirstByUserId(db *gorm.DB, entity *T, userId uint64) error {
+	return db.Where("user_id = ?", userId).First(entity).Error
+}
+
+// FindFirstByUserIdForUpdate sama seperti FindFirstByUserId tetapi mengunci barisnya (SELECT ... FOR UPDATE) sampai transaksi selesai
+func (r *Repository[T]) FindFirstByUserIdForUpdate(db *gorm.DB, entity *T, userId uint64) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(entity).Error
+}
+
+func (r *Repository[T]) FindAllByUserId(db *gorm.DB, entities *[]T, userId uint64) error {
+	return db.Where("user_id = ?", userId).Find(entities).Error
+}
+
+func (r *Repository[T]) CountBankAccountByAccountNumber(db *gorm.DB, entity *T, userId uint64, channelCode string, accountNumber string) (int64, error) {
+	var count int64
+	err := db.Model(entity).Where("user_id = ? AND channel_code = ? AND account_number = ?", userId, channelCode, accountNumber).Count(&count).Error
+	return count, err
+}
+
+func (r *Repository[T]) FindAndCountWithdrawPolicyByMethod(db *gorm.DB, entity *T, method string) (int64, error) {
+	var count int64
+	if err := db.Model(entity).Where("method = ?", method).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, db.Where("method = ?", method).First(entity).Error
+}
+
+func (r *Repository[T]) FindAndCountXenditWebhookEventByEventId(db *gorm.DB, entity *T, eventId string) (int64, error) {
+	var count int64
+	if err := db.Model(entity).Where("event_id = ?", eventId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, db.Where("event_id = ?", eventId).First(entity).Error
+}
+
+func (r *Repository[T]) FindAndCountMidtransTransactionByMidtransOrderId(db *gorm.DB, entity *T, midtransOrderId string) (int64, error) {
+	var count int64
+	if err := db.Model(entity).Where("midtrans_order_id = ?", midtransOrderId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, db.Where("midtrans_order_id = ?", midtransOrderId).First(entity).Error
+}
+
+func (r *Repository[T]) FindAndCountMidtransTransactionByOrderId(db *gorm.DB, entity *T, orderId uint64) (int64, error) {
+	var count int64
+	if err := db.Model(entity).Where("order_id = ?", orderId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	// satu order bisa punya beberapa transaksi midtrans, ambil yang terbaru
+	return count, db.Where("order_id = ?", orderId).Preload("Order").Order("id DESC").First(entity).Error
+}
+
+// ClaimXenditWebhookEvent mengubah status event menjadi processing hanya jika statusnya masih
+// salah satu dari fromStatuses, sehingga event yang sama tidak diproses dua kali secara bersamaan.
+// Event yang tertahan di processing sejak sebelum staleBefore (misal karena crash) juga boleh diklaim ulang
+func (r *Repository[T]) ClaimXenditWebhookEvent(db *gorm.DB, entity *T, id uint64, fromStatuses []string, staleBefore time.Time) (int64, error) {
+	result := db.Model(entity).Where("id = ? AND (status IN ? OR (status = ? AND updated_at < ?))", id, fromStatuses, "processing", staleBefore).Updates(map[string]any{
+		"status":   "processing",
+		"attempts": gorm.Expr("attempts + 1"),
+	})
+	return result.RowsAffected, result.Error
+}
+
+func (r *Repository[T]) CountCashCollectionByOrderId(db *gorm.DB, entity *T, orderId uint64) (int64, error) {
+	var count int64
+	err := db.Model(entity).Where("order_id = ?", orderId).Count(&count).Error
+	return count, err
+}
+
+// FindCashCollectionsBetween mengambil uang tunai yang diterima staff pada rentang waktu tertentu,
+// collectedBy 0 berarti semua staff
+func (r *Repository[T]) FindCashCollectionsBetween(db *gorm.DB, entities *[]T, start time.Time, end time.Time, collectedBy uint64) error {
+	query := db.Where("collected_at >= ? AND collected_at < ?", start, end)
+	if collectedBy > 0 {
+		query = query.Where("collected_by = ?", collectedBy)
+	}
+
+	return query.Preload("Order").Preload("Collector").Order("collected_at ASC").Find(entities).Error
+}
+
+func (r *Repository[T]) ReconcileCashCollections(db *gorm.DB, entity *T, start time.Time, end time.Time, collectedBy uint64, reconciledBy uint64, reconciledAt time.Time) (int64, error) {
+	result := db.Model(entity).
+		Where("collected_at >= ? AND collected_at < ?", start, end).
+		Where("collected_by = ? AND reconciled_at IS NULL", collectedBy).
+		Updates(map[string]any{
+			"reconciled_by": reconciledBy,
+			"reconciled_at": reconciledAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// SumRefundedAmountByOrderId menjumlahkan refund yang belum gagal, dipakai untuk membatasi sisa nominal yang bisa direfund
+func (r *Repository[T]) SumRefundedAmountByOrderId(db *gorm.DB, entity *T, orderId uint64) (float32, error) {
+	var total float32
+	err := db.Model(entity).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND status <> ?", orderId, "failed").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *Repository[T]) SumRefundedAmountByOrderIdAndDestination(db *gorm.DB, entity *T, orderId uint64, destination string) (float32, error) {
+	var total float32
+	err := db.Model(entity).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND destination = ? AND status <> ?", orderId, destination, "failed").
+		Scan(&total).Error
+	return total, err
+}
+
+// SumRefundedQuantityByOrderId mengembalikan jumlah item yang sudah direfund per order product
+func (r *Repository[T]) SumRefundedQuantityByOrderId(db *gorm.DB, orderId uint64) (map[uint64]int, error) {
+	var rows []struct {
+		OrderProductId uint64
+		Quantity       int
+	}
+
+	err := db.Table("order_refund_items").
+		Select("order_refund_items.order_product_id, COALESCE(SUM(order_refund_items.quantity), 0) AS quantity").
+		Joins("JOIN order_refunds ON order_refunds.id = order_refund_items.order_refund_id").
+		Where("order_refunds.order_id = ? AND order_refunds.status <> ?", orderId, "failed").
+		Group("order_refund_items.order_product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := map[uint64]int{}
+	for _, row := range rows {
+		quantities[row.OrderProductId] = row.Quantity
+	}
+	return quantities, nil
+}
+
+func (r *Repository[T]) FindOrderRefundsByOrderId(db *gorm.DB, entities *[]T, orderId uint64) error {
+	return db.Where("order_id = ?", orderId).Preload("Items").Preload("Refunder").Order("created_at ASC").Find(entities).Error
+}
+
+func (r *Repository[T]) FirstOrderRefundByGatewayRefundId(db *gorm.DB, entity *T, gatewayRefundId string) error {
+	return db.Where("gateway_refund_id = ?", gatewayRefundId).First(entity).Error
+}
+
+// FindHeldWalletLegByOrderId mengambil saldo wallet yang masih ditahan untuk split payment,
+// ID 0 berarti order tidak memakai split payment atau saldonya sudah diproses
+func (r *Repository[T]) FindHeldWalletLegByOrderId(db *gorm.DB, entity *T, orderId uint64) error {
+	return db.Where("order_id = ? AND source = ? AND status = ?", orderId, "wallet", "held").Limit(1).Find(entity).Error
+}
+
+// SumPaidWalletLegAmountByOrderId menjumlahkan bagian order split payment yang dibayar dengan saldo wallet
+func (r *Repository[T]) SumPaidWalletLegAmountByOrderId(db *gorm.DB, entity *T, orderId uint64) (float32, error) {
+	var total float32
+	err := db.Model(entity).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND source = ? AND status = ?", orderId, "wallet", "paid").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *Repository[T]) UpdatePendingGatewayLegStatusByOrderId(db *gorm.DB, entity *T, orderId uint64, status string) error {
+	return db.Model(entity).Where("order_id = ? AND source = ? AND status = ?", orderId, "gateway", "pending").Update("status", status).Error
+}
+
+func (r *Repository[T]) SumWithdrawAmountByUserIdSince(db *gorm.DB, entity *T, userId uint64, since time.Time) (float32, error) {
+	var total float32
+	// request yang batal / ditolak / gagal tidak dihitung ke limit
+	err := db.Model(entity).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND created_at >= ?", userId, since).
+		Where("status NOT IN ?", []string{"rejected", "cancelled", "failed"}).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *Repository[T]) FindAndCountLatestTopUpByUserId(db *gorm.DB, entity *T, userId uint64) (int64, error) {
+	var count int64
+	query := db.Model(entity).Where("user_id = ? AND flow_type = ? AND transaction_type = ? AND status = ?", userId, "credit", "top_up", "completed")
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, db.Where("user_id = ? AND flow_type = ? AND transaction_type = ? AND status = ?", userId, "credit", "top_up", "completed").Order("created_at DESC").First(entity).Error
+}
+
+func (r *Repository[T]) FindPendingWalletCreditsByUserId(db *gorm.DB, entities *[]T, userId uint64) error {
+	return db.Where("user_id = ? AND flow_type = ? AND status = ?", userId, "credit", "pending").Find(entities).Error
+}
+
 func (r *Repository[T]) UpdateWalletBalance(db *gorm.DB, entity *T, userId uint64, balance float32) error {
 	return db.Model(entity).Where("user_id = ?", userId).Update("balance", balance).Error
 }
@@ -143,6 +361,26 @@ func (r *Repository[T]) FindXenditTransaction(db *gorm.DB, entity *T, payment_me
 	return count, result.Error // Kembalikan error jika ada kesalahan lain
 }
 
+func (r *Repository[T]) FindAndCountByXenditPayoutId(db *gorm.DB, entity *T, xenditPayoutId string) (int64, error) {
+	var count int64
+	err := db.Model(&entity).Where("xendit_payout_id = ?", xenditPayoutId).Count(&count)
+	if err.Error != nil {
+		return 0, err.Error
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, db.Where("xendit_payout_id = ?", xenditPayoutId).First(&entity).Error
+}
+
+// FindPendingXenditTransactions mengambil transaksi yang belum selesai dibayar dan dibuat sebelum createdBefore
+func (r *Repository[T]) FindPendingXenditTransactions(db *gorm.DB, entities *[]T, createdBefore time.Time, limit int) error {
+	return db.Where("status IN ? AND created_at <= ?", []string{"PENDING", "REQUIRES_ACTION"}, createdBefore).
+		Preload("Order").Order("created_at ASC").Limit(limit).Find(entities).Error
+}
+
 func (r *Repository[T]) FindXenditTransactionByPaymentMethodId(db *gorm.DB, entity *T, paymentMethodId string) (int64, error) {
 	var count int64
 	err := db.Model(&entity).Where("payment_method_id = ?", paymentMethodId).Count(&count)
@@ -182,14 +420,42 @@ func (r *Repository[T]) FindCount(db *gorm.DB, entity *T) (int64, error) {
 	return count, err
 }
 
-func (r *Repository[T]) FindUserByToken(db *gorm.DB, user *T, token_code string) error {
-	token := new(entity.Token)
-	// temukan data user_id
-	tokenWithUser := db.Where("token = ?", token_code).Joins("User").Find(&token).Error
-	if tokenWithUser != nil {
-		return tokenWithUser //return errornya
-	}
-	return db.Where("id = ?", token.UserId).Preload("Token").Preload("Addresses").Preload("Addresses.Delivery").Preload("Wallet").Preload("Cart").Preload("Cart.CartItems").Find(user).Error
+// FindCurrentUserById mengambil user pemilik access token beserta relasi yang dipakai handler
+func (r *Repository[T]) FindCurrentUserById(db *gorm.DB, user *T, userId uint64) error {
+	return db.Where("id = ?", userId).Preload("Addresses").Preload("Addresses.Delivery").Preload("Wallet").Preload("Cart").Preload("Cart.CartItems").Preload("StaffRole.Permissions").First(user).Error
+}
+
+func (r *Repository[T]) FindAllWithPreloads(db *gorm.DB, entities *[]T, preload string) error {
+	return db.Preload(preload).Order("id ASC").Find(entities).Error
+}
+
+// CountRoleByName menghitung role lain dengan nama yang sama, exceptId 0 berarti tanpa pengecualian
+func (r *Repository[T]) CountRoleByName(db *gorm.DB, entity *T, name string, exceptId uint64) (int64, error) {
+	var total int64
+	err := db.Model(entity).Where("name = ? AND id <> ?", name, exceptId).Count(&total).Error
+	return total, err
+}
+
+func (r *Repository[T]) DeleteAllByRoleId(db *gorm.DB, entity *T, roleId uint64) error {
+	return db.Where("role_id = ?", roleId).Delete(entity).Error
+}
+
+func (r *Repository[T]) CountUsersByRoleId(db *gorm.DB, entity *T, roleId uint64) (int64, error) {
+	var total int64
+	err := db.Model(entity).Where("role_id = ?", roleId).Count(&total).Error
+	return total, err
+}
+
+func (r *Repository[T]) CountByUserId(db *gorm.DB, entity *T, userId uint64) (int64, error) {
+	var total int64
+	err := db.Model(entity).Where("user_id = ?", userId).Count(&total).Error
+	return total, err
+}
+
+// DeleteAuditLogsBefore menghapus audit log yang melewati masa retensi
+func (r *Repository[T]) DeleteAuditLogsBefore(db *gorm.DB, entity *T, before time.Time) (int64, error) {
+	result := db.Where("created_at < ?", before).Delete(entity)
+	return result.RowsAffected, result.Error
 }
 
 func (r *Repository[T]) Delete(db *gorm.DB, entity *T) error {
@@ -208,6 +474,18 @@ func (r *Repository[T]) FindById(db *gorm.DB, entity *T) error {
 	return db.First(&entity).Error
 }
 
+// FindByIdForUpdate sama seperti FindById tetapi mengunci barisnya (SELECT ... FOR UPDATE) sampai transaksi selesai
+func (r *Repository[T]) FindByIdForUpdate(db *gorm.DB, entity *T) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity).Error
+}
+
+// UpdateStatusIfCurrent mengubah status hanya jika statusnya masih fromStatus, 0 baris berarti status
+// sudah lebih dulu diubah oleh request lain
+func (r *Repository[T]) UpdateStatusIfCurrent(db *gorm.DB, entity *T, id uint64, fromStatus string, toStatus string) (int64, error) {
+	result := db.Model(entity).Where("id = ? AND status = ?", id, fromStatus).Update("status", toStatus)
+	return result.RowsAffected, result.Error
+}
+
 func (r *Repository[T]) DeleteImages(db *gorm.DB, entity *T, ids []uint64, productId uint64) error {
 	return db.Where("id NOT IN ?", ids).Where("product_id = ?", productId).Delete(&entity).Error
 }
@@ -234,9 +512,78 @@ func (r *Repository[T]) FindAndCountProductById(db *gorm.DB, entity *T) (int64,
 	return count, nil
 }
 
-func (c *Repository[T]) DeleteToken(db *gorm.DB, entity *T, token string) *gorm.DB {
-	result := db.Where("token = ?", token).Delete(&entity)
-	return result
+// FindTokenByHash mengambil refresh token berdasarkan hash nya, termasuk yang sudah dirotasi / dicabut
+// agar pemakaian ulang refresh token bisa terdeteksi
+func (r *Repository[T]) FindTokenByHash(db *gorm.DB, entity *T, tokenHash string) error {
+	return db.Where("token = ?", tokenHash).First(entity).Error
+}
+
+// RotateToken menandai refresh token sudah dipakai, 0 baris berarti token sudah dirotasi / dicabut oleh request lain
+func (r *Repository[T]) RotateToken(db *gorm.DB, entity *T, tokenId uint64, rotatedAt time.Time) (int64, error) {
+	result := db.Model(entity).Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", tokenId).Update("rotated_at", rotatedAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *Repository[T]) RevokeTokenFamily(db *gorm.DB, entity *T, familyId string, revokedAt time.Time) error {
+	return db.Model(entity).Where("family_id = ? AND revoked_at IS NULL", familyId).Update("revoked_at", revokedAt).Error
+}
+
+// RevokeTokensByUserId mencabut semua refresh token user kecuali family exceptFamilyId (kosong berarti semua)
+func (r *Repository[T]) RevokeTokensByUserId(db *gorm.DB, entity *T, userId uint64, exceptFamilyId string, revokedAt time.Time) error {
+	return db.Model(entity).Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userId, exceptFamilyId).Update("revoked_at", revokedAt).Error
+}
+
+func (r *Repository[T]) RevokeSessionsByUserId(db *gorm.DB, entity *T, userId uint64, exceptSessionId string, revokedAt time.Time) error {
+	return db.Model(entity).Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userId, exceptSessionId).Update("revoked_at", revokedAt).Error
+}
+
+func (r *Repository[T]) FindActiveSessionsByUserId(db *gorm.DB, entities *[]T, userId uint64, now time.Time) error {
+	return db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, now).Order("last_seen_at DESC").Find(entities).Error
+}
+
+func (r *Repository[T]) FindActiveSessionByIdAndUserId(db *gorm.DB, entity *T, sessionId string, userId uint64) error {
+	return db.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionId, userId).First(entity).Error
+}
+
+// UseTwoFactorStep menyimpan langkah waktu TOTP yang terakhir dipakai, 0 baris berarti kode tersebut
+// (atau kode yang lebih baru) sudah pernah dipakai
+func (r *Repository[T]) UseTwoFactorStep(db *gorm.DB, entity *T, userId uint64, step int64) (int64, error) {
+	result := db.Model(entity).Where("id = ? AND two_factor_last_used_step < ?", userId, step).Update("two_factor_last_used_step", step)
+	return result.RowsAffected, result.Error
+}
+
+// UseRecoveryCode menandai kode pemulihan sudah dipakai, 0 baris berarti kode tidak ada atau sudah dipakai
+func (r *Repository[T]) UseRecoveryCode(db *gorm.DB, entity *T, userId uint64, codeHash string, usedAt time.Time) (int64, error) {
+	result := db.Model(entity).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).Update("used_at", usedAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *Repository[T]) CountUnusedRecoveryCodes(db *gorm.DB, entity *T, userId uint64) (int64, error) {
+	var count int64
+	err := db.Model(entity).Where("user_id = ? AND used_at IS NULL", userId).Count(&count).Error
+	return count, err
+}
+
+// FindLoginAttempt mengambil penghitung login gagal untuk satu akun / alamat IP
+func (r *Repository[T]) FindLoginAttempt(db *gorm.DB, entity *T, scope string, identifier string) (int64, error) {
+	result := db.Where("scope = ? AND identifier = ?", scope, identifier).Limit(1).Find(entity)
+	return result.RowsAffected, result.Error
+}
+
+// FindLoginAttemptForUpdate sama seperti FindLoginAttempt tetapi mengunci barisnya sampai transaksi selesai,
+// sehingga login gagal yang terjadi bersamaan tidak saling menimpa penghitungnya
+func (r *Repository[T]) FindLoginAttemptForUpdate(db *gorm.DB, entity *T, scope string, identifier string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("scope = ? AND identifier = ?", scope, identifier).First(entity).Error
+}
+
+func (r *Repository[T]) DeleteLoginAttempt(db *gorm.DB, entity *T, scope string, identifier string) error {
+	return db.Where("scope = ? AND identifier = ?", scope, identifier).Delete(entity).Error
+}
+
+// FindUserIdentity mengambil akun OIDC berdasarkan provider dan subject dari ID token
+func (r *Repository[T]) FindUserIdentity(db *gorm.DB, entity *T, provider string, subject string) (int64, error) {
+	result := db.Where("provider = ? AND subject = ?", provider, subject).Limit(1).Find(entity)
+	return result.RowsAffected, result.Error
 }
 
 func (r *Repository[T]) FindByEmail(db *gorm.DB, entity *T, email string) error {
@@ -257,6 +604,10 @@ func (r *Repository[T]) FindUserById(db *gorm.DB, entity *T, userId uint64) erro
 	return db.Where("id = ?", userId).Preload("Token").Preload("Addresses").Find(&entity).Error
 }
 
+func (r *Repository[T]) FindAllByIds(db *gorm.DB, entities *[]T, ids []uint64) error {
+	return db.Where("id IN ?", ids).Find(entities).Error
+}
+
 func (r *Repository[T]) FindImagesByProductIds(db *gorm.DB, entities *[]T, productIds []uint64) error {
 	return db.Where("product_id IN ?", productIds).Find(&entities).Error
 }
@@ -289,7 +640,7 @@ func (r *Repository[T]) FindWithPreloads(db *gorm.DB, entity *T, preload string)
 }
 
 func (r *Repository[T]) FindOrderByInvoiceId(db *gorm.DB, entity *T, invoiceId string) error {
-	return db.Where("invoice = ?", invoiceId).Preload("OrderProducts").Find(&entity).Error
+	return db.Where("invoice = ?", invoiceId).Preload("OrderProducts").Preload("Refunds", "status <> ?", "failed").Find(&entity).Error
 }
 
 func (r *Repository[T]) FindCurrentUserCartWithPreloads(db *gorm.DB, entity *T, preload string, userId uint64) error {
@@ -455,6 +806,16 @@ func Paginate[T any](db *gorm.DB, model *T, pagination *Pagination, queryFn func
 		return nil, 0, 0, 0, 0, err
 	}
 
+	// Tabel tanpa soft delete dianggap semuanya active
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	if stmt.Schema.LookUpField("deleted_at") == nil {
+		return results, total, totalReal, totalReal, 0, nil
+	}
+
 	// Hitung total active
 	var totalRealActive int64
 	if err := db.Unscoped().Model(model).Where("deleted_at IS NULL").Count(&totalRealActive).Error; err != nil {
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RolePermissionRepository struct {
+	Repository[entity.RolePermission]
+	Log *logrus.Logger
+}
+
+func NewRolePermissionRepository(log *logrus.Logger) *RolePermissionRepository {
+	return &RolePermissionRepository{
+		Log: log,
+	}
+}
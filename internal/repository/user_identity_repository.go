@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type UserIdentityRepository struct {
+	Repository[entity.UserIdentity]
+	Log *logrus.Logger
+}
+
+func NewUserIdentityRepository(log *logrus.Logger) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		Log: log,
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type UserRecoveryCodeRepository struct {
+	Repository[entity.UserRecoveryCode]
+	Log *logrus.Logger
+}
+
+func NewUserRecoveryCodeRepository(log *logrus.Logger) *UserRecoveryCodeRepository {
+	return &UserRecoveryCodeRepository{
+		Log: log,
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type UserSessionRepository struct {
+	Repository[entity.UserSession]
+	Log *logrus.Logger
+}
+
+func NewUserSessionRepository(log *logrus.Logger) *UserSessionRepository {
+	return &UserSessionRepository{
+		Log: log,
+	}
+}
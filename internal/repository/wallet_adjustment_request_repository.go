@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type WalletAdjustmentRequestRepository struct {
+	Repository[entity.WalletAdjustmentRequests]
+	Log *logrus.Logger
+}
+
+func NewWalletAdjustmentRequestRepository(log *logrus.Logger) *WalletAdjustmentRequestRepository {
+	return &WalletAdjustmentRequestRepository{
+		Log: log,
+	}
+}
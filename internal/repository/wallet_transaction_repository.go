@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type WalletTransactionRepository struct {
+	Repository[entity.WalletTransactions]
+	Log *logrus.Logger
+}
+
+func NewWalletTransactionRepository(log *logrus.Logger) *WalletTransactionRepository {
+	return &WalletTransactionRepository{
+		Log: log,
+	}
+}
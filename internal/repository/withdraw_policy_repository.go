@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type WithdrawPolicyRepository struct {
+	Repository[entity.WithdrawPolicy]
+	Log *logrus.Logger
+}
+
+func NewWithdrawPolicyRepository(log *logrus.Logger) *WithdrawPolicyRepository {
+	return &WithdrawPolicyRepository{
+		Log: log,
+	}
+}
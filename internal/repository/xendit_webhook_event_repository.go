@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"seblak-bombom-restful-api/internal/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+type XenditWebhookEventRepository struct {
+	Repository[entity.XenditWebhookEvent]
+	Log *logrus.Logger
+}
+
+func NewXenditWebhookEventRepository(log *logrus.Logger) *XenditWebhookEventRepository {
+	return &XenditWebhookEventRepository{
+		Log: log,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	// "os"
 	"seblak-bombom-restful-api/internal/entity"
 	"seblak-bombom-restful-api/internal/helper"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
 	"seblak-bombom-restful-api/internal/repository"
@@ -21,14 +22,16 @@ type ApplicationUseCase struct {
 	Log                   *logrus.Logger
 	Validate              *validator.Validate
 	ApplicationRepository *repository.ApplicationRepository
+	AuditLogUseCase       *AuditLogUseCase
 }
 
-func NewApplicationUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, applicationRepository *repository.ApplicationRepository) *ApplicationUseCase {
+func NewApplicationUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, applicationRepository *repository.ApplicationRepository, auditLogUseCase *AuditLogUseCase) *ApplicationUseCase {
 	return &ApplicationUseCase{
 		DB:                    db,
 		Log:                   log,
 		Validate:              validate,
 		ApplicationRepository: applicationRepository,
+		AuditLogUseCase:       auditLogUseCase,
 	}
 }
 
@@ -77,6 +80,14 @@ func (c *ApplicationUseCase) Add(ctx *fiber.Ctx, request *model.CreateApplicatio
 		// }
 	}
 
+	// nilai sebelum diubah untuk audit log, contoh: perubahan service fee
+	beforeApplication := c.AuditLogUseCase.Snapshot(tx, newApplication)
+	auditAction := enum_state.AUDIT_ACTION_UPDATE
+	if count == 0 {
+		beforeApplication = nil
+		auditAction = enum_state.AUDIT_ACTION_CREATE
+	}
+
 	newApplication.AppName = request.AppName
 	if request.Logo != nil {
 		newApplication.LogoFilename = hashedFilename
@@ -112,6 +123,10 @@ func (c *ApplicationUseCase) Add(ctx *fiber.Ctx, request *model.CreateApplicatio
 		}
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, auditAction, enum_state.AUDIT_ENTITY_APPLICATION, newApplication.ID, beforeApplication, c.AuditLogUseCase.Snapshot(tx, newApplication)); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/repository"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// kolom yang tidak dicatat karena selalu berubah setiap update
+var auditIgnoredColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// kolom rahasia hanya dicatat bahwa nilainya berubah, isinya tidak pernah disimpan
+var auditMaskedColumns = map[string]bool{
+	"password":           true,
+	"verification_token": true,
+	"two_factor_secret":  true,
+}
+
+const auditMaskedValue = "[REDACTED]"
+
+type AuditLogUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	AuditLogRepository *repository.AuditLogRepository
+	AuditLogConfig     *model.AuditLogConfig
+}
+
+func NewAuditLogUseCase(db *gorm.DB, log *logrus.Logger, auditLogRepository *repository.AuditLogRepository, auditLogConfig *model.AuditLogConfig) *AuditLogUseCase {
+	return &AuditLogUseCase{
+		DB:                 db,
+		Log:                log,
+		AuditLogRepository: auditLogRepository,
+		AuditLogConfig:     auditLogConfig,
+	}
+}
+
+// Snapshot mengambil nilai setiap kolom entity sebelum / sesudah diubah, relasi tidak ikut dicatat
+func (c *AuditLogUseCase) Snapshot(tx *gorm.DB, value any) map[string]any {
+	reflectValue := reflect.ValueOf(value)
+	if value == nil || (reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil()) {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(value); err != nil {
+		c.Log.Warnf("failed to parse audit log snapshot : %+v", err)
+		return nil
+	}
+
+	snapshot := map[string]any{}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || auditIgnoredColumns[field.DBName] {
+			continue
+		}
+
+		fieldValue, _ := field.ValueOf(tx.Statement.Context, reflectValue)
+		// tipe seperti sql.NullString dan gorm.DeletedAt dicatat sesuai nilai yang tersimpan di database
+		if valuer, ok := fieldValue.(driver.Valuer); ok {
+			if valuerValue := reflect.ValueOf(valuer); valuerValue.Kind() != reflect.Ptr || !valuerValue.IsNil() {
+				if databaseValue, err := valuer.Value(); err == nil {
+					fieldValue = databaseValue
+				}
+			}
+		}
+		// pointer disalin nilainya agar snapshot tidak ikut berubah saat entity diubah setelahnya
+		if pointerValue := reflect.ValueOf(fieldValue); pointerValue.Kind() == reflect.Ptr {
+			if pointerValue.IsNil() {
+				fieldValue = nil
+			} else {
+				fieldValue = pointerValue.Elem().Interface()
+			}
+		}
+		snapshot[field.DBName] = fieldValue
+	}
+
+	return snapshot
+}
+
+// Record menulis audit log di dalam transaksi pemanggil agar log hanya tersimpan jika perubahannya tersimpan.
+// Pelaku, IP dan request id dibaca dari context request (auth middleware dan request context middleware).
+func (c *AuditLogUseCase) Record(tx *gorm.DB, action enum_state.AuditAction, entityType enum_state.AuditEntityType, entityId any, before map[string]any, after map[string]any) error {
+	newAuditLog := new(entity.AuditLog)
+	newAuditLog.Action = action
+	newAuditLog.EntityType = entityType
+	newAuditLog.EntityId = fmt.Sprint(entityId)
+
+	if ctx := tx.Statement.Context; ctx != nil {
+		if auth, ok := ctx.Value("auth").(*model.UserResponse); ok && auth != nil {
+			actorId := auth.ID
+			newAuditLog.ActorId = &actorId
+			newAuditLog.ActorEmail = auth.Email
+			newAuditLog.ActorRole = auth.Role
+		}
+
+		newAuditLog.RequestId, _ = ctx.Value("request_id").(string)
+		newAuditLog.IPAddress, _ = ctx.Value("ip_address").(string)
+	}
+
+	changes := c.diff(before, after)
+	if len(changes) > 0 {
+		changesJSON, err := json.Marshal(changes)
+		if err != nil {
+			c.Log.Warnf("failed to marshal audit log changes : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to marshal audit log changes : %+v", err))
+		}
+
+		changesString := string(changesJSON)
+		newAuditLog.Changes = &changesString
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog); err != nil {
+		c.Log.Warnf("failed to create audit log into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create audit log into database : %+v", err))
+	}
+
+	return nil
+}
+
+// diff hanya menyimpan kolom yang nilainya berbeda, dibandingkan dalam bentuk json
+func (c *AuditLogUseCase) diff(before map[string]any, after map[string]any) map[string]model.AuditChange {
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, snapshot := range []map[string]any{before, after} {
+		for column := range snapshot {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	changes := map[string]model.AuditChange{}
+	for _, column := range columns {
+		beforeValue, beforeExists := before[column]
+		afterValue, afterExists := after[column]
+		beforeJSON, _ := json.Marshal(beforeValue)
+		afterJSON, _ := json.Marshal(afterValue)
+		if beforeExists == afterExists && string(beforeJSON) == string(afterJSON) {
+			continue
+		}
+
+		if auditMaskedColumns[column] {
+			if beforeExists {
+				beforeValue = auditMaskedValue
+			}
+			if afterExists {
+				afterValue = auditMaskedValue
+			}
+		}
+
+		changes[column] = model.AuditChange{
+			Before: beforeValue,
+			After:  afterValue,
+		}
+	}
+
+	return changes
+}
+
+func (c *AuditLogUseCase) GetAllPaginate(ctx context.Context, page int, perPage int, actorId uint64, action string, entityType string, entityId string, startDate string, endDate string, timeZone *time.Location, sortingColumn string, sortBy string) (*[]model.AuditLogResponse, int64, int64, int64, int64, int, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if page <= 0 {
+		page = 1
+	}
+
+	if sortingColumn == "" {
+		sortingColumn = "audit_logs.id"
+	}
+
+	newPagination := new(repository.Pagination)
+	newPagination.Page = page
+	newPagination.PageSize = perPage
+	newPagination.Column = sortingColumn
+	newPagination.SortBy = sortBy
+	allowedColumns := map[string]bool{
+		"audit_logs.id":          true,
+		"audit_logs.actor_id":    true,
+		"audit_logs.action":      true,
+		"audit_logs.entity_type": true,
+		"audit_logs.created_at":  true,
+	}
+
+	if !allowedColumns[newPagination.Column] {
+		c.Log.Warnf("invalid sort column : %s", newPagination.Column)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid sort column : %s", newPagination.Column))
+	}
+
+	// rentang tanggal mengikuti zona waktu admin, end_date ikut dihitung sampai akhir hari
+	var start, end *time.Time
+	if startDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", startDate, timeZone)
+		if err != nil {
+			c.Log.Warnf("invalid start_date : %+v", err)
+			return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid start_date : %+v", err))
+		}
+		start = &parsed
+	}
+
+	if endDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", endDate, timeZone)
+		if err != nil {
+			c.Log.Warnf("invalid end_date : %+v", err)
+			return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid end_date : %+v", err))
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		end = &parsed
+	}
+
+	auditLogs, totalCurrent, totalReal, totalActive, totalInactive, err := repository.Paginate(tx, &entity.AuditLog{}, newPagination, func(d *gorm.DB) *gorm.DB {
+		result := d
+		if actorId > 0 {
+			result = result.Where("actor_id = ?", actorId)
+		}
+
+		if action != "" {
+			result = result.Where("action = ?", action)
+		}
+
+		if entityType != "" {
+			result = result.Where("entity_type = ?", entityType)
+		}
+
+		if entityId != "" {
+			result = result.Where("entity_id = ?", entityId)
+		}
+
+		if start != nil {
+			result = result.Where("created_at >= ?", *start)
+		}
+
+		if end != nil {
+			result = result.Where("created_at < ?", *end)
+		}
+		return result
+	})
+
+	if err != nil {
+		c.Log.Warnf("failed to paginate audit logs : %+v", err)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to paginate audit logs : %+v", err))
+	}
+
+	// Hitung total halaman
+	var totalPages int = 0
+	totalPages = int(totalCurrent / int64(perPage))
+	if totalCurrent%int64(perPage) > 0 {
+		totalPages++
+	}
+
+	return converter.AuditLogsToResponse(&auditLogs), totalCurrent, totalReal, totalActive, totalInactive, totalPages, nil
+}
+
+// PruneExpired menghapus audit log yang lebih lama dari masa retensi, 0 hari = disimpan selamanya
+func (c *AuditLogUseCase) PruneExpired(ctx context.Context) (int64, error) {
+	if c.AuditLogConfig == nil || c.AuditLogConfig.RetentionDays <= 0 {
+		return 0, nil
+	}
+
+	tx := c.DB.WithContext(ctx)
+	before := time.Now().AddDate(0, 0, -c.AuditLogConfig.RetentionDays)
+	deleted, err := c.AuditLogRepository.DeleteAuditLogsBefore(tx, &entity.AuditLog{}, before)
+	if err != nil {
+		c.Log.Warnf("failed to delete expired audit logs : %+v", err)
+		return 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete expired audit logs : %+v", err))
+	}
+
+	return deleted, nil
+}
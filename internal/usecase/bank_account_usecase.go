@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type BankAccountUseCase struct {
+	DB                    *gorm.DB
+	Log                   *logrus.Logger
+	Validate              *validator.Validate
+	BankAccountRepository *repository.BankAccountRepository
+	WalletConfig          *model.WalletConfig
+	AuditLogUseCase       *AuditLogUseCase
+}
+
+func NewBankAccountUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	bankAccountRepository *repository.BankAccountRepository, walletConfig *model.WalletConfig,
+	auditLogUseCase *AuditLogUseCase) *BankAccountUseCase {
+	return &BankAccountUseCase{
+		DB:                    db,
+		Log:                   log,
+		Validate:              validate,
+		BankAccountRepository: bankAccountRepository,
+		WalletConfig:          walletConfig,
+		AuditLogUseCase:       auditLogUseCase,
+	}
+}
+
+func (c *BankAccountUseCase) Add(ctx context.Context, request *model.CreateBankAccountRequest) (*model.BankAccountResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if _, ok := helper_others.FindPayoutChannel(request.ChannelCode); !ok {
+		c.Log.Warnf("channel code %s is not supported for payout!", request.ChannelCode)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not supported for payout!", request.ChannelCode))
+	}
+
+	newBankAccount := new(entity.BankAccount)
+	count, err := c.BankAccountRepository.CountBankAccountByAccountNumber(tx, newBankAccount, request.UserId, request.ChannelCode, request.AccountNumber)
+	if err != nil {
+		c.Log.Warnf("failed to count bank account by account number : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count bank account by account number : %+v", err))
+	}
+
+	if count > 0 {
+		c.Log.Warnf("bank account has already been saved!")
+		return nil, fiber.NewError(fiber.StatusConflict, "bank account has already been saved!")
+	}
+
+	newBankAccount.UserId = request.UserId
+	newBankAccount.ChannelCode = request.ChannelCode
+	newBankAccount.AccountNumber = request.AccountNumber
+	newBankAccount.AccountHolderName = request.AccountHolderName
+	newBankAccount.VerificationStatus = enum_state.BANK_ACCOUNT_UNVERIFIED
+	if err := c.BankAccountRepository.Create(tx, newBankAccount); err != nil {
+		c.Log.Warnf("failed to create bank account : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create bank account : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.BankAccountToResponse(newBankAccount), nil
+}
+
+func (c *BankAccountUseCase) GetAllCurrent(ctx context.Context, userId uint64) (*[]model.BankAccountResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	newBankAccounts := new([]entity.BankAccount)
+	if err := c.BankAccountRepository.FindAllByUserId(tx, newBankAccounts, userId); err != nil {
+		c.Log.Warnf("failed to get all bank accounts by user id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get all bank accounts by user id : %+v", err))
+	}
+
+	return converter.BankAccountsToResponse(newBankAccounts), nil
+}
+
+func (c *BankAccountUseCase) Delete(ctx context.Context, request *model.DeleteBankAccountRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newBankAccount := new(entity.BankAccount)
+	newBankAccount.ID = request.ID
+	count, err := c.BankAccountRepository.FindAndCountById(tx, newBankAccount)
+	if err != nil {
+		c.Log.Warnf("failed to find bank account by id : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find bank account by id : %+v", err))
+	}
+
+	if count < 1 || newBankAccount.UserId != request.UserId {
+		c.Log.Warnf("bank account not found!")
+		return false, fiber.NewError(fiber.StatusNotFound, "bank account not found!")
+	}
+
+	if err := c.BankAccountRepository.Delete(tx, newBankAccount); err != nil {
+		c.Log.Warnf("can't delete bank account by id : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("can't delete bank account by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return true, nil
+}
+
+func (c *BankAccountUseCase) UpdateVerificationByAdmin(ctx context.Context, request *model.VerifyBankAccountRequest) (*model.BankAccountResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.Status != enum_state.BANK_ACCOUNT_VERIFIED && request.Status != enum_state.BANK_ACCOUNT_REJECTED {
+		c.Log.Warnf("invalid bank account verification status : %s", request.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid bank account verification status : %s", request.Status))
+	}
+
+	newBankAccount := new(entity.BankAccount)
+	newBankAccount.ID = request.ID
+	count, err := c.BankAccountRepository.FindAndCountById(tx, newBankAccount)
+	if err != nil {
+		c.Log.Warnf("failed to find bank account by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find bank account by id : %+v", err))
+	}
+
+	if count < 1 {
+		c.Log.Warnf("bank account not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "bank account not found!")
+	}
+
+	// nilai sebelum diverifikasi untuk audit log
+	beforeBankAccount := c.AuditLogUseCase.Snapshot(tx, newBankAccount)
+	now := time.Now()
+	newBankAccount.VerificationStatus = request.Status
+	newBankAccount.VerificationNotes = request.VerificationNotes
+	newBankAccount.VerifiedBy = &request.CurrentAdminId
+	newBankAccount.VerifiedAt = &now
+	if err := c.BankAccountRepository.Update(tx, newBankAccount); err != nil {
+		c.Log.Warnf("failed to update bank account verification : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update bank account verification : %+v", err))
+	}
+
+	auditAction := enum_state.AUDIT_ACTION_VERIFY
+	if request.Status == enum_state.BANK_ACCOUNT_REJECTED {
+		auditAction = enum_state.AUDIT_ACTION_REJECT
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, auditAction, enum_state.AUDIT_ENTITY_BANK_ACCOUNT, newBankAccount.ID, beforeBankAccount, c.AuditLogUseCase.Snapshot(tx, newBankAccount)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.BankAccountToResponse(newBankAccount), nil
+}
+
+// FindPayableAccount memastikan rekening tersimpan milik user dan boleh menerima payout sebesar amount
+func (c *BankAccountUseCase) FindPayableAccount(tx *gorm.DB, bankAccountId uint64, userId uint64, amount float32) (*entity.BankAccount, error) {
+	newBankAccount := new(entity.BankAccount)
+	newBankAccount.ID = bankAccountId
+	count, err := c.BankAccountRepository.FindAndCountById(tx, newBankAccount)
+	if err != nil {
+		c.Log.Warnf("failed to find bank account by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find bank account by id : %+v", err))
+	}
+
+	if count < 1 || newBankAccount.UserId != userId {
+		c.Log.Warnf("bank account not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "bank account not found!")
+	}
+
+	if newBankAccount.VerificationStatus == enum_state.BANK_ACCOUNT_REJECTED {
+		c.Log.Warnf("the selected bank account has been rejected and can't receive payouts!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "the selected bank account has been rejected and can't receive payouts!")
+	}
+
+	if newBankAccount.VerificationStatus != enum_state.BANK_ACCOUNT_VERIFIED && amount > c.WalletConfig.UnverifiedPayoutLimit {
+		c.Log.Warnf("the selected bank account must be verified for payouts above %.2f!", c.WalletConfig.UnverifiedPayoutLimit)
+		return nil, fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("the selected bank account must be verified for payouts above %.2f!", c.WalletConfig.UnverifiedPayoutLimit))
+	}
+
+	return newBankAccount, nil
+}
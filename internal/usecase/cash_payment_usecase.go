@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/repository"
+	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CashPaymentUseCase struct {
+	DB                       *gorm.DB
+	Log                      *logrus.Logger
+	Validate                 *validator.Validate
+	OrderRepository          *repository.OrderRepository
+	CashCollectionRepository *repository.CashCollectionRepository
+	XenditCallbackUseCase    *xenditUseCase.XenditCallbackUseCase
+	AuditLogUseCase          *AuditLogUseCase
+}
+
+func NewCashPaymentUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	orderRepository *repository.OrderRepository, cashCollectionRepository *repository.CashCollectionRepository,
+	xenditCallbackUseCase *xenditUseCase.XenditCallbackUseCase, auditLogUseCase *AuditLogUseCase) *CashPaymentUseCase {
+	return &CashPaymentUseCase{
+		DB:                       db,
+		Log:                      log,
+		Validate:                 validate,
+		OrderRepository:          orderRepository,
+		CashCollectionRepository: cashCollectionRepository,
+		XenditCallbackUseCase:    xenditCallbackUseCase,
+		AuditLogUseCase:          auditLogUseCase,
+	}
+}
+
+// Confirm dipanggil admin (kasir) atau kurir setelah menerima uang tunai dari customer,
+// order baru bisa diteruskan ke dapur setelah payment status menjadi paid
+func (c *CashPaymentUseCase) Confirm(ctx *fiber.Ctx, request *model.ConfirmCashPaymentRequest) (*model.CashCollectionResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if !request.CurrentUserCanConfirm {
+		c.Log.Warnf("only admin or staff with cash payment permission can confirm cash payment!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "only admin or staff with cash payment permission can confirm cash payment!")
+	}
+
+	newOrder := new(entity.Order)
+	newOrder.ID = request.OrderId
+	count, err := c.OrderRepository.FindAndCountById(tx, newOrder)
+	if err != nil {
+		c.Log.Warnf("failed to find order by id into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id into database : %+v", err))
+	}
+
+	if count == 0 {
+		c.Log.Warnf("order not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "order not found!")
+	}
+
+	if newOrder.PaymentMethod != enum_state.PAYMENT_METHOD_CASH {
+		c.Log.Warnf("order %d is not paid with cash!", newOrder.ID)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("order %d is not paid with cash!", newOrder.ID))
+	}
+
+	// kurir hanya menerima uang tunai untuk pesanan yang diantar
+	if request.CurrentUserRole == enum_state.COURIER && !newOrder.IsDelivery {
+		c.Log.Warnf("courier can only confirm cash payment for delivery orders!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "courier can only confirm cash payment for delivery orders!")
+	}
+
+	if newOrder.OrderStatus == enum_state.ORDER_CANCELLED || newOrder.OrderStatus == enum_state.ORDER_REJECTED {
+		c.Log.Warnf("can't confirm cash payment for an order that has been cancelled/rejected!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "can't confirm cash payment for an order that has been cancelled/rejected!")
+	}
+
+	if newOrder.PaymentStatus != enum_state.PENDING_PAYMENT {
+		c.Log.Warnf("cash payment for order %d has been confirmed or can't be paid anymore!", newOrder.ID)
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cash payment for order %d has been confirmed or can't be paid anymore!", newOrder.ID))
+	}
+
+	newCashCollection := new(entity.CashCollection)
+	count, err = c.CashCollectionRepository.CountCashCollectionByOrderId(tx, newCashCollection, newOrder.ID)
+	if err != nil {
+		c.Log.Warnf("failed to count cash collection by order id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count cash collection by order id : %+v", err))
+	}
+
+	if count > 0 {
+		c.Log.Warnf("cash payment for order %d has been confirmed!", newOrder.ID)
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cash payment for order %d has been confirmed!", newOrder.ID))
+	}
+
+	// received amount kosong berarti customer membayar dengan uang pas
+	receivedAmount := request.ReceivedAmount
+	if receivedAmount == 0 {
+		receivedAmount = newOrder.TotalFinalPrice
+	}
+
+	if receivedAmount < newOrder.TotalFinalPrice {
+		c.Log.Warnf("received amount is less than the order total!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "received amount is less than the order total!")
+	}
+
+	now := time.Now()
+	newCashCollection.OrderId = newOrder.ID
+	newCashCollection.CollectedBy = request.CurrentUserId
+	newCashCollection.Amount = newOrder.TotalFinalPrice
+	newCashCollection.ReceivedAmount = receivedAmount
+	newCashCollection.ChangeAmount = receivedAmount - newOrder.TotalFinalPrice
+	newCashCollection.Note = request.Note
+	newCashCollection.CollectedAt = now
+	if err := c.CashCollectionRepository.Create(tx, newCashCollection); err != nil {
+		c.Log.Warnf("failed to create cash collection into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create cash collection into database : %+v", err))
+	}
+
+	updateOrderPaymentStatus := &model.UpdateOrderPaymentStatus{
+		OrderId:         newOrder.ID,
+		PaymentStatus:   enum_state.PAID_PAYMENT,
+		UpdatedAt:       now,
+		Lang:            request.Lang,
+		TimeZone:        request.TimeZone,
+		BaseFrontEndURL: request.BaseFrontEndURL,
+	}
+
+	if err := c.XenditCallbackUseCase.UpdateOrderPaymentStatus(ctx, tx, updateOrderPaymentStatus); err != nil {
+		c.Log.Warnf("failed to update order payment status : %+v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	newCashCollection.Order = newOrder
+	return converter.CashCollectionToResponse(newCashCollection), nil
+}
+
+// GetReconciliation menampilkan rekap uang tunai per staff pada satu hari sesuai zona waktu admin
+func (c *CashPaymentUseCase) GetReconciliation(ctx *fiber.Ctx, request *model.GetCashReconciliationRequest) (*model.CashReconciliationResponse, error) {
+	tx := c.DB.WithContext(ctx.Context())
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", request.Date, &request.TimeZone)
+	if err != nil {
+		c.Log.Warnf("invalid date : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid date : %+v", err))
+	}
+
+	cashCollections := new([]entity.CashCollection)
+	if err := c.CashCollectionRepository.FindCashCollectionsBetween(tx, cashCollections, start, start.AddDate(0, 0, 1), request.CollectedBy); err != nil {
+		c.Log.Warnf("failed to find cash collections from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find cash collections from database : %+v", err))
+	}
+
+	response := new(model.CashReconciliationResponse)
+	response.Date = request.Date
+	response.Staffs = []model.CashStaffSummaryResponse{}
+	staffIndex := map[uint64]int{}
+	for _, cashCollection := range *cashCollections {
+		idx, exists := staffIndex[cashCollection.CollectedBy]
+		if !exists {
+			staff := model.CashStaffSummaryResponse{CollectedBy: cashCollection.CollectedBy}
+			if cashCollection.Collector != nil {
+				staff.CollectorName = cashCollection.Collector.Name.FirstName + " " + cashCollection.Collector.Name.LastName
+			}
+			response.Staffs = append(response.Staffs, staff)
+			idx = len(response.Staffs) - 1
+			staffIndex[cashCollection.CollectedBy] = idx
+		}
+
+		response.Staffs[idx].TotalCollections++
+		response.Staffs[idx].TotalAmount += cashCollection.Amount
+		if cashCollection.ReconciledAt != nil {
+			response.Staffs[idx].ReconciledAmount += cashCollection.Amount
+		} else {
+			response.Staffs[idx].UnreconciledAmount += cashCollection.Amount
+			response.UnreconciledAmount += cashCollection.Amount
+		}
+
+		response.TotalCollections++
+		response.TotalAmount += cashCollection.Amount
+	}
+
+	response.Collections = converter.CashCollectionsToResponse(cashCollections)
+	return response, nil
+}
+
+// Reconcile menandai uang tunai yang sudah disetor staff ke admin pada akhir hari
+func (c *CashPaymentUseCase) Reconcile(ctx *fiber.Ctx, request *model.ReconcileCashRequest) (*model.CashReconciliationResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", request.Date, &request.TimeZone)
+	if err != nil {
+		c.Log.Warnf("invalid date : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid date : %+v", err))
+	}
+
+	affected, err := c.CashCollectionRepository.ReconcileCashCollections(tx, new(entity.CashCollection), start, start.AddDate(0, 0, 1), request.CollectedBy, request.CurrentAdminId, time.Now())
+	if err != nil {
+		c.Log.Warnf("failed to reconcile cash collections : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to reconcile cash collections : %+v", err))
+	}
+
+	if affected == 0 {
+		c.Log.Warnf("no unreconciled cash collections found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "no unreconciled cash collections found!")
+	}
+
+	// rekonsiliasi berlaku untuk banyak baris sekaligus, dicatat per tanggal setoran
+	reconciledCashCollections := map[string]any{
+		"collected_by":     request.CollectedBy,
+		"reconciled_count": affected,
+	}
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_RECONCILE, enum_state.AUDIT_ENTITY_CASH_COLLECTION, request.Date, nil, reconciledCashCollections); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return c.GetReconciliation(ctx, &model.GetCashReconciliationRequest{
+		Date:        request.Date,
+		CollectedBy: request.CollectedBy,
+		TimeZone:    request.TimeZone,
+	})
+}
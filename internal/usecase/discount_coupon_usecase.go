@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
 	"seblak-bombom-restful-api/internal/repository"
@@ -19,15 +20,17 @@ type DiscountCouponUseCase struct {
 	Log                      *logrus.Logger
 	Validate                 *validator.Validate
 	DiscountCouponRepository *repository.DiscountCouponRepository
+	AuditLogUseCase          *AuditLogUseCase
 }
 
 func NewDiscountCouponUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
-	DiscountCouponRepository *repository.DiscountCouponRepository) *DiscountCouponUseCase {
+	DiscountCouponRepository *repository.DiscountCouponRepository, auditLogUseCase *AuditLogUseCase) *DiscountCouponUseCase {
 	return &DiscountCouponUseCase{
 		DB:                       db,
 		Log:                      log,
 		Validate:                 validate,
 		DiscountCouponRepository: DiscountCouponRepository,
+		AuditLogUseCase:          auditLogUseCase,
 	}
 }
 
@@ -70,6 +73,10 @@ func (c *DiscountCouponUseCase) Add(ctx context.Context, request *model.CreateDi
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create a new discount : %+v", err))
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_DISCOUNT_COUPON, newDiscount.ID, nil, c.AuditLogUseCase.Snapshot(tx, newDiscount)); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -177,6 +184,8 @@ func (c *DiscountCouponUseCase) Edit(ctx context.Context, request *model.UpdateD
 		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("discount code has been used : %+v", err))
 	}
 
+	// nilai sebelum diubah untuk audit log
+	beforeDiscount := c.AuditLogUseCase.Snapshot(tx, newDiscount)
 	newDiscount.ID = request.ID
 	newDiscount.Name = request.Name
 	newDiscount.Description = request.Description
@@ -195,6 +204,10 @@ func (c *DiscountCouponUseCase) Edit(ctx context.Context, request *model.UpdateD
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("can't update discount by id : %+v", err))
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_UPDATE, enum_state.AUDIT_ENTITY_DISCOUNT_COUPON, newDiscount.ID, beforeDiscount, c.AuditLogUseCase.Snapshot(tx, newDiscount)); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -213,6 +226,12 @@ func (c *DiscountCouponUseCase) Remove(ctx context.Context, request *model.Delet
 		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
+	currentDiscountCoupons := new([]entity.DiscountCoupon)
+	if err := c.DiscountCouponRepository.FindAllByIds(tx, currentDiscountCoupons, request.IDs); err != nil {
+		c.Log.Warnf("failed to find discount coupons by ids : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find discount coupons by ids : %+v", err))
+	}
+
 	newDiscountCoupons := []entity.DiscountCoupon{}
 
 	for _, idCoupon := range request.IDs {
@@ -228,6 +247,13 @@ func (c *DiscountCouponUseCase) Remove(ctx context.Context, request *model.Delet
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete discount by id: %+v", err))
 	}
 
+	for i := range *currentDiscountCoupons {
+		currentDiscountCoupon := &(*currentDiscountCoupons)[i]
+		if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_DELETE, enum_state.AUDIT_ENTITY_DISCOUNT_COUPON, currentDiscountCoupon.ID, c.AuditLogUseCase.Snapshot(tx, currentDiscountCoupon), nil); err != nil {
+			return false, err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	"seblak-bombom-restful-api/internal/repository"
+	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type MidtransTransactionUseCase struct {
+	DB                            *gorm.DB
+	Log                           *logrus.Logger
+	Validate                      *validator.Validate
+	PaymentGateways               *payment_gateway.Registry
+	OrderRepository               *repository.OrderRepository
+	MidtransTransactionRepository *repository.MidtransTransactionRepository
+	XenditCallbackUseCase         *xenditUseCase.XenditCallbackUseCase
+	FrontEndConfig                *model.FrontEndConfig
+}
+
+func NewMidtransTransactionUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	paymentGateways *payment_gateway.Registry, orderRepository *repository.OrderRepository,
+	midtransTransactionRepository *repository.MidtransTransactionRepository,
+	xenditCallbackUseCase *xenditUseCase.XenditCallbackUseCase, frontEndConfig *model.FrontEndConfig) *MidtransTransactionUseCase {
+	return &MidtransTransactionUseCase{
+		DB:                            db,
+		Log:                           log,
+		Validate:                      validate,
+		PaymentGateways:               paymentGateways,
+		OrderRepository:               orderRepository,
+		MidtransTransactionRepository: midtransTransactionRepository,
+		XenditCallbackUseCase:         xenditCallbackUseCase,
+		FrontEndConfig:                frontEndConfig,
+	}
+}
+
+func (c *MidtransTransactionUseCase) Add(ctx *fiber.Ctx, request *model.CreateMidtransTransaction, tx *gorm.DB) (*model.MidtransTransactionResponse, error) {
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	selectedOrder := new(entity.Order)
+	selectedOrder.ID = request.OrderId
+	if err := c.OrderRepository.FindWithPreloads(tx, selectedOrder, "OrderProducts"); err != nil {
+		c.Log.Warnf("failed to find order by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id : %+v", err))
+	}
+
+	paymentGateway, err := c.PaymentGateways.Get(enum_state.PAYMENT_GATEWAY_MIDTRANS)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	paymentItems := []model.PaymentGatewayItem{}
+	for _, product := range selectedOrder.OrderProducts {
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: strconv.FormatUint(product.ProductId, 10),
+			Name:        product.ProductName,
+			Category:    product.Category,
+			Type:        string(enum_state.ITEM_TYPE_PHYSICAL_PRODUCT),
+			Quantity:    product.Quantity,
+			Price:       product.Price,
+		})
+	}
+
+	if selectedOrder.DeliveryCost > 0 {
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: fmt.Sprintf("DELIVERY-%d", selectedOrder.ID),
+			Name:        "Delivery Cost",
+			Category:    "delivery",
+			Type:        string(enum_state.ITEM_TYPE_DELIVERY_FEE),
+			Quantity:    1,
+			Price:       selectedOrder.DeliveryCost,
+		})
+	}
+
+	if selectedOrder.TotalDiscount > 0 {
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: fmt.Sprintf("DISCOUNT-%d", selectedOrder.ID),
+			Name:        "Discount",
+			Category:    "discount",
+			Type:        string(enum_state.ITEM_TYPE_DISCOUNT),
+			Quantity:    1,
+			Price:       selectedOrder.TotalDiscount,
+		})
+	}
+
+	metadata := map[string]any{
+		"user_id":   selectedOrder.UserId,
+		"order_id":  selectedOrder.ID,
+		"invoice":   selectedOrder.Invoice,
+		"time_zone": request.TimeZone.String(),
+		"lang":      request.Lang,
+	}
+
+	// order_id midtrans hanya boleh berisi huruf, angka, - _ ~ . dan harus unik per transaksi
+	midtransOrderId := fmt.Sprintf("ORDER-%d-%d", selectedOrder.ID, time.Now().Unix())
+	createPaymentRequest := &model.CreatePaymentGatewayRequest{
+		OrderId:        selectedOrder.ID,
+		ReferenceId:    midtransOrderId,
+		CustomerId:     strconv.FormatUint(selectedOrder.UserId, 10),
+		Amount:         selectedOrder.TotalFinalPrice,
+		Currency:       "IDR",
+		PaymentMethod:  selectedOrder.PaymentMethod,
+		ChannelCode:    selectedOrder.ChannelCode,
+		Description:    fmt.Sprintf("This is a product ordered by %s %s", selectedOrder.FirstName, selectedOrder.LastName),
+		Items:          paymentItems,
+		Metadata:       metadata,
+		ExpiresAt:      time.Now().Add(15 * time.Minute),
+		IdempotencyKey: midtransOrderId,
+	}
+
+	resp, err := paymentGateway.CreatePayment(ctx.Context(), createPaymentRequest)
+	if err != nil {
+		c.Log.Warnf("failed to create new payment on %s : %+v", paymentGateway.Name(), err)
+		return nil, err
+	}
+
+	jsonMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		c.Log.Warnf("failed to parse to json metadata : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse to json metadata : %+v", err))
+	}
+
+	newMidtransTransaction := new(entity.MidtransTransaction)
+	newMidtransTransaction.OrderId = selectedOrder.ID
+	newMidtransTransaction.MidtransOrderId = resp.ID
+	newMidtransTransaction.TransactionId = resp.PaymentMethodId
+	newMidtransTransaction.ApiType = payment_gateway.MIDTRANS_API_TYPE_CORE_API
+	if selectedOrder.ChannelCode == enum_state.MIDTRANS_SNAP_CHANNEL_CODE {
+		newMidtransTransaction.ApiType = payment_gateway.MIDTRANS_API_TYPE_SNAP
+	}
+	newMidtransTransaction.PaymentType = resp.PaymentMethod
+	newMidtransTransaction.ChannelCode = selectedOrder.ChannelCode
+	newMidtransTransaction.GrossAmount = resp.Amount
+	newMidtransTransaction.Currency = resp.Currency
+	newMidtransTransaction.TransactionStatus = resp.RawStatus
+	newMidtransTransaction.FraudStatus = resp.FraudStatus
+	newMidtransTransaction.VaBank = resp.VirtualAccountBank
+	newMidtransTransaction.VaNumber = resp.VirtualAccountNumber
+	newMidtransTransaction.QrString = resp.QrString
+	newMidtransTransaction.DeeplinkUrl = resp.DeeplinkUrl
+	newMidtransTransaction.SnapToken = resp.CheckoutToken
+	newMidtransTransaction.RedirectUrl = resp.CheckoutUrl
+	newMidtransTransaction.Metadata = jsonMetadata
+	expiresAt := resp.ExpiresAt
+	newMidtransTransaction.ExpiresAt = &expiresAt
+	if err := c.MidtransTransactionRepository.Create(tx, newMidtransTransaction); err != nil {
+		c.Log.Warnf("failed to insert midtrans transaction into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "An error occurred on the server. Please try again later!")
+	}
+
+	return converter.MidtransTransactionToResponse(newMidtransTransaction), nil
+}
+
+// GetTransaction mengambil status terbaru dari midtrans, berguna jika notifikasi belum diterima
+func (c *MidtransTransactionUseCase) GetTransaction(ctx *fiber.Ctx, request *model.GetMidtransTransaction) (*model.MidtransTransactionResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newMidtransTransaction := new(entity.MidtransTransaction)
+	count, err := c.MidtransTransactionRepository.FindAndCountMidtransTransactionByOrderId(tx, newMidtransTransaction, request.OrderId)
+	if err != nil {
+		c.Log.Warnf("failed to find midtrans transaction by order id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find midtrans transaction by order id : %+v", err))
+	}
+
+	if count < 1 {
+		c.Log.Warnf("midtrans transaction not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "midtrans transaction not found!")
+	}
+
+	paymentGateway, err := c.PaymentGateways.Get(enum_state.PAYMENT_GATEWAY_MIDTRANS)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	resp, err := paymentGateway.GetPaymentStatus(ctx.Context(), newMidtransTransaction.MidtransOrderId)
+	if err != nil {
+		// transaksi snap belum tercatat di midtrans sampai customer memilih channel pembayaran
+		if fiberErr, ok := err.(*fiber.Error); ok && fiberErr.Code == fiber.StatusNotFound && newMidtransTransaction.ApiType == payment_gateway.MIDTRANS_API_TYPE_SNAP {
+			return converter.MidtransTransactionToResponse(newMidtransTransaction), nil
+		}
+
+		c.Log.Warnf("failed to find payment on %s : %+v", paymentGateway.Name(), err)
+		return nil, err
+	}
+
+	updateStatus := &model.MidtransNotificationRequest{
+		TransactionStatus: resp.RawStatus,
+		TransactionId:     resp.PaymentMethodId,
+		FraudStatus:       resp.FraudStatus,
+		PaymentType:       resp.PaymentMethod,
+	}
+
+	if err := c.updateStatus(ctx, tx, newMidtransTransaction, updateStatus, resp.VirtualAccountBank, resp.VirtualAccountNumber, resp.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.MidtransTransactionToResponse(newMidtransTransaction), nil
+}
+
+// Notification memproses http notification midtrans. Signature key diverifikasi terlebih dahulu,
+// notifikasi dengan status yang sama dengan yang tersimpan tidak akan diproses ulang.
+func (c *MidtransTransactionUseCase) Notification(ctx *fiber.Ctx, rawBody []byte) error {
+	paymentGateway, err := c.PaymentGateways.Get(enum_state.PAYMENT_GATEWAY_MIDTRANS)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	webhook, err := paymentGateway.ParseWebhook(rawBody)
+	if err != nil {
+		c.Log.Warnf("failed to parse midtrans notification : %+v", err)
+		return err
+	}
+
+	request := new(model.MidtransNotificationRequest)
+	if err := json.Unmarshal(rawBody, request); err != nil {
+		c.Log.Warnf("failed to unmarshall midtrans notification : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to unmarshall midtrans notification : %+v", err))
+	}
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	newMidtransTransaction := new(entity.MidtransTransaction)
+	count, err := c.MidtransTransactionRepository.FindAndCountMidtransTransactionByMidtransOrderId(tx, newMidtransTransaction, request.OrderId)
+	if err != nil {
+		c.Log.Warnf("failed to find midtrans transaction by midtrans order id : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find midtrans transaction by midtrans order id : %+v", err))
+	}
+
+	if count < 1 {
+		c.Log.Warnf("midtrans transaction %s not found!", request.OrderId)
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("midtrans transaction %s not found!", request.OrderId))
+	}
+
+	vaBank := newMidtransTransaction.VaBank
+	vaNumber := newMidtransTransaction.VaNumber
+	if len(request.VaNumbers) > 0 {
+		vaBank = request.VaNumbers[0].Bank
+		vaNumber = request.VaNumbers[0].VaNumber
+	}
+
+	if request.PermataVaNumber != "" {
+		vaBank = "permata"
+		vaNumber = request.PermataVaNumber
+	}
+
+	if err := c.updateStatus(ctx, tx, newMidtransTransaction, request, vaBank, vaNumber, webhook.UpdatedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return nil
+}
+
+// updateStatus menyimpan status terbaru transaksi midtrans lalu memperbarui payment status order jika berubah
+func (c *MidtransTransactionUseCase) updateStatus(ctx *fiber.Ctx, tx *gorm.DB, midtransTransaction *entity.MidtransTransaction, request *model.MidtransNotificationRequest, vaBank string, vaNumber string, updatedAt time.Time) error {
+	if midtransTransaction.TransactionStatus == request.TransactionStatus && midtransTransaction.FraudStatus == request.FraudStatus {
+		return nil
+	}
+
+	currentPaymentStatus := payment_gateway.MidtransPaymentStatus(midtransTransaction.TransactionStatus, midtransTransaction.FraudStatus)
+	newPaymentStatus := payment_gateway.MidtransPaymentStatus(request.TransactionStatus, request.FraudStatus)
+
+	// refund / chargeback tetap dicatat pada transaksi midtrans, payment status order diurus oleh alur refund
+	isRefund := false
+	switch request.TransactionStatus {
+	case "refund", "partial_refund", "chargeback", "partial_chargeback":
+		isRefund = true
+	}
+
+	// notifikasi bisa datang tidak berurutan, status akhir (paid, failed, cancelled, expired) bersifat final
+	// dan tidak boleh berubah lagi menjadi status lain kecuali karena refund
+	if currentPaymentStatus != enum_state.PENDING_PAYMENT && currentPaymentStatus != newPaymentStatus && !isRefund {
+		c.Log.Infof("midtrans transaction %s already %s, notification %s skipped", midtransTransaction.MidtransOrderId, currentPaymentStatus, request.TransactionStatus)
+		return nil
+	}
+
+	updateMidtransTransaction := map[string]any{
+		"transaction_status": request.TransactionStatus,
+		"fraud_status":       request.FraudStatus,
+		"va_bank":            vaBank,
+		"va_number":          vaNumber,
+		"updated_at":         updatedAt,
+	}
+
+	if request.TransactionId != "" {
+		updateMidtransTransaction["transaction_id"] = request.TransactionId
+	}
+
+	if request.PaymentType != "" {
+		updateMidtransTransaction["payment_type"] = request.PaymentType
+	}
+
+	if request.StatusCode != "" {
+		updateMidtransTransaction["status_code"] = request.StatusCode
+	}
+
+	if err := c.MidtransTransactionRepository.UpdateCustomColumns(tx, &entity.MidtransTransaction{ID: midtransTransaction.ID}, updateMidtransTransaction); err != nil {
+		c.Log.Warnf("failed to update midtrans transaction status into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update midtrans transaction status into database : %+v", err))
+	}
+
+	midtransTransaction.TransactionStatus = request.TransactionStatus
+	midtransTransaction.FraudStatus = request.FraudStatus
+	midtransTransaction.VaBank = vaBank
+	midtransTransaction.VaNumber = vaNumber
+	midtransTransaction.UpdatedAt = updatedAt
+	if request.TransactionId != "" {
+		midtransTransaction.TransactionId = request.TransactionId
+	}
+
+	if request.PaymentType != "" {
+		midtransTransaction.PaymentType = request.PaymentType
+	}
+
+	if currentPaymentStatus == newPaymentStatus || isRefund {
+		return nil
+	}
+
+	metadata := map[string]any{}
+	if len(midtransTransaction.Metadata) > 0 {
+		if err := json.Unmarshal(midtransTransaction.Metadata, &metadata); err != nil {
+			c.Log.Warnf("failed to unmarshall midtrans transaction metadata : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to unmarshall midtrans transaction metadata : %+v", err))
+		}
+	}
+
+	lang, _ := metadata["lang"].(string)
+	if lang == "" {
+		lang = string(enum_state.ENGLISH)
+	}
+
+	timeZone, _ := metadata["time_zone"].(string)
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		c.Log.Warnf("failed to load time zone : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to load time zone : %+v", err))
+	}
+
+	updateOrderPaymentStatus := &model.UpdateOrderPaymentStatus{
+		OrderId:         midtransTransaction.OrderId,
+		PaymentStatus:   newPaymentStatus,
+		UpdatedAt:       updatedAt,
+		Lang:            enum_state.Languange(lang),
+		TimeZone:        *loc,
+		BaseFrontEndURL: c.FrontEndConfig.BaseURL,
+	}
+
+	return c.XenditCallbackUseCase.UpdateOrderPaymentStatus(ctx, tx, updateOrderPaymentStatus)
+}
@@ -0,0 +1,428 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type OrderRefundUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	OrderRepository           *repository.OrderRepository
+	OrderRefundRepository     *repository.OrderRefundRepository
+	WalletRepository          *repository.WalletRepository
+	PaymentGateways           *payment_gateway.Registry
+	WalletConfig              *model.WalletConfig
+	OrderPaymentLegRepository *repository.OrderPaymentLegRepository
+	AuditLogUseCase           *AuditLogUseCase
+}
+
+func NewOrderRefundUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	orderRepository *repository.OrderRepository, orderRefundRepository *repository.OrderRefundRepository,
+	walletRepository *repository.WalletRepository, paymentGateways *payment_gateway.Registry,
+	walletConfig *model.WalletConfig, orderPaymentLegRepository *repository.OrderPaymentLegRepository,
+	auditLogUseCase *AuditLogUseCase) *OrderRefundUseCase {
+	return &OrderRefundUseCase{
+		DB:                        db,
+		Log:                       log,
+		Validate:                  validate,
+		OrderRepository:           orderRepository,
+		OrderRefundRepository:     orderRefundRepository,
+		WalletRepository:          walletRepository,
+		PaymentGateways:           paymentGateways,
+		WalletConfig:              walletConfig,
+		OrderPaymentLegRepository: orderPaymentLegRepository,
+		AuditLogUseCase:           auditLogUseCase,
+	}
+}
+
+// Create membuat refund penuh / sebagian untuk order yang sudah dibayar.
+// refund ke metode pembayaran asal dikirim ke payment gateway, statusnya diperbarui lewat callback
+func (c *OrderRefundUseCase) Create(ctx *fiber.Ctx, request *model.CreateOrderRefundRequest) (*model.OrderRefundResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.Amount > 0 && len(request.Items) > 0 {
+		c.Log.Warnf("refund can only be requested by amount or by items, not both!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "refund can only be requested by amount or by items, not both!")
+	}
+
+	// order dikunci sampai transaksi selesai agar refund yang dibuat bersamaan tidak melebihi nominal yang dibayar
+	if err := c.OrderRepository.FindByIdForUpdate(tx, &entity.Order{ID: request.OrderId}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("order not found!")
+			return nil, fiber.NewError(fiber.StatusNotFound, "order not found!")
+		}
+		c.Log.Warnf("failed to lock order by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to lock order by id : %+v", err))
+	}
+
+	newOrder := new(entity.Order)
+	newOrder.ID = request.OrderId
+	if err := c.OrderRepository.FindWith3Preloads(tx, newOrder, "OrderProducts", "XenditTransaction", "MidtransTransaction"); err != nil {
+		c.Log.Warnf("failed to find order by id from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id from database : %+v", err))
+	}
+
+	if newOrder.Invoice == "" {
+		c.Log.Warnf("order not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "order not found!")
+	}
+
+	if newOrder.PaymentStatus != enum_state.PAID_PAYMENT {
+		c.Log.Warnf("only paid order can be refunded!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "only paid order can be refunded!")
+	}
+
+	newOrderRefund := new(entity.OrderRefund)
+	totalRefunded, err := c.OrderRefundRepository.SumRefundedAmountByOrderId(tx, newOrderRefund, newOrder.ID)
+	if err != nil {
+		c.Log.Warnf("failed to sum refunded amount by order id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum refunded amount by order id : %+v", err))
+	}
+
+	refundableLeft := newOrder.TotalFinalPrice - totalRefunded
+	if refundableLeft <= 0 {
+		c.Log.Warnf("order %d has been fully refunded!", newOrder.ID)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("order %d has been fully refunded!", newOrder.ID))
+	}
+
+	// hitung nominal refund dari item, dibatasi sisa quantity yang belum direfund
+	refundItems := []entity.OrderRefundItem{}
+	amount := request.Amount
+	if len(request.Items) > 0 {
+		refundedQuantities, err := c.OrderRefundRepository.SumRefundedQuantityByOrderId(tx, newOrder.ID)
+		if err != nil {
+			c.Log.Warnf("failed to sum refunded quantity by order id : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum refunded quantity by order id : %+v", err))
+		}
+
+		orderProducts := map[uint64]entity.OrderProduct{}
+		for _, orderProduct := range newOrder.OrderProducts {
+			orderProducts[orderProduct.ID] = orderProduct
+		}
+
+		for _, item := range request.Items {
+			orderProduct, exists := orderProducts[item.OrderProductId]
+			if !exists {
+				c.Log.Warnf("order product %d not found in order %d!", item.OrderProductId, newOrder.ID)
+				return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order product %d not found in order %d!", item.OrderProductId, newOrder.ID))
+			}
+
+			quantityLeft := orderProduct.Quantity - refundedQuantities[orderProduct.ID]
+			if item.Quantity > quantityLeft {
+				c.Log.Warnf("refund quantity for %s exceeds the remaining quantity (%d)!", orderProduct.ProductName, quantityLeft)
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("refund quantity for %s exceeds the remaining quantity (%d)!", orderProduct.ProductName, quantityLeft))
+			}
+			refundedQuantities[orderProduct.ID] += item.Quantity
+
+			itemAmount := orderProduct.Price * float32(item.Quantity)
+			refundItems = append(refundItems, entity.OrderRefundItem{
+				OrderProductId: orderProduct.ID,
+				Quantity:       item.Quantity,
+				Amount:         itemAmount,
+			})
+			amount += itemAmount
+		}
+	}
+
+	// tanpa amount dan items berarti refund seluruh sisa pembayaran
+	if amount == 0 {
+		amount = refundableLeft
+	}
+
+	if amount > refundableLeft {
+		c.Log.Warnf("refund amount exceeds the refundable amount (%.2f)!", refundableLeft)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("refund amount exceeds the refundable amount (%.2f)!", refundableLeft))
+	}
+
+	var paymentId string
+	destination := request.Destination
+	if destination == enum_state.REFUND_DESTINATION_ORIGINAL_METHOD {
+		if newOrder.PaymentMethod == enum_state.PAYMENT_METHOD_CASH {
+			c.Log.Warnf("cash payment can only be refunded to wallet!")
+			return nil, fiber.NewError(fiber.StatusBadRequest, "cash payment can only be refunded to wallet!")
+		}
+
+		// metode pembayaran asal dari order yang dibayar pakai saldo adalah wallet itu sendiri
+		if newOrder.PaymentGateway == enum_state.PAYMENT_GATEWAY_SYSTEM {
+			destination = enum_state.REFUND_DESTINATION_WALLET
+		}
+	}
+
+	// order split payment hanya bisa direfund ke metode asal sebesar bagian yang dibayar lewat payment gateway
+	if destination == enum_state.REFUND_DESTINATION_ORIGINAL_METHOD {
+		walletPaid, err := c.OrderPaymentLegRepository.SumPaidWalletLegAmountByOrderId(tx, new(entity.OrderPaymentLeg), newOrder.ID)
+		if err != nil {
+			c.Log.Warnf("failed to sum wallet payment leg amount by order id : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum wallet payment leg amount by order id : %+v", err))
+		}
+
+		if walletPaid > 0 {
+			originalRefunded, err := c.OrderRefundRepository.SumRefundedAmountByOrderIdAndDestination(tx, new(entity.OrderRefund), newOrder.ID, string(enum_state.REFUND_DESTINATION_ORIGINAL_METHOD))
+			if err != nil {
+				c.Log.Warnf("failed to sum refunded amount by order id : %+v", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum refunded amount by order id : %+v", err))
+			}
+
+			gatewayRefundableLeft := newOrder.TotalFinalPrice - walletPaid - originalRefunded
+			if amount > gatewayRefundableLeft {
+				c.Log.Warnf("split payment order can only be refunded up to %.2f to original method, refund the rest to wallet!", gatewayRefundableLeft)
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("split payment order can only be refunded up to %.2f to original method, refund the rest to wallet!", gatewayRefundableLeft))
+			}
+		}
+	}
+
+	refundType := enum_state.REFUND_TYPE_PARTIAL
+	if totalRefunded == 0 && amount == newOrder.TotalFinalPrice {
+		refundType = enum_state.REFUND_TYPE_FULL
+	}
+
+	newOrderRefund.OrderId = newOrder.ID
+	newOrderRefund.RefundedBy = request.CurrentAdminId
+	newOrderRefund.ReferenceId = fmt.Sprintf("rfd-%s", uuid.NewString())
+	newOrderRefund.Type = refundType
+	newOrderRefund.Destination = destination
+	newOrderRefund.Amount = amount
+	newOrderRefund.Reason = request.Reason
+	newOrderRefund.Status = enum_state.REFUND_STATUS_PENDING
+	newOrderRefund.Items = refundItems
+
+	if destination == enum_state.REFUND_DESTINATION_WALLET {
+		note := fmt.Sprintf("Refund an order %s", newOrder.Invoice)
+		if err := refundOrderToWallet(tx, c.WalletRepository, newOrder, amount, c.WalletConfig.HoldCreditsWhenFrozen, enum_state.WALLET_FLOW_TYPE_CREDIT, enum_state.WALLET_TRANSACTION_TYPE_ORDER_REFUND, note, request.Reason, request.CurrentAdminId); err != nil {
+			c.Log.Warnf("failed to refund order to wallet : %+v", err)
+			return nil, err
+		}
+
+		now := time.Now()
+		newOrderRefund.Status = enum_state.REFUND_STATUS_SUCCEEDED
+		newOrderRefund.ProcessedAt = &now
+		if err := c.OrderRefundRepository.Create(tx, newOrderRefund); err != nil {
+			c.Log.Warnf("failed to create order refund into database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create order refund into database : %+v", err))
+		}
+	} else {
+		paymentId, err = c.getGatewayPaymentId(newOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := c.PaymentGateways.Get(newOrder.PaymentGateway); err != nil {
+			c.Log.Warnf("failed to get payment gateway : %+v", err)
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+		}
+
+		// refund dicatat pending dan di-commit dulu sebelum dikirim ke payment gateway,
+		// sehingga refund di payment gateway selalu punya catatan di database
+		newOrderRefund.PaymentGateway = newOrder.PaymentGateway
+		if err := c.OrderRefundRepository.Create(tx, newOrderRefund); err != nil {
+			c.Log.Warnf("failed to create order refund into database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create order refund into database : %+v", err))
+		}
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_ORDER_REFUND, newOrderRefund.ID, nil, c.AuditLogUseCase.Snapshot(tx, newOrderRefund)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	if destination == enum_state.REFUND_DESTINATION_ORIGINAL_METHOD {
+		if err := c.dispatchGatewayRefund(ctx, newOrderRefund, paymentId, request.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	newOrderRefund.Order = newOrder
+	return converter.OrderRefundToResponse(newOrderRefund), nil
+}
+
+// dispatchGatewayRefund mengirim refund yang sudah tercatat ke payment gateway dengan idempotency key dari id refund,
+// dijalankan setelah commit agar kegagalan database tidak meninggalkan refund di payment gateway tanpa catatan
+func (c *OrderRefundUseCase) dispatchGatewayRefund(ctx *fiber.Ctx, orderRefund *entity.OrderRefund, paymentId string, reason string) error {
+	paymentGateway, err := c.PaymentGateways.Get(orderRefund.PaymentGateway)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	db := c.DB.WithContext(ctx.Context())
+	resp, err := paymentGateway.RefundPayment(ctx.Context(), &model.RefundPaymentGatewayRequest{
+		PaymentId:      paymentId,
+		ReferenceId:    orderRefund.ReferenceId,
+		Amount:         orderRefund.Amount,
+		Reason:         reason,
+		IdempotencyKey: fmt.Sprintf("order-refund-%d", orderRefund.ID),
+	})
+	if err != nil {
+		c.Log.Warnf("failed to refund payment on %s : %+v", paymentGateway.Name(), err)
+
+		// refund yang ditolak payment gateway ditandai gagal, selain itu tetap pending karena
+		// refund bisa saja sudah diproses (misal timeout) dan nominalnya tidak boleh direfund ulang
+		if fiberErr, ok := err.(*fiber.Error); ok && fiberErr.Code >= fiber.StatusBadRequest && fiberErr.Code < fiber.StatusInternalServerError {
+			now := time.Now()
+			updateOrderRefund := map[string]any{
+				"status":       enum_state.REFUND_STATUS_FAILED,
+				"failure_code": "REJECTED_BY_GATEWAY",
+				"processed_at": now,
+			}
+
+			if err := c.OrderRefundRepository.UpdateCustomColumns(db, &entity.OrderRefund{ID: orderRefund.ID}, updateOrderRefund); err != nil {
+				c.Log.Warnf("failed to update order refund into database : %+v", err)
+			}
+		}
+		return err
+	}
+
+	updateOrderRefund := map[string]any{
+		"gateway_refund_id": resp.ID,
+		"failure_code":      resp.FailureCode,
+	}
+
+	orderRefund.GatewayRefundId = &resp.ID
+	orderRefund.FailureCode = resp.FailureCode
+	switch resp.Status {
+	case "SUCCEEDED":
+		now := time.Now()
+		orderRefund.Status = enum_state.REFUND_STATUS_SUCCEEDED
+		orderRefund.ProcessedAt = &now
+		updateOrderRefund["status"] = orderRefund.Status
+		updateOrderRefund["processed_at"] = now
+	case "FAILED":
+		now := time.Now()
+		orderRefund.Status = enum_state.REFUND_STATUS_FAILED
+		orderRefund.ProcessedAt = &now
+		updateOrderRefund["status"] = orderRefund.Status
+		updateOrderRefund["processed_at"] = now
+	}
+
+	if err := c.OrderRefundRepository.UpdateCustomColumns(db, &entity.OrderRefund{ID: orderRefund.ID}, updateOrderRefund); err != nil {
+		c.Log.Warnf("failed to update order refund into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update order refund into database : %+v", err))
+	}
+
+	return nil
+}
+
+func (c *OrderRefundUseCase) GetByOrderId(ctx *fiber.Ctx, orderId uint64) (*model.OrderRefundSummaryResponse, error) {
+	tx := c.DB.WithContext(ctx.Context())
+
+	newOrder := new(entity.Order)
+	newOrder.ID = orderId
+	count, err := c.OrderRepository.FindAndCountById(tx, newOrder)
+	if err != nil {
+		c.Log.Warnf("failed to find order by id from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id from database : %+v", err))
+	}
+
+	if count == 0 {
+		c.Log.Warnf("order not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "order not found!")
+	}
+
+	orderRefunds := new([]entity.OrderRefund)
+	if err := c.OrderRefundRepository.FindOrderRefundsByOrderId(tx, orderRefunds, orderId); err != nil {
+		c.Log.Warnf("failed to find order refunds from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order refunds from database : %+v", err))
+	}
+
+	response := &model.OrderRefundSummaryResponse{
+		OrderId:         newOrder.ID,
+		TotalFinalPrice: newOrder.TotalFinalPrice,
+		Refunds:         converter.OrderRefundsToResponse(orderRefunds),
+	}
+
+	for _, orderRefund := range *orderRefunds {
+		if orderRefund.Status != enum_state.REFUND_STATUS_FAILED {
+			response.TotalRefunded += orderRefund.Amount
+		}
+	}
+
+	// refund hanya bisa dilakukan untuk order yang sudah dibayar
+	if newOrder.PaymentStatus == enum_state.PAID_PAYMENT {
+		response.RefundableLeft = newOrder.TotalFinalPrice - response.TotalRefunded
+	}
+
+	return response, nil
+}
+
+// getGatewayPaymentId mengambil id pembayaran di payment gateway yang dipakai untuk refund
+func (c *OrderRefundUseCase) getGatewayPaymentId(order *entity.Order) (string, error) {
+	switch order.PaymentGateway {
+	case enum_state.PAYMENT_GATEWAY_XENDIT, enum_state.PAYMENT_GATEWAY_SIMULATOR:
+		if order.XenditTransaction == nil || order.XenditTransaction.ID == "" {
+			c.Log.Warnf("xendit transaction for order %d not found!", order.ID)
+			return "", fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("xendit transaction for order %d not found!", order.ID))
+		}
+		return order.XenditTransaction.ID, nil
+	case enum_state.PAYMENT_GATEWAY_MIDTRANS:
+		if order.MidtransTransaction == nil || order.MidtransTransaction.MidtransOrderId == "" {
+			c.Log.Warnf("midtrans transaction for order %d not found!", order.ID)
+			return "", fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("midtrans transaction for order %d not found!", order.ID))
+		}
+		return order.MidtransTransaction.MidtransOrderId, nil
+	default:
+		c.Log.Warnf("payment gateway %s doesn't support refund to original method!", order.PaymentGateway)
+		return "", fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment gateway %s doesn't support refund to original method!", order.PaymentGateway))
+	}
+}
+
+// refundOrderToWallet mengembalikan dana order ke saldo customer, ditahan dulu jika wallet sedang dibekukan
+func refundOrderToWallet(tx *gorm.DB, walletRepository *repository.WalletRepository, order *entity.Order, amount float32, holdWhenFrozen bool, flowType enum_state.WalletFlowType, transactionType enum_state.WalletTransactionType, note string, adminNote string, processedBy uint64) error {
+	// wallet dikunci supaya debit / kredit lain pada wallet yang sama menunggu refund ini selesai
+	findWallet := new(entity.Wallet)
+	if err := walletRepository.FindFirstByUserIdForUpdate(tx, findWallet, order.UserId); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
+	}
+
+	refundStatus, err := helper_others.CreditWalletBalance(tx, findWallet, amount, holdWhenFrozen)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
+	}
+
+	now := time.Now()
+	newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
+	newSaveWalletTransaction.DB = tx
+	newSaveWalletTransaction.UserId = order.UserId
+	newSaveWalletTransaction.OrderId = &order.ID
+	newSaveWalletTransaction.Amount = amount
+	newSaveWalletTransaction.FlowType = flowType
+	newSaveWalletTransaction.TransactionType = transactionType
+	newSaveWalletTransaction.PaymentMethod = order.PaymentMethod
+	newSaveWalletTransaction.Status = refundStatus
+	newSaveWalletTransaction.ReferenceNumber = order.Invoice
+	newSaveWalletTransaction.Note = note
+	newSaveWalletTransaction.AdminNote = adminNote
+	newSaveWalletTransaction.ProcessedAt = &now
+	newSaveWalletTransaction.ProcessedBy = &processedBy
+	if err := helper_others.SaveWalletTransaction(newSaveWalletTransaction); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+	}
+
+	return nil
+}
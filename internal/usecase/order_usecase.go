@@ -10,7 +10,9 @@ import (
 	"seblak-bombom-restful-api/internal/helper/mailer"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/payment_gateway"
 	"seblak-bombom-restful-api/internal/repository"
+	midtransUseCase "seblak-bombom-restful-api/internal/usecase/midtrans"
 	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
 	"strings"
 	"time"
@@ -19,8 +21,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"github.com/xendit/xendit-go/v6"
 	"gorm.io/gorm"
 )
 
@@ -36,13 +38,18 @@ type OrderUseCase struct {
 	DiscountUsageRepository        *repository.DiscountUsageRepository
 	DeliveryRepository             *repository.DeliveryRepository
 	OrderProductRepository         *repository.OrderProductRepository
+	OrderRefundRepository          *repository.OrderRefundRepository
 	WalletRepository               *repository.WalletRepository
 	XenditTransactionRepository    *repository.XenditTransctionRepository
 	XenditTransactionQRCodeUseCase *xenditUseCase.XenditTransactionQRCodeUseCase
-	XenditClient                   *xendit.APIClient
+	MidtransTransactionUseCase     *midtransUseCase.MidtransTransactionUseCase
+	PaymentGateways                *payment_gateway.Registry
 	ApplicationRepository          *repository.ApplicationRepository
 	NotificationRepository         *repository.NotificationRepository
 	Email                          *mailer.EmailWorker
+	WalletConfig                   *model.WalletConfig
+	OrderPaymentLegRepository      *repository.OrderPaymentLegRepository
+	WalletTransactionRepository    *repository.WalletTransactionRepository
 }
 
 func NewOrderUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
@@ -51,8 +58,11 @@ func NewOrderUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Valida
 	discountRepository *repository.DiscountCouponRepository, discountUsageRepository *repository.DiscountUsageRepository,
 	deliveryRepository *repository.DeliveryRepository, orderProductRepository *repository.OrderProductRepository,
 	walletRepository *repository.WalletRepository, xenditTransactionRepository *repository.XenditTransctionRepository,
-	xenditTransactionQRCodeUseCase *xenditUseCase.XenditTransactionQRCodeUseCase, xenditClient *xendit.APIClient,
-	applicationRepository *repository.ApplicationRepository, email *mailer.EmailWorker, notificationRepository *repository.NotificationRepository) *OrderUseCase {
+	xenditTransactionQRCodeUseCase *xenditUseCase.XenditTransactionQRCodeUseCase,
+	midtransTransactionUseCase *midtransUseCase.MidtransTransactionUseCase, paymentGateways *payment_gateway.Registry,
+	applicationRepository *repository.ApplicationRepository, email *mailer.EmailWorker, notificationRepository *repository.NotificationRepository,
+	walletConfig *model.WalletConfig, orderRefundRepository *repository.OrderRefundRepository,
+	orderPaymentLegRepository *repository.OrderPaymentLegRepository, walletTransactionRepository *repository.WalletTransactionRepository) *OrderUseCase {
 	return &OrderUseCase{
 		DB:                             db,
 		Log:                            log,
@@ -65,13 +75,18 @@ func NewOrderUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Valida
 		DiscountUsageRepository:        discountUsageRepository,
 		DeliveryRepository:             deliveryRepository,
 		OrderProductRepository:         orderProductRepository,
+		OrderRefundRepository:          orderRefundRepository,
 		WalletRepository:               walletRepository,
 		XenditTransactionRepository:    xenditTransactionRepository,
 		XenditTransactionQRCodeUseCase: xenditTransactionQRCodeUseCase,
-		XenditClient:                   xenditClient,
+		MidtransTransactionUseCase:     midtransTransactionUseCase,
+		PaymentGateways:                paymentGateways,
 		ApplicationRepository:          applicationRepository,
 		Email:                          email,
 		NotificationRepository:         notificationRepository,
+		WalletConfig:                   walletConfig,
+		OrderPaymentLegRepository:      orderPaymentLegRepository,
+		WalletTransactionRepository:    walletTransactionRepository,
 	}
 }
 
@@ -298,7 +313,13 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 	newOrder.PaymentGateway = request.PaymentGateway
 	newOrder.PaymentStatus = enum_state.PENDING_PAYMENT
 
-	if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_SYSTEM {
+	// pembayaran tunai dibayar di kasir / ke kurir, order tetap pending sampai dikonfirmasi admin atau kurir
+	if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_SYSTEM && request.PaymentMethod == enum_state.PAYMENT_METHOD_CASH {
+		if request.ChannelCode != enum_state.CASH_CHANNEL_CODE {
+			c.Log.Warnf("channel code %s is not available on payment method cash!", request.ChannelCode)
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment method cash!", request.ChannelCode))
+		}
+	} else if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_SYSTEM {
 		if request.PaymentMethod != enum_state.PAYMENT_METHOD_WALLET {
 			c.Log.Warnf("payment method %s is not available on payment gateway system!", request.PaymentMethod)
 			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available on payment gateway system!", request.PaymentMethod))
@@ -309,14 +330,26 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway system!", request.ChannelCode))
 		}
 
+		// wallet dikunci agar dua order yang dibuat bersamaan tidak memakai saldo yang sama
+		currentWallet := new(entity.Wallet)
+		if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, currentWallet, newOrder.UserId); err != nil {
+			c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
+		}
+
+		if currentWallet.Status == enum_state.INACIVE_WALLET {
+			c.Log.Warnf("your wallet is frozen and can't be used for this transaction!")
+			return nil, fiber.NewError(fiber.StatusForbidden, "your wallet is frozen and can't be used for this transaction!")
+		}
+
 		// langsung paid dan proses walletnya
-		if request.CurrentBalance < newOrder.TotalFinalPrice {
+		if currentWallet.Balance < newOrder.TotalFinalPrice {
 			// tampilkan error bahwa saldo kurang
 			c.Log.Warnf("your balance is insufficient to perform this transaction!")
 			return nil, fiber.NewError(fiber.StatusBadRequest, "your balance is insufficient to perform this transaction!")
 		}
 
-		newBalance := request.CurrentBalance - newOrder.TotalFinalPrice
+		newBalance := currentWallet.Balance - newOrder.TotalFinalPrice
 		newWallet := new(entity.Wallet)
 		if err := c.WalletRepository.UpdateWalletBalance(tx, newWallet, newOrder.UserId, newBalance); err != nil {
 			c.Log.Warnf("failed to update new balance : %+v", err)
@@ -326,8 +359,14 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		newOrder.PaymentStatus = enum_state.PAID_PAYMENT
 	}
 
-	if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_XENDIT {
-		if request.PaymentMethod != enum_state.PAYMENT_METHOD_QR_CODE {
+	if request.PaymentGateway != enum_state.PAYMENT_GATEWAY_SYSTEM && !c.PaymentGateways.Has(request.PaymentGateway) {
+		c.Log.Warnf("payment gateway %s is not available!", request.PaymentGateway)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment gateway %s is not available!", request.PaymentGateway))
+	}
+
+	// simulator memakai aturan payment method dan channel code yang sama dengan xendit
+	if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_XENDIT || request.PaymentGateway == enum_state.PAYMENT_GATEWAY_SIMULATOR {
+		if request.PaymentMethod != enum_state.PAYMENT_METHOD_QR_CODE && request.PaymentMethod != enum_state.PAYMENT_METHOD_EWALLET && request.PaymentMethod != enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT {
 			c.Log.Warnf("payment method %s is not available on payment gateway %s!", request.PaymentMethod, request.PaymentGateway)
 			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available on payment gateway %s!", request.PaymentMethod, request.PaymentGateway))
 		} else {
@@ -337,6 +376,19 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 					enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
 					enum_state.XENDIT_QR_LINKAJA_CHANNEL_CODE,
 				},
+				enum_state.PAYMENT_METHOD_EWALLET: {
+					enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE,
+					enum_state.XENDIT_EWALLET_DANA_CHANNEL_CODE,
+					enum_state.XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE,
+					enum_state.XENDIT_EWALLET_LINKAJA_CHANNEL_CODE,
+				},
+				enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT: {
+					enum_state.XENDIT_VA_BCA_CHANNEL_CODE,
+					enum_state.XENDIT_VA_BNI_CHANNEL_CODE,
+					enum_state.XENDIT_VA_BRI_CHANNEL_CODE,
+					enum_state.XENDIT_VA_MANDIRI_CHANNEL_CODE,
+					enum_state.XENDIT_VA_PERMATA_CHANNEL_CODE,
+				},
 			}
 
 			// Cek apakah ChannelCode valid untuk PaymentMethod yang dipilih
@@ -352,7 +404,82 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 				c.Log.Warnf("channel code %s is not available on payment gateway %s!", request.ChannelCode, request.PaymentGateway)
 				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway %s!", request.ChannelCode, request.PaymentGateway))
 			}
+
+			// OVO mengirim permintaan pembayaran ke aplikasi customer sehingga nomor HP wajib diisi
+			if request.ChannelCode == enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE {
+				mobileNumber, ok := helper_others.NormalizeIndonesianMobileNumber(request.MobileNumber)
+				if !ok {
+					c.Log.Warnf("invalid mobile number for OVO payment : %s", request.MobileNumber)
+					return nil, fiber.NewError(fiber.StatusBadRequest, "mobile number is required for OVO payment and must be a valid indonesian mobile number!")
+				}
+				request.MobileNumber = mobileNumber
+			}
+		}
+	}
+
+	if request.PaymentGateway == enum_state.PAYMENT_GATEWAY_MIDTRANS {
+		validChannelCodes := map[enum_state.PaymentMethod][]enum_state.ChannelCode{
+			enum_state.PAYMENT_METHOD_QR_CODE: {
+				enum_state.MIDTRANS_QRIS_CHANNEL_CODE,
+			},
+			enum_state.PAYMENT_METHOD_EWALLET: {
+				enum_state.MIDTRANS_GOPAY_CHANNEL_CODE,
+			},
+			enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT: {
+				enum_state.MIDTRANS_VA_BCA_CHANNEL_CODE,
+				enum_state.MIDTRANS_VA_BNI_CHANNEL_CODE,
+				enum_state.MIDTRANS_VA_BRI_CHANNEL_CODE,
+				enum_state.MIDTRANS_VA_PERMATA_CHANNEL_CODE,
+			},
+			enum_state.PAYMENT_METHOD_CHECKOUT: {
+				enum_state.MIDTRANS_SNAP_CHANNEL_CODE,
+			},
+		}
+
+		validCodes, exists := validChannelCodes[request.PaymentMethod]
+		if !exists {
+			c.Log.Warnf("payment method %s is not available on payment gateway %s!", request.PaymentMethod, request.PaymentGateway)
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("payment method %s is not available on payment gateway %s!", request.PaymentMethod, request.PaymentGateway))
+		}
+
+		if !slices.Contains(validCodes, request.ChannelCode) {
+			c.Log.Warnf("channel code %s is not available on payment gateway %s!", request.ChannelCode, request.PaymentGateway)
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("channel code %s is not available on payment gateway %s!", request.ChannelCode, request.PaymentGateway))
+		}
+	}
+
+	// split payment: seluruh saldo wallet dipakai dan sisanya dibayar lewat payment gateway,
+	// saldo wallet ditahan sampai pembayaran di payment gateway berhasil
+	var walletHoldAmount float32
+	splitWallet := new(entity.Wallet)
+	if request.UseWallet {
+		if request.PaymentGateway != enum_state.PAYMENT_GATEWAY_XENDIT && request.PaymentGateway != enum_state.PAYMENT_GATEWAY_SIMULATOR {
+			c.Log.Warnf("wallet balance can't be combined with payment gateway %s!", request.PaymentGateway)
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("wallet balance can't be combined with payment gateway %s!", request.PaymentGateway))
 		}
+
+		// wallet dikunci sampai saldo selesai ditahan agar tidak dipakai order lain secara bersamaan
+		if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, splitWallet, newOrder.UserId); err != nil {
+			c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
+		}
+
+		if splitWallet.Status == enum_state.INACIVE_WALLET {
+			c.Log.Warnf("your wallet is frozen and can't be used for this transaction!")
+			return nil, fiber.NewError(fiber.StatusForbidden, "your wallet is frozen and can't be used for this transaction!")
+		}
+
+		if splitWallet.Balance <= 0 {
+			c.Log.Warnf("your wallet balance is empty!")
+			return nil, fiber.NewError(fiber.StatusBadRequest, "your wallet balance is empty!")
+		}
+
+		if splitWallet.Balance >= newOrder.TotalFinalPrice {
+			c.Log.Warnf("your balance is sufficient, please pay the order with wallet instead!")
+			return nil, fiber.NewError(fiber.StatusBadRequest, "your balance is sufficient, please pay the order with wallet instead!")
+		}
+
+		walletHoldAmount = splitWallet.Balance
 	}
 
 	// mengambil alamat utama yang diambil oleh user
@@ -416,12 +543,52 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		}
 	}
 
-	// jika pembayaran menggunakan xendit,maka panggil xendit usecase
-	if newOrder.PaymentGateway == enum_state.PAYMENT_GATEWAY_XENDIT && newOrder.PaymentMethod == enum_state.PAYMENT_METHOD_QR_CODE {
+	if walletHoldAmount > 0 {
+		// saldo langsung dipotong agar tidak bisa dipakai transaksi lain selama menunggu pembayaran payment gateway
+		if err := c.WalletRepository.UpdateWalletBalance(tx, new(entity.Wallet), newOrder.UserId, splitWallet.Balance-walletHoldAmount); err != nil {
+			c.Log.Warnf("failed to update new balance : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update new balance : %+v", err))
+		}
+
+		holdTransaction := new(entity.WalletTransactions)
+		holdTransaction.UserId = newOrder.UserId
+		holdTransaction.OrderId = &newOrder.ID
+		holdTransaction.Amount = walletHoldAmount
+		holdTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
+		holdTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_ORDER_PAYMENT
+		holdTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
+		holdTransaction.Status = enum_state.WALLET_TRANSACTION_STATUS_PENDING
+		holdTransaction.ReferenceNumber = newOrder.Invoice
+		holdTransaction.Note = fmt.Sprintf("Hold wallet balance for split payment of order %s", invoice)
+		if err := c.WalletTransactionRepository.Create(tx, holdTransaction); err != nil {
+			c.Log.Warnf("failed to save wallet transaction : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+		}
+
+		walletLeg := new(entity.OrderPaymentLeg)
+		walletLeg.OrderId = newOrder.ID
+		walletLeg.Source = enum_state.ORDER_PAYMENT_LEG_SOURCE_WALLET
+		walletLeg.PaymentGateway = enum_state.PAYMENT_GATEWAY_SYSTEM
+		walletLeg.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
+		walletLeg.ChannelCode = enum_state.WALLET_CHANNEL_CODE
+		walletLeg.Amount = walletHoldAmount
+		walletLeg.Status = enum_state.ORDER_PAYMENT_LEG_HELD
+		walletLeg.WalletTransactionId = &holdTransaction.ID
+		if err := c.OrderPaymentLegRepository.Create(tx, walletLeg); err != nil {
+			c.Log.Warnf("failed to create wallet payment leg : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create wallet payment leg : %+v", err))
+		}
+	}
+
+	// jika pembayaran menggunakan payment gateway (xendit / simulator), maka buat transaksi QR code / e-wallet / virtual account
+	if (newOrder.PaymentGateway == enum_state.PAYMENT_GATEWAY_XENDIT || newOrder.PaymentGateway == enum_state.PAYMENT_GATEWAY_SIMULATOR) &&
+		(newOrder.PaymentMethod == enum_state.PAYMENT_METHOD_QR_CODE || newOrder.PaymentMethod == enum_state.PAYMENT_METHOD_EWALLET || newOrder.PaymentMethod == enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT) {
 		newXenditQRCodeRequest := new(model.CreateXenditTransaction)
 		newXenditQRCodeRequest.OrderId = newOrder.ID
+		newXenditQRCodeRequest.MobileNumber = request.MobileNumber
 		newXenditQRCodeRequest.Lang = request.Lang
 		newXenditQRCodeRequest.TimeZone = request.TimeZone
+		newXenditQRCodeRequest.BaseFrontEndURL = request.BaseFrontEndURL
 		result, err := c.XenditTransactionQRCodeUseCase.Add(ctx, newXenditQRCodeRequest, tx)
 		if err != nil {
 			c.Log.Warn(err)
@@ -438,6 +605,10 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		newXenditTransaction.PaymentMethodId = result.PaymentMethodId
 		newXenditTransaction.ChannelCode = result.ChannelCode
 		newXenditTransaction.QrString = result.QrString
+		newXenditTransaction.MobileNumber = result.MobileNumber
+		newXenditTransaction.CheckoutUrl = result.CheckoutUrl
+		newXenditTransaction.DeeplinkUrl = result.DeeplinkUrl
+		newXenditTransaction.VirtualAccountNumber = result.VirtualAccountNumber
 		newXenditTransaction.Status = result.Status
 		newXenditTransaction.Description = result.Description
 		newXenditTransaction.FailureCode = result.FailureCode
@@ -447,10 +618,37 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		newXenditTransaction.UpdatedAt = helper_others.TimeRFC3339.ToTime(result.UpdatedAt)
 
 		newOrder.XenditTransaction = newXenditTransaction
+
+		if walletHoldAmount > 0 {
+			gatewayLeg := new(entity.OrderPaymentLeg)
+			gatewayLeg.OrderId = newOrder.ID
+			gatewayLeg.Source = enum_state.ORDER_PAYMENT_LEG_SOURCE_GATEWAY
+			gatewayLeg.PaymentGateway = newOrder.PaymentGateway
+			gatewayLeg.PaymentMethod = newOrder.PaymentMethod
+			gatewayLeg.ChannelCode = newOrder.ChannelCode
+			gatewayLeg.Amount = float32(result.Amount)
+			gatewayLeg.Status = enum_state.ORDER_PAYMENT_LEG_PENDING
+			gatewayLeg.XenditTransactionId = &result.ID
+			if err := c.OrderPaymentLegRepository.Create(tx, gatewayLeg); err != nil {
+				c.Log.Warnf("failed to create gateway payment leg : %+v", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create gateway payment leg : %+v", err))
+			}
+		}
+	}
+
+	if newOrder.PaymentGateway == enum_state.PAYMENT_GATEWAY_MIDTRANS {
+		newMidtransRequest := new(model.CreateMidtransTransaction)
+		newMidtransRequest.OrderId = newOrder.ID
+		newMidtransRequest.Lang = request.Lang
+		newMidtransRequest.TimeZone = request.TimeZone
+		if _, err := c.MidtransTransactionUseCase.Add(ctx, newMidtransRequest, tx); err != nil {
+			c.Log.Warn(err)
+			return nil, err
+		}
 	}
 
 	// tidak perlu preload xendit_transactions karena sudah di handle pada if diatas
-	if err := c.OrderRepository.FindWithPreloads(tx, newOrder, "OrderProducts"); err != nil {
+	if err := c.OrderRepository.FindWith3Preloads(tx, newOrder, "OrderProducts", "MidtransTransaction", "PaymentLegs"); err != nil {
 		c.Log.Warnf("failed to find newly created order : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find newly created order : %+v", err))
 	}
@@ -467,7 +665,13 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert logo to base64 : %+v", err))
 	}
 
-	if newOrder.PaymentStatus == enum_state.PAID_PAYMENT {
+	// customer virtual account perlu menerima nomor VA dan cara pembayaran lewat email
+	var virtualAccountInstructions []string
+	if newOrder.XenditTransaction != nil && newOrder.XenditTransaction.VirtualAccountNumber != "" {
+		virtualAccountInstructions = helper_others.VirtualAccountInstructions(newOrder.XenditTransaction.ChannelCode, newOrder.XenditTransaction.VirtualAccountNumber, request.Lang)
+	}
+
+	if newOrder.PaymentStatus == enum_state.PAID_PAYMENT || len(virtualAccountInstructions) > 0 {
 		newMail := new(model.Mail)
 		newMail.To = []string{newOrder.Email}
 		newMail.Subject = "Payment Successfull"
@@ -475,6 +679,23 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 			newMail.Subject = "Pembayaran Berhasil"
 		}
 
+		if newOrder.PaymentStatus == enum_state.PENDING_PAYMENT {
+			newMail.Subject = "Waiting for Payment"
+			if request.Lang == enum_state.INDONESIA {
+				newMail.Subject = "Menunggu Pembayaran"
+			}
+		}
+
+		var virtualAccountBank, virtualAccountNumber, virtualAccountExpiresAt string
+		if len(virtualAccountInstructions) > 0 {
+			virtualAccountBank = newOrder.XenditTransaction.ChannelCode
+			if bank, ok := helper_others.FindVirtualAccountBank(newOrder.XenditTransaction.ChannelCode); ok {
+				virtualAccountBank = bank.Name
+			}
+			virtualAccountNumber = newOrder.XenditTransaction.VirtualAccountNumber
+			virtualAccountExpiresAt = newOrder.XenditTransaction.ExpiresAt.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST")
+		}
+
 		baseTemplatePath := "internal/templates/base_template_email1.html"
 		childPath := fmt.Sprintf("internal/templates/%s/email/order_payment.html", request.Lang)
 		tmpl, err := template.ParseFiles(baseTemplatePath, childPath)
@@ -505,6 +726,11 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 			"PaymentStatus":    newOrder.PaymentStatus,
 			"PaymentLink":      paymentLink,
 			"OrderTrackingURL": orderTrackingURL,
+			// hanya terisi untuk pembayaran virtual account
+			"VirtualAccountBank":         virtualAccountBank,
+			"VirtualAccountNumber":       virtualAccountNumber,
+			"VirtualAccountExpiresAt":    virtualAccountExpiresAt,
+			"VirtualAccountInstructions": virtualAccountInstructions,
 		})
 		if err != nil {
 			c.Log.Warnf("failed to execute template file html : %+v", err)
@@ -567,7 +793,12 @@ func (c *OrderUseCase) Add(ctx *fiber.Ctx, request *model.CreateOrderRequest) (*
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
 	}
 
-	return converter.OrderToResponse(newOrder), nil
+	response := converter.OrderToResponse(newOrder)
+	if response.XenditTransaction != nil && len(virtualAccountInstructions) > 0 {
+		response.XenditTransaction.VirtualAccountInstructions = virtualAccountInstructions
+	}
+
+	return response, nil
 }
 
 func (c *OrderUseCase) GetAllCurrent(ctx context.Context, request *model.GetOrderByCurrentRequest) (*[]model.OrderResponse, error) {
@@ -593,12 +824,12 @@ func (c *OrderUseCase) GetOrderById(ctx context.Context, orderId uint64, current
 
 	newOrders := new(entity.Order)
 	newOrders.ID = orderId
-	if err := c.OrderRepository.FindWith3Preloads(tx, newOrders, "OrderProducts", "OrderProducts.Product", "XenditTransaction"); err != nil {
+	if err := c.OrderRepository.FindWith3Preloads(tx.Preload("MidtransTransaction").Preload("PaymentLegs"), newOrders, "OrderProducts", "OrderProducts.Product", "XenditTransaction"); err != nil {
 		c.Log.Warnf("failed to find order by id : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id : %+v", err))
 	}
 
-	if currentUser.Role != enum_state.ADMIN && newOrders.UserId != currentUser.ID {
+	if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_READ) && newOrders.UserId != currentUser.ID {
 		c.Log.Warnf("cannot access another order except admin!")
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "cannot access another order except admin")
 	}
@@ -659,8 +890,10 @@ func (c *OrderUseCase) GetAllPaginate(ctx context.Context, page int, perPage int
 		result := d.Joins("JOIN order_products ON order_products.order_id = orders.id").
 			Preload("OrderProducts").
 			Preload("OrderProducts.Product.Images").
-			Preload("XenditTransaction").Where("order_products.product_name LIKE ?", "%"+search+"%")
-		if currentUser.Role == enum_state.CUSTOMER {
+			Preload("XenditTransaction").
+			Preload("MidtransTransaction").Where("order_products.product_name LIKE ?", "%"+search+"%")
+		// customer dan staff tanpa permission orders.read hanya melihat pesanan miliknya
+		if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_READ) {
 			result.Where("user_id = ?", currentUser.ID)
 		}
 		return result
@@ -724,53 +957,10 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 		}
 
 		if newOrder.OrderStatus == enum_state.ORDER_PENDING && newOrder.PaymentStatus == enum_state.PAID_PAYMENT {
-			// jika pending dan paid maka kembalikan saldo
-			// find user wallet
-			findWallet := new(entity.Wallet)
-			count, err := c.WalletRepository.FindAndCountFirstWalletByUserId(tx, findWallet, newOrder.UserId, "active")
-			if err != nil {
-				c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
-			}
-
-			if count < 1 {
-				c.Log.Warnf("the selected wallet is not found!")
-				return nil, fiber.NewError(fiber.StatusBadRequest, "the selected wallet is not found!")
-			}
-
-			// return to wallet balance
-			newWallet := new(entity.Wallet)
-			newWallet.ID = findWallet.ID
-			totalBalance := newOrder.TotalFinalPrice + findWallet.Balance
-			updateBalance := map[string]any{
-				"balance": totalBalance,
-			}
-
-			if err := c.WalletRepository.UpdateCustomColumns(tx, newWallet, updateBalance); err != nil {
-				c.Log.Warnf("failed to update wallet balance : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
-			}
-
-			now := time.Now()
-			newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
-			newSaveWalletTransaction.DB = tx
-			newSaveWalletTransaction.UserId = newOrder.UserId
-			newSaveWalletTransaction.OrderId = &newOrder.ID
-			newSaveWalletTransaction.Amount = newOrder.TotalFinalPrice
-			newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
-			newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_ORDER_PAYMENT
-			newSaveWalletTransaction.PaymentMethod = newOrder.PaymentMethod
-			newSaveWalletTransaction.Status = enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
-			newSaveWalletTransaction.ReferenceNumber = newOrder.Invoice
-			newSaveWalletTransaction.Note = fmt.Sprintf("Cancel an order %s : %s", newOrder.Invoice, request.CancellationNotes)
-			newSaveWalletTransaction.AdminNote = ""
-			newSaveWalletTransaction.ProcessedAt = &now
-			newSaveWalletTransaction.ProcessedBy = &newOrder.UserId
-
-			err = helper_others.SaveWalletTransaction(newSaveWalletTransaction)
-			if err != nil {
-				c.Log.Warnf("failed to save wallet transaction : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+			// jika pending dan paid maka kembalikan sisa dana yang belum direfund ke saldo
+			note := fmt.Sprintf("Cancel an order %s : %s", newOrder.Invoice, request.CancellationNotes)
+			if err := c.refundRemainingToWallet(tx, newOrder, note, "", request.CancellationNotes, newOrder.UserId); err != nil {
+				return nil, err
 			}
 
 			newOrder.OrderStatus = request.OrderStatus
@@ -801,7 +991,7 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 
 	if request.OrderStatus == enum_state.ORDER_REJECTED {
 		// Admin access only for reject
-		if currentUser.Role == enum_state.CUSTOMER {
+		if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_UPDATE_STATUS) {
 			c.Log.Warn("admin access only!")
 			return nil, fiber.NewError(fiber.StatusUnauthorized, "admin access only!")
 		}
@@ -826,55 +1016,11 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 			return nil, fiber.NewError(fiber.StatusBadRequest, "can't reject an order that is been delivered!")
 		}
 
-		// maka balikkan saldo customer
+		// maka balikkan sisa saldo customer yang belum direfund
 		if newOrder.PaymentStatus == enum_state.PAID_PAYMENT {
-			// find user wallet
-			// kembalikan saldo ke user
-			findWallet := new(entity.Wallet)
-			count, err := c.WalletRepository.FindAndCountFirstWalletByUserId(tx, findWallet, newOrder.UserId, "active")
-			if err != nil {
-				c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
-			}
-
-			if count < 1 {
-				c.Log.Warnf("the selected wallet is not found!")
-				return nil, fiber.NewError(fiber.StatusBadRequest, "the selected wallet is not found!")
-			}
-
-			// return to wallet balance
-			newWallet := new(entity.Wallet)
-			newWallet.ID = findWallet.ID
-			totalBalance := newOrder.TotalFinalPrice + findWallet.Balance
-			updateBalance := map[string]any{
-				"balance": totalBalance,
-			}
-
-			if err := c.WalletRepository.UpdateCustomColumns(tx, newWallet, updateBalance); err != nil {
-				c.Log.Warnf("failed to update wallet balance : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
-			}
-
-			now := time.Now()
-			newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
-			newSaveWalletTransaction.DB = tx
-			newSaveWalletTransaction.UserId = newOrder.UserId
-			newSaveWalletTransaction.OrderId = &newOrder.ID
-			newSaveWalletTransaction.Amount = newOrder.TotalFinalPrice
-			newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
-			newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_ORDER_PAYMENT
-			newSaveWalletTransaction.PaymentMethod = newOrder.PaymentMethod
-			newSaveWalletTransaction.Status = enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
-			newSaveWalletTransaction.ReferenceNumber = newOrder.Invoice
-			newSaveWalletTransaction.Note = fmt.Sprintf("Rejected an order %s", newOrder.Invoice)
-			newSaveWalletTransaction.AdminNote = request.RejectionNotes
-			newSaveWalletTransaction.ProcessedAt = &now
-			newSaveWalletTransaction.ProcessedBy = &currentUser.ID
-
-			err = helper_others.SaveWalletTransaction(newSaveWalletTransaction)
-			if err != nil {
-				c.Log.Warnf("failed to save wallet transaction : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+			note := fmt.Sprintf("Rejected an order %s", newOrder.Invoice)
+			if err := c.refundRemainingToWallet(tx, newOrder, note, request.RejectionNotes, request.RejectionNotes, currentUser.ID); err != nil {
+				return nil, err
 			}
 
 			is_send_email = true
@@ -891,7 +1037,7 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 
 	if request.OrderStatus == enum_state.ORDER_RECEIVED {
 		// Admin access only for received
-		if currentUser.Role == enum_state.CUSTOMER {
+		if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_UPDATE_STATUS) {
 			c.Log.Warn("admin access only!")
 			return nil, fiber.NewError(fiber.StatusUnauthorized, "admin access only!")
 		}
@@ -938,7 +1084,7 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 
 	if request.OrderStatus == enum_state.READY_FOR_PICKUP {
 		// Admin access only for pick up
-		if currentUser.Role == enum_state.CUSTOMER {
+		if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_UPDATE_STATUS) {
 			c.Log.Warn("admin access only!")
 			return nil, fiber.NewError(fiber.StatusUnauthorized, "admin access only!")
 		}
@@ -978,7 +1124,7 @@ func (c *OrderUseCase) EditOrderStatus(ctx context.Context, request *model.Updat
 
 	if request.OrderStatus == enum_state.ORDER_BEING_DELIVERED {
 		// Admin access only for being delivered
-		if currentUser.Role == enum_state.CUSTOMER {
+		if !currentUser.HasPermission(enum_state.PERMISSION_ORDERS_UPDATE_STATUS) {
 			c.Log.Warn("admin access only!")
 			return nil, fiber.NewError(fiber.StatusUnauthorized, "admin access only!")
 		}
@@ -1176,3 +1322,45 @@ func (c *OrderUseCase) GetInvoice(ctx context.Context, invoiceId string) (*model
 
 	return converter.OrderToResponse(newOrder), converter.ApplicationToResponse(newApplication), nil
 }
+
+// refundRemainingToWallet mengembalikan sisa dana order yang belum direfund ke saldo customer
+// dan mencatatnya sebagai refund agar dana tidak dikembalikan dua kali
+func (c *OrderUseCase) refundRemainingToWallet(tx *gorm.DB, order *entity.Order, note string, adminNote string, reason string, refundedBy uint64) error {
+	newOrderRefund := new(entity.OrderRefund)
+	totalRefunded, err := c.OrderRefundRepository.SumRefundedAmountByOrderId(tx, newOrderRefund, order.ID)
+	if err != nil {
+		c.Log.Warnf("failed to sum refunded amount by order id : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum refunded amount by order id : %+v", err))
+	}
+
+	amount := order.TotalFinalPrice - totalRefunded
+	if amount <= 0 {
+		return nil
+	}
+
+	// transaksi wallet pembatalan / penolakan order tetap dicatat seperti sebelumnya (debit order payment)
+	if err := refundOrderToWallet(tx, c.WalletRepository, order, amount, c.WalletConfig.HoldCreditsWhenFrozen, enum_state.WALLET_FLOW_TYPE_DEBIT, enum_state.WALLET_TRANSACTION_TYPE_ORDER_PAYMENT, note, adminNote, refundedBy); err != nil {
+		c.Log.Warnf("failed to refund order to wallet : %+v", err)
+		return err
+	}
+
+	now := time.Now()
+	newOrderRefund.OrderId = order.ID
+	newOrderRefund.RefundedBy = refundedBy
+	newOrderRefund.ReferenceId = fmt.Sprintf("rfd-%s", uuid.NewString())
+	newOrderRefund.Type = enum_state.REFUND_TYPE_PARTIAL
+	if totalRefunded == 0 {
+		newOrderRefund.Type = enum_state.REFUND_TYPE_FULL
+	}
+	newOrderRefund.Destination = enum_state.REFUND_DESTINATION_WALLET
+	newOrderRefund.Amount = amount
+	newOrderRefund.Reason = reason
+	newOrderRefund.Status = enum_state.REFUND_STATUS_SUCCEEDED
+	newOrderRefund.ProcessedAt = &now
+	if err := c.OrderRefundRepository.Create(tx, newOrderRefund); err != nil {
+		c.Log.Warnf("failed to create order refund into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create order refund into database : %+v", err))
+	}
+
+	return nil
+}
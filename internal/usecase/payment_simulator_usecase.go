@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"fmt"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentSimulatorUseCase struct {
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	SimulatorGateway          *payment_gateway.SimulatorGateway
+	XenditWebhookEventUseCase *xenditUseCase.XenditWebhookEventUseCase
+}
+
+func NewPaymentSimulatorUseCase(log *logrus.Logger, validate *validator.Validate,
+	simulatorGateway *payment_gateway.SimulatorGateway, xenditWebhookEventUseCase *xenditUseCase.XenditWebhookEventUseCase) *PaymentSimulatorUseCase {
+	return &PaymentSimulatorUseCase{
+		Log:                       log,
+		Validate:                  validate,
+		SimulatorGateway:          simulatorGateway,
+		XenditWebhookEventUseCase: xenditWebhookEventUseCase,
+	}
+}
+
+// Simulate mengubah status pembayaran simulator lalu mengirim webhook-nya lewat alur webhook yang sama dengan xendit
+func (c *PaymentSimulatorUseCase) Simulate(ctx *fiber.Ctx, request *model.SimulatePaymentRequest) error {
+	request.Status = strings.ToUpper(request.Status)
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	rawBody, err := c.SimulatorGateway.Simulate(request.PaymentId, request.Status)
+	if err != nil {
+		c.Log.Warnf("failed to simulate payment : %+v", err)
+		return err
+	}
+
+	eventId := fmt.Sprintf("sim-%s", uuid.NewString())
+	if err := c.XenditWebhookEventUseCase.Receive(ctx, enum_state.PAYMENT_GATEWAY_SIMULATOR, enum_state.XENDIT_WEBHOOK_TYPE_PAYMENT_REQUEST, eventId, rawBody); err != nil {
+		c.Log.Warnf("failed to process simulator webhook : %+v", err)
+		return err
+	}
+
+	return nil
+}
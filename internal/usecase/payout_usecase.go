@@ -25,11 +25,14 @@ type PayoutUseCase struct {
 	XenditPayoutUseCase *xenditUseCase.XenditPayoutUseCase
 	WalletRepository    *repository.WalletRepository
 	UserRepository      *repository.UserRepository
+	BankAccountUseCase  *BankAccountUseCase
+	AuditLogUseCase     *AuditLogUseCase
 }
 
 func NewPayoutUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
 	payoutRepository *repository.PayoutRepository, xenditPayoutUseCase *xenditUseCase.XenditPayoutUseCase,
-	walletRepository *repository.WalletRepository, userRepository *repository.UserRepository) *PayoutUseCase {
+	walletRepository *repository.WalletRepository, userRepository *repository.UserRepository,
+	bankAccountUseCase *BankAccountUseCase, auditLogUseCase *AuditLogUseCase) *PayoutUseCase {
 	return &PayoutUseCase{
 		DB:                  db,
 		Log:                 log,
@@ -38,6 +41,8 @@ func NewPayoutUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Valid
 		XenditPayoutUseCase: xenditPayoutUseCase,
 		UserRepository:      userRepository,
 		WalletRepository:    walletRepository,
+		BankAccountUseCase:  bankAccountUseCase,
+		AuditLogUseCase:     auditLogUseCase,
 	}
 }
 
@@ -54,8 +59,29 @@ func (c *PayoutUseCase) Add(ctx *fiber.Ctx, request *model.CreatePayoutRequest)
 	var xenditPayoutResponse *model.XenditPayoutResponse
 	var xenditPayoutId sql.NullString
 	var status enum_state.PayoutStatus
+	var bankAccountId *uint64
 	if request.Method == enum_state.PAYOUT_METHOD_ONLINE {
-		result, err := c.XenditPayoutUseCase.AddPayout(ctx, request.XenditPayoutRequest, tx)
+		bankAccount, err := c.BankAccountUseCase.FindPayableAccount(tx, request.BankAccountId, request.UserId, request.Amount)
+		if err != nil {
+			return nil, err
+		}
+		bankAccountId = &bankAccount.ID
+
+		// payload xendit selalu dibuat di server, nominal sama dengan yang sudah dicek di FindPayableAccount
+		// dan tujuan payout selalu diambil dari rekening tersimpan
+		newXenditPayout := new(model.CreateXenditPayout)
+		newXenditPayout.UserId = request.UserId
+		newXenditPayout.Amount = request.Amount
+		newXenditPayout.Currency = request.Currency
+		if newXenditPayout.Currency == "" {
+			newXenditPayout.Currency = "IDR"
+		}
+		newXenditPayout.Description = request.Notes
+		newXenditPayout.ChannelCode = bankAccount.ChannelCode
+		newXenditPayout.AccountNumber = bankAccount.AccountNumber
+		newXenditPayout.AccountHolderName = bankAccount.AccountHolderName
+
+		result, err := c.XenditPayoutUseCase.AddPayout(ctx, newXenditPayout, tx)
 		if err != nil {
 			return nil, err
 		}
@@ -65,15 +91,14 @@ func (c *PayoutUseCase) Add(ctx *fiber.Ctx, request *model.CreatePayoutRequest)
 	} else {
 		status = enum_state.PAYOUT_ACCEPTED
 		newWallet := new(entity.Wallet)
-		count, err := c.WalletRepository.FindAndCountFirstWalletByUserId(tx, newWallet, request.UserId, "active")
-		if err != nil {
+		if err := c.WalletRepository.FindFirstByUserId(tx, newWallet, request.UserId); err != nil {
 			c.Log.Warnf("failed to find wallet by user id : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id : %+v", err))
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find wallet by user id : %+v", err))
 		}
 
-		if count < 1 {
-			c.Log.Warnf("the selected wallet is not found!")
-			return nil, fiber.NewError(fiber.StatusBadRequest, "the selected wallet is not found!")
+		if newWallet.Status == enum_state.INACIVE_WALLET {
+			c.Log.Warnf("your wallet is frozen and can't be used for this transaction!")
+			return nil, fiber.NewError(fiber.StatusForbidden, "your wallet is frozen and can't be used for this transaction!")
 		}
 
 		if request.Amount > newWallet.Balance {
@@ -95,6 +120,7 @@ func (c *PayoutUseCase) Add(ctx *fiber.Ctx, request *model.CreatePayoutRequest)
 	newPayout := new(entity.Payout)
 	newPayout.UserId = request.UserId
 	newPayout.XenditPayoutId = xenditPayoutId
+	newPayout.BankAccountId = bankAccountId
 	newPayout.Amount = request.Amount
 	newPayout.Currency = request.Currency
 	newPayout.Method = request.Method
@@ -106,6 +132,10 @@ func (c *PayoutUseCase) Add(ctx *fiber.Ctx, request *model.CreatePayoutRequest)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create payout request into database : %+v", err))
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_PAYOUT, newPayout.ID, nil, c.AuditLogUseCase.Snapshot(tx, newPayout)); err != nil {
+		return nil, err
+	}
+
 	if xenditPayoutResponse != nil {
 		if newPayout.XenditPayout == nil {
 			newPayout.XenditPayout = &entity.XenditPayout{}
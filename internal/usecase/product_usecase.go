@@ -7,6 +7,7 @@ import (
 	// "os"
 	"seblak-bombom-restful-api/internal/entity"
 	"seblak-bombom-restful-api/internal/helper"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
 	"seblak-bombom-restful-api/internal/repository"
@@ -25,10 +26,12 @@ type ProductUseCase struct {
 	CategoryRepository *repository.CategoryRepository
 	ProductRepository  *repository.ProductRepository
 	ImageRepository    *repository.ImageRepository
+	AuditLogUseCase    *AuditLogUseCase
 }
 
 func NewProductUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
-	categoryRepository *repository.CategoryRepository, productRepository *repository.ProductRepository, imageRepository *repository.ImageRepository) *ProductUseCase {
+	categoryRepository *repository.CategoryRepository, productRepository *repository.ProductRepository, imageRepository *repository.ImageRepository,
+	auditLogUseCase *AuditLogUseCase) *ProductUseCase {
 	return &ProductUseCase{
 		DB:                 db,
 		Log:                log,
@@ -36,6 +39,7 @@ func NewProductUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Vali
 		CategoryRepository: categoryRepository,
 		ProductRepository:  productRepository,
 		ImageRepository:    imageRepository,
+		AuditLogUseCase:    auditLogUseCase,
 	}
 }
 
@@ -105,6 +109,10 @@ func (c *ProductUseCase) Add(ctx *fiber.Ctx, request *model.CreateProductRequest
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create product into database : %+v", err))
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_PRODUCT, newProduct.ID, nil, c.AuditLogUseCase.Snapshot(tx, newProduct)); err != nil {
+		return nil, err
+	}
+
 	if err := c.ProductRepository.FindWithJoins(tx, newProduct, "Category"); err != nil {
 		c.Log.Warnf("failed to find product by id from database : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find product by id from database : %+v", err))
@@ -295,6 +303,8 @@ func (c *ProductUseCase) Update(ctx *fiber.Ctx, request *model.UpdateProductRequ
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find product from database : %+v", err))
 	}
 
+	// nilai sebelum diubah untuk audit log, contoh: perubahan harga
+	beforeProduct := c.AuditLogUseCase.Snapshot(tx, newProduct)
 	newProduct.CategoryId = request.CategoryId
 	newProduct.Name = request.Name
 	newProduct.Description = request.Description
@@ -306,6 +316,10 @@ func (c *ProductUseCase) Update(ctx *fiber.Ctx, request *model.UpdateProductRequ
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed update product by id : %+v", err))
 	}
 
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_UPDATE, enum_state.AUDIT_ENTITY_PRODUCT, newProduct.ID, beforeProduct, c.AuditLogUseCase.Snapshot(tx, newProduct)); err != nil {
+		return nil, err
+	}
+
 	if len(updateCurrentImages.Images) > 0 {
 		var currentIds []uint64
 		for _, currentImage := range updateCurrentImages.Images {
@@ -399,6 +413,12 @@ func (c *ProductUseCase) Delete(ctx context.Context, request *model.DeleteProduc
 		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
+	currentProducts := new([]entity.Product)
+	if err := c.ProductRepository.FindAllByIds(tx, currentProducts, request.IDs); err != nil {
+		c.Log.Warnf("failed to find products by ids : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find products by ids : %+v", err))
+	}
+
 	currentImages := new([]entity.Image)
 	if err := c.ImageRepository.FindImagesByProductIds(tx, currentImages, request.IDs); err != nil {
 		c.Log.Warnf("failed to find product images by product id : %+v", err)
@@ -444,6 +464,13 @@ func (c *ProductUseCase) Delete(ctx context.Context, request *model.DeleteProduc
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed delete in batch product by id : %+v", err))
 	}
 
+	for i := range *currentProducts {
+		currentProduct := &(*currentProducts)[i]
+		if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_DELETE, enum_state.AUDIT_ENTITY_PRODUCT, currentProduct.ID, c.AuditLogUseCase.Snapshot(tx, currentProduct), nil); err != nil {
+			return false, err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
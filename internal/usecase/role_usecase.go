@@ -0,0 +1,494 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/repository"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RoleUseCase struct {
+	DB                       *gorm.DB
+	Log                      *logrus.Logger
+	Validate                 *validator.Validate
+	RoleRepository           *repository.RoleRepository
+	RolePermissionRepository *repository.RolePermissionRepository
+	UserRepository           *repository.UserRepository
+	AuditLogUseCase          *AuditLogUseCase
+}
+
+func NewRoleUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	roleRepository *repository.RoleRepository, rolePermissionRepository *repository.RolePermissionRepository,
+	userRepository *repository.UserRepository, auditLogUseCase *AuditLogUseCase) *RoleUseCase {
+	return &RoleUseCase{
+		DB:                       db,
+		Log:                      log,
+		Validate:                 validate,
+		RoleRepository:           roleRepository,
+		RolePermissionRepository: rolePermissionRepository,
+		UserRepository:           userRepository,
+		AuditLogUseCase:          auditLogUseCase,
+	}
+}
+
+func (c *RoleUseCase) GetPermissions() []enum_state.Permission {
+	return enum_state.GrantablePermissions()
+}
+
+func (c *RoleUseCase) GetAll(ctx context.Context) (*[]model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	newRoles := new([]entity.Role)
+	if err := c.RoleRepository.FindAllWithPreloads(tx, newRoles, "Permissions"); err != nil {
+		c.Log.Warnf("failed to get all roles : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get all roles : %+v", err))
+	}
+
+	return converter.RolesToResponse(newRoles), nil
+}
+
+func (c *RoleUseCase) Get(ctx context.Context, request *model.GetRoleRequest) (*model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newRole, err := c.findRole(tx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.RoleToResponse(newRole), nil
+}
+
+func (c *RoleUseCase) Create(ctx context.Context, request *model.CreateRoleRequest) (*model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if err := c.validateRole(tx, request.Name, request.Permissions, 0); err != nil {
+		return nil, err
+	}
+
+	newRole := new(entity.Role)
+	newRole.Name = request.Name
+	newRole.Description = request.Description
+	if err := c.RoleRepository.Create(tx, newRole); err != nil {
+		c.Log.Warnf("failed to create role into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create role into database : %+v", err))
+	}
+
+	if err := c.savePermissions(tx, newRole.ID, request.Permissions); err != nil {
+		return nil, err
+	}
+
+	newRole, err := c.findRole(tx, newRole.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_ROLE, newRole.ID, nil, c.roleSnapshot(tx, newRole)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.RoleToResponse(newRole), nil
+}
+
+// Update mengganti nama, deskripsi dan seluruh permission role, berlaku langsung untuk semua staff dengan role ini
+func (c *RoleUseCase) Update(ctx context.Context, request *model.UpdateRoleRequest) (*model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	currentRole, err := c.findRole(tx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	beforeRole := c.roleSnapshot(tx, currentRole)
+
+	if err := c.validateRole(tx, request.Name, request.Permissions, request.ID); err != nil {
+		return nil, err
+	}
+
+	updateFields := map[string]any{
+		"name":        request.Name,
+		"description": request.Description,
+	}
+	if err := c.RoleRepository.UpdateCustomColumns(tx, &entity.Role{ID: request.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to update role : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update role : %+v", err))
+	}
+
+	if err := c.RolePermissionRepository.DeleteAllByRoleId(tx, new(entity.RolePermission), request.ID); err != nil {
+		c.Log.Warnf("failed to delete role permissions : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete role permissions : %+v", err))
+	}
+
+	if err := c.savePermissions(tx, request.ID, request.Permissions); err != nil {
+		return nil, err
+	}
+
+	newRole, err := c.findRole(tx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_UPDATE, enum_state.AUDIT_ENTITY_ROLE, newRole.ID, beforeRole, c.roleSnapshot(tx, newRole)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.RoleToResponse(newRole), nil
+}
+
+func (c *RoleUseCase) Delete(ctx context.Context, request *model.DeleteRoleRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newRole, err := c.findRole(tx, request.ID)
+	if err != nil {
+		return false, err
+	}
+
+	totalUsers, err := c.UserRepository.CountUsersByRoleId(tx, new(entity.User), newRole.ID)
+	if err != nil {
+		c.Log.Warnf("failed to count users by role : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count users by role : %+v", err))
+	}
+
+	if totalUsers > 0 {
+		c.Log.Warnf("role is still assigned to %d users!", totalUsers)
+		return false, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("role is still assigned to %d users!", totalUsers))
+	}
+
+	if err := c.RolePermissionRepository.DeleteAllByRoleId(tx, new(entity.RolePermission), newRole.ID); err != nil {
+		c.Log.Warnf("failed to delete role permissions : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete role permissions : %+v", err))
+	}
+
+	if err := c.RoleRepository.Delete(tx, newRole); err != nil {
+		c.Log.Warnf("failed to delete role : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete role : %+v", err))
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_DELETE, enum_state.AUDIT_ENTITY_ROLE, newRole.ID, c.roleSnapshot(tx, newRole), nil); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return true, nil
+}
+
+// AssignToUser menjadikan user sebagai staff dengan role tertentu, akun kurir tetap memakai role kurir
+// agar aturan khusus kurir (hanya pesanan antar) tetap berlaku
+func (c *RoleUseCase) AssignToUser(ctx context.Context, request *model.AssignStaffRoleRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForRoleChange(tx, request.UserId, request.CurrentAdminId, request.CurrentAdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.findRole(tx, request.RoleId); err != nil {
+		return nil, err
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	accountRole := enum_state.STAFF
+	if newUser.Role == enum_state.COURIER {
+		accountRole = enum_state.COURIER
+	}
+
+	updateFields := map[string]any{
+		"role":    accountRole,
+		"role_id": request.RoleId,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to assign staff role : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to assign staff role : %+v", err))
+	}
+
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, newUser.ID); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CHANGE_ROLE, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+func (c *RoleUseCase) RemoveFromUser(ctx context.Context, request *model.RemoveStaffRoleRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForRoleChange(tx, request.UserId, request.CurrentAdminId, request.CurrentAdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if newUser.RoleId == nil {
+		c.Log.Warnf("user does not have a staff role!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "user does not have a staff role!")
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	updateFields := map[string]any{
+		"role":    enum_state.CUSTOMER,
+		"role_id": nil,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to remove staff role : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to remove staff role : %+v", err))
+	}
+
+	newUser.StaffRole = nil
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, newUser.ID); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CHANGE_ROLE, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+// UpdateUserRole mengganti role akun, staff dan kurir wajib memakai role permission, admin dan customer tidak
+func (c *RoleUseCase) UpdateUserRole(ctx context.Context, request *model.UpdateUserRoleRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForRoleChange(tx, request.UserId, request.CurrentAdminId, request.CurrentAdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Role == enum_state.ADMIN && request.CurrentAdminRole != enum_state.ADMIN {
+		c.Log.Warnf("only admin can promote a user to admin!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "only admin can promote a user to admin!")
+	}
+
+	var roleId *uint64
+	if request.Role == enum_state.STAFF || request.Role == enum_state.COURIER {
+		if request.RoleId == nil {
+			c.Log.Warnf("role_id is required for staff and courier!")
+			return nil, fiber.NewError(fiber.StatusBadRequest, "role_id is required for staff and courier!")
+		}
+
+		if _, err := c.findRole(tx, *request.RoleId); err != nil {
+			return nil, err
+		}
+		roleId = request.RoleId
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	updateFields := map[string]any{
+		"role":    request.Role,
+		"role_id": roleId,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to update user role : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update user role : %+v", err))
+	}
+
+	newUser.StaffRole = nil
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, newUser.ID); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CHANGE_ROLE, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+func (c *RoleUseCase) findRole(tx *gorm.DB, roleId uint64) (*entity.Role, error) {
+	newRole := new(entity.Role)
+	newRole.ID = roleId
+	count, err := c.RoleRepository.FindAndCountById(tx, newRole)
+	if err != nil {
+		c.Log.Warnf("failed to find role by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find role by id : %+v", err))
+	}
+
+	if count == 0 {
+		c.Log.Warnf("role not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "role not found!")
+	}
+
+	if err := c.RoleRepository.FindWithPreloads(tx, newRole, "Permissions"); err != nil {
+		c.Log.Warnf("failed to find role permissions : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find role permissions : %+v", err))
+	}
+
+	return newRole, nil
+}
+
+// findUserForRoleChange menolak perubahan role untuk akun admin sendiri agar admin tidak mengunci dirinya sendiri,
+// role akun admin juga hanya boleh diubah oleh admin
+func (c *RoleUseCase) findUserForRoleChange(tx *gorm.DB, userId uint64, currentAdminId uint64, currentAdminRole enum_state.Role) (*entity.User, error) {
+	if userId == currentAdminId {
+		c.Log.Warnf("can't change your own role!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "can't change your own role!")
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = userId
+	count, err := c.UserRepository.FindAndCountById(tx, newUser)
+	if err != nil {
+		c.Log.Warnf("failed to find user by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user by id : %+v", err))
+	}
+
+	if count == 0 {
+		c.Log.Warnf("user not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "user not found!")
+	}
+
+	if newUser.Role == enum_state.ADMIN && currentAdminRole != enum_state.ADMIN {
+		c.Log.Warnf("only admin can manage an admin account!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "only admin can manage an admin account!")
+	}
+
+	return newUser, nil
+}
+
+func (c *RoleUseCase) validateRole(tx *gorm.DB, name string, permissions []enum_state.Permission, exceptId uint64) error {
+	for _, permission := range permissions {
+		if !enum_state.IsValidPermission(permission) {
+			c.Log.Warnf("invalid permission : %s", permission)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid permission : %s", permission))
+		}
+
+		if !enum_state.IsGrantablePermission(permission) {
+			c.Log.Warnf("permission %s can only be held by admin!", permission)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("permission %s can only be held by admin!", permission))
+		}
+	}
+
+	total, err := c.RoleRepository.CountRoleByName(tx, new(entity.Role), name, exceptId)
+	if err != nil {
+		c.Log.Warnf("failed to count roles by name : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count roles by name : %+v", err))
+	}
+
+	if total > 0 {
+		c.Log.Warnf("role name has already exists!")
+		return fiber.NewError(fiber.StatusConflict, "role name has already exists!")
+	}
+
+	return nil
+}
+
+func (c *RoleUseCase) savePermissions(tx *gorm.DB, roleId uint64, permissions []enum_state.Permission) error {
+	// permission yang sama dikirim dua kali cukup disimpan sekali
+	saved := map[enum_state.Permission]bool{}
+	newRolePermissions := []entity.RolePermission{}
+	for _, permission := range permissions {
+		if saved[permission] {
+			continue
+		}
+		saved[permission] = true
+		newRolePermissions = append(newRolePermissions, entity.RolePermission{
+			RoleId:     roleId,
+			Permission: string(permission),
+		})
+	}
+
+	if err := c.RolePermissionRepository.CreateInBatch(tx, &newRolePermissions); err != nil {
+		c.Log.Warnf("failed to create role permissions into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create role permissions into database : %+v", err))
+	}
+
+	return nil
+}
+
+// roleSnapshot menambahkan daftar permission ke snapshot audit log karena permission disimpan di tabel terpisah
+func (c *RoleUseCase) roleSnapshot(tx *gorm.DB, role *entity.Role) map[string]any {
+	snapshot := c.AuditLogUseCase.Snapshot(tx, role)
+	if snapshot == nil {
+		snapshot = map[string]any{}
+	}
+
+	permissions := []string{}
+	for _, rolePermission := range role.Permissions {
+		permissions = append(permissions, rolePermission.Permission)
+	}
+	sort.Strings(permissions)
+	snapshot["permissions"] = permissions
+	return snapshot
+}
@@ -0,0 +1,445 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/totp_helper"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// jumlah kode pemulihan yang dibuat setiap kali 2FA diaktifkan / kode dibuat ulang
+const totalRecoveryCodes = 10
+
+type TwoFactorUseCase struct {
+	DB                         *gorm.DB
+	Log                        *logrus.Logger
+	Validate                   *validator.Validate
+	UserRepository             *repository.UserRepository
+	UserRecoveryCodeRepository *repository.UserRecoveryCodeRepository
+	UserSessionRepository      *repository.UserSessionRepository
+	AuthConfig                 *model.AuthConfig
+}
+
+func NewTwoFactorUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	userRepository *repository.UserRepository, userRecoveryCodeRepository *repository.UserRecoveryCodeRepository,
+	userSessionRepository *repository.UserSessionRepository, authConfig *model.AuthConfig) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		DB:                         db,
+		Log:                        log,
+		Validate:                   validate,
+		UserRepository:             userRepository,
+		UserRecoveryCodeRepository: userRecoveryCodeRepository,
+		UserSessionRepository:      userSessionRepository,
+		AuthConfig:                 authConfig,
+	}
+}
+
+func (c *TwoFactorUseCase) GetStatus(ctx context.Context, currentUser *model.UserResponse) (*model.TwoFactorStatusResponse, error) {
+	newUser := new(entity.User)
+	newUser.ID = currentUser.ID
+	if err := c.UserRepository.FindById(c.DB, newUser); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	response := new(model.TwoFactorStatusResponse)
+	response.Enabled = newUser.TwoFactorEnabledAt != nil
+	response.EnabledAt = newUser.TwoFactorEnabledAt
+	if response.Enabled {
+		remaining, err := c.UserRecoveryCodeRepository.CountUnusedRecoveryCodes(c.DB, new(entity.UserRecoveryCode), newUser.ID)
+		if err != nil {
+			c.Log.Warnf("failed to count recovery codes : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count recovery codes : %+v", err))
+		}
+		response.RemainingRecoveryCodes = remaining
+	}
+
+	return response, nil
+}
+
+// Setup membuat secret TOTP baru, 2FA belum aktif sampai kode pertama diverifikasi lewat Enable
+func (c *TwoFactorUseCase) Setup(ctx context.Context, request *model.SetupTwoFactorRequest, currentUser *model.UserResponse) (*model.TwoFactorSetupResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = currentUser.ID
+	if err := c.UserRepository.FindById(tx, newUser); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if newUser.TwoFactorEnabledAt != nil {
+		c.Log.Warnf("two-factor authentication is already enabled!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is already enabled!")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(newUser.Password), []byte(request.Password)); err != nil {
+		c.Log.Warnf("password is wrong : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("password is wrong : %+v", err))
+	}
+
+	secret, err := totp_helper.GenerateSecret()
+	if err != nil {
+		c.Log.Warnf("failed to generate two-factor secret : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate two-factor secret : %+v", err))
+	}
+
+	updateFields := map[string]any{
+		"two_factor_secret":         secret,
+		"two_factor_last_used_step": 0,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to update two-factor secret : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update two-factor secret : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return &model.TwoFactorSetupResponse{
+		Issuer:          c.AuthConfig.TwoFactorIssuer,
+		AccountName:     newUser.Email,
+		Secret:          secret,
+		ProvisioningURI: totp_helper.ProvisioningURI(c.AuthConfig.TwoFactorIssuer, newUser.Email, secret),
+	}, nil
+}
+
+// Enable mengaktifkan 2FA setelah kode dari aplikasi authenticator cocok dan mengembalikan kode pemulihan
+func (c *TwoFactorUseCase) Enable(ctx context.Context, request *model.TwoFactorCodeRequest, currentUser *model.UserResponse) (*model.TwoFactorRecoveryCodesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = currentUser.ID
+	if err := c.UserRepository.FindById(tx, newUser); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if newUser.TwoFactorEnabledAt != nil {
+		c.Log.Warnf("two-factor authentication is already enabled!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is already enabled!")
+	}
+
+	if newUser.TwoFactorSecret == nil {
+		c.Log.Warnf("two-factor authentication has not been set up!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication has not been set up!")
+	}
+
+	now := time.Now()
+	// kode pemulihan belum ada sebelum 2FA aktif, hanya kode TOTP yang diterima
+	valid, err := c.VerifyCode(tx, newUser, request.Code, now, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		c.Log.Warnf("two-factor code is not valid!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor code is not valid!")
+	}
+
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, map[string]any{"two_factor_enabled_at": now}); err != nil {
+		c.Log.Warnf("failed to enable two-factor authentication : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to enable two-factor authentication : %+v", err))
+	}
+
+	recoveryCodes, err := c.generateRecoveryCodes(tx, newUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// sesi yang mengaktifkan 2FA langsung dianggap sudah terkonfirmasi
+	if err := c.markSessionVerified(tx, currentUser.SessionId, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return &model.TwoFactorRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (c *TwoFactorUseCase) Disable(ctx context.Context, request *model.DisableTwoFactorRequest, currentUser *model.UserResponse) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if c.AuthConfig.TwoFactorRequiredForAdmin && currentUser.Role == enum_state.ADMIN {
+		c.Log.Warnf("two-factor authentication is required for admin accounts!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is required for admin accounts!")
+	}
+
+	if currentUser.Role != enum_state.ADMIN && currentUser.HasMoneyMovingPermission() {
+		c.Log.Warnf("two-factor authentication is required to move money!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is required to move money!")
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = currentUser.ID
+	if err := c.UserRepository.FindById(tx, newUser); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return false, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if newUser.TwoFactorEnabledAt == nil {
+		c.Log.Warnf("two-factor authentication is not enabled!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is not enabled!")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(newUser.Password), []byte(request.Password)); err != nil {
+		c.Log.Warnf("password is wrong : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("password is wrong : %+v", err))
+	}
+
+	valid, err := c.VerifyCode(tx, newUser, request.Code, time.Now(), true)
+	if err != nil {
+		return false, err
+	}
+
+	if !valid {
+		c.Log.Warnf("two-factor code is not valid!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "two-factor code is not valid!")
+	}
+
+	updateFields := map[string]any{
+		"two_factor_secret":         nil,
+		"two_factor_enabled_at":     nil,
+		"two_factor_last_used_step": 0,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to disable two-factor authentication : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to disable two-factor authentication : %+v", err))
+	}
+
+	if err := c.UserRecoveryCodeRepository.DeleteAllByUserId(tx, new(entity.UserRecoveryCode), newUser.ID).Error; err != nil {
+		c.Log.Warnf("failed to delete recovery codes : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete recovery codes : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return true, nil
+}
+
+// RegenerateRecoveryCodes mengganti semua kode pemulihan, kode lama tidak bisa dipakai lagi
+func (c *TwoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, request *model.TwoFactorCodeRequest, currentUser *model.UserResponse) (*model.TwoFactorRecoveryCodesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	newUser, err := c.verifyCurrentUserCode(tx, request, currentUser, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := c.generateRecoveryCodes(tx, newUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return &model.TwoFactorRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// Confirm mencatat konfirmasi 2FA pada sesi yang sedang dipakai untuk membuka endpoint berisiko tinggi
+func (c *TwoFactorUseCase) Confirm(ctx context.Context, request *model.TwoFactorCodeRequest, currentUser *model.UserResponse) (*model.TwoFactorConfirmResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := c.verifyCurrentUserCode(tx, request, currentUser, true); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := c.markSessionVerified(tx, currentUser.SessionId, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return &model.TwoFactorConfirmResponse{
+		VerifiedAt: now,
+		ExpiresAt:  now.Add(c.AuthConfig.TwoFactorFreshDuration),
+	}, nil
+}
+
+// RequireFreshConfirmation memastikan sesi yang sedang dipakai baru saja dikonfirmasi dengan 2FA,
+// user tanpa 2FA tetap diizinkan kecuali 2FA diwajibkan untuk admin atau user staff memegang
+// permission yang memindahkan uang
+func (c *TwoFactorUseCase) RequireFreshConfirmation(ctx context.Context, currentUser *model.UserResponse) error {
+	if !currentUser.TwoFactorEnabled {
+		if c.AuthConfig.TwoFactorRequiredForAdmin && currentUser.Role == enum_state.ADMIN {
+			c.Log.Warnf("two-factor authentication must be enabled for admin accounts!")
+			return fiber.NewError(fiber.StatusForbidden, "two-factor authentication must be enabled for admin accounts!")
+		}
+
+		if currentUser.Role != enum_state.ADMIN && currentUser.HasMoneyMovingPermission() {
+			c.Log.Warnf("two-factor authentication must be enabled to move money!")
+			return fiber.NewError(fiber.StatusForbidden, "two-factor authentication must be enabled to move money!")
+		}
+		return nil
+	}
+
+	newSession := new(entity.UserSession)
+	if err := c.UserSessionRepository.FindActiveSessionByIdAndUserId(c.DB.WithContext(ctx), newSession, currentUser.SessionId, currentUser.ID); err != nil {
+		c.Log.Warnf("failed to find user session : %+v", err)
+		return fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to find user session : %+v", err))
+	}
+
+	if newSession.TwoFactorVerifiedAt == nil || time.Since(*newSession.TwoFactorVerifiedAt) > c.AuthConfig.TwoFactorFreshDuration {
+		c.Log.Warnf("two-factor confirmation is required!")
+		return fiber.NewError(fiber.StatusForbidden, "two-factor confirmation is required, please confirm with your two-factor code!")
+	}
+
+	return nil
+}
+
+// VerifyCode mencocokkan kode TOTP (atau kode pemulihan jika allowRecoveryCode) milik user, kode yang cocok
+// langsung ditandai terpakai di tx sehingga tidak bisa dipakai dua kali
+func (c *TwoFactorUseCase) VerifyCode(tx *gorm.DB, user *entity.User, code string, now time.Time, allowRecoveryCode bool) (bool, error) {
+	if user.TwoFactorSecret == nil {
+		return false, nil
+	}
+
+	if step, ok := totp_helper.Validate(*user.TwoFactorSecret, code, now); ok {
+		affected, err := c.UserRepository.UseTwoFactorStep(tx, new(entity.User), user.ID, step)
+		if err != nil {
+			c.Log.Warnf("failed to update two-factor last used step : %+v", err)
+			return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update two-factor last used step : %+v", err))
+		}
+
+		if affected < 1 {
+			c.Log.Warnf("two-factor code has already been used")
+		}
+
+		return affected > 0, nil
+	}
+
+	if !allowRecoveryCode {
+		return false, nil
+	}
+
+	affected, err := c.UserRecoveryCodeRepository.UseRecoveryCode(tx, new(entity.UserRecoveryCode), user.ID, totp_helper.HashRecoveryCode(code), now)
+	if err != nil {
+		c.Log.Warnf("failed to update recovery code : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update recovery code : %+v", err))
+	}
+
+	return affected > 0, nil
+}
+
+func (c *TwoFactorUseCase) verifyCurrentUserCode(tx *gorm.DB, request *model.TwoFactorCodeRequest, currentUser *model.UserResponse, allowRecoveryCode bool) (*entity.User, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = currentUser.ID
+	if err := c.UserRepository.FindById(tx, newUser); err != nil {
+		c.Log.Warnf("failed to find user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find user : %+v", err))
+	}
+
+	if newUser.TwoFactorEnabledAt == nil {
+		c.Log.Warnf("two-factor authentication is not enabled!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor authentication is not enabled!")
+	}
+
+	valid, err := c.VerifyCode(tx, newUser, request.Code, time.Now(), allowRecoveryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		c.Log.Warnf("two-factor code is not valid!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "two-factor code is not valid!")
+	}
+
+	return newUser, nil
+}
+
+func (c *TwoFactorUseCase) generateRecoveryCodes(tx *gorm.DB, userId uint64) ([]string, error) {
+	if err := c.UserRecoveryCodeRepository.DeleteAllByUserId(tx, new(entity.UserRecoveryCode), userId).Error; err != nil {
+		c.Log.Warnf("failed to delete recovery codes : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete recovery codes : %+v", err))
+	}
+
+	recoveryCodes := make([]string, totalRecoveryCodes)
+	newRecoveryCodes := make([]entity.UserRecoveryCode, totalRecoveryCodes)
+	for i := range recoveryCodes {
+		code, err := totp_helper.GenerateRecoveryCode()
+		if err != nil {
+			c.Log.Warnf("failed to generate recovery code : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate recovery code : %+v", err))
+		}
+
+		recoveryCodes[i] = code
+		newRecoveryCodes[i] = entity.UserRecoveryCode{
+			UserId:   userId,
+			CodeHash: totp_helper.HashRecoveryCode(code),
+		}
+	}
+
+	if err := c.UserRecoveryCodeRepository.CreateInBatch(tx, &newRecoveryCodes); err != nil {
+		c.Log.Warnf("failed to create recovery codes into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create recovery codes into database : %+v", err))
+	}
+
+	return recoveryCodes, nil
+}
+
+func (c *TwoFactorUseCase) markSessionVerified(tx *gorm.DB, sessionId string, verifiedAt time.Time) error {
+	if sessionId == "" {
+		return nil
+	}
+
+	if err := c.UserSessionRepository.UpdateCustomColumns(tx, &entity.UserSession{ID: sessionId}, map[string]any{"two_factor_verified_at": verifiedAt}); err != nil {
+		c.Log.Warnf("failed to update user session : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update user session : %+v", err))
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -10,8 +11,10 @@ import (
 	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
 	"seblak-bombom-restful-api/internal/helper/mailer"
+	"seblak-bombom-restful-api/internal/helper/token_helper"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/oidc_provider"
 	"seblak-bombom-restful-api/internal/repository"
 	"strconv"
 	"strings"
@@ -39,6 +42,14 @@ type UserUseCase struct {
 	Email                  *mailer.EmailWorker
 	ApplicationRepository  *repository.ApplicationRepository
 	PasswordReset          *repository.PasswordResetRepository
+	AuthConfig             *model.AuthConfig
+	UserSessionRepository  *repository.UserSessionRepository
+	LoginAttemptRepository *repository.LoginAttemptRepository
+	TwoFactorUseCase       *TwoFactorUseCase
+	UserIdentityRepository *repository.UserIdentityRepository
+	OIDCProviders          *oidc_provider.Registry
+	OrderRepository        *repository.OrderRepository
+	AuditLogUseCase        *AuditLogUseCase
 }
 
 func NewUserUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
@@ -46,7 +57,11 @@ func NewUserUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validat
 	addressRepository *repository.AddressRepository, walletRepository *repository.WalletRepository,
 	cartRepository *repository.CartRepository, notificationRepository *repository.NotificationRepository,
 	email *mailer.EmailWorker, applicationRepository *repository.ApplicationRepository,
-	passwordReset *repository.PasswordResetRepository) *UserUseCase {
+	passwordReset *repository.PasswordResetRepository, authConfig *model.AuthConfig,
+	userSessionRepository *repository.UserSessionRepository, loginAttemptRepository *repository.LoginAttemptRepository,
+	twoFactorUseCase *TwoFactorUseCase, userIdentityRepository *repository.UserIdentityRepository,
+	oidcProviders *oidc_provider.Registry, orderRepository *repository.OrderRepository,
+	auditLogUseCase *AuditLogUseCase) *UserUseCase {
 	return &UserUseCase{
 		DB:                     db,
 		Log:                    log,
@@ -60,6 +75,14 @@ func NewUserUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validat
 		Email:                  email,
 		ApplicationRepository:  applicationRepository,
 		PasswordReset:          passwordReset,
+		AuthConfig:             authConfig,
+		UserSessionRepository:  userSessionRepository,
+		LoginAttemptRepository: loginAttemptRepository,
+		TwoFactorUseCase:       twoFactorUseCase,
+		UserIdentityRepository: userIdentityRepository,
+		OIDCProviders:          oidcProviders,
+		OrderRepository:        orderRepository,
+		AuditLogUseCase:        auditLogUseCase,
 	}
 }
 
@@ -128,39 +151,53 @@ func (c *UserUseCase) Create(ctx *fiber.Ctx, request *model.RegisterUserRequest)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create a new cart into database : %+v", err))
 	}
 
+	if err := c.sendVerificationEmail(ctx, tx, newUser, request.Lang, request.TimeZone); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+// sendVerificationEmail mengirim link verifikasi email, admin memakai template email verifikasi admin
+func (c *UserUseCase) sendVerificationEmail(ctx *fiber.Ctx, tx *gorm.DB, user *entity.User, lang enum_state.Languange, timeZone time.Location) error {
 	newApp := new(entity.Application)
 	if err := c.ApplicationRepository.FindFirst(tx, newApp); err != nil {
 		c.Log.Warnf("failed to find application from database : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find application from database : %+v", err))
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find application from database : %+v", err))
 	}
 
 	logoImagePath := fmt.Sprintf("uploads/images/application/%s", newApp.LogoFilename)
 	logoImageBase64, err := helper_others.ImageToBase64(logoImagePath)
 	if err != nil {
 		c.Log.Warnf("failed to convert logo to base64 : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert logo to base64 : %+v", err))
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert logo to base64 : %+v", err))
 	}
 
-	if request.Role == enum_state.CUSTOMER {
+	if user.Role != enum_state.ADMIN {
 		// setelah semuanya berhasil maka kirim notifikasi email
 		newMail := new(model.Mail)
-		newMail.To = []string{newUser.Email}
+		newMail.To = []string{user.Email}
 		newMail.Subject = "Email Verification"
-		if request.Lang == enum_state.INDONESIA {
+		if lang == enum_state.INDONESIA {
 			newMail.Subject = "Verifikasi Email"
 		}
 		baseTemplatePath := "internal/templates/base_template_email1.html"
-		childPath := fmt.Sprintf("internal/templates/%s/email/email_verification.html", request.Lang)
+		childPath := fmt.Sprintf("internal/templates/%s/email/email_verification.html", lang)
 		tmpl, err := template.ParseFiles(baseTemplatePath, childPath)
 		if err != nil {
 			c.Log.Warnf("failed to parse template file html : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
 		}
 
-		baseURL := fmt.Sprintf("%s://%s/api/users/verify-email/%s", ctx.Protocol(), ctx.Hostname(), newUser.VerificationToken)
+		baseURL := fmt.Sprintf("%s://%s/api/users/verify-email/%s", ctx.Protocol(), ctx.Hostname(), user.VerificationToken)
 		params := url.Values{}
-		params.Set("lang", string(request.Lang))
-		params.Set("timezone", request.TimeZone.String())
+		params.Set("lang", string(lang))
+		params.Set("timezone", timeZone.String())
 
 		verifyURL := baseURL
 		if encoded := params.Encode(); encoded != "" {
@@ -169,16 +206,16 @@ func (c *UserUseCase) Create(ctx *fiber.Ctx, request *model.RegisterUserRequest)
 
 		bodyBuilder := new(strings.Builder)
 		err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]string{
-			"Name":            newUser.Name.FirstName,
+			"Name":            user.Name.FirstName,
 			"Year":            time.Now().Format("2006"),
 			"CompanyName":     newApp.AppName,
 			"LogoImage":       logoImageBase64,
 			"VerificationURL": verifyURL,
-			"TokenExpiry":     newUser.TokenExpiry.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
+			"TokenExpiry":     user.TokenExpiry.In(&timeZone).Format("02 Jan 2006 15:04 MST"),
 		})
 		if err != nil {
 			c.Log.Warnf("failed to execute template file html : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
 		}
 
 		newMail.Template = *bodyBuilder
@@ -186,28 +223,28 @@ func (c *UserUseCase) Create(ctx *fiber.Ctx, request *model.RegisterUserRequest)
 		select {
 		case c.Email.MailQueue <- *newMail:
 		default:
-			c.Log.Warnf("email queue full, failed to send to %s", newUser.Email)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", newUser.Email))
+			c.Log.Warnf("email queue full, failed to send to %s", user.Email)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", user.Email))
 		}
 	} else {
 		newMail := new(model.Mail)
-		newMail.To = []string{newUser.Email}
+		newMail.To = []string{user.Email}
 		newMail.Subject = "Admin Email Verification"
-		if request.Lang == enum_state.INDONESIA {
+		if lang == enum_state.INDONESIA {
 			newMail.Subject = "Verifikasi Email Admin"
 		}
 		baseTemplatePath := "internal/templates/base_template_email1.html"
-		childPath := fmt.Sprintf("internal/templates/%s/email/email_verification_admin.html", request.Lang)
+		childPath := fmt.Sprintf("internal/templates/%s/email/email_verification_admin.html", lang)
 		tmpl, err := template.ParseFiles(baseTemplatePath, childPath)
 		if err != nil {
 			c.Log.Warnf("failed to parse template file html : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
 		}
 
-		baseURL := fmt.Sprintf("%s://%s/api/users/verify-email/%s", ctx.Protocol(), ctx.Hostname(), newUser.VerificationToken)
+		baseURL := fmt.Sprintf("%s://%s/api/users/verify-email/%s", ctx.Protocol(), ctx.Hostname(), user.VerificationToken)
 		params := url.Values{}
-		params.Set("lang", string(request.Lang))
-		params.Set("timezone", request.TimeZone.String())
+		params.Set("lang", string(lang))
+		params.Set("timezone", timeZone.String())
 
 		verifyURL := baseURL
 		if encoded := params.Encode(); encoded != "" {
@@ -216,16 +253,16 @@ func (c *UserUseCase) Create(ctx *fiber.Ctx, request *model.RegisterUserRequest)
 
 		bodyBuilder := new(strings.Builder)
 		err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]string{
-			"AdminName":       newUser.Name.FirstName,
+			"AdminName":       user.Name.FirstName,
 			"LogoImage":       logoImageBase64,
 			"CompanyName":     newApp.AppName,
 			"VerificationURL": verifyURL,
-			"TokenExpiry":     newUser.TokenExpiry.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
+			"TokenExpiry":     user.TokenExpiry.In(&timeZone).Format("02 Jan 2006 15:04 MST"),
 		})
 
 		if err != nil {
 			c.Log.Warnf("failed to execute template file html : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
 		}
 
 		newMail.Template = *bodyBuilder
@@ -233,17 +270,12 @@ func (c *UserUseCase) Create(ctx *fiber.Ctx, request *model.RegisterUserRequest)
 		select {
 		case c.Email.MailQueue <- *newMail:
 		default:
-			c.Log.Warnf("email queue full, failed to send to %s", newUser.Email)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", newUser.Email))
+			c.Log.Warnf("email queue full, failed to send to %s", user.Email)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", user.Email))
 		}
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("failed to commit transaction : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
-	}
-
-	return converter.UserToResponse(newUser), nil
+	return nil
 }
 
 func (c *UserUseCase) VerifyEmailRegistration(ctx *fiber.Ctx, request *model.VerifyEmailRegisterRequest) (*model.UserResponse, error) {
@@ -401,75 +433,904 @@ func (c *UserUseCase) ValidateVerifyTokenIsValid(ctx *fiber.Ctx, verifyToken str
 	return nil
 }
 
-func (c *UserUseCase) Authenticate(ctx context.Context, request *model.LoginUserRequest) (*model.UserTokenResponse, *model.UserResponse, error) {
+func (c *UserUseCase) Authenticate(ctx context.Context, request *model.LoginUserRequest) (*model.UserTokenResponse, *model.UserResponse, *model.TwoFactorChallengeResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.Warnf("invalid request body : %+v", err)
-		return nil, nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+		return nil, nil, nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	now := time.Now()
+	loginAttemptKeys := c.loginAttemptKeys(request.Email, request.IPAddress)
+	if err := c.checkLoginThrottle(loginAttemptKeys, now); err != nil {
+		return nil, nil, nil, err
 	}
 
 	newUser := new(entity.User)
 	if err := c.UserRepository.FindByEmail(c.DB, newUser, request.Email); err != nil {
 		c.Log.Warnf("user not found : %+v", err)
-		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("user not found : %+v", err))
+		c.recordLoginFailure(ctx, loginAttemptKeys, nil, request)
+		return nil, nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("user not found : %+v", err))
 	}
 
 	if !newUser.EmailVerified {
 		c.Log.Warnf("your account has not verified email")
-		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, "your account has not verified email!")
+		return nil, nil, nil, fiber.NewError(fiber.StatusInternalServerError, "your account has not verified email!")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(newUser.Password), []byte(request.Password)); err != nil {
 		c.Log.Warnf("password is wrong : %+v", err)
-		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("password is wrong : %+v", err))
+		c.recordLoginFailure(ctx, loginAttemptKeys, newUser, request)
+		return nil, nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("password is wrong : %+v", err))
+	}
+
+	if err := c.checkSuspended(newUser); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// password benar, akun dengan 2FA harus menyelesaikan langkah kedua sebelum sesi dibuat
+	if newUser.TwoFactorEnabledAt != nil {
+		challenge, err := c.issueTwoFactorChallenge(newUser, request.Remember, now)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return nil, nil, challenge, nil
+	}
+
+	// login berhasil mengosongkan penghitung akun, penghitung IP tetap berjalan agar tidak bisa direset
+	// dengan login ke akun milik penyerang sendiri
+	if err := c.LoginAttemptRepository.DeleteLoginAttempt(tx, new(entity.LoginAttempt), string(enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT), loginAttemptKeys[0].Identifier); err != nil {
+		c.Log.Warnf("failed to reset login attempt : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to reset login attempt : %+v", err))
+	}
+
+	response, err := c.startSession(tx, newUser, request.Remember, request.UserAgent, request.IPAddress, nil, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return response, converter.UserToResponse(newUser), nil, nil
+}
+
+// AuthenticateTwoFactor adalah langkah kedua login untuk akun dengan 2FA, memakai kode TOTP atau kode pemulihan
+func (c *UserUseCase) AuthenticateTwoFactor(ctx context.Context, request *model.LoginTwoFactorRequest) (*model.UserTokenResponse, *model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	claims, err := token_helper.ParseTwoFactorChallenge(c.AuthConfig.AccessTokenSecret, request.ChallengeToken)
+	if err != nil {
+		c.Log.Warnf("invalid two-factor challenge : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "two-factor challenge is not valid or has expired, please login again!")
+	}
+
+	newUser := new(entity.User)
+	if err := c.UserRepository.FindCurrentUserById(c.DB, newUser, claims.Subject); err != nil {
+		c.Log.Warnf("user not found : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("user not found : %+v", err))
+	}
+
+	if newUser.TwoFactorEnabledAt == nil {
+		c.Log.Warnf("two-factor authentication is not enabled!")
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "two-factor challenge is not valid or has expired, please login again!")
+	}
+
+	// kode 2FA yang salah dihitung sebagai login gagal sehingga ikut backoff dan penguncian akun
+	now := time.Now()
+	loginAttemptKeys := c.loginAttemptKeys(newUser.Email, request.IPAddress)
+	if err := c.checkLoginThrottle(loginAttemptKeys, now); err != nil {
+		return nil, nil, err
+	}
+
+	valid, err := c.TwoFactorUseCase.VerifyCode(tx, newUser, request.Code, now, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !valid {
+		c.Log.Warnf("two-factor code is not valid!")
+		c.recordLoginFailure(ctx, loginAttemptKeys, newUser, &model.LoginUserRequest{
+			Email:     newUser.Email,
+			IPAddress: request.IPAddress,
+			Lang:      request.Lang,
+			TimeZone:  request.TimeZone,
+		})
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "two-factor code is not valid!")
+	}
+
+	if err := c.LoginAttemptRepository.DeleteLoginAttempt(tx, new(entity.LoginAttempt), string(enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT), loginAttemptKeys[0].Identifier); err != nil {
+		c.Log.Warnf("failed to reset login attempt : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to reset login attempt : %+v", err))
+	}
+
+	response, err := c.startSession(tx, newUser, claims.Remember, request.UserAgent, request.IPAddress, &now, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return response, converter.UserToResponse(newUser), nil
+}
+
+func (c *UserUseCase) issueTwoFactorChallenge(user *entity.User, remember bool, now time.Time) (*model.TwoFactorChallengeResponse, error) {
+	expiresAt := now.Add(c.AuthConfig.TwoFactorChallengeTTL)
+	challengeToken, err := token_helper.GenerateTwoFactorChallenge(c.AuthConfig.AccessTokenSecret, &token_helper.TwoFactorChallengeClaims{
+		Subject:   user.ID,
+		Remember:  remember,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		c.Log.Warnf("failed to generate two-factor challenge : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate two-factor challenge : %+v", err))
+	}
+
+	return &model.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+func (c *UserUseCase) GetOIDCProviders() []model.OIDCProviderResponse {
+	providers := []model.OIDCProviderResponse{}
+	for _, name := range c.OIDCProviders.Names() {
+		providers = append(providers, model.OIDCProviderResponse{Name: name})
+	}
+
+	return providers
+}
+
+// OIDCAuthorize membuat URL halaman login provider OpenID Connect beserta state yang ditandatangani
+func (c *UserUseCase) OIDCAuthorize(ctx context.Context, request *model.OIDCAuthorizeRequest) (*model.OIDCAuthorizationResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	provider, err := c.OIDCProviders.Get(request.Provider)
+	if err != nil {
+		c.Log.Warnf("%+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	nonce, err := token_helper.GenerateOIDCNonce()
+	if err != nil {
+		c.Log.Warnf("failed to generate oidc nonce : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate oidc nonce : %+v", err))
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(c.AuthConfig.OIDCStateTTL)
+	state, err := token_helper.GenerateOIDCState(c.AuthConfig.AccessTokenSecret, &token_helper.OIDCStateClaims{
+		Provider:  provider.Name(),
+		Nonce:     nonce,
+		Remember:  request.Remember,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		c.Log.Warnf("failed to generate oidc state : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate oidc state : %+v", err))
+	}
+
+	codeChallenge := token_helper.OIDCCodeChallenge(token_helper.OIDCCodeVerifier(c.AuthConfig.AccessTokenSecret, nonce))
+	authorizationURL, err := provider.AuthorizationURL(ctx, state, nonce, codeChallenge)
+	if err != nil {
+		c.Log.Warnf("failed to create oidc authorization url : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("failed to create oidc authorization url : %+v", err))
+	}
+
+	return &model.OIDCAuthorizationResponse{
+		Provider:         provider.Name(),
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// OIDCLogin menyelesaikan login OpenID Connect, user dicari dari akun OIDC yang sudah terhubung, lalu dari email
+// yang sudah diverifikasi provider, jika belum ada dibuat user customer baru tanpa perlu verifikasi email
+func (c *UserUseCase) OIDCLogin(ctx context.Context, request *model.OIDCCallbackRequest) (*model.UserTokenResponse, *model.UserResponse, *model.TwoFactorChallengeResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	provider, err := c.OIDCProviders.Get(request.Provider)
+	if err != nil {
+		c.Log.Warnf("%+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	claims, err := token_helper.ParseOIDCState(c.AuthConfig.AccessTokenSecret, request.State)
+	if err != nil || claims.Provider != provider.Name() {
+		c.Log.Warnf("invalid oidc state : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusBadRequest, "oidc state is not valid or has expired, please login again!")
+	}
+
+	codeVerifier := token_helper.OIDCCodeVerifier(c.AuthConfig.AccessTokenSecret, claims.Nonce)
+	identity, err := provider.Exchange(ctx, request.Code, codeVerifier, claims.Nonce)
+	if err != nil {
+		c.Log.Warnf("failed to verify oidc login : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to verify oidc login : %+v", err))
+	}
+
+	now := time.Now()
+	newUser, err := c.findOrCreateOIDCUser(tx, identity, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if newUser.TwoFactorEnabledAt != nil {
+		// akun OIDC sudah tersimpan, langkah kedua memakai endpoint /api/users/login/two-factor
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("failed to commit transaction : %+v", err)
+			return nil, nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+		}
+
+		challenge, err := c.issueTwoFactorChallenge(newUser, claims.Remember, now)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return nil, nil, challenge, nil
+	}
+
+	response, err := c.startSession(tx, newUser, claims.Remember, request.UserAgent, request.IPAddress, nil, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return response, converter.UserToResponse(newUser), nil, nil
+}
+
+func (c *UserUseCase) findOrCreateOIDCUser(tx *gorm.DB, identity *model.OIDCIdentity, now time.Time) (*entity.User, error) {
+	newIdentity := new(entity.UserIdentity)
+	count, err := c.UserIdentityRepository.FindUserIdentity(tx, newIdentity, identity.Provider, identity.Subject)
+	if err != nil {
+		c.Log.Warnf("failed to find user identity : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user identity : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	if count > 0 {
+		if err := c.UserIdentityRepository.UpdateCustomColumns(tx, &entity.UserIdentity{ID: newIdentity.ID}, map[string]any{"last_login_at": now}); err != nil {
+			c.Log.Warnf("failed to update user identity : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update user identity : %+v", err))
+		}
+
+		if err := c.UserRepository.FindCurrentUserById(tx, newUser, newIdentity.UserId); err != nil {
+			c.Log.Warnf("user not found : %+v", err)
+			return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("user not found : %+v", err))
+		}
+
+		return newUser, nil
+	}
+
+	// akun OIDC baru hanya bisa dihubungkan lewat email yang sudah diverifikasi provider
+	if identity.Email == "" || !identity.EmailVerified {
+		c.Log.Warnf("email from oidc provider is not verified!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "email from oidc provider is not verified!")
+	}
+
+	total, err := c.UserRepository.UserCountByEmail(tx, newUser, identity.Email)
+	if err != nil {
+		c.Log.Warnf("failed to count users from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count users from database : %+v", err))
+	}
+
+	if total > 0 {
+		if err := c.UserRepository.FindByEmail(tx, newUser, identity.Email); err != nil {
+			c.Log.Warnf("user not found : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("user not found : %+v", err))
+		}
+
+		// registrasi yang belum diverifikasi bisa dibuat orang lain dengan email ini,
+		// password nya diganti agar tidak bisa dipakai login ke akun yang sekarang terhubung
+		if !newUser.EmailVerified {
+			password, err := randomPasswordHash()
+			if err != nil {
+				c.Log.Warnf("failed to generate bcrypt on password hash : %+v", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate bcrypt on password hash : %+v", err))
+			}
+
+			updateFields := map[string]any{
+				"email_verified":     true,
+				"verification_token": "",
+				"password":           password,
+			}
+			if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+				c.Log.Warnf("failed to verify user email : %+v", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to verify user email : %+v", err))
+			}
+		}
+	} else {
+		password, err := randomPasswordHash()
+		if err != nil {
+			c.Log.Warnf("failed to generate bcrypt on password hash : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate bcrypt on password hash : %+v", err))
+		}
+
+		newUser.Name.FirstName = identity.FirstName
+		if newUser.Name.FirstName == "" {
+			newUser.Name.FirstName = strings.Split(identity.Email, "@")[0]
+		}
+		newUser.Name.LastName = identity.LastName
+		newUser.Email = identity.Email
+		// user OIDC belum punya password, bisa dibuat lewat lupa password
+		newUser.Password = password
+		newUser.Role = enum_state.CUSTOMER
+		newUser.TokenExpiry = now
+		newUser.EmailVerified = true
+		if err := c.UserRepository.Create(tx, newUser); err != nil {
+			c.Log.Warnf("failed to create user into database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create user into database : %+v", err))
+		}
+
+		newWallet := &entity.Wallet{}
+		newWallet.UserId = newUser.ID
+		newWallet.Balance = 0
+		newWallet.Status = enum_state.ACTIVE_WALLET
+		if err := c.WalletRepository.Create(tx, newWallet); err != nil {
+			c.Log.Warnf("failed to create a new wallet into database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create a new wallet into database : %+v", err))
+		}
+
+		newCart := &entity.Cart{}
+		newCart.UserID = newUser.ID
+		if err := c.CartRepository.Create(tx, newCart); err != nil {
+			c.Log.Warnf("failed to create a new cart into database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create a new cart into database : %+v", err))
+		}
+	}
+
+	newIdentity.UserId = newUser.ID
+	newIdentity.Provider = identity.Provider
+	newIdentity.Subject = identity.Subject
+	newIdentity.Email = identity.Email
+	newIdentity.LastLoginAt = &now
+	if err := c.UserIdentityRepository.Create(tx, newIdentity); err != nil {
+		c.Log.Warnf("failed to create user identity into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create user identity into database : %+v", err))
+	}
+
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, newUser.ID); err != nil {
+		c.Log.Warnf("user not found : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("user not found : %+v", err))
+	}
+
+	return newUser, nil
+}
+
+func randomPasswordHash() (string, error) {
+	password, err := bcrypt.GenerateFromPassword([]byte(uuid.NewString()), bcrypt.DefaultCost)
+	return string(password), err
+}
+
+// startSession membuat sesi login (family refresh token baru) beserta pasangan token nya,
+// masa berlaku family tidak diperpanjang saat rotasi
+func (c *UserUseCase) startSession(tx *gorm.DB, user *entity.User, remember bool, userAgent string, ipAddress string, twoFactorVerifiedAt *time.Time, now time.Time) (*model.UserTokenResponse, error) {
+	// semua jalur login (password, 2FA, OIDC) membuat sesi lewat sini
+	if err := c.checkSuspended(user); err != nil {
+		return nil, err
+	}
+
+	refreshTokenTTL := c.AuthConfig.RefreshTokenTTL
+	if remember {
+		refreshTokenTTL = refreshTokenTTL * 3
+	}
+
+	newSession := new(entity.UserSession)
+	newSession.ID = uuid.NewString()
+	newSession.UserId = user.ID
+	newSession.UserAgent = truncateUserAgent(userAgent)
+	newSession.IPAddress = ipAddress
+	newSession.LastSeenAt = now
+	newSession.TwoFactorVerifiedAt = twoFactorVerifiedAt
+	newSession.ExpiresAt = now.Add(refreshTokenTTL)
+	if err := c.UserSessionRepository.Create(tx, newSession); err != nil {
+		c.Log.Warnf("failed to create user session into database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create user session into database : %+v", err))
+	}
+
+	return c.issueTokenPair(tx, user, newSession.ID, newSession.ExpiresAt)
+}
+
+func (c *UserUseCase) checkSuspended(user *entity.User) error {
+	if user.SuspendedAt != nil {
+		c.Log.Warnf("account %d has been suspended!", user.ID)
+		return fiber.NewError(fiber.StatusForbidden, "your account has been suspended!")
+	}
+
+	return nil
+}
+
+type loginAttemptKey struct {
+	Scope      enum_state.LoginAttemptScope
+	Identifier string
+	MaxFailed  int
+}
+
+// loginAttemptKeys mengembalikan penghitung login gagal yang berlaku, akun selalu di index pertama
+func (c *UserUseCase) loginAttemptKeys(email string, ipAddress string) []loginAttemptKey {
+	keys := []loginAttemptKey{
+		{
+			Scope:      enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT,
+			Identifier: strings.ToLower(strings.TrimSpace(email)),
+			MaxFailed:  c.AuthConfig.LoginMaxFailedAttempts,
+		},
+	}
+
+	if ipAddress != "" {
+		keys = append(keys, loginAttemptKey{
+			Scope:      enum_state.LOGIN_ATTEMPT_SCOPE_IP,
+			Identifier: ipAddress,
+			MaxFailed:  c.AuthConfig.LoginIPMaxFailedAttempts,
+		})
+	}
+
+	return keys
+}
+
+// loginBackoff menghitung penundaan setelah login gagal, separuh pertama dari batas percobaan tidak ditunda,
+// setelah itu penundaan berlipat dua (1 detik, 2 detik, 4 detik, ...) sampai batas tercapai dan dikunci
+func (c *UserUseCase) loginBackoff(failedCount int, maxFailed int) time.Duration {
+	lockout := c.AuthConfig.LoginLockoutDuration
+	if failedCount >= maxFailed {
+		return lockout
+	}
+
+	exponent := failedCount - maxFailed/2
+	if exponent <= 0 {
+		return 0
+	}
+
+	if exponent > 30 {
+		return lockout
+	}
+
+	delay := time.Second << (exponent - 1)
+	if delay > lockout {
+		return lockout
+	}
+
+	return delay
+}
+
+// checkLoginThrottle menolak percobaan login selama akun / alamat IP masih dalam masa backoff atau terkunci,
+// percobaan yang ditolak di sini tidak menambah penghitung
+func (c *UserUseCase) checkLoginThrottle(keys []loginAttemptKey, now time.Time) error {
+	for _, key := range keys {
+		attempt := new(entity.LoginAttempt)
+		count, err := c.LoginAttemptRepository.FindLoginAttempt(c.DB, attempt, string(key.Scope), key.Identifier)
+		if err != nil {
+			c.Log.Warnf("failed to find login attempt from database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find login attempt from database : %+v", err))
+		}
+
+		if count < 1 || attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) {
+			continue
+		}
+
+		retryAfter := int(attempt.LockedUntil.Sub(now).Seconds()) + 1
+		if key.Scope == enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT && attempt.FailedCount >= key.MaxFailed {
+			c.Log.Warnf("account is temporarily locked until %s", attempt.LockedUntil.Format(time.RFC3339))
+			return fiber.NewError(fiber.StatusTooManyRequests, fmt.Sprintf("account is temporarily locked due to too many failed login attempts, try again in %d seconds", retryAfter))
+		}
+
+		c.Log.Warnf("login is throttled for %s until %s", key.Scope, attempt.LockedUntil.Format(time.RFC3339))
+		return fiber.NewError(fiber.StatusTooManyRequests, fmt.Sprintf("too many failed login attempts, try again in %d seconds", retryAfter))
+	}
+
+	return nil
+}
+
+// recordLoginFailure menambah penghitung login gagal di transaksi tersendiri agar tetap tersimpan walaupun login
+// mengembalikan error, pemilik akun dikirimi email saat akun nya mulai dikunci
+func (c *UserUseCase) recordLoginFailure(ctx context.Context, keys []loginAttemptKey, user *entity.User, request *model.LoginUserRequest) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	var accountLockedUntil *time.Time
+	for _, key := range keys {
+		// baris penghitung dibuat dulu jika belum ada lalu dikunci, login gagal yang bersamaan harus antre
+		// agar penambahan penghitungnya tidak saling menimpa
+		newAttempt := &entity.LoginAttempt{
+			Scope:      key.Scope,
+			Identifier: key.Identifier,
+		}
+		if err := c.LoginAttemptRepository.CreateIfNotExists(tx, newAttempt); err != nil {
+			c.Log.Warnf("failed to create login attempt into database : %+v", err)
+			return
+		}
+
+		attempt := new(entity.LoginAttempt)
+		if err := c.LoginAttemptRepository.FindLoginAttemptForUpdate(tx, attempt, string(key.Scope), key.Identifier); err != nil {
+			c.Log.Warnf("failed to find login attempt from database : %+v", err)
+			return
+		}
+
+		// penghitung dimulai dari awal jika login gagal terakhir sudah lebih lama dari masa penguncian
+		if attempt.LastFailedAt != nil && now.Sub(*attempt.LastFailedAt) > c.AuthConfig.LoginLockoutDuration {
+			attempt.FailedCount = 0
+		}
+
+		attempt.Scope = key.Scope
+		attempt.Identifier = key.Identifier
+		attempt.FailedCount++
+		attempt.LastFailedAt = &now
+		attempt.LockedUntil = nil
+		if delay := c.loginBackoff(attempt.FailedCount, key.MaxFailed); delay > 0 {
+			lockedUntil := now.Add(delay)
+			attempt.LockedUntil = &lockedUntil
+		}
+
+		if err := c.LoginAttemptRepository.Update(tx, attempt); err != nil {
+			c.Log.Warnf("failed to save login attempt into database : %+v", err)
+			return
+		}
+
+		if key.Scope == enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT && attempt.FailedCount == key.MaxFailed {
+			accountLockedUntil = attempt.LockedUntil
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return
+	}
+
+	if user != nil && accountLockedUntil != nil {
+		if err := c.sendAccountLockedEmail(user, *accountLockedUntil, request); err != nil {
+			c.Log.Warnf("failed to send account locked email : %+v", err)
+		}
+	}
+}
+
+func (c *UserUseCase) sendAccountLockedEmail(user *entity.User, lockedUntil time.Time, request *model.LoginUserRequest) error {
+	newApp := new(entity.Application)
+	if err := c.ApplicationRepository.FindFirst(c.DB, newApp); err != nil {
+		return err
+	}
+
+	logoImagePath := fmt.Sprintf("uploads/images/application/%s", newApp.LogoFilename)
+	logoImageBase64, err := helper_others.ImageToBase64(logoImagePath)
+	if err != nil {
+		return err
+	}
+
+	mailSubject := "Your Account Has Been Temporarily Locked"
+	if request.Lang == enum_state.INDONESIA {
+		mailSubject = "Akun Anda Dikunci Sementara"
+	}
+
+	baseTemplatePath := "internal/templates/base_template_email1.html"
+	childPath := fmt.Sprintf("internal/templates/%s/email/account_locked.html", request.Lang)
+	return c.Email.SendEmail(
+		c.Log,
+		[]string{user.Email},
+		[]string{},
+		mailSubject,
+		baseTemplatePath,
+		childPath,
+		map[string]any{
+			"FirstName":      user.Name.FirstName,
+			"FailedAttempts": c.AuthConfig.LoginMaxFailedAttempts,
+			"IPAddress":      request.IPAddress,
+			"LockedUntil":    lockedUntil.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
+			"LogoImage":      logoImageBase64,
+			"CompanyName":    newApp.AppName,
+			"CompanyTitle":   newApp.AppName,
+			"Year":           time.Now().Format("2006"),
+		},
+	)
+}
+
+// RefreshToken merotasi refresh token menjadi pasangan token baru, refresh token yang sudah pernah dipakai
+// dianggap dicuri sehingga seluruh family (sesi login) dicabut
+func (c *UserUseCase) RefreshToken(ctx context.Context, request *model.RefreshTokenRequest) (*model.UserTokenResponse, *model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	currentToken := new(entity.Token)
+	if err := c.TokenRepository.FindTokenByHash(tx, currentToken, token_helper.HashRefreshToken(request.RefreshToken)); err != nil {
+		c.Log.Warnf("failed to find refresh token : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token isn't valid!")
+	}
+
+	if currentToken.RevokedAt != nil {
+		c.Log.Warnf("refresh token family %s has been revoked!", currentToken.FamilyId)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token has been revoked!")
 	}
 
-	var token = &entity.Token{}
 	now := time.Now()
-	hours := 24
-	if request.Remember {
-		hours = hours * 3
-	}
-	totalHours := now.Add(time.Duration(hours) * time.Hour)
-	token.Token = uuid.New().String()
-	token.UserId = newUser.ID
-	token.ExpiryDate = totalHours
-	if err := c.TokenRepository.Create(tx, token); err != nil {
+	if currentToken.ExpiryDate.Before(now) {
+		c.Log.Warn("refresh token is expired!")
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token is expired!")
+	}
+
+	rotated, err := c.TokenRepository.RotateToken(tx, new(entity.Token), currentToken.ID, now)
+	if err != nil {
+		c.Log.Warnf("failed to rotate refresh token : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to rotate refresh token : %+v", err))
+	}
+
+	if rotated == 0 {
+		if err := c.revokeReusedTokenFamily(tx, currentToken, now); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token has already been used, please login again!")
+	}
+
+	// last seen diperbarui setiap refresh, bukan setiap request, agar access token tetap tanpa query ke tabel sesi
+	updateSession := map[string]any{
+		"last_seen_at": now,
+		"ip_address":   request.IPAddress,
+		"user_agent":   truncateUserAgent(request.UserAgent),
+	}
+	if err := c.UserSessionRepository.UpdateCustomColumns(tx, &entity.UserSession{ID: currentToken.FamilyId}, updateSession); err != nil {
+		c.Log.Warnf("failed to update user session : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update user session : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, currentToken.UserId); err != nil {
+		c.Log.Warnf("failed to find user by id : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to find user by id : %+v", err))
+	}
+
+	if err := c.checkSuspended(newUser); err != nil {
+		return nil, nil, err
+	}
+
+	response, err := c.issueTokenPair(tx, newUser, currentToken.FamilyId, currentToken.ExpiryDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return response, converter.UserToResponse(newUser), nil
+}
+
+// revokeReusedTokenFamily mencabut seluruh family dan langsung commit karena request tetap dibalas error
+func (c *UserUseCase) revokeReusedTokenFamily(tx *gorm.DB, reusedToken *entity.Token, now time.Time) error {
+	c.Log.Warnf("refresh token reuse detected for user %d, revoking token family %s!", reusedToken.UserId, reusedToken.FamilyId)
+	if err := c.revokeUserSession(tx, reusedToken.FamilyId, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return nil
+}
+
+// issueTokenPair menyimpan hash refresh token baru dalam family dan menandatangani access token untuk sesi tersebut
+func (c *UserUseCase) issueTokenPair(tx *gorm.DB, user *entity.User, familyId string, refreshTokenExpiryDate time.Time) (*model.UserTokenResponse, error) {
+	refreshToken, refreshTokenHash, err := token_helper.GenerateRefreshToken()
+	if err != nil {
+		c.Log.Warnf("failed to generate refresh token : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to generate refresh token : %+v", err))
+	}
+
+	newToken := new(entity.Token)
+	newToken.Token = refreshTokenHash
+	newToken.FamilyId = familyId
+	newToken.UserId = user.ID
+	newToken.ExpiryDate = refreshTokenExpiryDate
+	if err := c.TokenRepository.Create(tx, newToken); err != nil {
 		c.Log.Warnf("failed to create token by user into database : %+v", err)
-		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create token by user into database : %+v", err))
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create token by user into database : %+v", err))
+	}
+
+	now := time.Now()
+	accessTokenExpiryDate := now.Add(c.AuthConfig.AccessTokenTTL)
+	accessToken, err := token_helper.GenerateAccessToken(c.AuthConfig.AccessTokenSecret, &token_helper.AccessTokenClaims{
+		Subject:   user.ID,
+		SessionId: familyId,
+		Role:      string(user.Role),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: accessTokenExpiryDate.Unix(),
+	})
+	if err != nil {
+		c.Log.Warnf("failed to sign access token : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sign access token : %+v", err))
+	}
+
+	return converter.UserTokenToResponse(newToken, accessToken, accessTokenExpiryDate, refreshToken), nil
+}
+
+func (c *UserUseCase) GetUserByToken(ctx context.Context, request *model.GetUserByTokenRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("token is not included in header : %+v", err)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("token is not included in header : %+v", err))
+	}
+
+	// access token diverifikasi dari tanda tangan JWT, lalu sesinya dicek di tabel user_sessions
+	claims, err := token_helper.ParseAccessToken(c.AuthConfig.AccessTokenSecret, request.Token)
+	if errors.Is(err, token_helper.ErrExpiredToken) {
+		c.Log.Warn("token is expired!")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "token is expired!")
+	}
+
+	if err != nil {
+		c.Log.Warnf("failed to parse token : %+v", err)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to parse token : %+v", err))
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindCurrentUserById(tx, user, claims.Subject); err != nil {
+		c.Log.Warnf("failed to find user by token : %+v", err)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to find user by token : %+v", err))
+	}
+
+	// suspend dicek setiap request agar langsung berlaku
+	if err := c.checkSuspended(user); err != nil {
+		return nil, err
+	}
+
+	// logout, pencabutan sesi, ganti / reset password dan suspend mencabut sesi,
+	// access token dari sesi yang sudah dicabut langsung ditolak tanpa menunggu kedaluwarsa
+	session := new(entity.UserSession)
+	if err := c.UserSessionRepository.FindActiveSessionByIdAndUserId(tx, session, claims.SessionId, user.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("session has been revoked!")
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "session has been revoked!")
+		}
+
+		c.Log.Warnf("failed to find user session : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user session : %+v", err))
+	}
+
+	response := converter.UserToResponse(user)
+	response.SessionId = claims.SessionId
+	return response, nil
+}
+
+func (c *UserUseCase) GetSessions(ctx context.Context, currentUser *model.UserResponse) (*[]model.UserSessionResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	sessions := []entity.UserSession{}
+	if err := c.UserSessionRepository.FindActiveSessionsByUserId(tx, &sessions, currentUser.ID, time.Now()); err != nil {
+		c.Log.Warnf("failed to find user sessions from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user sessions from database : %+v", err))
+	}
+
+	response := make([]model.UserSessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = *converter.UserSessionToResponse(&session, currentUser.SessionId)
+	}
+
+	return &response, nil
+}
+
+func (c *UserUseCase) RevokeSession(ctx context.Context, request *model.RevokeUserSessionRequest, currentUser *model.UserResponse) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.ID == currentUser.SessionId {
+		c.Log.Warnf("current session can't be revoked, please logout instead!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "current session can't be revoked, please logout instead!")
+	}
+
+	session := new(entity.UserSession)
+	if err := c.UserSessionRepository.FindActiveSessionByIdAndUserId(tx, session, request.ID, currentUser.ID); err != nil {
+		c.Log.Warnf("session not found : %+v", err)
+		return false, fiber.NewError(fiber.StatusNotFound, "session not found!")
+	}
+
+	if err := c.revokeUserSession(tx, session.ID, time.Now()); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return true, nil
+}
+
+// RevokeOtherSessions adalah "log out everywhere else", semua sesi selain yang sedang dipakai dicabut
+func (c *UserUseCase) RevokeOtherSessions(ctx context.Context, currentUser *model.UserResponse) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.revokeUserSessions(tx, currentUser.ID, currentUser.SessionId); err != nil {
+		return false, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
-		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
 	}
 
-	return converter.UserTokenToResponse(token), converter.UserToResponse(newUser), nil
+	return true, nil
 }
 
-func (c *UserUseCase) GetUserByToken(ctx context.Context, request *model.GetUserByTokenRequest) (*model.UserResponse, error) {
-	tx := c.DB.WithContext(ctx)
+// revokeUserSession mencabut satu sesi beserta family refresh token nya,
+// access token dari sesi tersebut tetap berlaku sampai kadaluwarsa (maksimal AccessTokenTTL)
+func (c *UserUseCase) revokeUserSession(tx *gorm.DB, sessionId string, now time.Time) error {
+	if err := c.UserSessionRepository.UpdateCustomColumns(tx, &entity.UserSession{ID: sessionId}, map[string]any{"revoked_at": now}); err != nil {
+		c.Log.Warnf("failed to revoke user session : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to revoke user session : %+v", err))
+	}
 
-	if err := c.Validate.Struct(request); err != nil {
-		c.Log.Warnf("token is not included in header : %+v", err)
-		return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("token is not included in header : %+v", err))
+	if err := c.TokenRepository.RevokeTokenFamily(tx, new(entity.Token), sessionId, now); err != nil {
+		c.Log.Warnf("failed to revoke refresh token family : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to revoke refresh token family : %+v", err))
 	}
 
-	user := new(entity.User)
-	if err := c.UserRepository.FindUserByToken(tx, user, request.Token); err != nil {
-		c.Log.Warnf("failed to find token : %+v", err)
-		return nil, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to find token : %+v", err))
+	return nil
+}
+
+// revokeUserSessions mencabut semua sesi user kecuali exceptSessionId (kosong berarti semua sesi)
+func (c *UserUseCase) revokeUserSessions(tx *gorm.DB, userId uint64, exceptSessionId string) error {
+	now := time.Now()
+	if err := c.UserSessionRepository.RevokeSessionsByUserId(tx, new(entity.UserSession), userId, exceptSessionId, now); err != nil {
+		c.Log.Warnf("failed to revoke user sessions : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to revoke user sessions : %+v", err))
 	}
 
-	expiredDate := user.Token.ExpiryDate
-	if expiredDate.Before(time.Now()) {
-		c.Log.Warn("token is expired!")
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "token is expired!")
+	if err := c.TokenRepository.RevokeTokensByUserId(tx, new(entity.Token), userId, exceptSessionId, now); err != nil {
+		c.Log.Warnf("failed to revoke user tokens : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to revoke user tokens : %+v", err))
 	}
 
-	return converter.UserToResponse(user), nil
+	return nil
+}
+
+// truncateUserAgent menyesuaikan user agent dengan panjang kolom user_sessions.user_agent
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > 255 {
+		return userAgent[:255]
+	}
+	return userAgent
 }
 
 func (c *UserUseCase) Update(ctx *fiber.Ctx, request *model.UpdateUserRequest, currentUser *model.UserResponse) (*model.UserResponse, error) {
@@ -508,22 +1369,28 @@ func (c *UserUseCase) Update(ctx *fiber.Ctx, request *model.UpdateUserRequest, c
 		}
 	}
 
-	newUser := new(entity.User)
-	newUser.ID = currentUser.ID
-	newUser.Email = request.Email
-	newUser.Name.FirstName = request.FirstName
-	newUser.Name.LastName = request.LastName
-	newUser.Phone = request.Phone
-	newUser.Role = enum_state.ADMIN
+	// hanya kolom profil yang diubah, kolom lain (role, 2FA, suspend, dll) tidak boleh ikut tertimpa
+	updateUser := map[string]any{
+		"email":      request.Email,
+		"first_name": request.FirstName,
+		"last_name":  request.LastName,
+		"phone":      request.Phone,
+	}
 	if request.UserProfile != nil {
-		newUser.UserProfile = hashedFilename
+		updateUser["user_profile"] = hashedFilename
 	}
 
-	if err := c.UserRepository.Update(tx, newUser); err != nil {
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: currentUser.ID}, updateUser); err != nil {
 		c.Log.Warnf("failed to update data user : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update data user : %+v", err))
 	}
 
+	newUser := new(entity.User)
+	if err := c.UserRepository.FindUserById(tx, newUser, currentUser.ID); err != nil {
+		c.Log.Warnf("failed to find user by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user by id : %+v", err))
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -564,6 +1431,11 @@ func (c *UserUseCase) UpdatePassword(ctx context.Context, request *model.UpdateU
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update password user : %+v", err))
 	}
 
+	// semua sesi login lain dicabut setelah password diganti, sesi yang sedang dipakai tetap aktif
+	if err := c.revokeUserSessions(tx, newUser.ID, user.SessionId); err != nil {
+		return false, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("failed to commit transaction : %+v", err)
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
@@ -576,11 +1448,15 @@ func (c *UserUseCase) Logout(ctx context.Context, token *model.GetUserByTokenReq
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	newToken := new(entity.Token)
-	result := c.TokenRepository.DeleteToken(tx, newToken, token.Token)
-	if result.RowsAffected == 0 {
-		c.Log.Warnf("can't delete token : %+v", result.Error)
-		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("can't delete token : %+v", result.Error))
+	claims, err := token_helper.ParseAccessToken(c.AuthConfig.AccessTokenSecret, token.Token)
+	if err != nil {
+		c.Log.Warnf("failed to parse token : %+v", err)
+		return false, fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("failed to parse token : %+v", err))
+	}
+
+	// cabut sesi ini beserta family refresh token nya, access token yang tersisa kadaluwarsa sendiri
+	if err := c.revokeUserSession(tx, claims.SessionId, time.Now()); err != nil {
+		return false, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -623,6 +1499,16 @@ func (c *UserUseCase) RemoveCurrentAccount(ctx context.Context, request *model.D
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("can't delete current user : %+v", err))
 	}
 
+	if err := c.revokeUserSessions(tx, newUser.ID, ""); err != nil {
+		return false, err
+	}
+
+	// akun OIDC dilepas agar login sosial berikutnya membuat user baru
+	if err := c.UserIdentityRepository.DeleteAllByUserId(tx, new(entity.UserIdentity), newUser.ID).Error; err != nil {
+		c.Log.Warnf("failed to delete user identities : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete user identities : %+v", err))
+	}
+
 	// kirim email
 	newApp := new(entity.Application)
 	if err := c.ApplicationRepository.FindFirst(tx, newApp); err != nil {
@@ -812,8 +1698,7 @@ func (c *UserUseCase) ValidateForgotPassword(ctx *fiber.Ctx, request *model.Vali
 	}
 
 	if newPasswordReset.VerificationCode != request.VerificationCode {
-		c.Log.Warnf("verification code is not match!")
-		return false, fiber.NewError(fiber.StatusBadRequest, "verification code is not match!")
+		return false, c.recordPasswordResetFailure(tx, newPasswordReset)
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -824,6 +1709,51 @@ func (c *UserUseCase) ValidateForgotPassword(ctx *fiber.Ctx, request *model.Vali
 	return true, nil
 }
 
+// recordPasswordResetFailure menambah percobaan kode verifikasi yang salah, setelah batas tercapai kode dihapus
+// sehingga user harus meminta kode baru
+func (c *UserUseCase) recordPasswordResetFailure(tx *gorm.DB, passwordReset *entity.PasswordReset) error {
+	// penghitung ditambah langsung di database agar percobaan yang bersamaan tidak saling menimpa,
+	// update juga mengunci barisnya sehingga nilai yang dibaca ulang sudah termasuk percobaan lain
+	updateFields := map[string]any{
+		"failed_attempts": gorm.Expr("failed_attempts + 1"),
+	}
+	if err := c.PasswordReset.UpdateCustomColumns(tx, &entity.PasswordReset{ID: passwordReset.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to update password reset into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update password reset into database : %+v", err))
+	}
+
+	count, err := c.PasswordReset.FindAndCountById(tx, passwordReset)
+	if err != nil {
+		c.Log.Warnf("failed to find password reset from database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find password reset from database : %+v", err))
+	}
+
+	// kode sudah dihapus oleh percobaan lain yang mencapai batas
+	if count < 1 {
+		c.Log.Warnf("password reset or verification code not found!")
+		return fiber.NewError(fiber.StatusNotFound, "password reset or verification code not found!")
+	}
+
+	message := "verification code is not match!"
+	if passwordReset.FailedAttempts >= c.AuthConfig.PasswordResetMaxAttempts {
+		if err := c.PasswordReset.Delete(tx, passwordReset); err != nil {
+			c.Log.Warnf("failed to delete password reset from database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to delete password reset from database : %+v", err))
+		}
+
+		message = "verification code is not match, too many failed attempts, please request a new verification code!"
+	}
+
+	// percobaan yang salah tetap disimpan walaupun request mengembalikan error
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	c.Log.Warnf(message)
+	return fiber.NewError(fiber.StatusBadRequest, message)
+}
+
 func (c *UserUseCase) Reset(ctx *fiber.Ctx, request *model.PasswordResetRequest) (bool, error) {
 	tx := c.DB.WithContext(ctx.Context()).Begin()
 	defer tx.Rollback()
@@ -855,8 +1785,7 @@ func (c *UserUseCase) Reset(ctx *fiber.Ctx, request *model.PasswordResetRequest)
 	}
 
 	if newPasswordReset.VerificationCode != request.VerificationCode {
-		c.Log.Warnf("verification code is not match!")
-		return false, fiber.NewError(fiber.StatusBadRequest, "verification code is not match!")
+		return false, c.recordPasswordResetFailure(tx, newPasswordReset)
 	}
 
 	if errs := helper_others.ValidatePassword(request.NewPassword, request.Lang); len(errs) > 0 {
@@ -882,6 +1811,11 @@ func (c *UserUseCase) Reset(ctx *fiber.Ctx, request *model.PasswordResetRequest)
 		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update password user : %+v", err))
 	}
 
+	// semua sesi login dicabut setelah password direset
+	if err := c.revokeUserSessions(tx, newUser.ID, ""); err != nil {
+		return false, err
+	}
+
 	// send notif
 	newApp := new(entity.Application)
 	if err := c.ApplicationRepository.FindFirst(tx, newApp); err != nil {
@@ -978,3 +1912,353 @@ func (c *UserUseCase) Reset(ctx *fiber.Ctx, request *model.PasswordResetRequest)
 
 	return true, nil
 }
+
+// GetAllPaginate menampilkan user untuk admin, termasuk akun yang sudah dihapus (soft delete)
+func (c *UserUseCase) GetAllPaginate(ctx context.Context, page int, perPage int, search string, role string, isVerified string, isActive string, sortingColumn string, sortBy string) (*[]model.UserResponse, int64, int64, int64, int64, int, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if page <= 0 {
+		page = 1
+	}
+
+	if sortingColumn == "" {
+		sortingColumn = "users.id"
+	}
+
+	newPagination := new(repository.Pagination)
+	newPagination.Page = page
+	newPagination.PageSize = perPage
+	newPagination.Column = sortingColumn
+	newPagination.SortBy = sortBy
+	allowedColumns := map[string]bool{
+		"users.id":         true,
+		"users.first_name": true,
+		"users.last_name":  true,
+		"users.email":      true,
+		"users.role":       true,
+		"users.created_at": true,
+		"users.updated_at": true,
+	}
+
+	if !allowedColumns[newPagination.Column] {
+		c.Log.Warnf("invalid sort column : %s", newPagination.Column)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid sort column : %s", newPagination.Column))
+	}
+
+	allowedRoles := map[enum_state.Role]bool{
+		enum_state.ADMIN:    true,
+		enum_state.CUSTOMER: true,
+		enum_state.COURIER:  true,
+		enum_state.STAFF:    true,
+	}
+
+	if role != "" && !allowedRoles[enum_state.Role(role)] {
+		c.Log.Warnf("invalid role : %s", role)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid role : %s", role))
+	}
+
+	users, totalCurrentUser, totalRealUser, totalActiveUser, totalInactiveUser, err := repository.Paginate(tx, &entity.User{}, newPagination, func(d *gorm.DB) *gorm.DB {
+		query := d.Preload("StaffRole.Permissions")
+		if search != "" {
+			keyword := "%" + search + "%"
+			query = query.Where("(CONCAT(users.first_name, ' ', users.last_name) LIKE ? OR users.email LIKE ? OR users.phone LIKE ?)", keyword, keyword, keyword)
+		}
+
+		if role != "" {
+			query = query.Where("users.role = ?", role)
+		}
+
+		if isVerified == "true" {
+			query = query.Where("users.email_verified = ?", true)
+		} else if isVerified == "false" {
+			query = query.Where("users.email_verified = ?", false)
+		}
+
+		if isActive == "true" {
+			query = query.Where("users.deleted_at IS NULL")
+		} else if isActive == "false" {
+			query = query.Where("users.deleted_at IS NOT NULL")
+		}
+		return query
+	})
+
+	if err != nil {
+		c.Log.Warnf("failed to paginate users : %+v", err)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to paginate users : %+v", err))
+	}
+
+	// Hitung total halaman
+	var totalPages int = 0
+	totalPages = int(totalCurrentUser / int64(perPage))
+	if totalCurrentUser%int64(perPage) > 0 {
+		totalPages++
+	}
+
+	return converter.UsersToResponse(&users), totalCurrentUser, totalRealUser, totalActiveUser, totalInactiveUser, totalPages, nil
+}
+
+func (c *UserUseCase) GetByAdmin(ctx context.Context, request *model.AdminUserRequest) (*model.AdminUserDetailResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForAdmin(tx.Unscoped(), request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	totalOrders, err := c.OrderRepository.CountByUserId(tx, new(entity.Order), newUser.ID)
+	if err != nil {
+		c.Log.Warnf("failed to count orders by user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count orders by user : %+v", err))
+	}
+
+	response := new(model.AdminUserDetailResponse)
+	response.UserResponse = *converter.UserToResponse(newUser)
+	response.TotalOrders = totalOrders
+	if newUser.Wallet != nil {
+		response.WalletBalance = newUser.Wallet.Balance
+	}
+
+	return response, nil
+}
+
+// Suspend memblokir login user dan langsung mencabut semua sesi / refresh token miliknya
+func (c *UserUseCase) Suspend(ctx context.Context, request *model.SuspendUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.UserId == request.CurrentAdminId {
+		c.Log.Warnf("can't suspend your own account!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "can't suspend your own account!")
+	}
+
+	newUser, err := c.findUserForAdmin(tx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ensureCanManageUser(newUser, request.CurrentAdminRole); err != nil {
+		return nil, err
+	}
+
+	if newUser.SuspendedAt != nil {
+		c.Log.Warnf("user has already been suspended!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "user has already been suspended!")
+	}
+
+	updateFields := map[string]any{
+		"suspended_at":     time.Now(),
+		"suspended_reason": request.Reason,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to suspend user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to suspend user : %+v", err))
+	}
+
+	if err := c.revokeUserSessions(tx, newUser.ID, ""); err != nil {
+		return nil, err
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	newUser, err = c.findUserForAdmin(tx, newUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_SUSPEND, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+func (c *UserUseCase) Unsuspend(ctx context.Context, request *model.AdminUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForAdmin(tx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ensureCanManageUser(newUser, request.CurrentAdminRole); err != nil {
+		return nil, err
+	}
+
+	if newUser.SuspendedAt == nil {
+		c.Log.Warnf("user is not suspended!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "user is not suspended!")
+	}
+
+	updateFields := map[string]any{
+		"suspended_at":     nil,
+		"suspended_reason": nil,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to unsuspend user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to unsuspend user : %+v", err))
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	newUser, err = c.findUserForAdmin(tx, newUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_UNSUSPEND, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+// ResendVerification membuat token verifikasi baru dan mengirim ulang email verifikasi
+func (c *UserUseCase) ResendVerification(ctx *fiber.Ctx, request *model.AdminUserRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForAdmin(tx, request.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	if newUser.EmailVerified {
+		c.Log.Warnf("email has already been verified!")
+		return false, fiber.NewError(fiber.StatusBadRequest, "email has already been verified!")
+	}
+
+	newUser.VerificationToken = uuid.New().String()
+	newUser.TokenExpiry = time.Now().Add(time.Minute * 30)
+	updateFields := map[string]any{
+		"verification_token": newUser.VerificationToken,
+		"token_expiry":       newUser.TokenExpiry,
+	}
+	if err := c.UserRepository.UpdateCustomColumns(tx, &entity.User{ID: newUser.ID}, updateFields); err != nil {
+		c.Log.Warnf("failed to update verification token : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update verification token : %+v", err))
+	}
+
+	if err := c.sendVerificationEmail(ctx, tx, newUser, request.Lang, request.TimeZone); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return true, nil
+}
+
+// Restore mengembalikan akun yang dihapus, akun OIDC yang sudah dilepas tidak ikut dikembalikan
+func (c *UserUseCase) Restore(ctx context.Context, request *model.AdminUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser, err := c.findUserForAdmin(tx.Unscoped(), request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ensureCanManageUser(newUser, request.CurrentAdminRole); err != nil {
+		return nil, err
+	}
+
+	if !newUser.DeletedAt.Valid {
+		c.Log.Warnf("user is not deleted!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "user is not deleted!")
+	}
+
+	// email bisa sudah dipakai registrasi baru setelah akun ini dihapus
+	total, err := c.UserRepository.UserCountByEmail(tx, new(entity.User), newUser.Email)
+	if err != nil {
+		c.Log.Warnf("failed to count users from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to count users from database : %+v", err))
+	}
+
+	if total > 0 {
+		c.Log.Warnf("email user has already exists!")
+		return nil, fiber.NewError(fiber.StatusConflict, "email user has already exists!")
+	}
+
+	if err := c.UserRepository.UpdateCustomColumns(tx.Unscoped(), &entity.User{ID: newUser.ID}, map[string]any{"deleted_at": nil}); err != nil {
+		c.Log.Warnf("failed to restore user : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to restore user : %+v", err))
+	}
+
+	beforeUser := c.AuditLogUseCase.Snapshot(tx, newUser)
+	newUser, err = c.findUserForAdmin(tx, newUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_RESTORE, enum_state.AUDIT_ENTITY_USER, newUser.ID, beforeUser, c.AuditLogUseCase.Snapshot(tx, newUser)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.UserToResponse(newUser), nil
+}
+
+func (c *UserUseCase) findUserForAdmin(tx *gorm.DB, userId uint64) (*entity.User, error) {
+	newUser := new(entity.User)
+	if err := c.UserRepository.FindCurrentUserById(tx, newUser, userId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("user not found!")
+			return nil, fiber.NewError(fiber.StatusNotFound, "user not found!")
+		}
+
+		c.Log.Warnf("failed to find user by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user by id : %+v", err))
+	}
+
+	return newUser, nil
+}
+
+// ensureCanManageUser menolak staff yang mencoba mengelola akun admin
+func (c *UserUseCase) ensureCanManageUser(target *entity.User, currentAdminRole enum_state.Role) error {
+	if target.Role == enum_state.ADMIN && currentAdminRole != enum_state.ADMIN {
+		c.Log.Warnf("only admin can manage an admin account!")
+		return fiber.NewError(fiber.StatusForbidden, "only admin can manage an admin account!")
+	}
+
+	return nil
+}
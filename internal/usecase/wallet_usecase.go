@@ -2,13 +2,16 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"seblak-bombom-restful-api/internal/entity"
 	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/helper/mailer"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
 	"seblak-bombom-restful-api/internal/repository"
+	xenditUseCase "seblak-bombom-restful-api/internal/usecase/xendit"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -18,24 +21,48 @@ import (
 )
 
 type WalletUseCase struct {
-	DB                              *gorm.DB
-	Log                             *logrus.Logger
-	Validate                        *validator.Validate
-	UserRepository                  *repository.UserRepository
-	WalletRepository                *repository.WalletRepository
-	WalletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository
+	DB                                *gorm.DB
+	Log                               *logrus.Logger
+	Validate                          *validator.Validate
+	UserRepository                    *repository.UserRepository
+	WalletRepository                  *repository.WalletRepository
+	WalletWithdrawRequestRepository   *repository.WalletWithdrawRequestRepository
+	WalletAdjustmentRequestRepository *repository.WalletAdjustmentRequestRepository
+	WalletTransactionRepository       *repository.WalletTransactionRepository
+	ApplicationRepository             *repository.ApplicationRepository
+	Email                             *mailer.EmailWorker
+	WalletConfig                      *model.WalletConfig
+	XenditPayoutUseCase               *xenditUseCase.XenditPayoutUseCase
+	BankAccountUseCase                *BankAccountUseCase
+	WithdrawPolicyUseCase             *WithdrawPolicyUseCase
+	AuditLogUseCase                   *AuditLogUseCase
 }
 
 func NewWalletUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
 	userRepository *repository.UserRepository, walletRepository *repository.WalletRepository,
-	walletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository) *WalletUseCase {
+	walletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository,
+	walletAdjustmentRequestRepository *repository.WalletAdjustmentRequestRepository,
+	walletTransactionRepository *repository.WalletTransactionRepository,
+	applicationRepository *repository.ApplicationRepository, email *mailer.EmailWorker,
+	walletConfig *model.WalletConfig, xenditPayoutUseCase *xenditUseCase.XenditPayoutUseCase,
+	bankAccountUseCase *BankAccountUseCase, withdrawPolicyUseCase *WithdrawPolicyUseCase,
+	auditLogUseCase *AuditLogUseCase) *WalletUseCase {
 	return &WalletUseCase{
-		DB:                              db,
-		Log:                             log,
-		Validate:                        validate,
-		UserRepository:                  userRepository,
-		WalletRepository:                walletRepository,
-		WalletWithdrawRequestRepository: walletWithdrawRequestRepository,
+		DB:                                db,
+		Log:                               log,
+		Validate:                          validate,
+		UserRepository:                    userRepository,
+		WalletRepository:                  walletRepository,
+		WalletWithdrawRequestRepository:   walletWithdrawRequestRepository,
+		WalletAdjustmentRequestRepository: walletAdjustmentRequestRepository,
+		WalletTransactionRepository:       walletTransactionRepository,
+		ApplicationRepository:             applicationRepository,
+		Email:                             email,
+		WalletConfig:                      walletConfig,
+		XenditPayoutUseCase:               xenditPayoutUseCase,
+		BankAccountUseCase:                bankAccountUseCase,
+		WithdrawPolicyUseCase:             withdrawPolicyUseCase,
+		AuditLogUseCase:                   auditLogUseCase,
 	}
 }
 
@@ -49,37 +76,70 @@ func (c *WalletUseCase) WithdrawByCustRequest(ctx context.Context, request *mode
 		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
-	newUser := new(entity.User)
-	newUser.ID = request.UserId
-	if err := c.UserRepository.FindWithPreloads(tx, newUser, "Wallet"); err != nil {
-		c.Log.Warnf("failed to get user by id : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get user by id : %+v", err))
+	// wallet dikunci sebelum pengecekan apa pun agar withdraw milik user yang sama berjalan bergantian,
+	// saldo dan total limit harian / bulanan di bawah dibaca setelah lock didapat
+	newWallet := new(entity.Wallet)
+	if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, newWallet, request.UserId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("the selected wallet is not found!")
+			return nil, fiber.NewError(fiber.StatusNotFound, "the selected wallet is not found!")
+		}
+		c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
+	}
+
+	if newWallet.Status == enum_state.INACIVE_WALLET {
+		c.Log.Warnf("your wallet is frozen and can't be used for this transaction!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "your wallet is frozen and can't be used for this transaction!")
+	}
+
+	fee, err := c.WithdrawPolicyUseCase.CheckWithdraw(tx, request.UserId, request.Method, request.Amount, request.Lang, &request.TimeZone)
+	if err != nil {
+		return nil, err
 	}
 
-	if request.Amount > newUser.Wallet.Balance {
-		c.Log.Warnf("your balance is insufficient to perform this withdraw transaction!")
-		return nil, fiber.NewError(fiber.StatusBadRequest, "your balance is insufficient to perform this withdraw transaction!")
+	// biaya withdraw ikut dipotong dari saldo
+	if request.Amount+fee > newWallet.Balance {
+		message := localizedMessage(request.Lang,
+			"your balance is insufficient to perform this withdraw transaction!",
+			"saldo kamu tidak cukup untuk melakukan penarikan ini!")
+		if fee > 0 {
+			message = localizedMessage(request.Lang,
+				fmt.Sprintf("your balance is insufficient to perform this withdraw transaction including the Rp %.0f fee!", fee),
+				fmt.Sprintf("saldo kamu tidak cukup untuk melakukan penarikan ini termasuk biaya Rp %.0f!", fee))
+		}
+		c.Log.Warnf("withdraw policy violation : %s", message)
+		return nil, fiber.NewError(fiber.StatusBadRequest, message)
 	}
 
 	newWithdrawRequest := new(entity.WalletWithdrawRequests)
 	newWithdrawRequest.UserId = request.UserId
 	newWithdrawRequest.Amount = request.Amount
+	newWithdrawRequest.Fee = fee
 	newWithdrawRequest.Method = request.Method
-	newWithdrawRequest.BankName = request.BankName
-	newWithdrawRequest.BankAcountName = request.BankAccountName
-	newWithdrawRequest.BankAcountNumber = request.BankAccountNumber
-	newWithdrawRequest.Status = request.Status
+	if request.Method == enum_state.WALLET_WITHDRAW_REQUEST_METHOD_BANK_TRANSFER {
+		bankAccount, err := c.BankAccountUseCase.FindPayableAccount(tx, request.BankAccountId, request.UserId, request.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		// simpan salinan data rekening, rekening tersimpan bisa dihapus setelahnya
+		newWithdrawRequest.BankAccountId = &bankAccount.ID
+		newWithdrawRequest.ChannelCode = bankAccount.ChannelCode
+		if channel, ok := helper_others.FindPayoutChannel(bankAccount.ChannelCode); ok {
+			newWithdrawRequest.BankName = channel.Name
+		}
+		newWithdrawRequest.BankAcountName = bankAccount.AccountHolderName
+		newWithdrawRequest.BankAcountNumber = bankAccount.AccountNumber
+	}
+	newWithdrawRequest.Status = enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING
 	newWithdrawRequest.Note = request.Note
 	if err := c.WalletWithdrawRequestRepository.Create(tx, newWithdrawRequest); err != nil {
 		c.Log.Warnf("failed to create new wallet withdraw request : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create new wallet withdraw request : %+v", err))
 	}
 
-	newWallet := new(entity.Wallet)
-	newWallet.ID = newUser.Wallet.ID
-	newWallet.UserId = newUser.ID
-	newWallet.Balance = newUser.Wallet.Balance - request.Amount
-	newWallet.Status = newUser.Wallet.Status
+	newWallet.Balance = newWallet.Balance - request.Amount - fee
 	if err := c.WalletRepository.Update(tx, newWallet); err != nil {
 		c.Log.Warnf("failed to update wallet balance : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
@@ -106,6 +166,28 @@ func (c *WalletUseCase) WithdrawByCustRequest(ctx context.Context, request *mode
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
 	}
 
+	// biaya withdraw dicatat sebagai transaksi tersendiri
+	if fee > 0 {
+		newFeeTransaction := new(helper_others.SaveWalletTransactionRequest)
+		newFeeTransaction.DB = tx
+		newFeeTransaction.UserId = request.UserId
+		newFeeTransaction.OrderId = nil
+		newFeeTransaction.Amount = fee
+		newFeeTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
+		newFeeTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW_FEE
+		newFeeTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_CASH
+		newFeeTransaction.Status = enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
+		newFeeTransaction.ReferenceNumber = ""
+		newFeeTransaction.Note = fmt.Sprintf("Withdraw fee of withdraw request #%d", newWithdrawRequest.ID)
+		newFeeTransaction.AdminNote = ""
+		newFeeTransaction.ProcessedAt = nil
+		newFeeTransaction.ProcessedBy = nil
+		if err := helper_others.SaveWalletTransaction(newFeeTransaction); err != nil {
+			c.Log.Warnf("failed to save wallet transaction : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+		}
+	}
+
 	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
 		c.Log.Warnf("failed to get wallet by id : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by id : %+v", err))
@@ -119,8 +201,8 @@ func (c *WalletUseCase) WithdrawByCustRequest(ctx context.Context, request *mode
 	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
 }
 
-func (c *WalletUseCase) WithdrawByAdminApproval(ctx context.Context, request *model.WithdrawWalletApprovalRequest) (*model.WithdrawWalletResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
+func (c *WalletUseCase) WithdrawByAdminApproval(ctx *fiber.Ctx, request *model.WithdrawWalletApprovalRequest) (*model.WithdrawWalletResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
 	defer tx.Rollback()
 
 	err := c.Validate.Struct(request)
@@ -129,9 +211,10 @@ func (c *WalletUseCase) WithdrawByAdminApproval(ctx context.Context, request *mo
 		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
+	// baris dikunci agar dua admin tidak memproses request yang sama bersamaan
 	newWithdrawRequest := new(entity.WalletWithdrawRequests)
 	newWithdrawRequest.ID = request.ID
-	if err := c.WalletWithdrawRequestRepository.FindById(tx, newWithdrawRequest); err != nil {
+	if err := c.WalletWithdrawRequestRepository.FindByIdForUpdate(tx, newWithdrawRequest); err != nil {
 		c.Log.Warnf("failed to find withdraw wallet request by id : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find withdraw wallet request by id : %+v", err))
 	}
@@ -142,74 +225,698 @@ func (c *WalletUseCase) WithdrawByAdminApproval(ctx context.Context, request *mo
 		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("can't pending an wallet withdraw that has been %s!", newWithdrawRequest.Status))
 	}
 
+	if request.Status != enum_state.WALLET_WITHDRAW_REQUEST_STATUS_APPROVED && request.Status != enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED {
+		c.Log.Warnf("invalid wallet withdraw approval status : %s", request.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid wallet withdraw approval status : %s", request.Status))
+	}
+
 	if request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED {
-		if newWithdrawRequest.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED || newWithdrawRequest.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_APPROVED {
+		if newWithdrawRequest.Status != enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING {
 			c.Log.Warnf("can't pending an wallet withdraw that has been %s!", newWithdrawRequest.Status)
 			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("can't pending an wallet withdraw that has been %s!", newWithdrawRequest.Status))
 		}
 		transactionStatus = enum_state.WALLET_TRANSACTION_STATUS_FAILED
 	}
 
+	isBankTransfer := request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_APPROVED && newWithdrawRequest.Method == enum_state.WALLET_WITHDRAW_REQUEST_METHOD_BANK_TRANSFER
+	// request processing yang payoutnya belum tercatat boleh disetujui ulang,
+	// payout dikirim ulang dengan idempotency key yang sama sehingga xendit tidak mencairkan dua kali
+	isRetryPayout := isBankTransfer && newWithdrawRequest.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PROCESSING && newWithdrawRequest.XenditPayoutId == nil
+
 	if request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_APPROVED {
-		if newWithdrawRequest.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED || newWithdrawRequest.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_APPROVED {
+		if newWithdrawRequest.Status != enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING && !isRetryPayout {
 			c.Log.Warnf("can't pending an wallet withdraw that has been %s!", newWithdrawRequest.Status)
 			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("can't pending an wallet withdraw that has been %s!", newWithdrawRequest.Status))
 		}
 	}
 
-	now := time.Now()
-	newWithdrawRequest.Status = request.Status
-	newWithdrawRequest.RejectionNotes = request.RejectionNotes
-	newWithdrawRequest.ProcessedAt = &now
-	newWithdrawRequest.ProcessedBy = &request.CurrentAdminId
-	if err := c.WalletWithdrawRequestRepository.Update(tx, newWithdrawRequest); err != nil {
+	if !isRetryPayout {
+		// nilai sebelum diproses untuk audit log
+		beforeWithdrawRequest := c.AuditLogUseCase.Snapshot(tx, newWithdrawRequest)
+		now := time.Now()
+		newWithdrawRequest.Status = request.Status
+		newWithdrawRequest.RejectionNotes = request.RejectionNotes
+		newWithdrawRequest.ProcessedAt = &now
+		newWithdrawRequest.ProcessedBy = &request.CurrentAdminId
+
+		// bank transfer dicairkan lewat xendit payout setelah commit, statusnya diselesaikan dari callback payout
+		if isBankTransfer {
+			newWithdrawRequest.Status = enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PROCESSING
+		}
+
+		if err := c.WalletWithdrawRequestRepository.Update(tx, newWithdrawRequest); err != nil {
+			c.Log.Warnf("failed to update wallet withdraw request : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet withdraw request : %+v", err))
+		}
+
+		withdrawAuditAction := enum_state.AUDIT_ACTION_APPROVE
+		if request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED {
+			withdrawAuditAction = enum_state.AUDIT_ACTION_REJECT
+		}
+
+		if err := c.AuditLogUseCase.Record(tx, withdrawAuditAction, enum_state.AUDIT_ENTITY_WALLET_WITHDRAW_REQUEST, newWithdrawRequest.ID, beforeWithdrawRequest, c.AuditLogUseCase.Snapshot(tx, newWithdrawRequest)); err != nil {
+			return nil, err
+		}
+
+		walletTransactionStatus := enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
+		if request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED {
+			walletTransactionStatus = enum_state.WALLET_TRANSACTION_STATUS_FAILED
+			note := fmt.Sprintf("Refund of rejected withdraw request #%d", newWithdrawRequest.ID)
+			if err := c.refundWithdrawRequest(tx, newWithdrawRequest, note, request.RejectionNotes, &now, &request.CurrentAdminId); err != nil {
+				return nil, err
+			}
+		}
+
+		newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
+		newSaveWalletTransaction.DB = tx
+		newSaveWalletTransaction.UserId = newWithdrawRequest.UserId
+		newSaveWalletTransaction.OrderId = nil
+		newSaveWalletTransaction.Amount = newWithdrawRequest.Amount
+		newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
+		newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW
+		newSaveWalletTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_CASH
+		newSaveWalletTransaction.Status = transactionStatus
+		newSaveWalletTransaction.ReferenceNumber = ""
+		newSaveWalletTransaction.Note = newWithdrawRequest.Note
+		newSaveWalletTransaction.AdminNote = request.RejectionNotes
+		newSaveWalletTransaction.ProcessedAt = &now
+		newSaveWalletTransaction.ProcessedBy = &request.CurrentAdminId
+		newSaveWalletTransaction.Status = walletTransactionStatus
+
+		err = helper_others.SaveWalletTransaction(newSaveWalletTransaction)
+		if err != nil {
+			c.Log.Warnf("failed to save wallet transaction : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+		}
+	}
+
+	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	if isBankTransfer {
+		return c.dispatchWithdrawPayout(ctx, newWithdrawRequest)
+	}
+
+	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
+}
+
+// dispatchWithdrawPayout mengirim payout xendit untuk withdraw request yang sudah disimpan sebagai processing.
+// Jika gagal, request tetap processing dan admin bisa menyetujuinya ulang dengan idempotency key yang sama.
+func (c *WalletUseCase) dispatchWithdrawPayout(ctx *fiber.Ctx, withdrawRequest *entity.WalletWithdrawRequests) (*model.WithdrawWalletResponse, error) {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	withdrawPayoutKey := fmt.Sprintf("withdraw-%d", withdrawRequest.ID)
+	newXenditPayout := new(model.CreateXenditPayout)
+	newXenditPayout.ChannelCode = withdrawRequest.ChannelCode
+	newXenditPayout.UserId = withdrawRequest.UserId
+	newXenditPayout.AccountNumber = withdrawRequest.BankAcountNumber
+	newXenditPayout.AccountHolderName = withdrawRequest.BankAcountName
+	newXenditPayout.Amount = withdrawRequest.Amount
+	newXenditPayout.Description = fmt.Sprintf("Wallet withdraw request #%d", withdrawRequest.ID)
+	newXenditPayout.Currency = "IDR"
+	newXenditPayout.IsBalanceReserved = true
+	newXenditPayout.IdempotencyKey = withdrawPayoutKey
+	newXenditPayout.ReferenceId = withdrawPayoutKey
+	xenditPayoutResponse, err := c.XenditPayoutUseCase.AddPayout(ctx, newXenditPayout, tx)
+	if err != nil {
+		c.Log.Warnf("failed to dispatch payout for wallet withdraw request #%d : %+v", withdrawRequest.ID, err)
+		return nil, err
+	}
+
+	newWithdrawRequest := new(entity.WalletWithdrawRequests)
+	newWithdrawRequest.ID = withdrawRequest.ID
+	updateWithdrawRequest := map[string]any{
+		"xendit_payout_id": xenditPayoutResponse.ID,
+	}
+
+	if err := c.WalletWithdrawRequestRepository.UpdateCustomColumns(tx, newWithdrawRequest, updateWithdrawRequest); err != nil {
 		c.Log.Warnf("failed to update wallet withdraw request : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet withdraw request : %+v", err))
 	}
 
-	walletTransactionStatus := enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
-	if request.Status == enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED {
-		walletTransactionStatus = enum_state.WALLET_TRANSACTION_STATUS_FAILED
-		newWallet := new(entity.Wallet)
-		newWallet.UserId = newWithdrawRequest.UserId
-		if err := c.WalletRepository.FindFirstWalletByUserId(tx, newWallet, newWithdrawRequest.UserId, string(enum_state.ACTIVE_WALLET)); err != nil {
-			c.Log.Warnf("failed to get wallet by user id : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by user id : %+v", err))
+	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet withdraw request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet withdraw request by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
+}
+
+func (c *WalletUseCase) WithdrawCancelByCust(ctx context.Context, request *model.CancelWithdrawWalletRequest) (*model.WithdrawWalletResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	// baris dikunci seperti pada approval admin agar pembatalan dan approval tidak berjalan bersamaan
+	newWithdrawRequest := new(entity.WalletWithdrawRequests)
+	newWithdrawRequest.ID = request.ID
+	err = c.WalletWithdrawRequestRepository.FindByIdForUpdate(tx, newWithdrawRequest)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("failed to find wallet withdraw request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet withdraw request by id : %+v", err))
+	}
+
+	// request milik customer lain dianggap tidak ada
+	if err != nil || newWithdrawRequest.UserId != request.CurrentUserId {
+		c.Log.Warnf("wallet withdraw request not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "wallet withdraw request not found!")
+	}
+
+	if newWithdrawRequest.Status != enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING {
+		c.Log.Warnf("can't cancel a wallet withdraw request that has been %s!", newWithdrawRequest.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("can't cancel a wallet withdraw request that has been %s!", newWithdrawRequest.Status))
+	}
+
+	// status hanya berubah jika masih pending, sehingga dana tidak dikembalikan untuk request yang sudah diproses
+	affected, err := c.WalletWithdrawRequestRepository.UpdateStatusIfCurrent(tx, new(entity.WalletWithdrawRequests), newWithdrawRequest.ID, string(enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING), string(enum_state.WALLET_WITHDRAW_REQUEST_STATUS_CANCELLED))
+	if err != nil {
+		c.Log.Warnf("failed to update wallet withdraw request : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet withdraw request : %+v", err))
+	}
+
+	if affected != 1 {
+		c.Log.Warnf("wallet withdraw request #%d has already been processed!", newWithdrawRequest.ID)
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("wallet withdraw request #%d has already been processed!", newWithdrawRequest.ID))
+	}
+	newWithdrawRequest.Status = enum_state.WALLET_WITHDRAW_REQUEST_STATUS_CANCELLED
+
+	note := fmt.Sprintf("Refund of cancelled withdraw request #%d", newWithdrawRequest.ID)
+	if err := c.refundWithdrawRequest(tx, newWithdrawRequest, note, "", nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet withdraw request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet withdraw request by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
+}
+
+// refundWithdrawRequest mengembalikan nominal dan biaya withdraw ke wallet, ditahan dulu jika wallet sedang dibekukan
+func (c *WalletUseCase) refundWithdrawRequest(tx *gorm.DB, withdrawRequest *entity.WalletWithdrawRequests, note string, adminNote string, processedAt *time.Time, processedBy *uint64) error {
+	newWallet := new(entity.Wallet)
+	if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, newWallet, withdrawRequest.UserId); err != nil {
+		c.Log.Warnf("failed to get wallet by user id : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by user id : %+v", err))
+	}
+
+	refundStatus, err := helper_others.CreditWalletBalance(tx, newWallet, withdrawRequest.Amount+withdrawRequest.Fee, c.WalletConfig.HoldCreditsWhenFrozen)
+	if err != nil {
+		c.Log.Warnf("failed to update wallet balance : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
+	}
+
+	newRefundTransaction := new(helper_others.SaveWalletTransactionRequest)
+	newRefundTransaction.DB = tx
+	newRefundTransaction.UserId = withdrawRequest.UserId
+	newRefundTransaction.OrderId = nil
+	newRefundTransaction.Amount = withdrawRequest.Amount
+	newRefundTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_CREDIT
+	newRefundTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW
+	newRefundTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_CASH
+	newRefundTransaction.Status = refundStatus
+	newRefundTransaction.ReferenceNumber = ""
+	newRefundTransaction.Note = note
+	newRefundTransaction.AdminNote = adminNote
+	newRefundTransaction.ProcessedAt = processedAt
+	newRefundTransaction.ProcessedBy = processedBy
+	if err := helper_others.SaveWalletTransaction(newRefundTransaction); err != nil {
+		c.Log.Warnf("failed to save wallet transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+	}
+
+	if withdrawRequest.Fee > 0 {
+		newFeeRefundTransaction := new(helper_others.SaveWalletTransactionRequest)
+		newFeeRefundTransaction.DB = tx
+		newFeeRefundTransaction.UserId = withdrawRequest.UserId
+		newFeeRefundTransaction.OrderId = nil
+		newFeeRefundTransaction.Amount = withdrawRequest.Fee
+		newFeeRefundTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_CREDIT
+		newFeeRefundTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW_FEE
+		newFeeRefundTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_CASH
+		newFeeRefundTransaction.Status = refundStatus
+		newFeeRefundTransaction.ReferenceNumber = ""
+		newFeeRefundTransaction.Note = note
+		newFeeRefundTransaction.AdminNote = adminNote
+		newFeeRefundTransaction.ProcessedAt = processedAt
+		newFeeRefundTransaction.ProcessedBy = processedBy
+		if err := helper_others.SaveWalletTransaction(newFeeRefundTransaction); err != nil {
+			c.Log.Warnf("failed to save wallet transaction : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+		}
+	}
+
+	return nil
+}
+
+func (c *WalletUseCase) GetWithdrawRequestById(ctx context.Context, withdrawRequestId uint64, currentUser *model.UserResponse) (*model.WithdrawWalletResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	newWithdrawRequest := new(entity.WalletWithdrawRequests)
+	newWithdrawRequest.ID = withdrawRequestId
+	count, err := c.WalletWithdrawRequestRepository.FindAndCountById(tx, newWithdrawRequest)
+	if err != nil {
+		c.Log.Warnf("failed to find wallet withdraw request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet withdraw request by id : %+v", err))
+	}
+
+	if count < 1 || (!currentUser.HasPermission(enum_state.PERMISSION_WALLETS_READ) && newWithdrawRequest.UserId != currentUser.ID) {
+		c.Log.Warnf("wallet withdraw request not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "wallet withdraw request not found!")
+	}
+
+	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet withdraw request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet withdraw request by id : %+v", err))
+	}
+
+	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
+}
+
+func (c *WalletUseCase) GetAllWithdrawRequestsPaginate(ctx context.Context, page int, perPage int, status string, sortingColumn string, sortBy string, currentUser *model.UserResponse) (*[]model.WithdrawWalletResponse, int64, int64, int64, int64, int, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if page <= 0 {
+		page = 1
+	}
+
+	if sortingColumn == "" {
+		sortingColumn = "wallet_withdraw_requests.id"
+	}
+
+	newPagination := new(repository.Pagination)
+	newPagination.Page = page
+	newPagination.PageSize = perPage
+	newPagination.Column = sortingColumn
+	newPagination.SortBy = sortBy
+	allowedColumns := map[string]bool{
+		"wallet_withdraw_requests.id":         true,
+		"wallet_withdraw_requests.user_id":    true,
+		"wallet_withdraw_requests.amount":     true,
+		"wallet_withdraw_requests.method":     true,
+		"wallet_withdraw_requests.status":     true,
+		"wallet_withdraw_requests.created_at": true,
+		"wallet_withdraw_requests.updated_at": true,
+	}
+
+	if !allowedColumns[newPagination.Column] {
+		c.Log.Warnf("invalid sort column : %s", newPagination.Column)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid sort column : %s", newPagination.Column))
+	}
+
+	withdrawRequests, totalCurrent, totalReal, totalActive, totalInactive, err := repository.Paginate(tx, &entity.WalletWithdrawRequests{}, newPagination, func(d *gorm.DB) *gorm.DB {
+		result := d.Preload("User")
+		if !currentUser.HasPermission(enum_state.PERMISSION_WALLETS_READ) {
+			result = result.Where("user_id = ?", currentUser.ID)
+		}
+
+		if status != "" {
+			result = result.Where("status = ?", status)
+		}
+		return result
+	})
+
+	if err != nil {
+		c.Log.Warnf("failed to paginate wallet withdraw requests : %+v", err)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to paginate wallet withdraw requests : %+v", err))
+	}
+
+	// Hitung total halaman
+	var totalPages int = 0
+	totalPages = int(totalCurrent / int64(perPage))
+	if totalCurrent%int64(perPage) > 0 {
+		totalPages++
+	}
+
+	return converter.WalletWithdrawsToResponse(&withdrawRequests), totalCurrent, totalReal, totalActive, totalInactive, totalPages, nil
+}
+
+func (c *WalletUseCase) AdjustByAdmin(ctx context.Context, request *model.WalletAdjustmentRequest) (*model.WalletAdjustmentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newUser := new(entity.User)
+	newUser.ID = request.UserId
+	if err := c.UserRepository.FindWithPreloads(tx, newUser, "Wallet"); err != nil {
+		c.Log.Warnf("failed to get user by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get user by id : %+v", err))
+	}
+
+	if newUser.Wallet == nil {
+		c.Log.Warnf("wallet for user %d not found!", request.UserId)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("wallet for user %d not found!", request.UserId))
+	}
+
+	newAdjustment := new(entity.WalletAdjustmentRequests)
+	newAdjustment.UserId = request.UserId
+	newAdjustment.Amount = request.Amount
+	newAdjustment.Reason = request.Reason
+	newAdjustment.RequestedBy = request.CurrentAdminId
+	newAdjustment.Status = enum_state.WALLET_ADJUSTMENT_STATUS_PENDING
+
+	// penyesuaian di atas threshold harus menunggu persetujuan admin lain
+	needApproval := c.WalletConfig.AdjustmentApprovalThreshold > 0 && absFloat32(request.Amount) > c.WalletConfig.AdjustmentApprovalThreshold
+	if !needApproval {
+		now := time.Now()
+		newAdjustment.Status = enum_state.WALLET_ADJUSTMENT_STATUS_APPROVED
+		newAdjustment.ProcessedBy = &request.CurrentAdminId
+		newAdjustment.ProcessedAt = &now
+	}
+
+	if err := c.WalletAdjustmentRequestRepository.Create(tx, newAdjustment); err != nil {
+		c.Log.Warnf("failed to create new wallet adjustment request : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create new wallet adjustment request : %+v", err))
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_WALLET_ADJUSTMENT_REQUEST, newAdjustment.ID, nil, c.AuditLogUseCase.Snapshot(tx, newAdjustment)); err != nil {
+		return nil, err
+	}
+
+	var transactionStatus enum_state.WalletTransactionStatus
+	if !needApproval {
+		transactionStatus, err = c.applyWalletAdjustment(tx, newAdjustment, newUser)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		newWallet.Balance = newWallet.Balance + newWithdrawRequest.Amount
-		newWallet.Status = enum_state.ACTIVE_WALLET
+	if err := c.WalletAdjustmentRequestRepository.FindWithPreloads(tx, newAdjustment, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet adjustment request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet adjustment request by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	// email dikirim setelah commit agar SMTP yang lambat / gagal tidak menahan atau membatalkan penyesuaian saldo
+	if !needApproval {
+		if err := c.sendWalletAdjustmentEmail(newAdjustment, newUser, transactionStatus, request.Lang, &request.TimeZone); err != nil {
+			c.Log.Warnf("failed to send wallet adjustment email : %+v", err)
+		}
+	}
+
+	return converter.WalletAdjustmentToResponse(newAdjustment), nil
+}
+
+func (c *WalletUseCase) AdjustmentByAdminApproval(ctx context.Context, request *model.WalletAdjustmentApprovalRequest) (*model.WalletAdjustmentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.Status != enum_state.WALLET_ADJUSTMENT_STATUS_APPROVED && request.Status != enum_state.WALLET_ADJUSTMENT_STATUS_REJECTED {
+		c.Log.Warnf("invalid wallet adjustment status : %s", request.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid wallet adjustment status : %s", request.Status))
+	}
+
+	// baris dikunci agar dua admin yang menyetujui bersamaan tidak menerapkan penyesuaian dua kali
+	newAdjustment := new(entity.WalletAdjustmentRequests)
+	newAdjustment.ID = request.ID
+	if err := c.WalletAdjustmentRequestRepository.FindByIdForUpdate(tx, newAdjustment); err != nil {
+		c.Log.Warnf("failed to find wallet adjustment request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to find wallet adjustment request by id : %+v", err))
+	}
+
+	if newAdjustment.Status != enum_state.WALLET_ADJUSTMENT_STATUS_PENDING {
+		c.Log.Warnf("can't process a wallet adjustment that has been %s!", newAdjustment.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("can't process a wallet adjustment that has been %s!", newAdjustment.Status))
+	}
+
+	if newAdjustment.RequestedBy == request.CurrentAdminId {
+		c.Log.Warnf("wallet adjustment must be approved by a different admin!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "wallet adjustment must be approved by a different admin!")
+	}
+
+	// nilai sebelum diproses untuk audit log
+	beforeAdjustment := c.AuditLogUseCase.Snapshot(tx, newAdjustment)
+	now := time.Now()
+	newAdjustment.Status = request.Status
+	newAdjustment.RejectionNotes = request.RejectionNotes
+	newAdjustment.ProcessedBy = &request.CurrentAdminId
+	newAdjustment.ProcessedAt = &now
+	if err := c.WalletAdjustmentRequestRepository.Update(tx, newAdjustment); err != nil {
+		c.Log.Warnf("failed to update wallet adjustment request : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet adjustment request : %+v", err))
+	}
+
+	adjustmentAuditAction := enum_state.AUDIT_ACTION_APPROVE
+	if request.Status == enum_state.WALLET_ADJUSTMENT_STATUS_REJECTED {
+		adjustmentAuditAction = enum_state.AUDIT_ACTION_REJECT
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, adjustmentAuditAction, enum_state.AUDIT_ENTITY_WALLET_ADJUSTMENT_REQUEST, newAdjustment.ID, beforeAdjustment, c.AuditLogUseCase.Snapshot(tx, newAdjustment)); err != nil {
+		return nil, err
+	}
+
+	newUser := new(entity.User)
+	var transactionStatus enum_state.WalletTransactionStatus
+	if request.Status == enum_state.WALLET_ADJUSTMENT_STATUS_APPROVED {
+		newUser.ID = newAdjustment.UserId
+		if err := c.UserRepository.FindWithPreloads(tx, newUser, "Wallet"); err != nil {
+			c.Log.Warnf("failed to get user by id : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get user by id : %+v", err))
+		}
+
+		if newUser.Wallet == nil {
+			c.Log.Warnf("wallet for user %d not found!", newAdjustment.UserId)
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("wallet for user %d not found!", newAdjustment.UserId))
+		}
+
+		transactionStatus, err = c.applyWalletAdjustment(tx, newAdjustment, newUser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.WalletAdjustmentRequestRepository.FindWithPreloads(tx, newAdjustment, "User"); err != nil {
+		c.Log.Warnf("failed to get wallet adjustment request by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet adjustment request by id : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	if request.Status == enum_state.WALLET_ADJUSTMENT_STATUS_APPROVED {
+		if err := c.sendWalletAdjustmentEmail(newAdjustment, newUser, transactionStatus, request.Lang, &request.TimeZone); err != nil {
+			c.Log.Warnf("failed to send wallet adjustment email : %+v", err)
+		}
+	}
+
+	return converter.WalletAdjustmentToResponse(newAdjustment), nil
+}
+
+// applyWalletAdjustment mengubah saldo dan mencatat transaksi wallet, email dikirim pemanggil setelah commit
+func (c *WalletUseCase) applyWalletAdjustment(tx *gorm.DB, adjustment *entity.WalletAdjustmentRequests, user *entity.User) (enum_state.WalletTransactionStatus, error) {
+	flowType := enum_state.WALLET_FLOW_TYPE_CREDIT
+	if adjustment.Amount < 0 {
+		flowType = enum_state.WALLET_FLOW_TYPE_DEBIT
+	}
+
+	// saldo dibaca ulang dengan row lock, salinan wallet dari preload user bisa sudah usang
+	newWallet := new(entity.Wallet)
+	if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, newWallet, adjustment.UserId); err != nil {
+		c.Log.Warnf("failed to get wallet by user id : %+v", err)
+		return "", fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by user id : %+v", err))
+	}
+	user.Wallet = newWallet
+
+	amount := absFloat32(adjustment.Amount)
+	if flowType == enum_state.WALLET_FLOW_TYPE_DEBIT && amount > newWallet.Balance {
+		c.Log.Warnf("wallet balance is insufficient for this adjustment!")
+		return "", fiber.NewError(fiber.StatusBadRequest, "wallet balance is insufficient for this adjustment!")
+	}
+
+	transactionStatus := enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
+	if flowType == enum_state.WALLET_FLOW_TYPE_CREDIT {
+		// saldo masuk ditahan dulu jika wallet sedang dibekukan
+		status, err := helper_others.CreditWalletBalance(tx, newWallet, amount, c.WalletConfig.HoldCreditsWhenFrozen)
+		if err != nil {
+			c.Log.Warnf("failed to update wallet balance : %+v", err)
+			return "", fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
+		}
+		transactionStatus = status
+	} else {
+		newWallet.Balance = newWallet.Balance - amount
 		if err := c.WalletRepository.Update(tx, newWallet); err != nil {
 			c.Log.Warnf("failed to update wallet balance : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
+			return "", fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance : %+v", err))
 		}
 	}
 
 	newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
 	newSaveWalletTransaction.DB = tx
-	newSaveWalletTransaction.UserId = newWithdrawRequest.UserId
+	newSaveWalletTransaction.UserId = adjustment.UserId
 	newSaveWalletTransaction.OrderId = nil
-	newSaveWalletTransaction.Amount = newWithdrawRequest.Amount
-	newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_DEBIT
-	newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW
-	newSaveWalletTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_CASH
+	newSaveWalletTransaction.Amount = amount
+	newSaveWalletTransaction.FlowType = flowType
+	newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_ADMIN_ADJUSTMENT
+	newSaveWalletTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
 	newSaveWalletTransaction.Status = transactionStatus
 	newSaveWalletTransaction.ReferenceNumber = ""
-	newSaveWalletTransaction.Note = newWithdrawRequest.Note
-	newSaveWalletTransaction.AdminNote = request.RejectionNotes
-	newSaveWalletTransaction.ProcessedAt = &now
-	newSaveWalletTransaction.ProcessedBy = &request.CurrentAdminId
-	newSaveWalletTransaction.Status = walletTransactionStatus
+	newSaveWalletTransaction.Note = adjustment.Reason
+	newSaveWalletTransaction.AdminNote = fmt.Sprintf("Wallet adjustment #%d requested by admin #%d", adjustment.ID, adjustment.RequestedBy)
+	newSaveWalletTransaction.ProcessedAt = adjustment.ProcessedAt
+	newSaveWalletTransaction.ProcessedBy = adjustment.ProcessedBy
 
-	err = helper_others.SaveWalletTransaction(newSaveWalletTransaction)
+	err := helper_others.SaveWalletTransaction(newSaveWalletTransaction)
 	if err != nil {
 		c.Log.Warnf("failed to save wallet transaction : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+		return "", fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
 	}
 
-	if err := c.WalletWithdrawRequestRepository.FindWithPreloads(tx, newWithdrawRequest, "User"); err != nil {
-		c.Log.Warnf("failed to get wallet by id : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet by id : %+v", err))
+	return transactionStatus, nil
+}
+
+// sendWalletAdjustmentEmail memberi tahu customer bahwa saldo wallet telah disesuaikan admin
+func (c *WalletUseCase) sendWalletAdjustmentEmail(adjustment *entity.WalletAdjustmentRequests, user *entity.User, transactionStatus enum_state.WalletTransactionStatus, lang enum_state.Languange, timeZone *time.Location) error {
+	flowType := enum_state.WALLET_FLOW_TYPE_CREDIT
+	if adjustment.Amount < 0 {
+		flowType = enum_state.WALLET_FLOW_TYPE_DEBIT
+	}
+
+	newApp := new(entity.Application)
+	if err := c.ApplicationRepository.FindFirst(c.DB, newApp); err != nil {
+		return err
+	}
+
+	logoImagePath := fmt.Sprintf("uploads/images/application/%s", newApp.LogoFilename)
+	logoImageBase64, err := helper_others.ImageToBase64(logoImagePath)
+	if err != nil {
+		return err
+	}
+
+	mailSubject := "Your Wallet Balance Has Been Adjusted"
+	if lang == enum_state.INDONESIA {
+		mailSubject = "Saldo Dompet Anda Telah Disesuaikan"
+	}
+
+	baseTemplatePath := "internal/templates/base_template_email1.html"
+	childPath := fmt.Sprintf("internal/templates/%s/email/wallet_adjustment.html", lang)
+	data := map[string]any{
+		"FirstName":    user.Name.FirstName,
+		"FlowType":     string(flowType),
+		"Amount":       helper_others.FormatNumberFloat32(absFloat32(adjustment.Amount)),
+		"Balance":      helper_others.FormatNumberFloat32(user.Wallet.Balance),
+		"Reason":       adjustment.Reason,
+		"IsHeld":       transactionStatus == enum_state.WALLET_TRANSACTION_STATUS_PENDING,
+		"Date":         adjustment.ProcessedAt.In(timeZone).Format("02 Jan 2006 15:04 MST"),
+		"LogoImage":    logoImageBase64,
+		"CompanyName":  newApp.AppName,
+		"CompanyTitle": newApp.AppName,
+		"Year":         time.Now().Format("2006"),
+	}
+
+	return c.Email.SendEmail(
+		c.Log,
+		[]string{user.Email},
+		[]string{},
+		mailSubject,
+		baseTemplatePath,
+		childPath,
+		data,
+	)
+}
+
+func (c *WalletUseCase) UpdateStatusByAdmin(ctx context.Context, request *model.UpdateWalletStatusRequest) (*model.WalletResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	// wallet dikunci agar saldo pending yang masuk bersamaan (misal pelepasan saldo split payment) tidak terlewat
+	newWallet := new(entity.Wallet)
+	if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, newWallet, request.UserId); err != nil {
+		c.Log.Warnf("failed to get wallet by user id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("failed to get wallet by user id : %+v", err))
+	}
+
+	if newWallet.Status == request.Status {
+		c.Log.Warnf("wallet is already %s!", request.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("wallet is already %s!", request.Status))
+	}
+
+	// nilai sebelum diubah untuk audit log
+	beforeWallet := c.AuditLogUseCase.Snapshot(tx, newWallet)
+	now := time.Now()
+	newWallet.Status = request.Status
+	newWallet.StatusReason = request.Reason
+	newWallet.StatusUpdatedBy = &request.CurrentAdminId
+	newWallet.StatusUpdatedAt = &now
+
+	if request.Status == enum_state.ACTIVE_WALLET {
+		// lepaskan semua saldo masuk yang ditahan selama wallet dibekukan
+		pendingCredits := make([]entity.WalletTransactions, 0)
+		if err := c.WalletTransactionRepository.FindPendingWalletCreditsByUserId(tx, &pendingCredits, request.UserId); err != nil {
+			c.Log.Warnf("failed to find pending wallet credits : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find pending wallet credits : %+v", err))
+		}
+
+		for i := range pendingCredits {
+			newWallet.Balance = newWallet.Balance + pendingCredits[i].Amount
+			pendingCredits[i].Status = enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
+			pendingCredits[i].ProcessedBy = &request.CurrentAdminId
+			pendingCredits[i].ProcessedAt = &now
+			if err := c.WalletTransactionRepository.Update(tx, &pendingCredits[i]); err != nil {
+				c.Log.Warnf("failed to release pending wallet credit : %+v", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to release pending wallet credit : %+v", err))
+			}
+		}
+	}
+
+	if err := c.WalletRepository.Update(tx, newWallet); err != nil {
+		c.Log.Warnf("failed to update wallet status : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet status : %+v", err))
+	}
+
+	walletAuditAction := enum_state.AUDIT_ACTION_UNFREEZE
+	if request.Status == enum_state.INACIVE_WALLET {
+		walletAuditAction = enum_state.AUDIT_ACTION_FREEZE
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, walletAuditAction, enum_state.AUDIT_ENTITY_WALLET, newWallet.ID, beforeWallet, c.AuditLogUseCase.Snapshot(tx, newWallet)); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -217,5 +924,12 @@ func (c *WalletUseCase) WithdrawByAdminApproval(ctx context.Context, request *mo
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
 	}
 
-	return converter.WalletWithdrawToResponse(newWithdrawRequest), nil
+	return converter.WalletToResponse(newWallet), nil
+}
+
+func absFloat32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
 }
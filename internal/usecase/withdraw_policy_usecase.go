@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type WithdrawPolicyUseCase struct {
+	DB                              *gorm.DB
+	Log                             *logrus.Logger
+	Validate                        *validator.Validate
+	WithdrawPolicyRepository        *repository.WithdrawPolicyRepository
+	WalletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository
+	WalletTransactionRepository     *repository.WalletTransactionRepository
+	AuditLogUseCase                 *AuditLogUseCase
+	WalletConfig                    *model.WalletConfig
+}
+
+func NewWithdrawPolicyUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	withdrawPolicyRepository *repository.WithdrawPolicyRepository,
+	walletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository,
+	walletTransactionRepository *repository.WalletTransactionRepository,
+	auditLogUseCase *AuditLogUseCase, walletConfig *model.WalletConfig) *WithdrawPolicyUseCase {
+	return &WithdrawPolicyUseCase{
+		DB:                              db,
+		Log:                             log,
+		Validate:                        validate,
+		WithdrawPolicyRepository:        withdrawPolicyRepository,
+		WalletWithdrawRequestRepository: walletWithdrawRequestRepository,
+		WalletTransactionRepository:     walletTransactionRepository,
+		AuditLogUseCase:                 auditLogUseCase,
+		WalletConfig:                    walletConfig,
+	}
+}
+
+func (c *WithdrawPolicyUseCase) GetAll(ctx context.Context) (*[]model.WithdrawPolicyResponse, error) {
+	tx := c.DB.WithContext(ctx)
+
+	newWithdrawPolicies := new([]entity.WithdrawPolicy)
+	if err := c.WithdrawPolicyRepository.FindAll(tx, newWithdrawPolicies); err != nil {
+		c.Log.Warnf("failed to get all withdraw policies : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get all withdraw policies : %+v", err))
+	}
+
+	return converter.WithdrawPoliciesToResponse(newWithdrawPolicies), nil
+}
+
+func (c *WithdrawPolicyUseCase) UpsertByAdmin(ctx context.Context, request *model.UpsertWithdrawPolicyRequest) (*model.WithdrawPolicyResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	if request.Method != enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH && request.Method != enum_state.WALLET_WITHDRAW_REQUEST_METHOD_BANK_TRANSFER {
+		c.Log.Warnf("invalid withdraw method : %s", request.Method)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid withdraw method : %s", request.Method))
+	}
+
+	if request.FeeType != enum_state.WITHDRAW_FEE_TYPE_FLAT && request.FeeType != enum_state.WITHDRAW_FEE_TYPE_PERCENT {
+		c.Log.Warnf("invalid withdraw fee type : %s", request.FeeType)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid withdraw fee type : %s", request.FeeType))
+	}
+
+	if request.FeeType == enum_state.WITHDRAW_FEE_TYPE_PERCENT && request.FeeValue > 100 {
+		c.Log.Warnf("percent withdraw fee can't be more than 100!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "percent withdraw fee can't be more than 100!")
+	}
+
+	if request.DailyLimit > 0 && request.MonthlyLimit > 0 && request.DailyLimit > request.MonthlyLimit {
+		c.Log.Warnf("daily withdraw limit can't be more than monthly withdraw limit!")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "daily withdraw limit can't be more than monthly withdraw limit!")
+	}
+
+	newWithdrawPolicy := new(entity.WithdrawPolicy)
+	count, err := c.WithdrawPolicyRepository.FindAndCountWithdrawPolicyByMethod(tx, newWithdrawPolicy, string(request.Method))
+	if err != nil {
+		c.Log.Warnf("failed to find withdraw policy by method : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find withdraw policy by method : %+v", err))
+	}
+
+	// nilai sebelum diubah untuk audit log, policy baru tidak punya nilai sebelumnya
+	var beforeWithdrawPolicy map[string]any
+	auditAction := enum_state.AUDIT_ACTION_CREATE
+	if count > 0 {
+		beforeWithdrawPolicy = c.AuditLogUseCase.Snapshot(tx, newWithdrawPolicy)
+		auditAction = enum_state.AUDIT_ACTION_UPDATE
+	}
+
+	newWithdrawPolicy.Method = request.Method
+	newWithdrawPolicy.MinAmount = request.MinAmount
+	newWithdrawPolicy.FeeType = request.FeeType
+	newWithdrawPolicy.FeeValue = request.FeeValue
+	newWithdrawPolicy.DailyLimit = request.DailyLimit
+	newWithdrawPolicy.MonthlyLimit = request.MonthlyLimit
+	newWithdrawPolicy.CoolingOffHours = request.CoolingOffHours
+	newWithdrawPolicy.UpdatedBy = &request.CurrentAdminId
+	if count > 0 {
+		if err := c.WithdrawPolicyRepository.Update(tx, newWithdrawPolicy); err != nil {
+			c.Log.Warnf("failed to update withdraw policy : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update withdraw policy : %+v", err))
+		}
+	} else {
+		if err := c.WithdrawPolicyRepository.Create(tx, newWithdrawPolicy); err != nil {
+			c.Log.Warnf("failed to create withdraw policy : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create withdraw policy : %+v", err))
+		}
+	}
+
+	if err := c.AuditLogUseCase.Record(tx, auditAction, enum_state.AUDIT_ENTITY_WITHDRAW_POLICY, newWithdrawPolicy.ID, beforeWithdrawPolicy, c.AuditLogUseCase.Snapshot(tx, newWithdrawPolicy)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return converter.WithdrawPolicyToResponse(newWithdrawPolicy), nil
+}
+
+// CheckWithdraw memvalidasi withdraw terhadap policy method-nya dan mengembalikan biaya withdraw,
+// method tanpa policy tidak punya batasan maupun biaya. timeZone hanya dipakai untuk menampilkan waktu ke customer.
+func (c *WithdrawPolicyUseCase) CheckWithdraw(tx *gorm.DB, userId uint64, method enum_state.WalletWithdrawRequest, amount float32, lang enum_state.Languange, timeZone *time.Location) (float32, error) {
+	newWithdrawPolicy := new(entity.WithdrawPolicy)
+	count, err := c.WithdrawPolicyRepository.FindAndCountWithdrawPolicyByMethod(tx, newWithdrawPolicy, string(method))
+	if err != nil {
+		c.Log.Warnf("failed to find withdraw policy by method : %+v", err)
+		return 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find withdraw policy by method : %+v", err))
+	}
+
+	if count < 1 {
+		return 0, nil
+	}
+
+	if amount < newWithdrawPolicy.MinAmount {
+		message := localizedMessage(lang,
+			fmt.Sprintf("the minimum withdraw amount for %s is Rp %.0f!", method, newWithdrawPolicy.MinAmount),
+			fmt.Sprintf("minimal penarikan untuk %s adalah Rp %.0f!", method, newWithdrawPolicy.MinAmount))
+		c.Log.Warnf("withdraw policy violation : %s", message)
+		return 0, fiber.NewError(fiber.StatusBadRequest, message)
+	}
+
+	// jendela harian / bulanan mengikuti zona waktu bisnis agar customer tidak bisa menggeser batasnya
+	businessTimeZone := c.WalletConfig.BusinessTimeZone
+	now := time.Now().In(businessTimeZone)
+	if newWithdrawPolicy.CoolingOffHours > 0 {
+		newTopUp := new(entity.WalletTransactions)
+		topUpCount, err := c.WalletTransactionRepository.FindAndCountLatestTopUpByUserId(tx, newTopUp, userId)
+		if err != nil {
+			c.Log.Warnf("failed to find latest top up by user id : %+v", err)
+			return 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find latest top up by user id : %+v", err))
+		}
+
+		if topUpCount > 0 {
+			availableAt := newTopUp.CreatedAt.Add(time.Duration(newWithdrawPolicy.CoolingOffHours) * time.Hour).In(timeZone)
+			if time.Now().Before(availableAt) {
+				message := localizedMessage(lang,
+					fmt.Sprintf("withdraw is only available %d hours after your last top up, please try again after %s!", newWithdrawPolicy.CoolingOffHours, availableAt.Format("2006-01-02 15:04")),
+					fmt.Sprintf("penarikan baru bisa dilakukan %d jam setelah top up terakhir, silakan coba lagi setelah %s!", newWithdrawPolicy.CoolingOffHours, availableAt.Format("2006-01-02 15:04")))
+				c.Log.Warnf("withdraw policy violation : %s", message)
+				return 0, fiber.NewError(fiber.StatusForbidden, message)
+			}
+		}
+	}
+
+	if newWithdrawPolicy.DailyLimit > 0 {
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, businessTimeZone)
+		totalToday, err := c.WalletWithdrawRequestRepository.SumWithdrawAmountByUserIdSince(tx, &entity.WalletWithdrawRequests{}, userId, startOfDay)
+		if err != nil {
+			c.Log.Warnf("failed to sum daily withdraw amount : %+v", err)
+			return 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum daily withdraw amount : %+v", err))
+		}
+
+		if totalToday+amount > newWithdrawPolicy.DailyLimit {
+			remaining := float32(math.Max(0, float64(newWithdrawPolicy.DailyLimit-totalToday)))
+			message := localizedMessage(lang,
+				fmt.Sprintf("the daily withdraw limit is Rp %.0f, you can only withdraw Rp %.0f more today!", newWithdrawPolicy.DailyLimit, remaining),
+				fmt.Sprintf("batas penarikan harian adalah Rp %.0f, sisa penarikan hari ini Rp %.0f!", newWithdrawPolicy.DailyLimit, remaining))
+			c.Log.Warnf("withdraw policy violation : %s", message)
+			return 0, fiber.NewError(fiber.StatusBadRequest, message)
+		}
+	}
+
+	if newWithdrawPolicy.MonthlyLimit > 0 {
+		startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, businessTimeZone)
+		totalThisMonth, err := c.WalletWithdrawRequestRepository.SumWithdrawAmountByUserIdSince(tx, &entity.WalletWithdrawRequests{}, userId, startOfMonth)
+		if err != nil {
+			c.Log.Warnf("failed to sum monthly withdraw amount : %+v", err)
+			return 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to sum monthly withdraw amount : %+v", err))
+		}
+
+		if totalThisMonth+amount > newWithdrawPolicy.MonthlyLimit {
+			remaining := float32(math.Max(0, float64(newWithdrawPolicy.MonthlyLimit-totalThisMonth)))
+			message := localizedMessage(lang,
+				fmt.Sprintf("the monthly withdraw limit is Rp %.0f, you can only withdraw Rp %.0f more this month!", newWithdrawPolicy.MonthlyLimit, remaining),
+				fmt.Sprintf("batas penarikan bulanan adalah Rp %.0f, sisa penarikan bulan ini Rp %.0f!", newWithdrawPolicy.MonthlyLimit, remaining))
+			c.Log.Warnf("withdraw policy violation : %s", message)
+			return 0, fiber.NewError(fiber.StatusBadRequest, message)
+		}
+	}
+
+	fee := newWithdrawPolicy.FeeValue
+	if newWithdrawPolicy.FeeType == enum_state.WITHDRAW_FEE_TYPE_PERCENT {
+		fee = float32(math.Round(float64(amount * newWithdrawPolicy.FeeValue / 100)))
+	}
+
+	return fee, nil
+}
+
+func localizedMessage(lang enum_state.Languange, en string, id string) string {
+	if lang == enum_state.INDONESIA {
+		return id
+	}
+	return en
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"seblak-bombom-restful-api/internal/entity"
@@ -21,19 +22,24 @@ import (
 )
 
 type XenditCallbackUseCase struct {
-	DB                          *gorm.DB
-	Log                         *logrus.Logger
-	Validate                    *validator.Validate
-	XenditClient                *xendit.APIClient
-	OrderRepository             *repository.OrderRepository
-	XenditTransactionRepository *repository.XenditTransctionRepository
-	UserRepository              *repository.UserRepository
-	WalletRepository            *repository.WalletRepository
-	XenditPayoutRepository      *repository.XenditPayoutRepository
-	PayoutRepository            *repository.PayoutRepository
-	ApplicationRepository       *repository.ApplicationRepository
-	NotificationRepository      *repository.NotificationRepository
-	Email                       *mailer.EmailWorker
+	DB                              *gorm.DB
+	Log                             *logrus.Logger
+	Validate                        *validator.Validate
+	XenditClient                    *xendit.APIClient
+	OrderRepository                 *repository.OrderRepository
+	XenditTransactionRepository     *repository.XenditTransctionRepository
+	UserRepository                  *repository.UserRepository
+	WalletRepository                *repository.WalletRepository
+	WalletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository
+	OrderRefundRepository           *repository.OrderRefundRepository
+	XenditPayoutRepository          *repository.XenditPayoutRepository
+	PayoutRepository                *repository.PayoutRepository
+	ApplicationRepository           *repository.ApplicationRepository
+	NotificationRepository          *repository.NotificationRepository
+	Email                           *mailer.EmailWorker
+	WalletConfig                    *model.WalletConfig
+	OrderPaymentLegRepository       *repository.OrderPaymentLegRepository
+	WalletTransactionRepository     *repository.WalletTransactionRepository
 }
 
 func NewXenditCallbackUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
@@ -41,21 +47,29 @@ func NewXenditCallbackUseCase(db *gorm.DB, log *logrus.Logger, validate *validat
 	xenditClient *xendit.APIClient, xenditPayoutRepository *repository.XenditPayoutRepository,
 	userRepository *repository.UserRepository, walletRepository *repository.WalletRepository,
 	payoutRepository *repository.PayoutRepository, applicationRepository *repository.ApplicationRepository,
-	notificationRepository *repository.NotificationRepository, email *mailer.EmailWorker) *XenditCallbackUseCase {
+	notificationRepository *repository.NotificationRepository, email *mailer.EmailWorker,
+	walletConfig *model.WalletConfig, walletWithdrawRequestRepository *repository.WalletWithdrawRequestRepository,
+	orderRefundRepository *repository.OrderRefundRepository, orderPaymentLegRepository *repository.OrderPaymentLegRepository,
+	walletTransactionRepository *repository.WalletTransactionRepository) *XenditCallbackUseCase {
 	return &XenditCallbackUseCase{
-		DB:                          db,
-		Log:                         log,
-		Validate:                    validate,
-		OrderRepository:             orderRepository,
-		XenditTransactionRepository: xenditTransactionRepository,
-		XenditPayoutRepository:      xenditPayoutRepository,
-		XenditClient:                xenditClient,
-		UserRepository:              userRepository,
-		WalletRepository:            walletRepository,
-		PayoutRepository:            payoutRepository,
-		ApplicationRepository:       applicationRepository,
-		NotificationRepository:      notificationRepository,
-		Email:                       email,
+		DB:                              db,
+		Log:                             log,
+		Validate:                        validate,
+		OrderRepository:                 orderRepository,
+		XenditTransactionRepository:     xenditTransactionRepository,
+		XenditPayoutRepository:          xenditPayoutRepository,
+		XenditClient:                    xenditClient,
+		UserRepository:                  userRepository,
+		WalletRepository:                walletRepository,
+		PayoutRepository:                payoutRepository,
+		ApplicationRepository:           applicationRepository,
+		NotificationRepository:          notificationRepository,
+		Email:                           email,
+		WalletConfig:                    walletConfig,
+		WalletWithdrawRequestRepository: walletWithdrawRequestRepository,
+		OrderRefundRepository:           orderRefundRepository,
+		OrderPaymentLegRepository:       orderPaymentLegRepository,
+		WalletTransactionRepository:     walletTransactionRepository,
 	}
 }
 
@@ -76,12 +90,22 @@ func (c *XenditCallbackUseCase) UpdateStatusPaymentRequestCallback(ctx *fiber.Ct
 		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get xendit transaction from database : %+v", err))
 	}
 
-	if count > 0 {
+	status := xenditCallbackStatus(request.Event, request.Data.Status)
+	if count > 0 && status == "" {
+		c.Log.Infof("ignore xendit %s callback with status %s", request.Event, request.Data.Status)
+	}
+
+	// status final (sudah dibayar, gagal, dll) tidak boleh diubah lagi oleh callback yang datang terlambat
+	if count > 0 && status != "" && xenditIsFinalStatus(newXenditTransaction.Status) && newXenditTransaction.Status != status {
+		c.Log.Infof("ignore xendit %s callback, transaction %s already %s", request.Event, newXenditTransaction.ID, newXenditTransaction.Status)
+		status = ""
+	}
+
+	if count > 0 && status != "" {
 		// update datanya
-		if newXenditTransaction.Status != request.Data.Status {
+		if newXenditTransaction.Status != status {
 			// update statusnya
 			updatedAt := request.Data.UpdatedAt
-			status := request.Data.Status
 			orderId := newXenditTransaction.OrderId
 			updateXenditTransaction := map[string]any{
 				"status":     status,
@@ -98,198 +122,385 @@ func (c *XenditCallbackUseCase) UpdateStatusPaymentRequestCallback(ctx *fiber.Ct
 			}
 
 			var payment_status enum_state.PaymentStatus
-			var is_send_email bool
-			var email_subject string
 			if status == string(payment_request.PAYMENTREQUESTSTATUS_SUCCEEDED) {
-				is_send_email = true
 				payment_status = enum_state.PAID_PAYMENT
-				email_subject = "Payment Successfull"
-				if request.Lang == enum_state.INDONESIA {
-					email_subject = "Pembayaran Berhasil"
-				}
 			}
 
 			if status == string(payment_request.PAYMENTREQUESTSTATUS_CANCELED) {
-				is_send_email = true
 				payment_status = enum_state.CANCELLED_PAYMENT
-				email_subject = "Payment Cancelled"
-				if request.Lang == enum_state.INDONESIA {
-					email_subject = "Pembayaran Dibatalkan"
-				}
 			}
 
 			if status == string(payment_request.PAYMENTREQUESTSTATUS_FAILED) {
-				is_send_email = true
 				payment_status = enum_state.FAILED_PAYMENT
-				email_subject = "Payment Failed"
-				if request.Lang == enum_state.INDONESIA {
-					email_subject = "Pembayaran Gagal"
-				}
 			}
 
 			if status == string(payment_request.PAYMENTREQUESTSTATUS_EXPIRED) {
-				is_send_email = true
 				payment_status = enum_state.EXPIRED_PAYMENT
-				email_subject = "Payment Expired"
-				if request.Lang == enum_state.INDONESIA {
-					email_subject = "Pembayaran Kadaluwarsa"
-				}
 			}
 
 			if status == string(payment_request.PAYMENTREQUESTSTATUS_PENDING) {
 				payment_status = enum_state.PENDING_PAYMENT
 			}
 
-			updateOrderStatus := map[string]any{
-				"payment_status": payment_status,
-				"updated_at":     updatedAt.ToTime(),
+			updateOrderPaymentStatus := &model.UpdateOrderPaymentStatus{
+				OrderId:         orderId,
+				PaymentStatus:   payment_status,
+				UpdatedAt:       updatedAt.ToTime(),
+				Lang:            request.Lang,
+				TimeZone:        request.TimeZone,
+				BaseFrontEndURL: request.BaseFrontEndURL,
 			}
 
-			newOrder := new(entity.Order)
-			newOrder.ID = orderId
-			if err := c.OrderRepository.UpdateCustomColumns(tx, newOrder, updateOrderStatus); err != nil {
-				c.Log.Warnf("failed to update order status into database : %+v", err)
-				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update order status into database : %+v", err))
+			if err := c.UpdateOrderPaymentStatus(ctx, tx, updateOrderPaymentStatus); err != nil {
+				return err
 			}
+		}
+	}
 
-			if is_send_email {
-				if err := c.OrderRepository.FindWith2Preloads(tx, newOrder, "OrderProducts", "OrderProducts.Product"); err != nil {
-					c.Log.Warnf("failed to find newly created order : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find newly created order : %+v", err))
-				}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
 
-				productsSelected := []map[string]any{}
-				for _, product := range newOrder.OrderProducts {
-					var productImageBase64 string
-					productImagePath := fmt.Sprintf("../uploads/images/products/%s", product.ProductFirstImagePosition)
-					productImageBase64, err := helper_others.ImageToBase64(productImagePath)
-					if err != nil {
-						c.Log.Warnf("failed to convert product image to base64 : %+v", err)
-						return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert product image to base64 : %+v", err))
-					}
+	return nil
+}
 
-					productImage := map[string]any{
-						"ProductImageFilename": product.ProductFirstImagePosition,
-						"ProductImage":         productImageBase64,
-						"ProductName":          product.ProductName,
-						"Quantity":             product.Quantity,
-						"Price":                helper_others.FormatNumberFloat32(product.Price),
-					}
+// UpdateStatusPaymentRequestFromWebhook menerjemahkan webhook payment gateway ke bentuk callback payment request xendit,
+// dipakai oleh webhook maupun rekonsiliasi status pembayaran agar status diproses lewat alur yang sama
+func (c *XenditCallbackUseCase) UpdateStatusPaymentRequestFromWebhook(ctx *fiber.Ctx, webhook *model.PaymentGatewayWebhook, baseFrontEndURL string) error {
+	var requestData model.XenditGetPaymentRequestCallbackStatus
+	requestData.Event = webhook.EventType
+	requestData.Data.PaymentMethod.ID = webhook.PaymentMethodId
+	requestData.Data.Status = webhook.Status
+	requestData.Data.Metadata = webhook.Metadata
+	requestData.Data.UpdatedAt = helper_others.TimeRFC3339(webhook.UpdatedAt)
+	lang, _ := requestData.Data.Metadata["lang"].(string)
+	if lang == "" {
+		lang = "en"
+	}
+	requestData.Lang = enum_state.Languange(lang)
+	timeZone, _ := requestData.Data.Metadata["time_zone"].(string)
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	requestData.TimeZone = *loc
+	requestData.BaseFrontEndURL = baseFrontEndURL
+	return c.UpdateStatusPaymentRequestCallback(ctx, &requestData)
+}
 
-					productsSelected = append(productsSelected, productImage)
-				}
+// xenditCallbackStatus menyamakan status dari berbagai bentuk callback (payment_request.*, payment.*, payment_method.*)
+// ke status payment request, status kosong berarti callback tidak mengubah status transaksi
+func xenditCallbackStatus(event string, status string) string {
+	if strings.HasPrefix(event, "payment_method.") {
+		// status payment method e-wallet: ACTIVE, INACTIVE, PENDING, REQUIRES_ACTION, EXPIRED, FAILED
+		switch status {
+		case string(payment_request.PAYMENTREQUESTSTATUS_EXPIRED), string(payment_request.PAYMENTREQUESTSTATUS_FAILED):
+			return status
+		default:
+			return ""
+		}
+	}
 
-				newApp := new(entity.Application)
-				if err := c.ApplicationRepository.FindFirst(tx, newApp); err != nil {
-					c.Log.Warnf("failed to find application from database : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find application from database : %+v", err))
-				}
-				if newApp.LogoFilename == "" {
-					c.Log.Warnf("application logo has not uploaded yet!")
-					return fiber.NewError(fiber.StatusBadRequest, "application logo has not uploaded yet!")
-				}
+	switch status {
+	case string(payment_request.PAYMENTREQUESTSTATUS_REQUIRES_ACTION), string(payment_request.PAYMENTREQUESTSTATUS_AWAITING_CAPTURE):
+		// e-wallet masih menunggu customer menyelesaikan pembayaran di aplikasi
+		return string(payment_request.PAYMENTREQUESTSTATUS_PENDING)
+	case string(payment_request.PAYMENTREQUESTSTATUS_PENDING), string(payment_request.PAYMENTREQUESTSTATUS_SUCCEEDED),
+		string(payment_request.PAYMENTREQUESTSTATUS_FAILED), string(payment_request.PAYMENTREQUESTSTATUS_CANCELED),
+		string(payment_request.PAYMENTREQUESTSTATUS_EXPIRED):
+		return status
+	default:
+		return ""
+	}
+}
 
-				logoImagePath := fmt.Sprintf("../uploads/images/application/%s", newApp.LogoFilename)
-				logoImageBase64, err := helper_others.ImageToBase64(logoImagePath)
-				if err != nil {
-					c.Log.Warnf("failed to convert logo to base64 : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert logo to base64 : %+v", err))
-				}
+func xenditIsFinalStatus(status string) bool {
+	return status != "" && status != string(payment_request.PAYMENTREQUESTSTATUS_PENDING) && status != string(payment_request.PAYMENTREQUESTSTATUS_REQUIRES_ACTION)
+}
 
-				newMail := new(model.Mail)
-				newMail.To = []string{newOrder.Email}
-				newMail.Subject = email_subject
-				baseTemplatePath := "internal/templates/base_template_email1.html"
-				childPath := fmt.Sprintf("internal/templates/%s/email/order_payment.html", request.Lang)
-				tmpl, err := template.ParseFiles(baseTemplatePath, childPath)
-				if err != nil {
-					c.Log.Warnf("failed to parse template file html : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
-				}
+// UpdateOrderPaymentStatus memperbarui payment status order lalu mengirim email dan notifikasi ke customer,
+// dipakai oleh callback xendit maupun payment gateway lain (midtrans)
+func (c *XenditCallbackUseCase) UpdateOrderPaymentStatus(ctx *fiber.Ctx, tx *gorm.DB, request *model.UpdateOrderPaymentStatus) error {
+	var is_send_email bool
+	var email_subject string
+	if request.PaymentStatus == enum_state.PAID_PAYMENT {
+		is_send_email = true
+		email_subject = "Payment Successfull"
+		if request.Lang == enum_state.INDONESIA {
+			email_subject = "Pembayaran Berhasil"
+		}
+	}
 
-				paymentLink := fmt.Sprintf("%s/payment/orders/%d/details", request.BaseFrontEndURL, newOrder.ID)
-				orderTrackingURL := fmt.Sprintf("%s/orders/%d/details", request.BaseFrontEndURL, newOrder.ID)
-				bodyBuilder := new(strings.Builder)
-				err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]any{
-					"CustomerName":     newOrder.FirstName + " " + newOrder.LastName,
-					"Invoice":          newOrder.Invoice,
-					"Date":             newOrder.CreatedAt.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
-					"PaymentMethod":    string(newOrder.PaymentMethod),
-					"Items":            productsSelected,
-					"LogoImage":        logoImageBase64,
-					"CompanyTitle":     newApp.AppName,
-					"TotalAmount":      helper_others.FormatNumberFloat32(newOrder.TotalFinalPrice),
-					"Year":             time.Now().Format("2006"),
-					"CustomerNotes":    newOrder.Note,
-					"ShippingMethod":   newOrder.IsDelivery,
-					"ShippingCost":     helper_others.FormatNumberFloat32(newOrder.DeliveryCost),
-					"ServiceFee":       helper_others.FormatNumberFloat32(newOrder.ServiceFee),
-					"Discount":         helper_others.FormatNumberFloat32(newOrder.TotalDiscount),
-					"Subject":          newMail.Subject,
-					"PaymentStatus":    newOrder.PaymentStatus,
-					"PaymentLink":      paymentLink,
-					"OrderTrackingURL": orderTrackingURL,
-				})
-				if err != nil {
-					c.Log.Warnf("failed to execute template file html : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
-				}
-				newMail.Template = *bodyBuilder
-				c.Email.Mailer.SenderName = fmt.Sprintf("System %s", newApp.AppName)
-				// send email
-				select {
-				case c.Email.MailQueue <- *newMail:
-				default:
-					c.Log.Warnf("email queue full, failed to send to %s", newOrder.Email)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", newOrder.Email))
-				}
+	if request.PaymentStatus == enum_state.CANCELLED_PAYMENT {
+		is_send_email = true
+		email_subject = "Payment Cancelled"
+		if request.Lang == enum_state.INDONESIA {
+			email_subject = "Pembayaran Dibatalkan"
+		}
+	}
 
-				newNotification := new(entity.Notification)
-				newNotification.UserID = newOrder.UserId
-				newNotification.Title = newMail.Subject
-				newNotification.IsRead = false
-				newNotification.Type = enum_state.TRANSACTION
-				baseTemplatePath = "internal/templates/base_template_notification1.html"
-				childPath = fmt.Sprintf("internal/templates/%s/notification/order_payment.html", request.Lang)
-				tmpl, err = template.ParseFiles(baseTemplatePath, childPath)
-				if err != nil {
-					c.Log.Warnf("failed to parse template file html : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
-				}
+	if request.PaymentStatus == enum_state.FAILED_PAYMENT {
+		is_send_email = true
+		email_subject = "Payment Failed"
+		if request.Lang == enum_state.INDONESIA {
+			email_subject = "Pembayaran Gagal"
+		}
+	}
 
-				logoImagePath = fmt.Sprintf("%s://%s/api/image/application/%s", ctx.Protocol(), ctx.Hostname(), newApp.LogoFilename)
-				bodyBuilder = new(strings.Builder)
-				err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]string{
-					"FirstName":        newOrder.FirstName,
-					"Year":             time.Now().Format("2006"),
-					"CompanyName":      newApp.AppName,
-					"LogoImagePath":    logoImagePath,
-					"Date":             newOrder.CreatedAt.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
-					"Invoice":          newOrder.Invoice,
-					"PaymentMethod":    string(newOrder.PaymentMethod),
-					"Subject":          newMail.Subject,
-					"PaymentStatus":    string(newOrder.PaymentStatus),
-					"PaymentLink":      paymentLink,
-					"OrderTrackingURL": orderTrackingURL,
-				})
+	if request.PaymentStatus == enum_state.EXPIRED_PAYMENT {
+		is_send_email = true
+		email_subject = "Payment Expired"
+		if request.Lang == enum_state.INDONESIA {
+			email_subject = "Pembayaran Kadaluwarsa"
+		}
+	}
 
-				if err != nil {
-					c.Log.Warnf("failed to execute template file html : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
-				}
+	updateOrderStatus := map[string]any{
+		"payment_status": request.PaymentStatus,
+		"updated_at":     request.UpdatedAt,
+	}
+
+	newOrder := new(entity.Order)
+	newOrder.ID = request.OrderId
+	if err := c.OrderRepository.UpdateCustomColumns(tx, newOrder, updateOrderStatus); err != nil {
+		c.Log.Warnf("failed to update order status into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update order status into database : %+v", err))
+	}
+
+	if err := c.settleWalletHold(tx, request.OrderId, request.PaymentStatus); err != nil {
+		return err
+	}
+
+	if is_send_email {
+		if err := c.OrderRepository.FindWith2Preloads(tx, newOrder, "OrderProducts", "OrderProducts.Product"); err != nil {
+			c.Log.Warnf("failed to find newly created order : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find newly created order : %+v", err))
+		}
+
+		productsSelected := []map[string]any{}
+		for _, product := range newOrder.OrderProducts {
+			var productImageBase64 string
+			productImagePath := fmt.Sprintf("../uploads/images/products/%s", product.ProductFirstImagePosition)
+			productImageBase64, err := helper_others.ImageToBase64(productImagePath)
+			if err != nil {
+				c.Log.Warnf("failed to convert product image to base64 : %+v", err)
+				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert product image to base64 : %+v", err))
+			}
+
+			productImage := map[string]any{
+				"ProductImageFilename": product.ProductFirstImagePosition,
+				"ProductImage":         productImageBase64,
+				"ProductName":          product.ProductName,
+				"Quantity":             product.Quantity,
+				"Price":                helper_others.FormatNumberFloat32(product.Price),
+			}
+
+			productsSelected = append(productsSelected, productImage)
+		}
+
+		newApp := new(entity.Application)
+		if err := c.ApplicationRepository.FindFirst(tx, newApp); err != nil {
+			c.Log.Warnf("failed to find application from database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find application from database : %+v", err))
+		}
+		if newApp.LogoFilename == "" {
+			c.Log.Warnf("application logo has not uploaded yet!")
+			return fiber.NewError(fiber.StatusBadRequest, "application logo has not uploaded yet!")
+		}
+
+		logoImagePath := fmt.Sprintf("../uploads/images/application/%s", newApp.LogoFilename)
+		logoImageBase64, err := helper_others.ImageToBase64(logoImagePath)
+		if err != nil {
+			c.Log.Warnf("failed to convert logo to base64 : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to convert logo to base64 : %+v", err))
+		}
+
+		newMail := new(model.Mail)
+		newMail.To = []string{newOrder.Email}
+		newMail.Subject = email_subject
+		baseTemplatePath := "internal/templates/base_template_email1.html"
+		childPath := fmt.Sprintf("internal/templates/%s/email/order_payment.html", request.Lang)
+		tmpl, err := template.ParseFiles(baseTemplatePath, childPath)
+		if err != nil {
+			c.Log.Warnf("failed to parse template file html : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
+		}
+
+		paymentLink := fmt.Sprintf("%s/payment/orders/%d/details", request.BaseFrontEndURL, newOrder.ID)
+		orderTrackingURL := fmt.Sprintf("%s/orders/%d/details", request.BaseFrontEndURL, newOrder.ID)
+		bodyBuilder := new(strings.Builder)
+		err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]any{
+			"CustomerName":     newOrder.FirstName + " " + newOrder.LastName,
+			"Invoice":          newOrder.Invoice,
+			"Date":             newOrder.CreatedAt.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
+			"PaymentMethod":    string(newOrder.PaymentMethod),
+			"Items":            productsSelected,
+			"LogoImage":        logoImageBase64,
+			"CompanyTitle":     newApp.AppName,
+			"TotalAmount":      helper_others.FormatNumberFloat32(newOrder.TotalFinalPrice),
+			"Year":             time.Now().Format("2006"),
+			"CustomerNotes":    newOrder.Note,
+			"ShippingMethod":   newOrder.IsDelivery,
+			"ShippingCost":     helper_others.FormatNumberFloat32(newOrder.DeliveryCost),
+			"ServiceFee":       helper_others.FormatNumberFloat32(newOrder.ServiceFee),
+			"Discount":         helper_others.FormatNumberFloat32(newOrder.TotalDiscount),
+			"Subject":          newMail.Subject,
+			"PaymentStatus":    newOrder.PaymentStatus,
+			"PaymentLink":      paymentLink,
+			"OrderTrackingURL": orderTrackingURL,
+		})
+		if err != nil {
+			c.Log.Warnf("failed to execute template file html : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
+		}
+		newMail.Template = *bodyBuilder
+		c.Email.Mailer.SenderName = fmt.Sprintf("System %s", newApp.AppName)
+		// send email
+		select {
+		case c.Email.MailQueue <- *newMail:
+		default:
+			c.Log.Warnf("email queue full, failed to send to %s", newOrder.Email)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("email queue full, failed to send to %s", newOrder.Email))
+		}
+
+		newNotification := new(entity.Notification)
+		newNotification.UserID = newOrder.UserId
+		newNotification.Title = newMail.Subject
+		newNotification.IsRead = false
+		newNotification.Type = enum_state.TRANSACTION
+		baseTemplatePath = "internal/templates/base_template_notification1.html"
+		childPath = fmt.Sprintf("internal/templates/%s/notification/order_payment.html", request.Lang)
+		tmpl, err = template.ParseFiles(baseTemplatePath, childPath)
+		if err != nil {
+			c.Log.Warnf("failed to parse template file html : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse template file html : %+v", err))
+		}
+
+		logoImagePath = fmt.Sprintf("%s://%s/api/image/application/%s", ctx.Protocol(), ctx.Hostname(), newApp.LogoFilename)
+		bodyBuilder = new(strings.Builder)
+		err = tmpl.ExecuteTemplate(bodyBuilder, "base", map[string]string{
+			"FirstName":        newOrder.FirstName,
+			"Year":             time.Now().Format("2006"),
+			"CompanyName":      newApp.AppName,
+			"LogoImagePath":    logoImagePath,
+			"Date":             newOrder.CreatedAt.In(&request.TimeZone).Format("02 Jan 2006 15:04 MST"),
+			"Invoice":          newOrder.Invoice,
+			"PaymentMethod":    string(newOrder.PaymentMethod),
+			"Subject":          newMail.Subject,
+			"PaymentStatus":    string(newOrder.PaymentStatus),
+			"PaymentLink":      paymentLink,
+			"OrderTrackingURL": orderTrackingURL,
+		})
+
+		if err != nil {
+			c.Log.Warnf("failed to execute template file html : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to execute template file html : %+v", err))
+		}
+
+		newNotification.BodyContent = bodyBuilder.String()
+		if err := c.NotificationRepository.Create(tx, newNotification); err != nil {
+			c.Log.Warnf("failed to create notification into database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create notification into database : %+v", err))
+		}
+	}
+
+	return nil
+}
+
+// settleWalletHold menyelesaikan saldo wallet yang ditahan untuk split payment,
+// saldo dipotong permanen jika pembayaran berhasil dan dikembalikan jika gagal / kadaluwarsa / dibatalkan
+func (c *XenditCallbackUseCase) settleWalletHold(tx *gorm.DB, orderId uint64, paymentStatus enum_state.PaymentStatus) error {
+	var walletLegStatus, gatewayLegStatus enum_state.OrderPaymentLegStatus
+	var walletTransactionStatus enum_state.WalletTransactionStatus
+	switch paymentStatus {
+	case enum_state.PAID_PAYMENT:
+		walletLegStatus = enum_state.ORDER_PAYMENT_LEG_PAID
+		gatewayLegStatus = enum_state.ORDER_PAYMENT_LEG_PAID
+		walletTransactionStatus = enum_state.WALLET_TRANSACTION_STATUS_COMPLETED
+	case enum_state.CANCELLED_PAYMENT, enum_state.FAILED_PAYMENT, enum_state.EXPIRED_PAYMENT:
+		walletLegStatus = enum_state.ORDER_PAYMENT_LEG_RELEASED
+		gatewayLegStatus = enum_state.ORDER_PAYMENT_LEG_FAILED
+		walletTransactionStatus = enum_state.WALLET_TRANSACTION_STATUS_CANCELLED
+	default:
+		return nil
+	}
+
+	walletLeg := new(entity.OrderPaymentLeg)
+	if err := c.OrderPaymentLegRepository.FindHeldWalletLegByOrderId(tx, walletLeg, orderId); err != nil {
+		c.Log.Warnf("failed to find held wallet payment leg : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find held wallet payment leg : %+v", err))
+	}
+
+	// order tidak memakai split payment atau saldonya sudah diproses sebelumnya
+	if walletLeg.ID == 0 {
+		return nil
+	}
+
+	if walletLeg.WalletTransactionId != nil {
+		holdTransaction := new(entity.WalletTransactions)
+		holdTransaction.ID = *walletLeg.WalletTransactionId
+		if err := c.WalletTransactionRepository.FindById(tx, holdTransaction); err != nil {
+			c.Log.Warnf("failed to find wallet transaction by id : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet transaction by id : %+v", err))
+		}
 
-				newNotification.BodyContent = bodyBuilder.String()
-				if err := c.NotificationRepository.Create(tx, newNotification); err != nil {
-					c.Log.Warnf("failed to create notification into database : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create notification into database : %+v", err))
+		if walletLegStatus == enum_state.ORDER_PAYMENT_LEG_RELEASED {
+			// wallet dikunci agar saldo yang dikembalikan tidak menimpa perubahan saldo dari transaksi lain
+			currentWallet := new(entity.Wallet)
+			if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, currentWallet, holdTransaction.UserId); err != nil {
+				c.Log.Warnf("failed to find wallet by user id from database : %+v", err)
+				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id from database : %+v", err))
+			}
+
+			releaseStatus, err := helper_others.CreditWalletBalance(tx, currentWallet, walletLeg.Amount, c.WalletConfig.HoldCreditsWhenFrozen)
+			if err != nil {
+				c.Log.Warnf("failed to release held wallet balance : %+v", err)
+				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to release held wallet balance : %+v", err))
+			}
+
+			// wallet sedang dibekukan, saldo dicatat sebagai credit pending dan masuk saat wallet diaktifkan kembali
+			if releaseStatus == enum_state.WALLET_TRANSACTION_STATUS_PENDING {
+				newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
+				newSaveWalletTransaction.DB = tx
+				newSaveWalletTransaction.UserId = holdTransaction.UserId
+				newSaveWalletTransaction.OrderId = &orderId
+				newSaveWalletTransaction.Amount = walletLeg.Amount
+				newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_CREDIT
+				newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_ORDER_REFUND
+				newSaveWalletTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
+				newSaveWalletTransaction.Status = releaseStatus
+				newSaveWalletTransaction.ReferenceNumber = holdTransaction.ReferenceNumber
+				newSaveWalletTransaction.Note = fmt.Sprintf("Release held wallet balance of order %s", holdTransaction.ReferenceNumber)
+				newSaveWalletTransaction.AdminNote = "Wallet is frozen, balance will be credited when the wallet is activated"
+				if err := helper_others.SaveWalletTransaction(newSaveWalletTransaction); err != nil {
+					c.Log.Warnf("failed to save wallet transaction : %+v", err)
+					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
 				}
 			}
 		}
+
+		now := time.Now()
+		updateWalletTransaction := map[string]any{
+			"status":       walletTransactionStatus,
+			"processed_at": now,
+			"updated_at":   now,
+		}
+
+		if err := c.WalletTransactionRepository.UpdateCustomColumns(tx, &entity.WalletTransactions{ID: holdTransaction.ID}, updateWalletTransaction); err != nil {
+			c.Log.Warnf("failed to update wallet transaction status : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet transaction status : %+v", err))
+		}
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("failed to commit transaction : %+v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	if err := c.OrderPaymentLegRepository.UpdateCustomColumns(tx, &entity.OrderPaymentLeg{ID: walletLeg.ID}, map[string]any{"status": walletLegStatus}); err != nil {
+		c.Log.Warnf("failed to update wallet payment leg status : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet payment leg status : %+v", err))
+	}
+
+	if err := c.OrderPaymentLegRepository.UpdatePendingGatewayLegStatusByOrderId(tx, new(entity.OrderPaymentLeg), orderId, string(gatewayLegStatus)); err != nil {
+		c.Log.Warnf("failed to update gateway payment leg status : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update gateway payment leg status : %+v", err))
 	}
 
 	return nil
@@ -305,17 +516,23 @@ func (c *XenditCallbackUseCase) UpdateStatusPayoutRequestCallback(ctx *fiber.Ctx
 		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
+	// baris payout dikunci agar callback untuk payout yang sama diproses bergantian
 	newXenditPayout := new(entity.XenditPayout)
 	newXenditPayout.ID = request.Data.PayoutId
-	count, err := c.XenditPayoutRepository.FindFirstAndCount(tx, newXenditPayout)
-	if err != nil {
-		c.Log.Warnf("failed to get xendit transaction from database : %+v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get xendit transaction from database : %+v", err))
+	count := int64(1)
+	if err := c.XenditPayoutRepository.FindByIdForUpdate(tx, newXenditPayout); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("failed to get xendit transaction from database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get xendit transaction from database : %+v", err))
+		}
+		count = 0
 	}
 
 	if count > 0 {
 		// update datanya
 		if newXenditPayout.Status != request.Data.Status {
+			// dana hanya dikembalikan sekali, yaitu saat payout yang belum final berubah menjadi gagal
+			previousStatus := newXenditPayout.Status
 			// update statusnya
 			updatedAt := request.Data.UpdatedAt
 			status := request.Data.Status
@@ -324,6 +541,7 @@ func (c *XenditCallbackUseCase) UpdateStatusPayoutRequestCallback(ctx *fiber.Ctx
 				"updated_at": updatedAt,
 			}
 
+			payoutUserId := newXenditPayout.UserID
 			*newXenditPayout = entity.XenditPayout{
 				ID: newXenditPayout.ID,
 			}
@@ -333,7 +551,40 @@ func (c *XenditCallbackUseCase) UpdateStatusPayoutRequestCallback(ctx *fiber.Ctx
 				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update xendit payout status into database : %+v", err))
 			}
 
-			if request.Data.Status == "SUCCEEDED" {
+			isPayoutFailed := isFailedXenditPayoutStatus(request.Data.Status)
+			shouldRefund := isPayoutFailed && !isFinalXenditPayoutStatus(previousStatus)
+
+			// payout bisa berasal dari withdraw request customer yang dicairkan otomatis
+			newWithdrawRequest := new(entity.WalletWithdrawRequests)
+			withdrawCount, err := c.WalletWithdrawRequestRepository.FindAndCountByXenditPayoutId(tx, newWithdrawRequest, newXenditPayout.ID)
+			if err != nil {
+				c.Log.Warnf("failed to get wallet withdraw request by xendit payout id from database : %+v", err)
+				return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get wallet withdraw request by xendit payout id from database : %+v", err))
+			}
+
+			if withdrawCount > 0 && isFinalWithdrawRequestStatus(newWithdrawRequest.Status) {
+				shouldRefund = false
+			} else if withdrawCount > 0 {
+				var withdrawStatus enum_state.WalletWithdrawRequest
+				if request.Data.Status == "SUCCEEDED" {
+					withdrawStatus = enum_state.WALLET_WITHDRAW_REQUEST_STATUS_COMPLETED
+				} else if isPayoutFailed {
+					withdrawStatus = enum_state.WALLET_WITHDRAW_REQUEST_STATUS_FAILED
+				}
+
+				if withdrawStatus != "" {
+					updateWithdrawStatus := map[string]any{
+						"status": withdrawStatus,
+					}
+
+					if err := c.WalletWithdrawRequestRepository.UpdateCustomColumns(tx, newWithdrawRequest, updateWithdrawStatus); err != nil {
+						c.Log.Warnf("failed to update wallet withdraw request status in the database : %+v", err)
+						return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet withdraw request status in the database : %+v", err))
+					}
+				}
+			}
+
+			if request.Data.Status == "SUCCEEDED" && withdrawCount < 1 {
 				// update tb_payout
 				newPayout := new(entity.Payout)
 				if err := c.PayoutRepository.FindFirstPayoutByXenditPayoutId(tx, newPayout, newXenditPayout.ID); err != nil {
@@ -357,39 +608,91 @@ func (c *XenditCallbackUseCase) UpdateStatusPayoutRequestCallback(ctx *fiber.Ctx
 				}
 			}
 
-			if request.Data.Status == "CANCELLED" || request.Data.Status == "failed" || request.Data.Status == "EXPIRED" || request.Data.Status == "REFUNDED" {
+			// payout dari admin dicatat di tb_payout, withdraw request tidak punya data payout
+			newPayout := new(entity.Payout)
+			if isPayoutFailed && withdrawCount < 1 {
+				if err := c.PayoutRepository.FindFirstPayoutByXenditPayoutId(tx, newPayout, newXenditPayout.ID); err != nil {
+					c.Log.Warnf("failed to get payout by xendit payout id from database : %+v", err)
+					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get payout by xendit payout id from database : %+v", err))
+				}
+
+				if newPayout.ID < 1 {
+					c.Log.Warnf("payout not found!")
+					return fiber.NewError(fiber.StatusNotFound, "payout not found!")
+				}
+
+				if newPayout.Status != enum_state.PAYOUT_PENDING && newPayout.Status != enum_state.PAYOUT_ACCEPTED {
+					shouldRefund = false
+				}
+			}
+
+			if shouldRefund {
 				// kembalikan saldonya
-				newUser := new(entity.User)
-				newUser.ID = newXenditPayout.UserID
-				if err := c.UserRepository.FindWithPreloads(tx, newUser, "Wallet"); err != nil {
+				newWallet := new(entity.Wallet)
+				if err := c.WalletRepository.FindFirstByUserIdForUpdate(tx, newWallet, payoutUserId); err != nil {
 					c.Log.Warnf("failed to find user wallet from database : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("ailed to find user wallet from database : %+v", err))
+					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find user wallet from database : %+v", err))
 				}
 
-				resultBalance := newUser.Wallet.Balance + request.Data.Amount
-				// update saldo
-				updateBalance := map[string]any{
-					"balance": resultBalance,
+				// nominal diambil dari data yang tersimpan, bukan dari body callback
+				// biaya withdraw request ikut dikembalikan karena dana tidak jadi dicairkan
+				payoutAmount := newPayout.Amount
+				refundAmount := newPayout.Amount
+				if withdrawCount > 0 {
+					payoutAmount = newWithdrawRequest.Amount
+					refundAmount = newWithdrawRequest.Amount + newWithdrawRequest.Fee
 				}
 
-				newWallet := new(entity.Wallet)
-				newWallet.ID = newUser.Wallet.ID
-				if err := c.WalletRepository.UpdateCustomColumns(tx, newWallet, updateBalance); err != nil {
+				// update saldo, ditahan dulu jika wallet sedang dibekukan
+				refundStatus, err := helper_others.CreditWalletBalance(tx, newWallet, refundAmount, c.WalletConfig.HoldCreditsWhenFrozen)
+				if err != nil {
 					c.Log.Warnf("failed to update wallet balance in the database : %+v", err)
 					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance in the database : %+v", err))
 				}
 
-				// update tb_payout
-				newPayout := new(entity.Payout)
-				if err := c.PayoutRepository.FindFirstPayoutByXenditPayoutId(tx, newPayout, newXenditPayout.ID); err != nil {
-					c.Log.Warnf("failed to get payout by xendit payout id from database : %+v", err)
-					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get payout by xendit payout id from database : %+v", err))
+				now := time.Now()
+				newSaveWalletTransaction := new(helper_others.SaveWalletTransactionRequest)
+				newSaveWalletTransaction.DB = tx
+				newSaveWalletTransaction.UserId = payoutUserId
+				newSaveWalletTransaction.OrderId = nil
+				newSaveWalletTransaction.Amount = payoutAmount
+				newSaveWalletTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_CREDIT
+				newSaveWalletTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW
+				newSaveWalletTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
+				newSaveWalletTransaction.Status = refundStatus
+				newSaveWalletTransaction.ReferenceNumber = newXenditPayout.ID
+				newSaveWalletTransaction.Note = fmt.Sprintf("Refund of %s payout %s", strings.ToLower(request.Data.Status), newXenditPayout.ID)
+				newSaveWalletTransaction.AdminNote = ""
+				newSaveWalletTransaction.ProcessedAt = &now
+				newSaveWalletTransaction.ProcessedBy = nil
+				if err := helper_others.SaveWalletTransaction(newSaveWalletTransaction); err != nil {
+					c.Log.Warnf("failed to save wallet transaction : %+v", err)
+					return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
 				}
 
-				if newPayout.ID < 1 {
-					c.Log.Warnf("payout not found!")
-					return fiber.NewError(fiber.StatusNotFound, "payout not found!")
-				} else {
+				if withdrawCount > 0 && newWithdrawRequest.Fee > 0 {
+					newSaveFeeTransaction := new(helper_others.SaveWalletTransactionRequest)
+					newSaveFeeTransaction.DB = tx
+					newSaveFeeTransaction.UserId = payoutUserId
+					newSaveFeeTransaction.OrderId = nil
+					newSaveFeeTransaction.Amount = newWithdrawRequest.Fee
+					newSaveFeeTransaction.FlowType = enum_state.WALLET_FLOW_TYPE_CREDIT
+					newSaveFeeTransaction.TransactionType = enum_state.WALLET_TRANSACTION_TYPE_WITHDRAW_FEE
+					newSaveFeeTransaction.PaymentMethod = enum_state.PAYMENT_METHOD_WALLET
+					newSaveFeeTransaction.Status = refundStatus
+					newSaveFeeTransaction.ReferenceNumber = newXenditPayout.ID
+					newSaveFeeTransaction.Note = fmt.Sprintf("Refund of withdraw fee for %s payout %s", strings.ToLower(request.Data.Status), newXenditPayout.ID)
+					newSaveFeeTransaction.AdminNote = ""
+					newSaveFeeTransaction.ProcessedAt = &now
+					newSaveFeeTransaction.ProcessedBy = nil
+					if err := helper_others.SaveWalletTransaction(newSaveFeeTransaction); err != nil {
+						c.Log.Warnf("failed to save wallet transaction : %+v", err)
+						return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to save wallet transaction : %+v", err))
+					}
+				}
+
+				// update tb_payout
+				if withdrawCount < 1 {
 					status := enum_state.PAYOUT_CANCELLED
 					if request.Data.Status == "failed" {
 						status = enum_state.PAYOUT_FAILED
@@ -420,3 +723,85 @@ func (c *XenditCallbackUseCase) UpdateStatusPayoutRequestCallback(ctx *fiber.Ctx
 
 	return nil
 }
+
+// isFailedXenditPayoutStatus true untuk status payout yang dananya tidak jadi dicairkan
+func isFailedXenditPayoutStatus(status string) bool {
+	switch status {
+	case "CANCELLED", "FAILED", "failed", "EXPIRED", "REFUNDED":
+		return true
+	default:
+		return false
+	}
+}
+
+// isFinalXenditPayoutStatus true jika payout sudah selesai diproses, dana untuk payout ini sudah
+// dicairkan atau sudah dikembalikan sehingga tidak boleh dikembalikan lagi
+func isFinalXenditPayoutStatus(status string) bool {
+	return status == "SUCCEEDED" || isFailedXenditPayoutStatus(status)
+}
+
+// isFinalWithdrawRequestStatus true jika withdraw request sudah selesai, dibatalkan atau ditolak
+func isFinalWithdrawRequestStatus(status enum_state.WalletWithdrawRequest) bool {
+	switch status {
+	case enum_state.WALLET_WITHDRAW_REQUEST_STATUS_COMPLETED, enum_state.WALLET_WITHDRAW_REQUEST_STATUS_FAILED, enum_state.WALLET_WITHDRAW_REQUEST_STATUS_CANCELLED, enum_state.WALLET_WITHDRAW_REQUEST_STATUS_REJECTED:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpdateStatusRefundCallback memperbarui status refund ke metode pembayaran asal,
+// refund yang sudah final (succeeded / failed) tidak diubah lagi oleh callback yang datang terlambat
+func (c *XenditCallbackUseCase) UpdateStatusRefundCallback(ctx *fiber.Ctx, request *model.XenditGetRefundCallbackStatus) error {
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("invalid request body : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
+	}
+
+	newOrderRefund := new(entity.OrderRefund)
+	if err := c.OrderRefundRepository.FirstOrderRefundByGatewayRefundId(tx, newOrderRefund, request.Data.RefundId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("order refund %s not found!", request.Data.RefundId)
+			return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order refund %s not found!", request.Data.RefundId))
+		}
+		c.Log.Warnf("failed to get order refund from database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to get order refund from database : %+v", err))
+	}
+
+	if newOrderRefund.Status != enum_state.REFUND_STATUS_PENDING {
+		return nil
+	}
+
+	var status enum_state.RefundStatus
+	switch request.Data.Status {
+	case "SUCCEEDED":
+		status = enum_state.REFUND_STATUS_SUCCEEDED
+	case "FAILED", "CANCELLED":
+		status = enum_state.REFUND_STATUS_FAILED
+	default:
+		// status lain masih diproses oleh xendit
+		return nil
+	}
+
+	updateOrderRefund := map[string]any{
+		"status":       status,
+		"failure_code": request.Data.FailureCode,
+		"processed_at": time.Time(request.Data.UpdatedAt),
+	}
+
+	if err := c.OrderRefundRepository.UpdateCustomColumns(tx, &entity.OrderRefund{ID: newOrderRefund.ID}, updateOrderRefund); err != nil {
+		c.Log.Warnf("failed to update order refund status into database : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update order refund status into database : %+v", err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	return nil
+}
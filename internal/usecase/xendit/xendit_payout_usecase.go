@@ -3,7 +3,9 @@ package usecase
 import (
 	"fmt"
 	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/interfaces"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
 	"seblak-bombom-restful-api/internal/repository"
@@ -25,12 +27,13 @@ type XenditPayoutUseCase struct {
 	XenditPayoutRepository *repository.XenditPayoutRepository
 	WalletRepository       *repository.WalletRepository
 	UserRepository         *repository.UserRepository
+	AuditLogger            interfaces.AuditLogger
 }
 
 func NewXenditPayoutUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
 	xenditPayoutRepository *repository.XenditPayoutRepository,
 	xenditClient *xendit.APIClient, walletRepository *repository.WalletRepository,
-	userRepository *repository.UserRepository) *XenditPayoutUseCase {
+	userRepository *repository.UserRepository, auditLogger interfaces.AuditLogger) *XenditPayoutUseCase {
 	return &XenditPayoutUseCase{
 		DB:                     db,
 		Log:                    log,
@@ -39,6 +42,7 @@ func NewXenditPayoutUseCase(db *gorm.DB, log *logrus.Logger, validate *validator
 		WalletRepository:       walletRepository,
 		UserRepository:         userRepository,
 		XenditClient:           xenditClient,
+		AuditLogger:            auditLogger,
 	}
 }
 
@@ -57,28 +61,48 @@ func (c *XenditPayoutUseCase) AddPayout(ctx *fiber.Ctx, request *model.CreateXen
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find wallet by user id : %+v", err))
 	}
 
-	if request.Amount > newUser.Wallet.Balance {
-		c.Log.Warnf("your balance is insufficient to perform this transaction!")
-		return nil, fiber.NewError(fiber.StatusBadRequest, "your balance is insufficient to perform this transaction!")
+	if newUser.Wallet == nil {
+		c.Log.Warnf("the selected wallet is not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "the selected wallet is not found!")
 	}
 
-	resultBalance := newUser.Wallet.Balance - request.Amount
-
-	// update saldo
-	updateBalance := map[string]any{
-		"balance": resultBalance,
+	if newUser.Wallet.Status == enum_state.INACIVE_WALLET {
+		c.Log.Warnf("your wallet is frozen and can't be used for this transaction!")
+		return nil, fiber.NewError(fiber.StatusForbidden, "your wallet is frozen and can't be used for this transaction!")
 	}
 
-	newWallet := new(entity.Wallet)
-	newWallet.ID = newUser.Wallet.ID
-	if err := c.WalletRepository.UpdateCustomColumns(tx, newWallet, updateBalance); err != nil {
-		c.Log.Warnf("failed to update wallet balance in the database : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance in the database : %+v", err))
+	// saldo withdraw request sudah dipotong sebelumnya, jangan dipotong dua kali
+	if !request.IsBalanceReserved {
+		if request.Amount > newUser.Wallet.Balance {
+			c.Log.Warnf("your balance is insufficient to perform this transaction!")
+			return nil, fiber.NewError(fiber.StatusBadRequest, "your balance is insufficient to perform this transaction!")
+		}
+
+		resultBalance := newUser.Wallet.Balance - request.Amount
+
+		// update saldo
+		updateBalance := map[string]any{
+			"balance": resultBalance,
+		}
+
+		newWallet := new(entity.Wallet)
+		newWallet.ID = newUser.Wallet.ID
+		if err := c.WalletRepository.UpdateCustomColumns(tx, newWallet, updateBalance); err != nil {
+			c.Log.Warnf("failed to update wallet balance in the database : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update wallet balance in the database : %+v", err))
+		}
 	}
 
 	milli := time.Now().UnixMilli()
 	idempotencyKey := fmt.Sprintf("disb-%d", milli)
+	if request.IdempotencyKey != "" {
+		idempotencyKey = request.IdempotencyKey
+	}
+
 	referenceId := fmt.Sprintf("payout-%d-%d", request.UserId, milli)
+	if request.ReferenceId != "" {
+		referenceId = request.ReferenceId
+	}
 	channelProperties := payout.NewDigitalPayoutChannelProperties(request.AccountNumber)
 	accountHolderName := payout.NewNullableString(&request.AccountHolderName)
 	channelProperties.AccountHolderName = *accountHolderName
@@ -116,6 +140,10 @@ func (c *XenditPayoutUseCase) AddPayout(ctx *fiber.Ctx, request *model.CreateXen
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to insert xendit payout into database : %+v", err))
 	}
 
+	if err := c.AuditLogger.Record(tx, enum_state.AUDIT_ACTION_CREATE, enum_state.AUDIT_ENTITY_XENDIT_PAYOUT, newXenditPayout.ID, nil, c.AuditLogger.Snapshot(tx, newXenditPayout)); err != nil {
+		return nil, err
+	}
+
 	return converter.XenditPayoutToResponse(newXenditPayout), nil
 }
 
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// event untuk status yang didapat dari polling payment gateway, bukan dari callback
+const xenditReconciliationEvent = "payment_request.reconciliation"
+
+type XenditReconciliationUseCase struct {
+	DB                          *gorm.DB
+	Log                         *logrus.Logger
+	XenditTransactionRepository *repository.XenditTransctionRepository
+	PaymentGateways             *payment_gateway.Registry
+	XenditCallbackUseCase       *XenditCallbackUseCase
+	FrontEndConfig              *model.FrontEndConfig
+	ReconciliationConfig        *model.PaymentReconciliationConfig
+}
+
+func NewXenditReconciliationUseCase(db *gorm.DB, log *logrus.Logger,
+	xenditTransactionRepository *repository.XenditTransctionRepository, paymentGateways *payment_gateway.Registry,
+	xenditCallbackUseCase *XenditCallbackUseCase, frontEndConfig *model.FrontEndConfig,
+	reconciliationConfig *model.PaymentReconciliationConfig) *XenditReconciliationUseCase {
+	return &XenditReconciliationUseCase{
+		DB:                          db,
+		Log:                         log,
+		XenditTransactionRepository: xenditTransactionRepository,
+		PaymentGateways:             paymentGateways,
+		XenditCallbackUseCase:       xenditCallbackUseCase,
+		FrontEndConfig:              frontEndConfig,
+		ReconciliationConfig:        reconciliationConfig,
+	}
+}
+
+// ReconcileOrder dipanggil admin untuk menyamakan status pembayaran satu order dengan payment gateway
+func (c *XenditReconciliationUseCase) ReconcileOrder(ctx *fiber.Ctx, orderId uint64) (*model.PaymentReconciliationResponse, error) {
+	newXenditTransaction := new(entity.XenditTransactions)
+	if err := c.XenditTransactionRepository.FirstXenditTransactionByOrderId(c.DB.WithContext(ctx.Context()), newXenditTransaction, orderId, "Order", "Order.OrderProducts"); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("xendit transaction for order %d not found!", orderId)
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("xendit transaction for order %d not found!", orderId))
+		}
+		c.Log.Warnf("failed to find xendit transaction by order id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find xendit transaction by order id : %+v", err))
+	}
+
+	return c.reconcile(ctx, newXenditTransaction)
+}
+
+// ReconcilePending mengecek semua transaksi yang masih pending ke payment gateway, dipakai oleh job berkala
+// untuk menangani callback yang tidak pernah sampai
+func (c *XenditReconciliationUseCase) ReconcilePending(ctx *fiber.Ctx) ([]model.PaymentReconciliationResponse, error) {
+	createdBefore := time.Now().Add(-c.ReconciliationConfig.MinAge)
+	pendingTransactions := new([]entity.XenditTransactions)
+	if err := c.XenditTransactionRepository.FindPendingXenditTransactions(c.DB.WithContext(ctx.Context()), pendingTransactions, createdBefore, c.ReconciliationConfig.BatchSize); err != nil {
+		c.Log.Warnf("failed to find pending xendit transactions : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find pending xendit transactions : %+v", err))
+	}
+
+	results := []model.PaymentReconciliationResponse{}
+	for i := range *pendingTransactions {
+		// satu transaksi yang gagal dicek tidak menghentikan pengecekan transaksi lainnya
+		result, err := c.reconcile(ctx, &(*pendingTransactions)[i])
+		if err != nil {
+			c.Log.Warnf("failed to reconcile xendit transaction %s : %+v", (*pendingTransactions)[i].ID, err)
+			continue
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+func (c *XenditReconciliationUseCase) reconcile(ctx *fiber.Ctx, xenditTransaction *entity.XenditTransactions) (*model.PaymentReconciliationResponse, error) {
+	if xenditTransaction.Order == nil {
+		c.Log.Warnf("order for xendit transaction %s not found!", xenditTransaction.ID)
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order for xendit transaction %s not found!", xenditTransaction.ID))
+	}
+
+	paymentGateway, err := c.PaymentGateways.Get(xenditTransaction.Order.PaymentGateway)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	resp, err := paymentGateway.GetPaymentStatus(ctx.Context(), xenditTransaction.ID)
+	if err != nil {
+		c.Log.Warnf("failed to find payment on %s : %+v", paymentGateway.Name(), err)
+		return nil, err
+	}
+
+	webhook, err := paymentStatusToWebhook(xenditTransaction, resp)
+	if err != nil {
+		c.Log.Warnf("failed to unmarshall xendit transaction metadata : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to unmarshall xendit transaction metadata : %+v", err))
+	}
+
+	if err := c.XenditCallbackUseCase.UpdateStatusPaymentRequestFromWebhook(ctx, webhook, c.FrontEndConfig.BaseURL); err != nil {
+		c.Log.Warnf("failed to update xendit transaction status : %+v", err)
+		return nil, err
+	}
+
+	currentXenditTransaction := new(entity.XenditTransactions)
+	currentXenditTransaction.ID = xenditTransaction.ID
+	if err := c.XenditTransactionRepository.FindWithPreloads(c.DB.WithContext(ctx.Context()), currentXenditTransaction, "Order"); err != nil {
+		c.Log.Warnf("failed to find xendit transaction : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find xendit transaction : %+v", err))
+	}
+
+	response := &model.PaymentReconciliationResponse{
+		OrderId:             xenditTransaction.OrderId,
+		XenditTransactionId: xenditTransaction.ID,
+		PreviousStatus:      xenditTransaction.Status,
+		GatewayStatus:       resp.Status,
+		CurrentStatus:       currentXenditTransaction.Status,
+		Updated:             currentXenditTransaction.Status != xenditTransaction.Status,
+	}
+
+	if currentXenditTransaction.Order != nil {
+		response.PaymentStatus = currentXenditTransaction.Order.PaymentStatus
+	}
+
+	return response, nil
+}
+
+// paymentStatusToWebhook mengubah hasil polling status pembayaran ke bentuk webhook,
+// sehingga hasil polling diproses lewat XenditCallbackUseCase seperti callback biasa
+func paymentStatusToWebhook(xenditTransaction *entity.XenditTransactions, resp *model.PaymentGatewayResponse) (*model.PaymentGatewayWebhook, error) {
+	// metadata bahasa dan zona waktu customer disimpan saat transaksi dibuat
+	metadata := resp.Metadata
+	if len(metadata) == 0 && len(xenditTransaction.Metadata) > 0 {
+		if err := json.Unmarshal(xenditTransaction.Metadata, &metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedAt := resp.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	return &model.PaymentGatewayWebhook{
+		EventType:       xenditReconciliationEvent,
+		PaymentId:       resp.ID,
+		PaymentMethodId: xenditTransaction.PaymentMethodId,
+		Status:          resp.Status,
+		RawStatus:       resp.RawStatus,
+		Metadata:        metadata,
+		UpdatedAt:       updatedAt,
+	}, nil
+}
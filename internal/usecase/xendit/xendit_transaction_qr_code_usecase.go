@@ -5,19 +5,18 @@ import (
 	"fmt"
 	"seblak-bombom-restful-api/internal/entity"
 	"seblak-bombom-restful-api/internal/helper/enum_state"
-	"seblak-bombom-restful-api/internal/helper/helper_others"
 	"seblak-bombom-restful-api/internal/model"
 	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/payment_gateway"
 	"time"
 
 	"seblak-bombom-restful-api/internal/repository"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"github.com/xendit/xendit-go/v6"
-	"github.com/xendit/xendit-go/v6/payment_request"
 	"gorm.io/gorm"
 )
 
@@ -25,21 +24,26 @@ type XenditTransactionQRCodeUseCase struct {
 	DB                          *gorm.DB
 	Log                         *logrus.Logger
 	Validate                    *validator.Validate
-	XenditClient                *xendit.APIClient
+	PaymentGateways             *payment_gateway.Registry
 	OrderRepository             *repository.OrderRepository
 	XenditTransactionRepository *repository.XenditTransctionRepository
+	XenditCallbackUseCase       *XenditCallbackUseCase
+	FrontEndConfig              *model.FrontEndConfig
 }
 
 func NewXenditTransactionQRCodeUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
 	orderRepository *repository.OrderRepository, xenditTransactionRepository *repository.XenditTransctionRepository,
-	xenditClient *xendit.APIClient) *XenditTransactionQRCodeUseCase {
+	paymentGateways *payment_gateway.Registry, xenditCallbackUseCase *XenditCallbackUseCase,
+	frontEndConfig *model.FrontEndConfig) *XenditTransactionQRCodeUseCase {
 	return &XenditTransactionQRCodeUseCase{
 		DB:                          db,
 		Log:                         log,
 		Validate:                    validate,
 		OrderRepository:             orderRepository,
 		XenditTransactionRepository: xenditTransactionRepository,
-		XenditClient:                xenditClient,
+		PaymentGateways:             paymentGateways,
+		XenditCallbackUseCase:       xenditCallbackUseCase,
+		FrontEndConfig:              frontEndConfig,
 	}
 }
 
@@ -53,69 +57,71 @@ func (c *XenditTransactionQRCodeUseCase) Add(ctx *fiber.Ctx, request *model.Crea
 	// get order id
 	selectedOrder := new(entity.Order)
 	selectedOrder.ID = request.OrderId
-	if err := c.OrderRepository.FindWithPreloads(tx, selectedOrder, "OrderProducts"); err != nil {
+	if err := c.OrderRepository.FindWith2Preloads(tx, selectedOrder, "OrderProducts", "PaymentLegs"); err != nil {
 		c.Log.Warnf("failed to find order by id : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id : %+v", err))
 	}
 
-	paymentRequestBasketItems := new([]payment_request.PaymentRequestBasketItem)
+	paymentGateway, err := c.PaymentGateways.Get(selectedOrder.PaymentGateway)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
+	}
+
+	paymentItems := []model.PaymentGatewayItem{}
 	for _, product := range selectedOrder.OrderProducts {
-		refId := strconv.FormatUint(product.ProductId, 10)
-		itemType := string(enum_state.ITEM_TYPE_PHYSICAL_PRODUCT)
-		paymentRequestBasketItem := &payment_request.PaymentRequestBasketItem{
-			ReferenceId: &refId,
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: strconv.FormatUint(product.ProductId, 10),
 			Name:        product.ProductName,
-			Currency:    string(payment_request.PAYMENTREQUESTCURRENCY_IDR),
-			Quantity:    float64(product.Quantity),
-			Price:       float64(product.Price),
 			Category:    product.Category,
-			Type:        &itemType,
-		}
-		*paymentRequestBasketItems = append(*paymentRequestBasketItems, *paymentRequestBasketItem)
+			Type:        string(enum_state.ITEM_TYPE_PHYSICAL_PRODUCT),
+			Quantity:    product.Quantity,
+			Price:       product.Price,
+		})
 	}
 
 	// cek apakah ada biaya pengiriman
 	if selectedOrder.DeliveryCost > 0 {
-		refId := fmt.Sprintf("DELIVERY/%s", strconv.FormatUint(selectedOrder.ID, 10))
-		itemType := string(enum_state.ITEM_TYPE_DELIVERY_FEE)
-		paymentRequestBasketItem := &payment_request.PaymentRequestBasketItem{
-			ReferenceId: &refId,
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: fmt.Sprintf("DELIVERY/%s", strconv.FormatUint(selectedOrder.ID, 10)),
 			Name:        "Delivery Cost",
-			Currency:    string(payment_request.PAYMENTREQUESTCURRENCY_IDR),
-			Quantity:    1,
-			Price:       float64(selectedOrder.DeliveryCost),
 			Category:    "delivery",
-			Type:        &itemType,
-		}
-		*paymentRequestBasketItems = append(*paymentRequestBasketItems, *paymentRequestBasketItem)
+			Type:        string(enum_state.ITEM_TYPE_DELIVERY_FEE),
+			Quantity:    1,
+			Price:       selectedOrder.DeliveryCost,
+		})
 	}
 
 	if selectedOrder.TotalDiscount > 0 {
-		refId := fmt.Sprintf("DISCOUNT/%s", strconv.FormatUint(selectedOrder.ID, 10))
-		itemType := string(enum_state.ITEM_TYPE_DISCOUNT)
-		paymentRequestBasketItem := &payment_request.PaymentRequestBasketItem{
-			ReferenceId: &refId,
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: fmt.Sprintf("DISCOUNT/%s", strconv.FormatUint(selectedOrder.ID, 10)),
 			Name:        "Discount",
-			Currency:    string(payment_request.PAYMENTREQUESTCURRENCY_IDR),
-			Quantity:    1,
-			Price:       float64(selectedOrder.TotalDiscount),
 			Category:    "discount",
-			Type:        &itemType,
-		}
-		*paymentRequestBasketItems = append(*paymentRequestBasketItems, *paymentRequestBasketItem)
+			Type:        string(enum_state.ITEM_TYPE_DISCOUNT),
+			Quantity:    1,
+			Price:       selectedOrder.TotalDiscount,
+		})
 	}
 
-	amountFloat64 := float64(selectedOrder.TotalFinalPrice)
-	desc := fmt.Sprintf("This is a product ordered by %s %s", selectedOrder.FirstName, selectedOrder.LastName)
-	qrCodeParam := new(payment_request.QRCodeParameters)
+	// split payment: bagian yang sudah dibayar dengan saldo wallet tidak ditagihkan lagi ke payment gateway
+	var walletAmount float32
+	for _, leg := range selectedOrder.PaymentLegs {
+		if leg.Source == enum_state.ORDER_PAYMENT_LEG_SOURCE_WALLET && (leg.Status == enum_state.ORDER_PAYMENT_LEG_HELD || leg.Status == enum_state.ORDER_PAYMENT_LEG_PAID) {
+			walletAmount += leg.Amount
+		}
+	}
 
-	qrisCode := payment_request.QRCODECHANNELCODE_DANA
-	qrCodeParam.ChannelCode = *payment_request.NewNullableQRCodeChannelCode(&qrisCode)
-	qrCodeParam.ChannelProperties = payment_request.NewQRCodeChannelProperties()
-	setExpiresAt := time.Now().Add(5 * time.Minute)
-	qrCodeParam.ChannelProperties.ExpiresAt = &setExpiresAt
+	if walletAmount > 0 {
+		paymentItems = append(paymentItems, model.PaymentGatewayItem{
+			ReferenceId: fmt.Sprintf("WALLET/%s", strconv.FormatUint(selectedOrder.ID, 10)),
+			Name:        "Wallet Balance",
+			Category:    "wallet",
+			Type:        string(enum_state.ITEM_TYPE_DISCOUNT),
+			Quantity:    1,
+			Price:       walletAmount,
+		})
+	}
 
-	custId := strconv.FormatUint(selectedOrder.UserId, 10)
 	metadata := map[string]any{
 		"user_id":   selectedOrder.UserId,
 		"order_id":  selectedOrder.ID,
@@ -124,70 +130,68 @@ func (c *XenditTransactionQRCodeUseCase) Add(ctx *fiber.Ctx, request *model.Crea
 		"lang":      request.Lang,
 	}
 
-	paymentRequestParameters := &payment_request.PaymentRequestParameters{
-		Amount:      &amountFloat64,
-		Currency:    payment_request.PAYMENTREQUESTCURRENCY_IDR,
-		Description: *payment_request.NewNullableString(&desc),
-		PaymentMethod: &payment_request.PaymentMethodParameters{
-			Type:        payment_request.PAYMENTMETHODTYPE_QR_CODE,
-			Reusability: payment_request.PAYMENTMETHODREUSABILITY_ONE_TIME_USE,
-			QrCode:      *payment_request.NewNullableQRCodeParameters(qrCodeParam),
-		},
-		Items:      *paymentRequestBasketItems,
-		CustomerId: *payment_request.NewNullableString(&custId),
-		Metadata:   metadata,
+	// virtual account butuh waktu lebih lama karena customer harus transfer lewat ATM / mobile banking
+	expiresAt := time.Now().Add(5 * time.Minute)
+	if selectedOrder.PaymentMethod == enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT {
+		expiresAt = time.Now().Add(24 * time.Hour)
 	}
 
-	idempotencyKey := fmt.Sprintf("%d-%s", selectedOrder.ID, selectedOrder.Invoice)
-	resp, _, resErr := c.XenditClient.PaymentRequestApi.CreatePaymentRequest(ctx.Context()).
-		PaymentRequestParameters(*paymentRequestParameters).IdempotencyKey(idempotencyKey).
-		Execute()
+	createPaymentRequest := &model.CreatePaymentGatewayRequest{
+		OrderId:        selectedOrder.ID,
+		ReferenceId:    selectedOrder.Invoice,
+		CustomerId:     strconv.FormatUint(selectedOrder.UserId, 10),
+		Amount:         selectedOrder.TotalFinalPrice - walletAmount,
+		Currency:       "IDR",
+		PaymentMethod:  selectedOrder.PaymentMethod,
+		ChannelCode:    selectedOrder.ChannelCode,
+		Description:    fmt.Sprintf("This is a product ordered by %s %s", selectedOrder.FirstName, selectedOrder.LastName),
+		Items:          paymentItems,
+		Metadata:       metadata,
+		ExpiresAt:      expiresAt,
+		IdempotencyKey: fmt.Sprintf("%d-%s", selectedOrder.ID, selectedOrder.Invoice),
+		CustomerName:   strings.TrimSpace(fmt.Sprintf("%s %s", selectedOrder.FirstName, selectedOrder.LastName)),
+		MobileNumber:   request.MobileNumber,
+		// customer diarahkan kembali ke halaman detail pembayaran order setelah membayar lewat e-wallet
+		SuccessReturnUrl: fmt.Sprintf("%s/payment/orders/%d/details?status=success", request.BaseFrontEndURL, selectedOrder.ID),
+		FailureReturnUrl: fmt.Sprintf("%s/payment/orders/%d/details?status=failed", request.BaseFrontEndURL, selectedOrder.ID),
+	}
 
-	if resErr != nil {
-		c.Log.Warnf("failed to create new xendit transaction : %+v", resErr.FullError())
-		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to create new xendit transaction : %+v", resErr.FullError()))
+	resp, err := paymentGateway.CreatePayment(ctx.Context(), createPaymentRequest)
+	if err != nil {
+		c.Log.Warnf("failed to create new payment on %s : %+v", paymentGateway.Name(), err)
+		return nil, err
 	}
 
 	// setelah itu tangkap semua response
 	newXenditTransaction := new(entity.XenditTransactions)
-	newXenditTransaction.ID = resp.Id
+	newXenditTransaction.ID = resp.ID
 	newXenditTransaction.OrderId = selectedOrder.ID
 	newXenditTransaction.ReferenceId = resp.ReferenceId
-	newXenditTransaction.Amount = *resp.Amount
-	newXenditTransaction.Currency = resp.Currency.String()
-	newXenditTransaction.PaymentMethod = resp.PaymentMethod.Type.String()
-	newXenditTransaction.PaymentMethodId = resp.PaymentMethod.Id
-	newXenditTransaction.ChannelCode = resp.PaymentMethod.QrCode.Get().ChannelCode.Get().String()
-	newXenditTransaction.QrString = *resp.PaymentMethod.QrCode.Get().ChannelProperties.QrString
-	newXenditTransaction.Status = string(resp.Status)
-	newXenditTransaction.FailureCode = resp.GetFailureCode()
-	getMetadata := resp.GetMetadata()
-	if getMetadata != nil {
+	newXenditTransaction.Amount = resp.Amount
+	newXenditTransaction.Currency = resp.Currency
+	newXenditTransaction.PaymentMethod = resp.PaymentMethod
+	newXenditTransaction.PaymentMethodId = resp.PaymentMethodId
+	newXenditTransaction.ChannelCode = resp.ChannelCode
+	newXenditTransaction.QrString = resp.QrString
+	newXenditTransaction.MobileNumber = request.MobileNumber
+	newXenditTransaction.CheckoutUrl = resp.CheckoutUrl
+	newXenditTransaction.DeeplinkUrl = resp.DeeplinkUrl
+	newXenditTransaction.VirtualAccountNumber = resp.VirtualAccountNumber
+	newXenditTransaction.Status = resp.Status
+	newXenditTransaction.FailureCode = resp.FailureCode
+	if resp.Metadata != nil {
 		jsonMetadata, err := json.Marshal(metadata)
 		if err != nil {
-			c.Log.Warnf("failed to parse to json metadata : %+v", resErr.FullError())
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse to json metadata : %+v", resErr.FullError()))
+			c.Log.Warnf("failed to parse to json metadata : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse to json metadata : %+v", err))
 		}
 		newXenditTransaction.Metadata = jsonMetadata
 	}
 
-	newXenditTransaction.Description = resp.GetDescription()
-	expiresAt := resp.PaymentMethod.QrCode.Get().ChannelProperties.ExpiresAt
-	newXenditTransaction.ExpiresAt = *expiresAt
-	parseCreatedAt, err := ParseToRFC3339(resp.Created)
-	if err != nil {
-		c.Log.Warnf("failed to parse created_at into UTC : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse created_at into UTC : %+v", err))
-	}
-
-	newXenditTransaction.CreatedAt = *parseCreatedAt
-	parseUpdatedAt, err := ParseToRFC3339(resp.Updated)
-	if err != nil {
-		c.Log.Warnf("failed to parse updated_at into UTC : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse updated_at into UTC : %+v", err))
-	}
-
-	newXenditTransaction.UpdatedAt = *parseUpdatedAt
+	newXenditTransaction.Description = resp.Description
+	newXenditTransaction.ExpiresAt = resp.ExpiresAt
+	newXenditTransaction.CreatedAt = resp.CreatedAt
+	newXenditTransaction.UpdatedAt = resp.UpdatedAt
 	if err := c.XenditTransactionRepository.Create(tx, newXenditTransaction); err != nil {
 		c.Log.Warnf("failed to insert xendit transaction into database : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "An error occurred on the server. Please try again later!")
@@ -205,103 +209,49 @@ func ParseToRFC3339(TimeRFC3339Nano string) (*time.Time, error) {
 	return &utcTime, nil
 }
 
+// GetTransaction mengambil status terbaru transaksi dari payment gateway. Perubahan status diproses
+// lewat XenditCallbackUseCase agar polling, rekonsiliasi dan webhook memakai alur yang sama
 func (c *XenditTransactionQRCodeUseCase) GetTransaction(ctx *fiber.Ctx, request *model.GetXenditQRCodeTransaction) (*model.XenditTransactionResponse, error) {
-	tx := c.DB.WithContext(ctx.Context()).Begin()
-	defer tx.Rollback()
-
 	err := c.Validate.Struct(request)
 	if err != nil {
 		c.Log.Warnf("invalid request body : %+v", err)
 		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body : %+v", err))
 	}
 
+	db := c.DB.WithContext(ctx.Context())
 	newXenditTransaction := new(entity.XenditTransactions)
-	if err := c.XenditTransactionRepository.FirstXenditTransactionByOrderId(tx, newXenditTransaction, request.OrderId, "Order", "Order.OrderProducts"); err != nil {
+	if err := c.XenditTransactionRepository.FirstXenditTransactionByOrderId(db, newXenditTransaction, request.OrderId, "Order", "Order.OrderProducts"); err != nil {
 		c.Log.Warnf("failed to find order by id : %+v", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id : %+v", err))
 	}
 
-	resp, _, resErr := c.XenditClient.PaymentRequestApi.GetPaymentRequestByID(ctx.Context(), newXenditTransaction.ID).
-		Execute()
-
-	if resErr != nil {
-		c.Log.Warnf("failed to find xendit transaction : %+v", resErr.FullError())
-		return nil, fiber.NewError(helper_others.SetFiberStatusCode(resErr.Status()), fmt.Sprintf("failed to find xendit transaction : %+v", resErr.FullError()))
+	paymentGateway, err := c.PaymentGateways.Get(newXenditTransaction.Order.PaymentGateway)
+	if err != nil {
+		c.Log.Warnf("failed to get payment gateway : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to get payment gateway : %+v", err))
 	}
 
-	if newXenditTransaction.Status != string(resp.Status) && newXenditTransaction.Status != string(payment_request.PAYMENTREQUESTSTATUS_SUCCEEDED) {
-		// update status payment
-		hasPaymentStatusUpdated := false
-		newXenditTransaction.Status = string(resp.Status)
-		parseUpdatedAt, err := time.Parse(time.RFC3339Nano, resp.Updated)
-		if err != nil {
-			c.Log.Warnf("failed to parse updated_at into UTC : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to parse updated_at into UTC : %+v", err))
-		}
-
-		order_status := ""
-
-		newXenditTransaction.UpdatedAt = parseUpdatedAt
-		updatePaymentStatus := map[string]any{
-			"status":     string(resp.Status),
-			"updated_at": parseUpdatedAt.Format(time.DateTime),
-		}
-
-		if err := c.XenditTransactionRepository.UpdateCustomColumns(tx, newXenditTransaction, updatePaymentStatus); err != nil {
-			c.Log.Warnf("failed to update xendit transaction : %+v", err)
-			return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update xendit transaction : %+v", err))
-		}
-
-		// update juga di orders
-		if resp.Status == payment_request.PAYMENTREQUESTSTATUS_SUCCEEDED {
-			// paid
-			newXenditTransaction.Order.PaymentStatus = enum_state.PAID_PAYMENT
-			hasPaymentStatusUpdated = true
-		}
-
-		if resp.Status == payment_request.PAYMENTREQUESTSTATUS_FAILED {
-			// not paid
-			newXenditTransaction.Order.PaymentStatus = enum_state.FAILED_PAYMENT
-			hasPaymentStatusUpdated = true
-			order_status = string(enum_state.ORDER_CANCELLED)
-		}
-
-		if resp.Status == payment_request.PAYMENTREQUESTSTATUS_CANCELED {
-			// cancelled
-			newXenditTransaction.Order.PaymentStatus = enum_state.CANCELLED_PAYMENT
-			hasPaymentStatusUpdated = true
-			order_status = string(enum_state.ORDER_CANCELLED)
-		}
-
-		if resp.Status == payment_request.PAYMENTREQUESTSTATUS_EXPIRED {
-			// expired
-			newXenditTransaction.Order.PaymentStatus = enum_state.EXPIRED_PAYMENT
-			hasPaymentStatusUpdated = true
-			order_status = string(enum_state.ORDER_CANCELLED)
-		}
-
-		if hasPaymentStatusUpdated {
-			updatePaymentStatus = map[string]any{
-				"payment_status": newXenditTransaction.Order.PaymentStatus,
-				"updated_at":     time.Now().Format(time.DateTime),
-			}
+	resp, err := paymentGateway.GetPaymentStatus(ctx.Context(), newXenditTransaction.ID)
+	if err != nil {
+		c.Log.Warnf("failed to find payment on %s : %+v", paymentGateway.Name(), err)
+		return nil, err
+	}
 
-			if order_status != "" {
-				updatePaymentStatus["order_status"] = order_status
-			}
+	webhook, err := paymentStatusToWebhook(newXenditTransaction, resp)
+	if err != nil {
+		c.Log.Warnf("failed to unmarshall xendit transaction metadata : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to unmarshall xendit transaction metadata : %+v", err))
+	}
 
-			orderObj := new(entity.Order)
-			orderObj.ID = newXenditTransaction.Order.ID
-			if err := c.OrderRepository.UpdateCustomColumns(tx, orderObj, updatePaymentStatus); err != nil {
-				c.Log.Warnf("failed to update order payment status : %+v", err)
-				return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update order payment status : %+v", err))
-			}
-		}
+	if err := c.XenditCallbackUseCase.UpdateStatusPaymentRequestFromWebhook(ctx, webhook, c.FrontEndConfig.BaseURL); err != nil {
+		c.Log.Warnf("failed to update xendit transaction status : %+v", err)
+		return nil, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("failed to commit transaction : %+v", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	newXenditTransaction = new(entity.XenditTransactions)
+	if err := c.XenditTransactionRepository.FirstXenditTransactionByOrderId(db, newXenditTransaction, request.OrderId, "Order", "Order.OrderProducts"); err != nil {
+		c.Log.Warnf("failed to find order by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find order by id : %+v", err))
 	}
 
 	return converter.XenditTransactionToResponse(*newXenditTransaction), nil
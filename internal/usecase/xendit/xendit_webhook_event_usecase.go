@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/model/converter"
+	"seblak-bombom-restful-api/internal/payment_gateway"
+	"seblak-bombom-restful-api/internal/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// event yang tertahan di status processing lebih lama dari ini dianggap macet (misal server crash / panic)
+// sehingga boleh diproses ulang oleh webhook berikutnya atau di-replay oleh admin
+const xenditWebhookEventProcessingTimeout = 15 * time.Minute
+
+type XenditWebhookEventUseCase struct {
+	DB                           *gorm.DB
+	Log                          *logrus.Logger
+	Validate                     *validator.Validate
+	XenditWebhookEventRepository *repository.XenditWebhookEventRepository
+	XenditCallbackUseCase        *XenditCallbackUseCase
+	PaymentGateways              *payment_gateway.Registry
+	FrontEndConfig               *model.FrontEndConfig
+}
+
+func NewXenditWebhookEventUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
+	xenditWebhookEventRepository *repository.XenditWebhookEventRepository,
+	xenditCallbackUseCase *XenditCallbackUseCase, paymentGateways *payment_gateway.Registry,
+	frontEndConfig *model.FrontEndConfig) *XenditWebhookEventUseCase {
+	return &XenditWebhookEventUseCase{
+		DB:                           db,
+		Log:                          log,
+		Validate:                     validate,
+		XenditWebhookEventRepository: xenditWebhookEventRepository,
+		XenditCallbackUseCase:        xenditCallbackUseCase,
+		PaymentGateways:              paymentGateways,
+		FrontEndConfig:               frontEndConfig,
+	}
+}
+
+// Receive menyimpan raw webhook dari payment gateway lalu memprosesnya tepat satu kali.
+// Webhook dengan event id yang sudah pernah diproses tidak akan diproses ulang.
+func (c *XenditWebhookEventUseCase) Receive(ctx *fiber.Ctx, gateway enum_state.PaymentGateway, webhookType enum_state.XenditWebhookType, eventId string, rawBody []byte) error {
+	if eventId == "" {
+		// xendit tidak selalu mengirim header webhook-id, gunakan hash raw body sebagai gantinya
+		hash := sha256.Sum256(rawBody)
+		eventId = fmt.Sprintf("%s:%s", webhookType, hex.EncodeToString(hash[:]))
+	}
+
+	eventType := struct {
+		Event string `json:"event"`
+	}{}
+	if err := json.Unmarshal(rawBody, &eventType); err != nil {
+		c.Log.Warnf("failed to unmarshall webhook body : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to unmarshall webhook body : %+v", err))
+	}
+
+	tx := c.DB.WithContext(ctx.Context()).Begin()
+	defer tx.Rollback()
+
+	newWebhookEvent := new(entity.XenditWebhookEvent)
+	count, err := c.XenditWebhookEventRepository.FindAndCountXenditWebhookEventByEventId(tx, newWebhookEvent, eventId)
+	if err != nil {
+		c.Log.Warnf("failed to find xendit webhook event by event id : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find xendit webhook event by event id : %+v", err))
+	}
+
+	if count < 1 {
+		newWebhookEvent.EventId = eventId
+		newWebhookEvent.Gateway = gateway
+		newWebhookEvent.WebhookType = webhookType
+		newWebhookEvent.EventType = eventType.Event
+		newWebhookEvent.RawBody = string(rawBody)
+		newWebhookEvent.Status = enum_state.XENDIT_WEBHOOK_EVENT_RECEIVED
+		if err := c.XenditWebhookEventRepository.Create(tx, newWebhookEvent); err != nil {
+			c.Log.Warnf("failed to create xendit webhook event into database : %+v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to create xendit webhook event into database : %+v", err))
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("failed to commit transaction : %+v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to commit transaction : %+v", err))
+	}
+
+	if newWebhookEvent.Status == enum_state.XENDIT_WEBHOOK_EVENT_PROCESSED {
+		c.Log.Infof("xendit webhook event %s already processed, skipped", eventId)
+		return nil
+	}
+
+	claimed, err := c.process(ctx, newWebhookEvent, enum_state.XENDIT_WEBHOOK_EVENT_RECEIVED, enum_state.XENDIT_WEBHOOK_EVENT_FAILED)
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
+		c.Log.Infof("xendit webhook event %s is being processed by another request, skipped", eventId)
+	}
+
+	return nil
+}
+
+func (c *XenditWebhookEventUseCase) GetAllPaginate(ctx context.Context, page int, perPage int, status string, webhookType string, sortingColumn string, sortBy string) (*[]model.XenditWebhookEventResponse, int64, int64, int64, int64, int, error) {
+	tx := c.DB.WithContext(ctx)
+
+	if page <= 0 {
+		page = 1
+	}
+
+	if sortingColumn == "" {
+		sortingColumn = "xendit_webhook_events.id"
+	}
+
+	newPagination := new(repository.Pagination)
+	newPagination.Page = page
+	newPagination.PageSize = perPage
+	newPagination.Column = sortingColumn
+	newPagination.SortBy = sortBy
+	allowedColumns := map[string]bool{
+		"xendit_webhook_events.id":           true,
+		"xendit_webhook_events.webhook_type": true,
+		"xendit_webhook_events.status":       true,
+		"xendit_webhook_events.attempts":     true,
+		"xendit_webhook_events.created_at":   true,
+		"xendit_webhook_events.updated_at":   true,
+	}
+
+	if !allowedColumns[newPagination.Column] {
+		c.Log.Warnf("invalid sort column : %s", newPagination.Column)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid sort column : %s", newPagination.Column))
+	}
+
+	webhookEvents, totalCurrent, totalReal, totalActive, totalInactive, err := repository.Paginate(tx, &entity.XenditWebhookEvent{}, newPagination, func(d *gorm.DB) *gorm.DB {
+		result := d
+		if status != "" {
+			result = result.Where("status = ?", status)
+		}
+
+		if webhookType != "" {
+			result = result.Where("webhook_type = ?", webhookType)
+		}
+		return result
+	})
+
+	if err != nil {
+		c.Log.Warnf("failed to paginate xendit webhook events : %+v", err)
+		return nil, 0, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to paginate xendit webhook events : %+v", err))
+	}
+
+	// Hitung total halaman
+	var totalPages int = 0
+	totalPages = int(totalCurrent / int64(perPage))
+	if totalCurrent%int64(perPage) > 0 {
+		totalPages++
+	}
+
+	return converter.XenditWebhookEventsToResponse(&webhookEvents), totalCurrent, totalReal, totalActive, totalInactive, totalPages, nil
+}
+
+// Replay memproses ulang webhook event yang sebelumnya gagal diproses
+func (c *XenditWebhookEventUseCase) Replay(ctx *fiber.Ctx, id uint64) (*model.XenditWebhookEventResponse, error) {
+	newWebhookEvent := new(entity.XenditWebhookEvent)
+	newWebhookEvent.ID = id
+	count, err := c.XenditWebhookEventRepository.FindAndCountById(c.DB.WithContext(ctx.Context()), newWebhookEvent)
+	if err != nil {
+		c.Log.Warnf("failed to find xendit webhook event by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find xendit webhook event by id : %+v", err))
+	}
+
+	if count < 1 {
+		c.Log.Warnf("xendit webhook event not found!")
+		return nil, fiber.NewError(fiber.StatusNotFound, "xendit webhook event not found!")
+	}
+
+	isStuckProcessing := newWebhookEvent.Status == enum_state.XENDIT_WEBHOOK_EVENT_PROCESSING && c.isStaleProcessing(newWebhookEvent)
+	if newWebhookEvent.Status != enum_state.XENDIT_WEBHOOK_EVENT_FAILED && !isStuckProcessing {
+		c.Log.Warnf("only failed or stuck processing xendit webhook event can be replayed, current status : %s", newWebhookEvent.Status)
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("only failed or stuck processing xendit webhook event can be replayed, current status : %s", newWebhookEvent.Status))
+	}
+
+	claimed, err := c.process(ctx, newWebhookEvent, enum_state.XENDIT_WEBHOOK_EVENT_FAILED)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claimed {
+		c.Log.Warnf("xendit webhook event is being processed by another request!")
+		return nil, fiber.NewError(fiber.StatusConflict, "xendit webhook event is being processed by another request!")
+	}
+
+	if err := c.XenditWebhookEventRepository.FindById(c.DB.WithContext(ctx.Context()), newWebhookEvent); err != nil {
+		c.Log.Warnf("failed to find xendit webhook event by id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to find xendit webhook event by id : %+v", err))
+	}
+
+	return converter.XenditWebhookEventToResponse(newWebhookEvent), nil
+}
+
+// isStaleProcessing mengecek apakah event sudah tertahan di status processing melewati batas waktu
+func (c *XenditWebhookEventUseCase) isStaleProcessing(webhookEvent *entity.XenditWebhookEvent) bool {
+	return webhookEvent.UpdatedAt.Before(time.Now().Add(-xenditWebhookEventProcessingTimeout))
+}
+
+// process mengklaim event (status menjadi processing) lalu menjalankan callback sesuai tipe webhook.
+// Event processing yang sudah melewati batas waktu juga ikut diklaim ulang.
+// Mengembalikan false jika event sudah diklaim oleh request lain.
+func (c *XenditWebhookEventUseCase) process(ctx *fiber.Ctx, webhookEvent *entity.XenditWebhookEvent, fromStatuses ...enum_state.XenditWebhookEventStatus) (bool, error) {
+	db := c.DB.WithContext(ctx.Context())
+	statuses := make([]string, len(fromStatuses))
+	for i, status := range fromStatuses {
+		statuses[i] = string(status)
+	}
+
+	claimed, err := c.XenditWebhookEventRepository.ClaimXenditWebhookEvent(db, &entity.XenditWebhookEvent{}, webhookEvent.ID, statuses, time.Now().Add(-xenditWebhookEventProcessingTimeout))
+	if err != nil {
+		c.Log.Warnf("failed to claim xendit webhook event : %+v", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to claim xendit webhook event : %+v", err))
+	}
+
+	if claimed < 1 {
+		return false, nil
+	}
+
+	processErr := c.dispatch(ctx, webhookEvent)
+	updateWebhookEvent := map[string]any{}
+	if processErr != nil {
+		updateWebhookEvent["status"] = enum_state.XENDIT_WEBHOOK_EVENT_FAILED
+		updateWebhookEvent["last_error"] = processErr.Error()
+	} else {
+		now := time.Now()
+		updateWebhookEvent["status"] = enum_state.XENDIT_WEBHOOK_EVENT_PROCESSED
+		updateWebhookEvent["last_error"] = ""
+		updateWebhookEvent["processed_at"] = &now
+	}
+
+	if err := c.XenditWebhookEventRepository.UpdateCustomColumns(db, &entity.XenditWebhookEvent{ID: webhookEvent.ID}, updateWebhookEvent); err != nil {
+		c.Log.Warnf("failed to update xendit webhook event status : %+v", err)
+		return true, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to update xendit webhook event status : %+v", err))
+	}
+
+	if processErr != nil {
+		c.Log.Warnf("failed to process xendit webhook event %s : %+v", webhookEvent.EventId, processErr)
+		return true, processErr
+	}
+
+	return true, nil
+}
+
+func (c *XenditWebhookEventUseCase) dispatch(ctx *fiber.Ctx, webhookEvent *entity.XenditWebhookEvent) error {
+	switch webhookEvent.WebhookType {
+	case enum_state.XENDIT_WEBHOOK_TYPE_PAYMENT_REQUEST:
+		paymentGateway, err := c.PaymentGateways.Get(webhookEvent.Gateway)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		webhook, err := paymentGateway.ParseWebhook([]byte(webhookEvent.RawBody))
+		if err != nil {
+			return err
+		}
+
+		return c.XenditCallbackUseCase.UpdateStatusPaymentRequestFromWebhook(ctx, webhook, c.FrontEndConfig.BaseURL)
+	case enum_state.XENDIT_WEBHOOK_TYPE_PAYOUT:
+		var requestData model.XenditGetPayoutRequestCallbackStatus
+		if err := json.Unmarshal([]byte(webhookEvent.RawBody), &requestData); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
+		}
+		return c.XenditCallbackUseCase.UpdateStatusPayoutRequestCallback(ctx, &requestData)
+	case enum_state.XENDIT_WEBHOOK_TYPE_REFUND:
+		var requestData model.XenditGetRefundCallbackStatus
+		if err := json.Unmarshal([]byte(webhookEvent.RawBody), &requestData); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Failed to unmarshall request body : %+v", err))
+		}
+		return c.XenditCallbackUseCase.UpdateStatusRefundCallback(ctx, &requestData)
+	default:
+		return fmt.Errorf("unknown xendit webhook type : %s", webhookEvent.WebhookType)
+	}
+}
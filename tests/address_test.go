@@ -34,7 +34,7 @@ func TestCreateAddress(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -73,7 +73,7 @@ func TestCreateAddressDeliveryIdNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -105,7 +105,7 @@ func TestCreateAddressBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -140,7 +140,7 @@ func TestUpdateAddress(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -177,7 +177,7 @@ func TestUpdateAddress(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/current/addresses/%+v", firstAddress.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -219,7 +219,7 @@ func TestUpdateAddressDeliveryIdNotFound(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -256,7 +256,7 @@ func TestUpdateAddressDeliveryIdNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/current/addresses/%+v", firstAddress.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -291,7 +291,7 @@ func TestUpdateAddressBadRequest(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -328,7 +328,7 @@ func TestUpdateAddressBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/current/addresses/%+v", firstAddress.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -361,7 +361,7 @@ func TestUpdateAddressIdNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/current/addresses/%+v", -99), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -395,7 +395,7 @@ func TestGetAllAddressByCurrentUser(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -419,7 +419,7 @@ func TestGetAllAddressByCurrentUser(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/users/current/addresses", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -465,7 +465,7 @@ func TestGetAddressById(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -493,7 +493,7 @@ func TestGetAddressById(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/current/addresses/%+v", getAddress.ID), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -534,7 +534,7 @@ func TestGetAddressButDeliveryDeleted(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -568,7 +568,7 @@ func TestGetAddressButDeliveryDeleted(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/current/addresses/%+v", getAddress.ID), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -613,7 +613,7 @@ func TestDeleteAddressByIds(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -641,7 +641,7 @@ func TestDeleteAddressByIds(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/current/addresses?ids=%+v", getIdAddress), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -684,7 +684,7 @@ func TestFailedDeleteAddressByIds(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -708,7 +708,7 @@ func TestFailedDeleteAddressByIds(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/current/addresses?ids=%+v", getIdAddress), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -0,0 +1,277 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func GetCustomerUserId(t *testing.T) uint64 {
+	customer := new(entity.User)
+	err := db.Unscoped().Where("email = ?", "fauzan.hidayat@binus.ac.id").First(customer).Error
+	assert.Nil(t, err)
+
+	return customer.ID
+}
+
+func DoGetAllUsersByAdmin(t *testing.T, token string, query string) *model.ApiResponsePagination[[]model.UserResponse] {
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/users?"+query, token, nil)
+	responseBody := new(model.ApiResponsePagination[[]model.UserResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return responseBody
+}
+
+func DoLoginCustomerRequest(t *testing.T) (*http.Response, []byte) {
+	return DoTwoFactorRequest(t, http.MethodPost, "/api/users/login", "", model.LoginUserRequest{
+		Email:       "fauzan.hidayat@binus.ac.id",
+		Password:    "Customer1#",
+		ReturnToken: true,
+	})
+}
+
+func TestAdminGetAllUsers(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	responseBody := DoGetAllUsersByAdmin(t, tokenAdmin, "search=binus")
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+	assert.Equal(t, "fauzan.hidayat@binus.ac.id", responseBody.Data[0].Email)
+
+	responseBody = DoGetAllUsersByAdmin(t, tokenAdmin, "search=Customer%201")
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+
+	responseBody = DoGetAllUsersByAdmin(t, tokenAdmin, "role=admin")
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+	assert.Equal(t, enum_state.ADMIN, responseBody.Data[0].Role)
+
+	// customer belum memverifikasi email
+	responseBody = DoGetAllUsersByAdmin(t, tokenAdmin, "is_verified=false")
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+	assert.False(t, responseBody.Data[0].EmailVerified)
+
+	responseBody = DoGetAllUsersByAdmin(t, tokenAdmin, "per_page=1&page=2&column=users.email&sort_by=asc")
+	assert.Equal(t, int64(2), responseBody.TotalCurrentDatas)
+	assert.Equal(t, 2, responseBody.TotalPages)
+	assert.Len(t, responseBody.Data, 1)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/users?role=owner", tokenAdmin, nil)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestAdminGetAllUsersByCustomer(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/users", tokenCustomer, nil)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestAdminGetUserDetail(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	userId := GetCustomerUserId(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, fmt.Sprintf("/api/admin/users/%d", userId), tokenAdmin, nil)
+	responseBody := new(model.ApiResponse[model.AdminUserDetailResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, userId, responseBody.Data.ID)
+	assert.Equal(t, "fauzan.hidayat@binus.ac.id", responseBody.Data.Email)
+	assert.Equal(t, int64(0), responseBody.Data.TotalOrders)
+	assert.Equal(t, float32(0), responseBody.Data.WalletBalance)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodGet, "/api/admin/users/999999", tokenAdmin, nil)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestAdminSuspendUser(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenCustomer := DoLoginCustomer(t)
+	userId := GetCustomerUserId(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/suspend", userId), tokenAdmin, model.SuspendUserRequest{
+		Reason: "chargeback berulang",
+	})
+	responseBody := new(model.ApiResponse[model.UserResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.SuspendedAt)
+	assert.Equal(t, "chargeback berulang", responseBody.Data.SuspendedReason)
+
+	// access token yang sudah ada langsung ditolak
+	response, _ = DoTwoFactorRequest(t, http.MethodGet, "/api/users/current", tokenCustomer, nil)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	var activeSessions int64
+	db.Model(&entity.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userId).Count(&activeSessions)
+	assert.Equal(t, int64(0), activeSessions)
+
+	response, bytes = DoLoginCustomerRequest(t)
+	errorResponse := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "your account has been suspended!", errorResponse.Error)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/unsuspend", userId), tokenAdmin, nil)
+	responseBody = new(model.ApiResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, responseBody.Data.SuspendedAt)
+
+	DoLoginCustomer(t)
+}
+
+func TestAdminSuspendOwnAccount(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	adminUser := new(entity.User)
+	err := db.Where("email = ?", "F3196813@gmail.com").First(adminUser).Error
+	assert.Nil(t, err)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/suspend", adminUser.ID), tokenAdmin, model.SuspendUserRequest{
+		Reason: "test",
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "can't suspend your own account!", errorResponse.Error)
+}
+
+func TestStaffSuspendAdminAccount(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenStaff := DoLoginCustomer(t)
+
+	role := DoCreateRole(t, tokenAdmin, "user-manager", []enum_state.Permission{enum_state.PERMISSION_USERS_MANAGE})
+	DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	adminUser := new(entity.User)
+	err := db.Where("email = ?", "F3196813@gmail.com").First(adminUser).Error
+	assert.Nil(t, err)
+
+	// staff dengan users.manage tidak boleh mensuspend akun admin
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/suspend", adminUser.ID), tokenStaff, model.SuspendUserRequest{
+		Reason: "test",
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "only admin can manage an admin account!", errorResponse.Error)
+
+	// sesi admin tidak ikut dicabut
+	response, _ = DoTwoFactorRequest(t, http.MethodGet, "/api/users/current", tokenAdmin, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestAdminUpdateUserRole(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	userId := GetCustomerUserId(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/role", userId), tokenAdmin, model.UpdateUserRoleRequest{
+		Role: enum_state.STAFF,
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "role_id is required for staff and courier!", errorResponse.Error)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/role", userId), tokenAdmin, model.UpdateUserRoleRequest{
+		Role: enum_state.ADMIN,
+	})
+	responseBody := new(model.ApiResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, enum_state.ADMIN, responseBody.Data.Role)
+	assert.ElementsMatch(t, enum_state.AllPermissions, responseBody.Data.Permissions)
+}
+
+func TestAdminResendVerification(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	userId := GetCustomerUserId(t)
+
+	oldCustomer := new(entity.User)
+	err := db.First(oldCustomer, userId).Error
+	assert.Nil(t, err)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/verification-email", userId), tokenAdmin, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	newCustomer := new(entity.User)
+	err = db.First(newCustomer, userId).Error
+	assert.Nil(t, err)
+	assert.NotEqual(t, oldCustomer.VerificationToken, newCustomer.VerificationToken)
+
+	DoVerificationEmail(t, "fauzan.hidayat@binus.ac.id")
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/verification-email", userId), tokenAdmin, nil)
+	errorResponse := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "email has already been verified!", errorResponse.Error)
+}
+
+func TestAdminRestoreUser(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenCustomer := DoLoginCustomer(t)
+	userId := GetCustomerUserId(t)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodDelete, "/api/users/current", tokenCustomer, model.DeleteCurrentUserRequest{
+		OldPassword: "Customer1#",
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	responseBody := DoGetAllUsersByAdmin(t, tokenAdmin, "is_active=false")
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+	assert.NotNil(t, responseBody.Data[0].DeletedAt)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/restore", userId), tokenAdmin, nil)
+	userResponse := new(model.ApiResponse[model.UserResponse])
+	err := json.Unmarshal(bytes, userResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, userResponse.Data.DeletedAt)
+
+	DoLoginCustomer(t)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/restore", userId), tokenAdmin, nil)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
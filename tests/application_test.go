@@ -131,7 +131,7 @@ func TestAddApplicationSetting(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/applications", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -206,7 +206,7 @@ func TestAddApplicationSettingBadRequest(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/applications", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -265,7 +265,7 @@ func TestUpdateApplicationSetting(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/applications", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -334,7 +334,7 @@ func TestUpdateApplicationSetting(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPost, "/api/applications", &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -409,7 +409,7 @@ func TestAddApplicationSettingFileLogoExceededThan1Mb(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/applications", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
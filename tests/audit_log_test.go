@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoGetAllAuditLogs(t *testing.T, token string, query string) *model.ApiResponsePagination[[]model.AuditLogResponse] {
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/audit-logs?"+query, token, nil)
+	responseBody := new(model.ApiResponsePagination[[]model.AuditLogResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return responseBody
+}
+
+func DoCreateAuditedDiscountCoupon(t *testing.T, token string) *model.DiscountCouponResponse {
+	parseStart, err := time.Parse(time.RFC3339, "2025-01-01T00:00:01Z")
+	assert.Nil(t, err)
+	parseEnd, err := time.Parse(time.RFC3339, "2025-12-30T23:59:59Z")
+	assert.Nil(t, err)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/discount-coupons", token, model.CreateDiscountCouponRequest{
+		Name:            "Diskon Audit",
+		Description:     "Discount Description Audit",
+		Code:            "AUDIT1",
+		Value:           15,
+		Type:            enum_state.PERCENT,
+		Start:           helper_others.TimeRFC3339(parseStart),
+		End:             helper_others.TimeRFC3339(parseEnd),
+		MaxUsagePerUser: 5,
+		UsedCount:       0,
+		MinOrderValue:   20000,
+		Status:          true,
+	})
+	responseBody := new(model.ApiResponse[model.DiscountCouponResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	return &responseBody.Data
+}
+
+func TestAuditLogDiscountCouponUpdate(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	coupon := DoCreateAuditedDiscountCoupon(t, token)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodPut, fmt.Sprintf("/api/discount-coupons/%d", coupon.ID), token, model.UpdateDiscountCouponRequest{
+		Name:            coupon.Name,
+		Description:     coupon.Description,
+		Code:            coupon.Code,
+		Value:           25,
+		Type:            coupon.Type,
+		Start:           coupon.Start,
+		End:             coupon.End,
+		MaxUsagePerUser: coupon.MaxUsagePerUser,
+		UsedCount:       coupon.UsedCount,
+		MinOrderValue:   coupon.MinOrderValue,
+		Status:          coupon.Status,
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("X-Request-Id"))
+
+	responseBody := DoGetAllAuditLogs(t, token, "entity_type=discount_coupon")
+	assert.Equal(t, int64(2), responseBody.TotalCurrentDatas)
+
+	// urutan default terbaru lebih dulu
+	auditLog := responseBody.Data[0]
+	assert.Equal(t, enum_state.AUDIT_ACTION_UPDATE, auditLog.Action)
+	assert.Equal(t, enum_state.AUDIT_ENTITY_DISCOUNT_COUPON, auditLog.EntityType)
+	assert.Equal(t, fmt.Sprint(coupon.ID), auditLog.EntityId)
+	assert.Equal(t, "F3196813@gmail.com", auditLog.ActorEmail)
+	assert.Equal(t, enum_state.ADMIN, auditLog.ActorRole)
+	assert.NotNil(t, auditLog.ActorId)
+	assert.NotEmpty(t, auditLog.RequestId)
+	assert.NotEmpty(t, auditLog.IPAddress)
+
+	// hanya kolom yang berubah yang dicatat
+	assert.NotContains(t, auditLog.Changes, "name")
+	assert.NotContains(t, auditLog.Changes, "code")
+	assert.Equal(t, float64(15), auditLog.Changes["value"].Before)
+	assert.Equal(t, float64(25), auditLog.Changes["value"].After)
+
+	assert.Equal(t, enum_state.AUDIT_ACTION_CREATE, responseBody.Data[1].Action)
+	assert.Nil(t, responseBody.Data[1].Changes["name"].Before)
+	assert.Equal(t, "Diskon Audit", responseBody.Data[1].Changes["name"].After)
+}
+
+func TestAuditLogRequestIdFromHeader(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	DoRegisterCustomer(t)
+	userId := GetCustomerUserId(t)
+
+	bodyJson, err := json.Marshal(model.SuspendUserRequest{Reason: "chargeback berulang"})
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/suspend", userId), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("X-Request-Id", "trace-audit-123")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "trace-audit-123", response.Header.Get("X-Request-Id"))
+
+	responseBody := DoGetAllAuditLogs(t, token, fmt.Sprintf("action=suspend&entity_type=user&entity_id=%d", userId))
+	assert.Equal(t, int64(1), responseBody.TotalCurrentDatas)
+	assert.Equal(t, "trace-audit-123", responseBody.Data[0].RequestId)
+	assert.Nil(t, responseBody.Data[0].Changes["suspended_at"].Before)
+	assert.NotNil(t, responseBody.Data[0].Changes["suspended_at"].After)
+
+	responseBody = DoGetAllAuditLogs(t, token, "action=unsuspend")
+	assert.Equal(t, int64(0), responseBody.TotalCurrentDatas)
+}
+
+func TestAuditLogForbiddenForCustomer(t *testing.T) {
+	ClearAll()
+	DoRegisterCustomer(t)
+	token := DoLoginCustomer(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/audit-logs", token, nil)
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestAuditLogInvalidFilter(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, "/api/admin/audit-logs?start_date=11-07-2025", token, nil)
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, errorResponse.Error, "invalid start_date")
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBankAccount(t *testing.T) {
+	ClearAll()
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	bankAccount := DoCreateBankAccount(t, tokenCust, "ID_BCA")
+	assert.Equal(t, "ID_BCA", bankAccount.ChannelCode)
+	assert.Equal(t, enum_state.PAYOUT_CHANNEL_CATEGORY_BANK, bankAccount.ChannelCategory)
+	assert.Equal(t, enum_state.BANK_ACCOUNT_UNVERIFIED, bankAccount.VerificationStatus)
+}
+
+func TestCreateBankAccountWithUnsupportedChannel(t *testing.T) {
+	ClearAll()
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	requestBody := model.CreateBankAccountRequest{
+		ChannelCode:       "ID_UNKNOWN_BANK",
+		AccountNumber:     "1234567890",
+		AccountHolderName: "Customer Seblak",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/current/bank-accounts", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestWithdrawRequestRejectedForLargeAmountToUnverifiedBankAccount(t *testing.T) {
+	ClearAll()
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	amount := walletConfig.UnverifiedPayoutLimit + 10000
+	DoSetBalanceManually(tokenCust, amount)
+
+	bankAccount := DoCreateBankAccount(t, tokenCust, "ID_BCA")
+	requestBody := model.WithdrawWalletRequest{
+		Method:        enum_state.WALLET_WITHDRAW_REQUEST_METHOD_BANK_TRANSFER,
+		BankAccountId: bankAccount.ID,
+		Amount:        amount,
+		Note:          "Saya mau narik duit ya",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, amount, customerBalance.Wallet.Balance)
+}
@@ -32,7 +32,7 @@ func TestAddProductToCart(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -71,7 +71,7 @@ func TestAddProductProductNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -105,7 +105,7 @@ func TestAddProductQuantityExceeded(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -139,7 +139,7 @@ func TestAddProductQuantityLowerThan1(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -181,7 +181,7 @@ func TestAddProductStockLowerThanQuantityRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -217,7 +217,7 @@ func TestAllCartItemByCurrentUser(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", tokenCust)
+		request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -249,7 +249,7 @@ func TestAllCartItemByCurrentUser(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", tokenCust)
+		request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -273,7 +273,7 @@ func TestAllCartItemByCurrentUser(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/carts", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -312,7 +312,7 @@ func TestUpdateCartItemByCurrentUser(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", tokenCust)
+		request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -345,7 +345,7 @@ func TestUpdateCartItemByCurrentUser(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/carts/cart-items/%d", getCartItemId), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -382,7 +382,7 @@ func TestDropProductFromCart(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/carts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -411,7 +411,7 @@ func TestDropProductFromCart(t *testing.T) {
 	request = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/carts/cart-items/%d", getCartId), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
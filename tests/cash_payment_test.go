@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoConfirmCashPayment(t *testing.T, token string, orderId uint64, receivedAmount float32) (*http.Response, *model.ApiResponse[model.CashCollectionResponse]) {
+	requestBody := model.ConfirmCashPaymentRequest{
+		ReceivedAmount: receivedAmount,
+		Note:           "paid at counter",
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/orders/%d/cash-payment/confirm?lang=id", orderId), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.CashCollectionResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func TestCreateOrderWithCashPayment(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SYSTEM,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_CASH,
+		ChannelCode:    enum_state.CASH_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, enum_state.PAYMENT_METHOD_CASH, responseBody.Data.PaymentMethod)
+	assert.Equal(t, enum_state.PENDING_PAYMENT, responseBody.Data.PaymentStatus)
+
+	// customer tidak boleh mengkonfirmasi pembayaran tunai miliknya sendiri
+	responseConfirm, _ := DoConfirmCashPayment(t, tokenCustomer, responseBody.Data.ID, 0)
+	assert.Equal(t, http.StatusForbidden, responseConfirm.StatusCode)
+
+	// uang yang diterima kurang dari total order
+	responseConfirm, _ = DoConfirmCashPayment(t, tokenAdmin, responseBody.Data.ID, responseBody.Data.TotalFinalPrice-1)
+	assert.Equal(t, http.StatusBadRequest, responseConfirm.StatusCode)
+
+	responseConfirm, responseBodyConfirm := DoConfirmCashPayment(t, tokenAdmin, responseBody.Data.ID, responseBody.Data.TotalFinalPrice+5000)
+	assert.Equal(t, http.StatusOK, responseConfirm.StatusCode)
+	assert.Equal(t, responseBody.Data.TotalFinalPrice, responseBodyConfirm.Data.Amount)
+	assert.Equal(t, float32(5000), responseBodyConfirm.Data.ChangeAmount)
+	assert.Nil(t, responseBodyConfirm.Data.ReconciledAt)
+
+	// konfirmasi kedua ditolak
+	responseConfirm, _ = DoConfirmCashPayment(t, tokenAdmin, responseBody.Data.ID, 0)
+	assert.Equal(t, http.StatusConflict, responseConfirm.StatusCode)
+
+	requestOrder := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", responseBody.Data.ID), nil)
+	requestOrder.Header.Set("Accept", "application/json")
+	requestOrder.Header.Set("Authorization", "Bearer "+tokenCustomer)
+
+	responseOrder, err := app.Test(requestOrder)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseOrder.Body)
+	assert.Nil(t, err)
+
+	responseBodyOrder := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBodyOrder)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseOrder.StatusCode)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyOrder.Data.PaymentStatus)
+
+	// rekap kas hari ini lalu setor ke admin
+	date := time.Now().UTC().Format("2006-01-02")
+	requestReconcile := httptest.NewRequest(http.MethodPatch, "/api/admin/cash-collections/reconcile", strings.NewReader(fmt.Sprintf(`{"date":"%s","collected_by":%d}`, date, responseBodyConfirm.Data.CollectedBy)))
+	requestReconcile.Header.Set("Content-Type", "application/json")
+	requestReconcile.Header.Set("Accept", "application/json")
+	requestReconcile.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	responseReconcile, err := app.Test(requestReconcile)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseReconcile.Body)
+	assert.Nil(t, err)
+
+	responseBodyReconcile := new(model.ApiResponse[model.CashReconciliationResponse])
+	err = json.Unmarshal(bytes, responseBodyReconcile)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseReconcile.StatusCode)
+	assert.Equal(t, 1, responseBodyReconcile.Data.TotalCollections)
+	assert.Equal(t, responseBody.Data.TotalFinalPrice, responseBodyReconcile.Data.TotalAmount)
+	assert.Equal(t, float32(0), responseBodyReconcile.Data.UnreconciledAmount)
+	assert.Len(t, responseBodyReconcile.Data.Staffs, 1)
+}
+
+func TestCreateOrderWithCashPaymentInvalidChannelCode(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SYSTEM,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_CASH,
+		ChannelCode:    enum_state.WALLET_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
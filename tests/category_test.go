@@ -28,7 +28,7 @@ func TestCreateCategory(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -63,7 +63,7 @@ func TestCreateCategoryBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -89,7 +89,7 @@ func TestCreateCategoryBadRequest(t *testing.T) {
 	request = httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -121,7 +121,7 @@ func TestUpdateCategory(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -150,7 +150,7 @@ func TestUpdateCategory(t *testing.T) {
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/categories/%+v", responseBody.Data.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -187,7 +187,7 @@ func TestUpdateCategoryBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -217,7 +217,7 @@ func TestUpdateCategoryBadRequest(t *testing.T) {
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/categories/%+v", responseBody.Data.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -247,7 +247,7 @@ func TestUpdateCategoryNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/categories/%+v", -9), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -277,7 +277,7 @@ func TestGetCategoryById(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -334,7 +334,7 @@ func TestGetAllCategoryPagination(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -392,7 +392,7 @@ func TestGetAllCategoryPaginationSearchNotFound(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -450,7 +450,7 @@ func TestGetAllCategoryPaginationSortingColumnDesc(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -512,7 +512,7 @@ func TestGetAllCategoryPaginationSortingColumnAsc(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -574,7 +574,7 @@ func TestGetAllCategoryPaginationSortingColumnNotFound(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -629,7 +629,7 @@ func TestDeleteCategoryById(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -653,7 +653,7 @@ func TestDeleteCategoryById(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/categories?ids="+getAllIds, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -677,7 +677,7 @@ func TestDeleteCategoriesIdNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/categories?ids="+getAllIds, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -31,7 +31,7 @@ func TestCreateDelivery(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/deliveries", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -71,7 +71,7 @@ func TestCreateDeliveryFailed(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/deliveries", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -105,7 +105,7 @@ func TestUpdateDeliveriesBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/deliveries/%+v", deliveryResponse.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -139,7 +139,7 @@ func TestUpdateDeliveries(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/deliveries/%+v", deliveryResponse.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -179,7 +179,7 @@ func TestUpdateDeliveriesNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/deliveries/%+v", -99), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -314,7 +314,7 @@ func TestDeleteDeliveries(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/deliveries?ids="+requestBody, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -338,7 +338,7 @@ func TestDeleteDeliveriesFailed(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/deliveries?ids="+requestBody, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
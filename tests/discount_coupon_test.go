@@ -49,7 +49,7 @@ func TestCreateDiscountCoupon(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -111,7 +111,7 @@ func TestCreateDiscountCouponFailed(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -266,7 +266,7 @@ func TestUpdateDiscountCouponById(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -313,7 +313,7 @@ func TestUpdateDiscountCouponById(t *testing.T) {
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/discount-coupons/%+v", responseBodyCreate.Data.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -371,7 +371,7 @@ func TestUpdateDiscountCouponByIdBadRequest(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -418,7 +418,7 @@ func TestUpdateDiscountCouponByIdBadRequest(t *testing.T) {
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/discount-coupons/%+v", responseBodyCreate.Data.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -466,7 +466,7 @@ func TestUpdateDiscountCouponByIdNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/discount-coupons/%+v", -999), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -515,7 +515,7 @@ func TestDeleteDiscountCoupon(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -548,7 +548,7 @@ func TestDeleteDiscountCoupon(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/discount-coupons?ids=%+v", getAllIds), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -573,7 +573,7 @@ func TestDeleteDiscountCouponIdsNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/discount-coupons?ids=%+v", getAllIds), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -18,6 +18,7 @@ import (
 	"seblak-bombom-restful-api/internal/entity"
 	"seblak-bombom-restful-api/internal/helper/enum_state"
 	"seblak-bombom-restful-api/internal/helper/helper_others"
+	"seblak-bombom-restful-api/internal/helper/token_helper"
 	"seblak-bombom-restful-api/internal/model"
 	"strconv"
 	"strings"
@@ -28,9 +29,15 @@ import (
 )
 
 func ClearAll() {
+	ClearAuditLogs()
 	ClearPasswordResets()
 	ClearDiscountCouponUsages()
 	ClearXenditTransactions()
+	ClearMidtransTransactions()
+	ClearCashCollections()
+	ClearOrderRefunds()
+	ClearOrderPaymentLegs()
+	ClearXenditWebhookEvents()
 	// DeleteAllApplicationImages()
 	ClearApplicationsSetting()
 	ClearOrderProducts()
@@ -42,13 +49,28 @@ func ClearAll() {
 	ClearDiscountUsages()
 	ClearDiscountCoupons()
 	ClearTokens()
+	ClearUserSessions()
+	ClearLoginAttempts()
+	ClearUserRecoveryCodes()
+	ClearUserIdentities()
 	ClearWallets()
 	ClearAddresses()
 	ClearDeliveries()
 	ClearCarts()
 	ClearWithdrawWalletRequests()
+	ClearBankAccounts()
+	ClearWithdrawPolicies()
+	ClearWalletAdjustmentRequests()
 	ClearWalletTransactions()
 	ClearUsers()
+	ClearRoles()
+}
+
+func ClearAuditLogs() {
+	err := db.Where("1 = 1").Delete(&entity.AuditLog{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear audit logs data : %+v", err)
+	}
 }
 
 func ClearPasswordResets() {
@@ -58,6 +80,39 @@ func ClearPasswordResets() {
 	}
 }
 
+func ClearMidtransTransactions() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.MidtransTransaction{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear midtrans transactions data : %+v", err)
+	}
+}
+
+func ClearCashCollections() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.CashCollection{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear cash collections data : %+v", err)
+	}
+}
+
+func ClearOrderRefunds() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.OrderRefundItem{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear order refund items data : %+v", err)
+	}
+
+	err = db.Unscoped().Where("1 = 1").Delete(&entity.OrderRefund{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear order refunds data : %+v", err)
+	}
+}
+
+func ClearOrderPaymentLegs() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.OrderPaymentLeg{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear order payment legs data : %+v", err)
+	}
+}
+
 func ClearWithdrawWalletRequests() {
 	err := db.Unscoped().Where("1 = 1").Delete(&entity.WalletWithdrawRequests{}).Error
 	if err != nil {
@@ -65,6 +120,27 @@ func ClearWithdrawWalletRequests() {
 	}
 }
 
+func ClearBankAccounts() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.BankAccount{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear bank accounts data : %+v", err)
+	}
+}
+
+func ClearWithdrawPolicies() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.WithdrawPolicy{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear withdraw policies data : %+v", err)
+	}
+}
+
+func ClearWalletAdjustmentRequests() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.WalletAdjustmentRequests{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear wallet adjustment requests data : %+v", err)
+	}
+}
+
 func ClearDiscountCouponUsages() {
 	err := db.Unscoped().Where("1 = 1").Delete(&entity.DiscountUsage{}).Error
 	if err != nil {
@@ -79,6 +155,13 @@ func ClearXenditTransactions() {
 	}
 }
 
+func ClearXenditWebhookEvents() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.XenditWebhookEvent{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear xendit webhook events data : %+v", err)
+	}
+}
+
 func ClearApplicationsSetting() {
 	err := db.Unscoped().Where("1 = 1").Delete(&entity.Application{}).Error
 	if err != nil {
@@ -135,6 +218,34 @@ func ClearTokens() {
 	}
 }
 
+func ClearUserSessions() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.UserSession{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear user sessions data : %+v", err)
+	}
+}
+
+func ClearLoginAttempts() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.LoginAttempt{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear login attempts data : %+v", err)
+	}
+}
+
+func ClearUserRecoveryCodes() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.UserRecoveryCode{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear user recovery codes data : %+v", err)
+	}
+}
+
+func ClearUserIdentities() {
+	err := db.Unscoped().Where("1 = 1").Delete(&entity.UserIdentity{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear user identities data : %+v", err)
+	}
+}
+
 func ClearCarts() {
 	err := db.Unscoped().Where("1 = 1").Delete(&entity.Cart{}).Error
 	if err != nil {
@@ -177,6 +288,14 @@ func ClearUsers() {
 	}
 }
 
+// ClearRoles hanya menghapus role buatan test, role bawaan migration (cashier, kitchen, courier) tetap ada
+func ClearRoles() {
+	err := db.Where("name NOT IN ?", []string{"cashier", "kitchen", "courier"}).Delete(&entity.Role{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear role data : %+v", err)
+	}
+}
+
 func ClearWalletTransactions() {
 	err := db.Unscoped().Where("1 = 1").Delete(&entity.WalletTransactions{}).Error
 	if err != nil {
@@ -185,9 +304,14 @@ func ClearWalletTransactions() {
 }
 
 func DoLoginAdmin(t *testing.T) string {
+	return DoLoginAdminTokenPair(t).Token
+}
+
+func DoLoginAdminTokenPair(t *testing.T) *model.UserTokenResponse {
 	requestBody := model.LoginUserRequest{
-		Email:    "F3196813@gmail.com",
-		Password: "JohnDoe123#",
+		Email:       "F3196813@gmail.com",
+		Password:    "JohnDoe123#",
+		ReturnToken: true,
 	}
 
 	DoVerificationEmail(t, requestBody.Email)
@@ -210,15 +334,17 @@ func DoLoginAdmin(t *testing.T) string {
 	assert.Nil(t, err)
 
 	assert.Equal(t, http.StatusOK, response.StatusCode)
-	assert.NotNil(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
 
-	return responseBody.Data.Token
+	return &responseBody.Data
 }
 
 func DoLoginCustomer(t *testing.T) string {
 	requestBody := model.LoginUserRequest{
-		Email:    "fauzan.hidayat@binus.ac.id",
-		Password: "Customer1#",
+		Email:       "fauzan.hidayat@binus.ac.id",
+		Password:    "Customer1#",
+		ReturnToken: true,
 	}
 
 	DoVerificationEmail(t, requestBody.Email)
@@ -261,7 +387,7 @@ func DoCreateDelivery(t *testing.T, token string) *model.DeliveryResponse {
 	request := httptest.NewRequest(http.MethodPost, "/api/deliveries", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -303,7 +429,7 @@ func DoCreateManyDelivery(t *testing.T, totalData int) string {
 		request := httptest.NewRequest(http.MethodPost, "/api/deliveries", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request, int(time.Second)*5)
 		assert.Nil(t, err)
@@ -344,7 +470,7 @@ func DoCreateManyAddress(t *testing.T, token string, totalData int, returnDataBy
 		request := httptest.NewRequest(http.MethodPost, "/api/users/current/addresses", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request, int(time.Second)*5)
 		assert.Nil(t, err)
@@ -405,7 +531,7 @@ func DoCreateManyDiscountCoupon(t *testing.T, token string, totalData int, retur
 		request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request, int(time.Second)*5)
 		assert.Nil(t, err)
@@ -460,7 +586,7 @@ func DoCreateDiscountCouponCustom(t *testing.T, token string, name string, desc
 	request := httptest.NewRequest(http.MethodPost, "/api/discount-coupons", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -501,7 +627,7 @@ func DoCreateCategory(t *testing.T, token string, categoryName string, categoryD
 	request := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -627,7 +753,7 @@ func DoCreateProduct(t *testing.T, token string, totalData int, getProductByInde
 
 		request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 		request.Header.Set("Content-Type", writer.FormDataContentType())
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request, int(time.Second)*5)
 		assert.Nil(t, err)
@@ -661,17 +787,20 @@ func DoCreateProduct(t *testing.T, token string, totalData int, getProductByInde
 }
 
 func DoSetBalanceManually(token string, balance_value float32) {
-	userEntity := new(entity.User)
-	db.Model(entity.User{}).Joins("left join tokens on tokens.user_id = users.id").Where("tokens.token = ?", token).Scan(&userEntity)
+	// access token berupa JWT, user id diambil dari claims nya
+	claims, err := token_helper.ParseAccessToken(authConfig.AccessTokenSecret, token)
+	if err != nil {
+		log.Fatalf("Failed parse access token : %+v", err)
+	}
 	// update balance
-	db.Model(entity.Wallet{}).Where("user_id = ?", userEntity.ID).Update("balance", balance_value)
+	db.Model(entity.Wallet{}).Where("user_id = ?", claims.Subject).Update("balance", balance_value)
 }
 
 func GetCurrentUserByToken(t *testing.T, token string) *model.UserResponse {
 	request := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -731,7 +860,7 @@ func DoCreateManyOrderUsingWalletPayment(t *testing.T, token string, totalOrder
 		request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request, int(time.Second)*5)
 		assert.Nil(t, err)
@@ -884,7 +1013,7 @@ func DoCreateOrderAsCustomerWithDeliveryAndDiscount(t *testing.T, tokenAdmin str
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -989,7 +1118,7 @@ func DoCreateApplicationSetting(t *testing.T, tokenAdmin string) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/applications", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", tokenAdmin)
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -1115,3 +1244,78 @@ func DoVerificationEmail(t *testing.T, email string) {
 		return
 	}
 }
+
+func DoCreateBankAccount(t *testing.T, token string, channelCode string) *model.BankAccountResponse {
+	requestBody := model.CreateBankAccountRequest{
+		ChannelCode:       channelCode,
+		AccountNumber:     "1234567890",
+		AccountHolderName: "Customer Seblak",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/current/bank-accounts", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.BankAccountResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	return &responseBody.Data
+}
+
+// DoCreatePaidSimulatorOrder membuat order QRIS lewat payment simulator lalu menandainya sudah dibayar
+func DoCreatePaidSimulatorOrder(t *testing.T, tokenAdmin string, tokenCustomer string, product *model.ProductResponse, quantity int) *model.OrderResponse {
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_QR_CODE,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  quantity,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+
+	requestSimulate := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/payment-simulator/payments/%s/simulate", responseBody.Data.XenditTransaction.ID), strings.NewReader(`{"status":"SUCCEEDED"}`))
+	requestSimulate.Header.Set("Content-Type", "application/json")
+	requestSimulate.Header.Set("Accept", "application/json")
+	requestSimulate.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	requestSimulate.Host = "localhost"
+
+	responseSimulate, err := app.Test(requestSimulate, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseSimulate.StatusCode)
+
+	return &responseBody.Data
+}
@@ -30,6 +30,14 @@ var authConfig *model.AuthConfig
 
 var frontEndConfig *model.FrontEndConfig
 
+var walletConfig *model.WalletConfig
+
+var midtransConfig *model.MidtransConfig
+
+var rateLimitConfig *model.RateLimitConfig
+
+var oidcProvider *StubOIDCProvider
+
 func init() {
 	os.Setenv("TZ", "UTC")
 	time.Local = time.UTC // ini yang benar-benar bikin time.Now() jadi UTC
@@ -40,17 +48,37 @@ func init() {
 	db = config.NewDatabaseDockerTest(viperConfig, log)
 	email = config.NewEmailWorker(viperConfig)
 	authConfig = config.NewAuthConfig(viperConfig)
+	if authConfig.AccessTokenSecret == "" {
+		authConfig.AccessTokenSecret = "access-token-secret-test"
+	}
 	frontEndConfig = config.NewFrontEndConfig(viperConfig)
+	walletConfig = config.NewWalletConfig(viperConfig)
+	midtransConfig = config.NewMidtransConfig(viperConfig)
+	if midtransConfig.ServerKey == "" {
+		// notifikasi midtrans di test tidak memanggil api midtrans, cukup server key untuk signature
+		midtransConfig.ServerKey = "SB-Mid-server-test"
+	}
+	rateLimitConfig = config.NewRateLimitConfig(viperConfig)
+	// semua request test berasal dari IP yang sama, rate limit dites terpisah di rate_limit_test.go
+	rateLimitConfig.Enabled = false
+	// login OpenID Connect dites dengan provider lokal, bukan Google
+	oidcProvider = NewStubOIDCProvider()
 	pusherClient := config.NewPusherClient(viperConfig)
 	config.Bootstrap(&config.BootstrapConfig{
-		DB:             db,
-		App:            app,
-		Log:            log,
-		Validate:       validate,
-		Config:         viperConfig,
-		Email:          email,
-		AuthConfig:     authConfig,
-		FrontEndConfig: frontEndConfig,
-		PusherClient:   pusherClient,
+		DB:              db,
+		App:             app,
+		Log:             log,
+		Validate:        validate,
+		Config:          viperConfig,
+		Email:           email,
+		AuthConfig:      authConfig,
+		FrontEndConfig:  frontEndConfig,
+		WalletConfig:    walletConfig,
+		MidtransConfig:  midtransConfig,
+		PusherClient:    pusherClient,
+		RateLimitConfig: rateLimitConfig,
+		OIDCConfig: &model.OIDCConfig{
+			Providers: []model.OIDCProviderConfig{oidcProvider.Config("stub")},
+		},
 	})
 }
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoLoginAdminWithPassword(t *testing.T, password string) (*http.Response, *model.ErrorResponse[string]) {
+	requestBody := model.LoginUserRequest{
+		Email:       "F3196813@gmail.com",
+		Password:    password,
+		ReturnToken: true,
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+// SkipLoginBackoff menghapus masa tunggu backoff agar test tidak perlu menunggu sebelum percobaan berikutnya
+func SkipLoginBackoff() {
+	err := db.Model(&entity.LoginAttempt{}).Where("1 = 1").Update("locked_until", nil).Error
+	if err != nil {
+		log.Fatalf("Failed skip login backoff : %+v", err)
+	}
+}
+
+func TestLoginBackoffAfterFailedAttempts(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoVerificationEmail(t, "F3196813@gmail.com")
+
+	// separuh pertama dari batas percobaan tidak ditunda
+	freeAttempts := authConfig.LoginMaxFailedAttempts / 2
+	for i := 0; i < freeAttempts; i++ {
+		response, _ := DoLoginAdminWithPassword(t, "WrongPassword123#")
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	response, _ := DoLoginAdminWithPassword(t, "WrongPassword123#")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// password benar pun ditolak selama masa backoff
+	response, responseBody := DoLoginAdminWithPassword(t, "JohnDoe123#")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.True(t, strings.HasPrefix(responseBody.Error, "too many failed login attempts, try again in"))
+
+	SkipLoginBackoff()
+	DoLoginAdminTokenPair(t)
+
+	// login berhasil mengosongkan penghitung akun, penghitung IP tetap ada
+	var totalAccountAttempts int64
+	err := db.Model(&entity.LoginAttempt{}).Where("scope = ?", enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT).Count(&totalAccountAttempts).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), totalAccountAttempts)
+
+	var totalIPAttempts int64
+	err = db.Model(&entity.LoginAttempt{}).Where("scope = ?", enum_state.LOGIN_ATTEMPT_SCOPE_IP).Count(&totalIPAttempts).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), totalIPAttempts)
+}
+
+func TestLoginAccountLockedAfterMaxFailedAttempts(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoVerificationEmail(t, "F3196813@gmail.com")
+
+	for i := 0; i < authConfig.LoginMaxFailedAttempts; i++ {
+		SkipLoginBackoff()
+		response, _ := DoLoginAdminWithPassword(t, "WrongPassword123#")
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	response, responseBody := DoLoginAdminWithPassword(t, "JohnDoe123#")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.True(t, strings.HasPrefix(responseBody.Error, "account is temporarily locked due to too many failed login attempts"))
+
+	attempt := new(entity.LoginAttempt)
+	err := db.Where("scope = ?", enum_state.LOGIN_ATTEMPT_SCOPE_ACCOUNT).First(attempt).Error
+	assert.Nil(t, err)
+	assert.Equal(t, authConfig.LoginMaxFailedAttempts, attempt.FailedCount)
+	assert.NotNil(t, attempt.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(authConfig.LoginLockoutDuration), *attempt.LockedUntil, time.Minute)
+}
+
+func TestForgotPasswordValidateTooManyAttempts(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, token)
+
+	requestBody := model.CreateForgotPassword{
+		Email: "F3196813@gmail.com",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/forgot-password", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.PasswordResetResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	wrongCode := 111111
+	if responseBody.Data.VerificationCode == wrongCode {
+		wrongCode = 222222
+	}
+
+	validateUrl := fmt.Sprintf("/api/users/forgot-password/%d/validate", responseBody.Data.ID)
+	for i := 1; i <= authConfig.PasswordResetMaxAttempts; i++ {
+		bodyJson, err = json.Marshal(model.ValidateForgotPassword{VerificationCode: wrongCode})
+		assert.Nil(t, err)
+		request = httptest.NewRequest(http.MethodPost, validateUrl, strings.NewReader(string(bodyJson)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json")
+
+		response, err = app.Test(request, int(time.Second)*5)
+		assert.Nil(t, err)
+
+		bytes, err = io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		responseBodyValidate := new(model.ErrorResponse[string])
+		err = json.Unmarshal(bytes, responseBodyValidate)
+		assert.Nil(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		if i < authConfig.PasswordResetMaxAttempts {
+			assert.Equal(t, "verification code is not match!", responseBodyValidate.Error)
+		} else {
+			assert.Equal(t, "verification code is not match, too many failed attempts, please request a new verification code!", responseBodyValidate.Error)
+		}
+	}
+
+	// kode yang benar tidak bisa dipakai lagi karena kode sudah dihapus
+	bodyJson, err = json.Marshal(model.ValidateForgotPassword{VerificationCode: responseBody.Data.VerificationCode})
+	assert.Nil(t, err)
+	request = httptest.NewRequest(http.MethodPost, validateUrl, strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err = app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
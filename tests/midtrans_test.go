@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func midtransNotificationBody(orderId string, transactionStatus string, statusCode string, grossAmount string, serverKey string) string {
+	hash := sha512.Sum512([]byte(orderId + statusCode + grossAmount + serverKey))
+	return fmt.Sprintf(`{"transaction_time":"2025-06-28 10:00:00","transaction_status":"%s","transaction_id":"trx-midtrans-test-1","status_code":"%s","signature_key":"%s","settlement_time":"2025-06-28 10:01:00","payment_type":"qris","order_id":"%s","gross_amount":"%s","fraud_status":"accept","currency":"IDR"}`,
+		transactionStatus, statusCode, hex.EncodeToString(hash[:]), orderId, grossAmount)
+}
+
+func TestMidtransNotificationUpdatesOrderPaymentStatus(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	// order dibuat lewat simulator lalu dipindah ke midtrans agar test tidak memanggil api midtrans
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_QR_CODE,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	err = db.Model(&entity.Order{}).Where("id = ?", responseBody.Data.ID).Updates(map[string]any{
+		"payment_gateway": enum_state.PAYMENT_GATEWAY_MIDTRANS,
+		"channel_code":    enum_state.MIDTRANS_QRIS_CHANNEL_CODE,
+	}).Error
+	assert.Nil(t, err)
+
+	midtransOrderId := fmt.Sprintf("ORDER-%d-1", responseBody.Data.ID)
+	newMidtransTransaction := &entity.MidtransTransaction{
+		OrderId:           responseBody.Data.ID,
+		MidtransOrderId:   midtransOrderId,
+		ApiType:           "CORE_API",
+		PaymentType:       "qris",
+		ChannelCode:       enum_state.MIDTRANS_QRIS_CHANNEL_CODE,
+		GrossAmount:       float64(responseBody.Data.TotalFinalPrice),
+		Currency:          "IDR",
+		TransactionStatus: "pending",
+		Metadata:          []byte(`{"lang":"id","time_zone":"UTC"}`),
+	}
+	err = db.Create(newMidtransTransaction).Error
+	assert.Nil(t, err)
+
+	grossAmount := fmt.Sprintf("%.2f", responseBody.Data.TotalFinalPrice)
+
+	// signature salah harus ditolak
+	requestInvalid := httptest.NewRequest(http.MethodPost, "/api/midtrans/notifications", strings.NewReader(midtransNotificationBody(midtransOrderId, "settlement", "200", grossAmount, "wrong-server-key")))
+	requestInvalid.Header.Set("Content-Type", "application/json")
+	responseInvalid, err := app.Test(requestInvalid)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, responseInvalid.StatusCode)
+
+	// notifikasi yang sama dikirim dua kali tetap diproses satu kali
+	for i := 0; i < 2; i++ {
+		requestNotification := httptest.NewRequest(http.MethodPost, "/api/midtrans/notifications", strings.NewReader(midtransNotificationBody(midtransOrderId, "settlement", "200", grossAmount, midtransConfig.ServerKey)))
+		requestNotification.Header.Set("Content-Type", "application/json")
+		requestNotification.Host = "localhost"
+		responseNotification, err := app.Test(requestNotification, int(time.Second)*5)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, responseNotification.StatusCode)
+	}
+
+	// notifikasi pending yang datang terlambat tidak boleh mengembalikan status menjadi pending
+	requestLate := httptest.NewRequest(http.MethodPost, "/api/midtrans/notifications", strings.NewReader(midtransNotificationBody(midtransOrderId, "pending", "201", grossAmount, midtransConfig.ServerKey)))
+	requestLate.Header.Set("Content-Type", "application/json")
+	responseLate, err := app.Test(requestLate)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseLate.StatusCode)
+
+	// status akhir lain (cancel) setelah settlement juga tidak boleh mengubah order yang sudah paid
+	requestCancel := httptest.NewRequest(http.MethodPost, "/api/midtrans/notifications", strings.NewReader(midtransNotificationBody(midtransOrderId, "cancel", "202", grossAmount, midtransConfig.ServerKey)))
+	requestCancel.Header.Set("Content-Type", "application/json")
+	responseCancel, err := app.Test(requestCancel)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseCancel.StatusCode)
+
+	requestOrder := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", responseBody.Data.ID), nil)
+	requestOrder.Header.Set("Accept", "application/json")
+	requestOrder.Header.Set("Authorization", "Bearer "+tokenCustomer)
+
+	responseOrder, err := app.Test(requestOrder)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseOrder.Body)
+	assert.Nil(t, err)
+
+	responseBodyOrder := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBodyOrder)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseOrder.StatusCode)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyOrder.Data.PaymentStatus)
+	assert.NotNil(t, responseBodyOrder.Data.MidtransTransaction)
+	assert.Equal(t, "settlement", responseBodyOrder.Data.MidtransTransaction.TransactionStatus)
+	assert.Equal(t, "trx-midtrans-test-1", responseBodyOrder.Data.MidtransTransaction.TransactionId)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyOrder.Data.MidtransTransaction.PaymentStatus)
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"seblak-bombom-restful-api/internal/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StubOIDCUser adalah akun yang "login" di provider OpenID Connect tiruan
+type StubOIDCUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+type stubOIDCAuthorization struct {
+	User          StubOIDCUser
+	Nonce         string
+	CodeChallenge string
+}
+
+// StubOIDCProvider adalah provider OpenID Connect lokal untuk test: discovery, JWKS dan token endpoint
+// yang menandatangani ID token dengan RS256 seperti Google
+type StubOIDCProvider struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+	key          *rsa.PrivateKey
+	mu           sync.Mutex
+	codes        map[string]stubOIDCAuthorization
+}
+
+func NewStubOIDCProvider() *StubOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	stub := &StubOIDCProvider{
+		ClientID:     "stub-client-id",
+		ClientSecret: "stub-client-secret",
+		key:          key,
+		codes:        map[string]stubOIDCAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", stub.handleDiscovery)
+	mux.HandleFunc("/jwks", stub.handleJwks)
+	mux.HandleFunc("/token", stub.handleToken)
+	stub.Server = httptest.NewServer(mux)
+	return stub
+}
+
+func (s *StubOIDCProvider) Config(name string) model.OIDCProviderConfig {
+	return model.OIDCProviderConfig{
+		Name:         name,
+		IssuerURL:    s.Server.URL,
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RedirectURL:  "http://localhost:3000/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+// Authorize meniru user yang menyetujui login di halaman provider, mengembalikan code dan state
+// yang dikirim provider ke redirect URL
+func (s *StubOIDCProvider) Authorize(authorizationURL string, user StubOIDCUser) (string, string) {
+	parsedURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		panic(err)
+	}
+
+	query := parsedURL.Query()
+	code := uuid.NewString()
+	s.mu.Lock()
+	s.codes[code] = stubOIDCAuthorization{
+		User:          user,
+		Nonce:         query.Get("nonce"),
+		CodeChallenge: query.Get("code_challenge"),
+	}
+	s.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (s *StubOIDCProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 s.Server.URL,
+		"authorization_endpoint": s.Server.URL + "/authorize",
+		"token_endpoint":         s.Server.URL + "/token",
+		"jwks_uri":               s.Server.URL + "/jwks",
+	})
+}
+
+func (s *StubOIDCProvider) handleJwks(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "stub-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *StubOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	if r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeStubJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// code hanya bisa ditukar satu kali
+	s.mu.Lock()
+	authorization, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.CodeChallenge {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code_verifier does not match"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := s.signIDToken(map[string]any{
+		"iss":            s.Server.URL,
+		"sub":            authorization.User.Subject,
+		"aud":            s.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          authorization.Nonce,
+		"email":          authorization.User.Email,
+		"email_verified": authorization.User.EmailVerified,
+		"given_name":     authorization.User.GivenName,
+		"family_name":    authorization.User.FamilyName,
+	})
+	if err != nil {
+		writeStubJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (s *StubOIDCProvider) signIDToken(claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "stub-key", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoOIDCAuthorize(t *testing.T) *model.OIDCAuthorizationResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/oidc/stub/authorize", nil)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OIDCAuthorizationResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, strings.HasPrefix(responseBody.Data.AuthorizationURL, oidcProvider.Server.URL+"/authorize?"))
+	assert.NotEmpty(t, responseBody.Data.State)
+
+	return &responseBody.Data
+}
+
+func DoOIDCCallback(t *testing.T, code string, state string) (*http.Response, []byte) {
+	bodyJson, err := json.Marshal(model.OIDCCallbackRequest{
+		Code:        code,
+		State:       state,
+		ReturnToken: true,
+	})
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/oidc/stub/callback", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	return response, bytes
+}
+
+// DoOIDCLogin menjalankan seluruh alur login OpenID Connect dengan provider lokal
+func DoOIDCLogin(t *testing.T, user StubOIDCUser) (*http.Response, []byte) {
+	authorization := DoOIDCAuthorize(t)
+	code, state := oidcProvider.Authorize(authorization.AuthorizationURL, user)
+	assert.Equal(t, authorization.State, state)
+	return DoOIDCCallback(t, code, state)
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	ClearAll()
+	oidcUser := StubOIDCUser{
+		Subject:       "stub-subject-1",
+		Email:         "oidc.customer@gmail.com",
+		EmailVerified: true,
+		GivenName:     "Oidc",
+		FamilyName:    "Customer",
+	}
+
+	response, bytes := DoOIDCLogin(t, oidcUser)
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
+	assert.Equal(t, oidcUser.Email, responseBody.Data.User.Email)
+	assert.Equal(t, oidcUser.GivenName, responseBody.Data.User.FirstName)
+	assert.Equal(t, enum_state.CUSTOMER, responseBody.Data.User.Role)
+
+	// user baru langsung terverifikasi dan memiliki wallet serta cart seperti registrasi biasa
+	newUser := new(entity.User)
+	err = db.Where("email = ?", oidcUser.Email).First(newUser).Error
+	assert.Nil(t, err)
+	assert.True(t, newUser.EmailVerified)
+
+	var totalWallet, totalCart int64
+	db.Model(&entity.Wallet{}).Where("user_id = ?", newUser.ID).Count(&totalWallet)
+	db.Model(&entity.Cart{}).Where("user_id = ?", newUser.ID).Count(&totalCart)
+	assert.Equal(t, int64(1), totalWallet)
+	assert.Equal(t, int64(1), totalCart)
+
+	// login berikutnya dengan subject yang sama memakai user yang sama
+	response, bytes = DoOIDCLogin(t, oidcUser)
+	secondResponse := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, secondResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, responseBody.Data.User.ID, secondResponse.Data.User.ID)
+
+	var totalUser int64
+	db.Model(&entity.User{}).Where("email = ?", oidcUser.Email).Count(&totalUser)
+	assert.Equal(t, int64(1), totalUser)
+}
+
+func TestOIDCLoginLinksExistingUserByVerifiedEmail(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoVerificationEmail(t, "F3196813@gmail.com")
+	currentUser := new(entity.User)
+	err := db.Where("email = ?", "F3196813@gmail.com").First(currentUser).Error
+	assert.Nil(t, err)
+
+	response, bytes := DoOIDCLogin(t, StubOIDCUser{
+		Subject:       "stub-subject-2",
+		Email:         "f3196813@gmail.com",
+		EmailVerified: true,
+		GivenName:     "John",
+	})
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, currentUser.ID, responseBody.Data.User.ID)
+
+	newIdentity := new(entity.UserIdentity)
+	err = db.Where("provider = ? AND subject = ?", "stub", "stub-subject-2").First(newIdentity).Error
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser.ID, newIdentity.UserId)
+
+	// password lama tetap bisa dipakai karena email sudah diverifikasi sebelumnya
+	DoLoginAdminTokenPair(t)
+}
+
+func TestOIDCLoginLinksUnverifiedRegistration(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	response, _ := DoOIDCLogin(t, StubOIDCUser{
+		Subject:       "stub-subject-3",
+		Email:         "F3196813@gmail.com",
+		EmailVerified: true,
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	currentUser := new(entity.User)
+	err := db.Where("email = ?", "F3196813@gmail.com").First(currentUser).Error
+	assert.Nil(t, err)
+	assert.True(t, currentUser.EmailVerified)
+
+	// password dari registrasi yang belum diverifikasi tidak berlaku lagi
+	response, _ = DoLoginAdminWithPassword(t, "JohnDoe123#")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	ClearAll()
+	response, bytes := DoOIDCLogin(t, StubOIDCUser{
+		Subject:       "stub-subject-4",
+		Email:         "oidc.unverified@gmail.com",
+		EmailVerified: false,
+	})
+	responseBody := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "email from oidc provider is not verified!", responseBody.Error)
+
+	var totalUser int64
+	db.Model(&entity.User{}).Where("email = ?", "oidc.unverified@gmail.com").Count(&totalUser)
+	assert.Equal(t, int64(0), totalUser)
+}
+
+func TestOIDCLoginInvalidState(t *testing.T) {
+	ClearAll()
+	authorization := DoOIDCAuthorize(t)
+	code, state := oidcProvider.Authorize(authorization.AuthorizationURL, StubOIDCUser{
+		Subject:       "stub-subject-5",
+		Email:         "oidc.state@gmail.com",
+		EmailVerified: true,
+	})
+
+	response, bytes := DoOIDCCallback(t, code, state+"x")
+	responseBody := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "oidc state is not valid or has expired, please login again!", responseBody.Error)
+
+	response, _ = DoOIDCCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// authorization code hanya bisa ditukar satu kali
+	response, _ = DoOIDCCallback(t, code, state)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
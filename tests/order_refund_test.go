@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminRefundOrderItemToOriginalMethod(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	order := DoCreatePaidSimulatorOrder(t, tokenAdmin, tokenCustomer, product, 2)
+
+	requestBody := model.CreateOrderRefundRequest{
+		Items: []model.CreateOrderRefundItem{
+			{
+				OrderProductId: order.OrderProducts[0].ID,
+				Quantity:       1,
+			},
+		},
+		Destination: enum_state.REFUND_DESTINATION_ORIGINAL_METHOD,
+		Reason:      "Satu porsi tumpah saat diantar",
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderRefundResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, enum_state.REFUND_TYPE_PARTIAL, responseBody.Data.Type)
+	assert.Equal(t, enum_state.REFUND_DESTINATION_ORIGINAL_METHOD, responseBody.Data.Destination)
+	assert.Equal(t, enum_state.REFUND_STATUS_SUCCEEDED, responseBody.Data.Status)
+	assert.Equal(t, product.Price, responseBody.Data.Amount)
+	assert.NotEmpty(t, responseBody.Data.GatewayRefundId)
+	assert.Equal(t, 1, len(responseBody.Data.Items))
+
+	// item yang sama tidak bisa direfund melebihi quantity yang dipesan
+	requestBody.Items[0].Quantity = 2
+	bodyJson, err = json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err = app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// nominal refund tidak boleh melebihi sisa pembayaran
+	requestAmount := model.CreateOrderRefundRequest{
+		Amount:      order.TotalFinalPrice,
+		Destination: enum_state.REFUND_DESTINATION_ORIGINAL_METHOD,
+		Reason:      "Refund melebihi sisa",
+	}
+	bodyJson, err = json.Marshal(requestAmount)
+	assert.Nil(t, err)
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err = app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	requestGet := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), nil)
+	requestGet.Header.Set("Accept", "application/json")
+	requestGet.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	responseGet, err := app.Test(requestGet)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseGet.Body)
+	assert.Nil(t, err)
+
+	responseBodyGet := new(model.ApiResponse[model.OrderRefundSummaryResponse])
+	err = json.Unmarshal(bytes, responseBodyGet)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseGet.StatusCode)
+	assert.Equal(t, 1, len(responseBodyGet.Data.Refunds))
+	assert.Equal(t, product.Price, responseBodyGet.Data.TotalRefunded)
+	assert.Equal(t, order.TotalFinalPrice-product.Price, responseBodyGet.Data.RefundableLeft)
+}
+
+func TestAdminRefundOrderToWallet(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	order := DoCreatePaidSimulatorOrder(t, tokenAdmin, tokenCustomer, product, 1)
+
+	walletBefore := new(entity.Wallet)
+	err := db.Where("user_id = ?", order.UserId).First(walletBefore).Error
+	assert.Nil(t, err)
+
+	// tanpa amount dan items berarti refund seluruh pembayaran
+	requestBody := model.CreateOrderRefundRequest{
+		Destination: enum_state.REFUND_DESTINATION_WALLET,
+		Reason:      "Toko tutup lebih awal",
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderRefundResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, enum_state.REFUND_TYPE_FULL, responseBody.Data.Type)
+	assert.Equal(t, enum_state.REFUND_STATUS_SUCCEEDED, responseBody.Data.Status)
+	assert.Equal(t, order.TotalFinalPrice, responseBody.Data.Amount)
+
+	walletAfter := new(entity.Wallet)
+	err = db.Where("user_id = ?", order.UserId).First(walletAfter).Error
+	assert.Nil(t, err)
+	assert.Equal(t, walletBefore.Balance+order.TotalFinalPrice, walletAfter.Balance)
+
+	// order yang sudah direfund penuh tidak bisa direfund lagi
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err = app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestXenditRefundCallbackUpdatesRefundStatus(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	order := DoCreatePaidSimulatorOrder(t, tokenAdmin, tokenCustomer, product, 1)
+
+	// refund xendit masih diproses sampai callback diterima
+	gatewayRefundId := "rfd-callback-test-1"
+	orderRefund := &entity.OrderRefund{
+		OrderId:         order.ID,
+		RefundedBy:      order.UserId,
+		ReferenceId:     "rfd-reference-test-1",
+		Type:            enum_state.REFUND_TYPE_FULL,
+		Destination:     enum_state.REFUND_DESTINATION_ORIGINAL_METHOD,
+		Amount:          order.TotalFinalPrice,
+		Status:          enum_state.REFUND_STATUS_PENDING,
+		PaymentGateway:  enum_state.PAYMENT_GATEWAY_XENDIT,
+		GatewayRefundId: &gatewayRefundId,
+	}
+	err := db.Create(orderRefund).Error
+	assert.Nil(t, err)
+
+	rawBody := fmt.Sprintf(`{"event":"refund.failed","data":{"id":"%s","status":"FAILED","failure_code":"INSUFFICIENT_BALANCE","updated":"2025-07-02T09:00:00Z"}}`, gatewayRefundId)
+	request := httptest.NewRequest(http.MethodPost, "/api/xendits/refund/notifications/callback", strings.NewReader(rawBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("webhook-id", "evt-refund-test-1")
+	request.Header.Set("X-Callback-Token", viperConfig.GetString("XENDIT_TEST_CALLBACK_TOKEN"))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	updatedRefund := new(entity.OrderRefund)
+	err = db.Where("id = ?", orderRefund.ID).First(updatedRefund).Error
+	assert.Nil(t, err)
+	assert.Equal(t, enum_state.REFUND_STATUS_FAILED, updatedRefund.Status)
+	assert.Equal(t, "INSUFFICIENT_BALANCE", updatedRefund.FailureCode)
+
+	// refund yang gagal tidak mengurangi sisa nominal yang bisa direfund
+	requestGet := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/admin/orders/%d/refunds", order.ID), nil)
+	requestGet.Header.Set("Accept", "application/json")
+	requestGet.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	responseGet, err := app.Test(requestGet)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(responseGet.Body)
+	assert.Nil(t, err)
+
+	responseBodyGet := new(model.ApiResponse[model.OrderRefundSummaryResponse])
+	err = json.Unmarshal(bytes, responseBodyGet)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseGet.StatusCode)
+	assert.Equal(t, float32(0), responseBodyGet.Data.TotalRefunded)
+	assert.Equal(t, order.TotalFinalPrice, responseBodyGet.Data.RefundableLeft)
+}
@@ -52,7 +52,7 @@ func TestCreateOrderAsAdminWithoutDeliveryAndDiscount(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCustomer)
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
 	request.Host = "localhost"
 
 	response, err := app.Test(request, int(time.Second) * 5)
@@ -144,7 +144,7 @@ func TestCreateOrderAsAdminWithDiscountButDeliveryDeleted(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -230,7 +230,7 @@ func TestCreateOrderAsAdminWithDeliveryAndNoDiscount(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -322,7 +322,7 @@ func TestCreateOrderAsAdminWithDeliveryAndDeliveryDeleted(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -380,7 +380,7 @@ func TestCreateOrderAsAdminWithDeliveryAndDiscount(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -479,7 +479,7 @@ func TestCreateOrderAsAdminWithDeliveryAndDiscountUsageExceededLimit(t *testing.
 		request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -568,7 +568,7 @@ func TestCreateOrderBalanceInsufficient(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -619,7 +619,7 @@ func TestCreateOrderDiscountNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -679,7 +679,7 @@ func TestCreateOrderDiscountNotYetActiveDate(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -739,7 +739,7 @@ func TestCreateOrderDiscountExpired(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -794,7 +794,7 @@ func TestCreateOrderDiscountMinOrder(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -850,7 +850,7 @@ func TestCreateOrderPaymentMethodNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -906,7 +906,7 @@ func TestCreateOrderChannelCodeNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -962,7 +962,7 @@ func TestCreateOrderPaymentGatewayNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1018,7 +1018,7 @@ func TestCreateOrderWalletWrongPaymentMethod(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1074,7 +1074,7 @@ func TestCreateOrderWalletWrongChannelCode(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1113,7 +1113,7 @@ func TestGetAllOrderPagination(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=5&page=2", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1164,7 +1164,7 @@ func TestGetAllOrderPaginationSortingColumnDesc(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=5&page=2&column=orders.id&sort_by=desc", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1220,7 +1220,7 @@ func TestGetAllOrderPaginationColumnNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=5&page=2&column=orders.mama", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1261,7 +1261,7 @@ func TestGetAllOrderPaginationSomeProductDeleted(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=5&page=2", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1312,7 +1312,7 @@ func TestGetAllOrderPaginationSearchProduct(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=10&page=1&search=produk", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1363,7 +1363,7 @@ func TestGetAllOrderPaginationSearchProductNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=10&page=1&search=alala", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1419,7 +1419,7 @@ func TestGetAllCurrentUserOrderPagination(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/orders?per_page=10&page=1", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1466,7 +1466,7 @@ func TestUpdateOrderReceivedAsCustomerError(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1498,7 +1498,7 @@ func TestUpdateReceivedOrderAsAdmin(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenAdmin)
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1569,7 +1569,7 @@ func TestUpdateReadyForPickupOrderAsAdmin(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenAdmin)
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1640,7 +1640,7 @@ func TestUpdateReadyForPickupOrderAsCustomer(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1672,7 +1672,7 @@ func TestUpdateBeingDeliveredOrderAsAdmin(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenAdmin)
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1743,7 +1743,7 @@ func TestUpdateBeingDeliveredOrderAsCustomer(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1775,7 +1775,7 @@ func TestUpdateDeliveredOrderAsAdmin(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenAdmin)
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1846,7 +1846,7 @@ func TestUpdateDeliveredOrderAsCustomer(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/orders/%d/status", order.ID), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1912,7 +1912,7 @@ func TestGetOrderById(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", order.ID), nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", custToken)
+	request.Header.Set("Authorization", "Bearer "+custToken)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
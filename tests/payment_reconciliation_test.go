@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminReconcileOrderWithMissedCallback(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_QR_CODE,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+
+	requestSimulate := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/payment-simulator/payments/%s/simulate", responseBody.Data.XenditTransaction.ID), strings.NewReader(`{"status":"SUCCEEDED"}`))
+	requestSimulate.Header.Set("Content-Type", "application/json")
+	requestSimulate.Header.Set("Accept", "application/json")
+	requestSimulate.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	requestSimulate.Host = "localhost"
+
+	responseSimulate, err := app.Test(requestSimulate, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseSimulate.StatusCode)
+
+	// anggap callback tidak pernah sampai, customer sudah membayar tetapi data masih pending
+	err = db.Model(&entity.XenditTransactions{}).Where("id = ?", responseBody.Data.XenditTransaction.ID).Update("status", "PENDING").Error
+	assert.Nil(t, err)
+	err = db.Model(&entity.Order{}).Where("id = ?", responseBody.Data.ID).Update("payment_status", enum_state.PENDING_PAYMENT).Error
+	assert.Nil(t, err)
+
+	requestReconcile := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/payment-reconciliation", responseBody.Data.ID), nil)
+	requestReconcile.Header.Set("Accept", "application/json")
+	requestReconcile.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	requestReconcile.Host = "localhost"
+
+	responseReconcile, err := app.Test(requestReconcile, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseReconcile.Body)
+	assert.Nil(t, err)
+
+	responseBodyReconcile := new(model.ApiResponse[model.PaymentReconciliationResponse])
+	err = json.Unmarshal(bytes, responseBodyReconcile)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseReconcile.StatusCode)
+	assert.True(t, responseBodyReconcile.Data.Updated)
+	assert.Equal(t, "PENDING", responseBodyReconcile.Data.PreviousStatus)
+	assert.Equal(t, "SUCCEEDED", responseBodyReconcile.Data.CurrentStatus)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyReconcile.Data.PaymentStatus)
+
+	// rekonsiliasi ulang tidak mengubah apa pun
+	requestReconcile = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/orders/%d/payment-reconciliation", responseBody.Data.ID), nil)
+	requestReconcile.Header.Set("Accept", "application/json")
+	requestReconcile.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	requestReconcile.Host = "localhost"
+
+	responseReconcile, err = app.Test(requestReconcile, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseReconcile.Body)
+	assert.Nil(t, err)
+
+	responseBodyReconcile = new(model.ApiResponse[model.PaymentReconciliationResponse])
+	err = json.Unmarshal(bytes, responseBodyReconcile)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseReconcile.StatusCode)
+	assert.False(t, responseBodyReconcile.Data.Updated)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyReconcile.Data.PaymentStatus)
+}
+
+func TestAdminReconcileOrderWithoutXenditTransaction(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/admin/orders/999999/payment-reconciliation", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
@@ -55,7 +55,7 @@ func TestCreateProduct(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -126,7 +126,7 @@ func TestCreateProductImageSizeExceedsLimit(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -183,7 +183,7 @@ func TestCreateProductImagePositionBadRequest(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -239,7 +239,7 @@ func TestCreateProductFileNotImage(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -295,7 +295,7 @@ func TestCreateProductImagesMoreThanFive(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -347,7 +347,7 @@ func TestCreateProductImagesPositionNotIncluded(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -403,7 +403,7 @@ func TestCreateProductStockNegativeNumber(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -459,7 +459,7 @@ func TestCreateProductPriceNegativeNumber(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -499,7 +499,7 @@ func TestCreateProductImagesNotUploaded(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -554,7 +554,7 @@ func TestCreateProductCategoryNotFound(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -589,7 +589,7 @@ func TestCreateProductDataBadRequest(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -645,7 +645,7 @@ func TestUpdateProduct(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -716,7 +716,7 @@ func TestUpdateProduct(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -780,7 +780,7 @@ func TestUpdateProductCategoryNotFound(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -850,7 +850,7 @@ func TestUpdateProductCategoryNotFound(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -906,7 +906,7 @@ func TestUpdateProductStockNegativeNumber(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -945,7 +945,7 @@ func TestUpdateProductStockNegativeNumber(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1001,7 +1001,7 @@ func TestUpdateProductPriceNegativeNumber(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1040,7 +1040,7 @@ func TestUpdateProductPriceNegativeNumber(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1096,7 +1096,7 @@ func TestUpdateProductDataBadRequest(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1135,7 +1135,7 @@ func TestUpdateProductDataBadRequest(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1191,7 +1191,7 @@ func TestUpdateProductNotFound(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1262,7 +1262,7 @@ func TestUpdateProductNotFound(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", 1), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1318,7 +1318,7 @@ func TestUpdateProductNewImagesIsNotSameWithNewImagePositions(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1389,7 +1389,7 @@ func TestUpdateProductNewImagesIsNotSameWithNewImagePositions(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1445,7 +1445,7 @@ func TestUpdateProductImageMoreThanFive(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1516,7 +1516,7 @@ func TestUpdateProductImageMoreThanFive(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1572,7 +1572,7 @@ func TestUpdateProductNewImageSizeExceedsLimit(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -1643,7 +1643,7 @@ func TestUpdateProductNewImageSizeExceedsLimit(t *testing.T) {
 
 	request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/products/%+v", responseBody.Data.ID), &c)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err = app.Test(request)
 	assert.Nil(t, err)
@@ -1922,7 +1922,7 @@ func TestDeleteProduct(t *testing.T) {
 
 		request := httptest.NewRequest(http.MethodPost, "/api/products", &b)
 		request.Header.Set("Content-Type", writer.FormDataContentType())
-		request.Header.Set("Authorization", token)
+		request.Header.Set("Authorization", "Bearer "+token)
 
 		response, err := app.Test(request)
 		assert.Nil(t, err)
@@ -1957,7 +1957,7 @@ func TestDeleteProduct(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/products?ids="+getAllIds, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -2003,7 +2003,7 @@ func TestDeleteProductIdNotValid(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/products?ids="+getAllIds, nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/delivery/middleware"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"seblak-bombom-restful-api/internal/rate_limiter"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewRateLimitTestApp membuat app terpisah agar rate limit bisa dites tanpa mengganggu test lain
+func NewRateLimitTestApp(policy model.RateLimitPolicy) *fiber.App {
+	testApp := fiber.New()
+	testApp.Use(func(c *fiber.Ctx) error {
+		if userId := c.Get("X-Test-User-Id"); userId != "" {
+			id, _ := strconv.ParseUint(userId, 10, 64)
+			c.Locals("auth", &model.UserResponse{ID: id})
+		}
+		return c.Next()
+	})
+	testApp.Get("/limited", middleware.NewRateLimit(rate_limiter.NewMemoryStore(), policy, log), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return testApp
+}
+
+func DoRateLimitedRequest(t *testing.T, testApp *fiber.App, headers map[string]string) *http.Response {
+	request := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := testApp.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	return response
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	testApp := NewRateLimitTestApp(model.RateLimitPolicy{
+		Name:   "test",
+		Limit:  2,
+		Period: time.Minute,
+		KeyBy:  enum_state.RATE_LIMIT_KEY_IP,
+	})
+
+	response := DoRateLimitedRequest(t, testApp, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "2;w=60", response.Header.Get("RateLimit-Policy"))
+	assert.Equal(t, "2", response.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", response.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", response.Header.Get("RateLimit-Reset"))
+
+	response = DoRateLimitedRequest(t, testApp, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "0", response.Header.Get("RateLimit-Remaining"))
+
+	response = DoRateLimitedRequest(t, testApp, nil)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Equal(t, "0", response.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", response.Header.Get("Retry-After"))
+}
+
+func TestRateLimitByAPIKey(t *testing.T) {
+	testApp := NewRateLimitTestApp(model.RateLimitPolicy{
+		Name:    "test",
+		Limit:   1,
+		Period:  time.Minute,
+		KeyBy:   enum_state.RATE_LIMIT_KEY_API_KEY,
+		APIKeys: []string{"key-a", "key-b"},
+	})
+
+	response := DoRateLimitedRequest(t, testApp, map[string]string{middleware.HeaderAPIKey: "key-a"})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	response = DoRateLimitedRequest(t, testApp, map[string]string{middleware.HeaderAPIKey: "key-a"})
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+
+	// api key lain punya bucket sendiri
+	response = DoRateLimitedRequest(t, testApp, map[string]string{middleware.HeaderAPIKey: "key-b"})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// tanpa api key memakai bucket IP
+	response = DoRateLimitedRequest(t, testApp, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// api key yang tidak terdaftar ikut bucket IP, bukan bucket baru
+	response = DoRateLimitedRequest(t, testApp, map[string]string{middleware.HeaderAPIKey: "key-unknown"})
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	testApp := NewRateLimitTestApp(model.RateLimitPolicy{
+		Name:   "test",
+		Limit:  1,
+		Period: time.Minute,
+		KeyBy:  enum_state.RATE_LIMIT_KEY_USER,
+	})
+
+	response := DoRateLimitedRequest(t, testApp, map[string]string{"X-Test-User-Id": "1"})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	response = DoRateLimitedRequest(t, testApp, map[string]string{"X-Test-User-Id": "1"})
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+
+	response = DoRateLimitedRequest(t, testApp, map[string]string{"X-Test-User-Id": "2"})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
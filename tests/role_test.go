@@ -0,0 +1,257 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoCreateRole(t *testing.T, token string, name string, permissions []enum_state.Permission) *model.RoleResponse {
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/admin/roles", token, model.CreateRoleRequest{
+		Name:        name,
+		Description: "role " + name,
+		Permissions: permissions,
+	})
+	responseBody := new(model.ApiResponse[model.RoleResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, name, responseBody.Data.Name)
+	assert.ElementsMatch(t, permissions, responseBody.Data.Permissions)
+
+	return &responseBody.Data
+}
+
+// DoAssignStaffRoleToCustomer menjadikan customer test sebagai staff dan mengembalikan id user tersebut
+func DoAssignStaffRoleToCustomer(t *testing.T, tokenAdmin string, roleId uint64) uint64 {
+	customer := new(entity.User)
+	err := db.Where("email = ?", "fauzan.hidayat@binus.ac.id").First(customer).Error
+	assert.Nil(t, err)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPut, fmt.Sprintf("/api/admin/users/%d/staff-role", customer.ID), tokenAdmin, model.AssignStaffRoleRequest{
+		RoleId: roleId,
+	})
+	responseBody := new(model.ApiResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, enum_state.STAFF, responseBody.Data.Role)
+	assert.NotNil(t, responseBody.Data.StaffRole)
+	assert.Equal(t, roleId, responseBody.Data.StaffRole.ID)
+
+	return customer.ID
+}
+
+func TestStaffRoutePermission(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenStaff := DoLoginCustomer(t)
+
+	role := DoCreateRole(t, tokenAdmin, "menu-editor", []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE})
+
+	// sebelum menjadi staff customer tidak bisa mengakses route admin
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/categories", tokenStaff, model.CreateCategoryRequest{
+		Name:        "Makanan",
+		Description: "Kategori makanan",
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "admin access only!", errorResponse.Error)
+
+	DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	// permission berlaku langsung untuk token yang sudah ada
+	DoCreateCategory(t, tokenStaff, "Makanan", "Kategori makanan")
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodPost, "/api/deliveries", tokenStaff, model.CreateDeliveryRequest{
+		City:     "Kebumen",
+		District: "Kebumen",
+		Village:  "Kebumen",
+		Hamlet:   "Kebumen",
+		Cost:     5000,
+	})
+	errorResponse = new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "you don't have permission deliveries.write!", errorResponse.Error)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodGet, "/api/admin/roles", tokenStaff, nil)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// staff tetap bisa mengakses route milik user biasa
+	response, bytes = DoTwoFactorRequest(t, http.MethodGet, "/api/users/current", tokenStaff, nil)
+	currentResponse := new(model.ApiResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, currentResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE}, currentResponse.Data.Permissions)
+}
+
+func TestUpdateRolePermissions(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenStaff := DoLoginCustomer(t)
+
+	role := DoCreateRole(t, tokenAdmin, "menu-editor", []enum_state.Permission{enum_state.PERMISSION_DELIVERIES_WRITE})
+	DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPut, fmt.Sprintf("/api/admin/roles/%d", role.ID), tokenAdmin, model.UpdateRoleRequest{
+		Name:        "menu-editor",
+		Permissions: []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE, enum_state.PERMISSION_CATEGORIES_WRITE},
+	})
+	responseBody := new(model.ApiResponse[model.RoleResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE}, responseBody.Data.Permissions)
+
+	DoCreateCategory(t, tokenStaff, "Makanan", "Kategori makanan")
+
+	response, _ = DoTwoFactorRequest(t, http.MethodPost, "/api/deliveries", tokenStaff, model.CreateDeliveryRequest{
+		City:     "Kebumen",
+		District: "Kebumen",
+		Village:  "Kebumen",
+		Hamlet:   "Kebumen",
+		Cost:     5000,
+	})
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestCreateRoleInvalidPermission(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/admin/roles", tokenAdmin, model.CreateRoleRequest{
+		Name:        "menu-editor",
+		Permissions: []enum_state.Permission{"products.delete_everything"},
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "invalid permission : products.delete_everything", errorResponse.Error)
+}
+
+func TestCreateRoleAdminOnlyPermission(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenStaff := DoLoginCustomer(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/admin/roles", tokenAdmin, model.CreateRoleRequest{
+		Name:        "role-manager",
+		Permissions: []enum_state.Permission{enum_state.PERMISSION_ROLES_MANAGE},
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "permission roles.manage can only be held by admin!", errorResponse.Error)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodGet, "/api/admin/permissions", tokenAdmin, nil)
+	permissionsResponse := new(model.ApiResponse[[]enum_state.Permission])
+	err = json.Unmarshal(bytes, permissionsResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotContains(t, permissionsResponse.Data, enum_state.PERMISSION_ROLES_MANAGE)
+
+	// permission khusus admin yang terlanjur tersimpan di role staff tidak berlaku
+	role := DoCreateRole(t, tokenAdmin, "menu-editor", []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE})
+	err = db.Create(&entity.RolePermission{RoleId: role.ID, Permission: string(enum_state.PERMISSION_ROLES_MANAGE)}).Error
+	assert.Nil(t, err)
+	staffId := DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodGet, "/api/admin/roles", tokenStaff, nil)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/users/%d/role", staffId), tokenStaff, model.UpdateUserRoleRequest{
+		Role: enum_state.ADMIN,
+	})
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestCreateRoleDuplicateName(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/admin/roles", tokenAdmin, model.CreateRoleRequest{
+		Name:        "cashier",
+		Permissions: []enum_state.Permission{enum_state.PERMISSION_ORDERS_READ},
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Equal(t, "role name has already exists!", errorResponse.Error)
+}
+
+func TestDeleteRoleInUse(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoLoginCustomer(t)
+
+	role := DoCreateRole(t, tokenAdmin, "menu-editor", []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE})
+	userId := DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodDelete, fmt.Sprintf("/api/admin/roles/%d", role.ID), tokenAdmin, nil)
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Equal(t, "role is still assigned to 1 users!", errorResponse.Error)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodDelete, fmt.Sprintf("/api/admin/users/%d/staff-role", userId), tokenAdmin, nil)
+	userResponse := new(model.ApiResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, userResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, enum_state.CUSTOMER, userResponse.Data.Role)
+	assert.Nil(t, userResponse.Data.StaffRole)
+	assert.Empty(t, userResponse.Data.Permissions)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodDelete, fmt.Sprintf("/api/admin/roles/%d", role.ID), tokenAdmin, nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestPayoutRequestRoutePermission(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenCustomer := DoLoginCustomer(t)
+
+	// customer biasa tidak boleh melihat atau membatalkan payout
+	response, _ := DoTwoFactorRequest(t, http.MethodGet, "/api/xendit/payout-request/payout-1", tokenCustomer, nil)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, _ = DoTwoFactorRequest(t, http.MethodPost, "/api/xendit/payout-request/payout-1/cancel", tokenCustomer, nil)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	role := DoCreateRole(t, tokenAdmin, "menu-editor", []enum_state.Permission{enum_state.PERMISSION_CATEGORIES_WRITE})
+	DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/xendit/payout-request/payout-1/cancel", tokenCustomer, nil)
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "you don't have permission payouts.create!", errorResponse.Error)
+}
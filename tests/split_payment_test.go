@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoCreateSplitPaymentOrder(t *testing.T, tokenCustomer string, product *model.ProductResponse) *model.OrderResponse {
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_QR_CODE,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		UseWallet:      true,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  2,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+
+	return &responseBody.Data
+}
+
+func DoSimulatePayment(t *testing.T, tokenAdmin string, xenditTransactionId string, status string) {
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/payment-simulator/payments/%s/simulate", xenditTransactionId), strings.NewReader(fmt.Sprintf(`{"status":"%s"}`, status)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func DoGetOrderById(t *testing.T, token string, orderId uint64) *model.OrderResponse {
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", orderId), nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return &responseBody.Data
+}
+
+func TestCreateOrderSplitPaymentWalletAndQRCodeSucceeded(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	DoSetBalanceManually(tokenCustomer, 20000)
+
+	order := DoCreateSplitPaymentOrder(t, tokenCustomer, product)
+	assert.Equal(t, enum_state.PENDING_PAYMENT, order.PaymentStatus)
+	assert.Equal(t, order.TotalFinalPrice-20000, float32(order.XenditTransaction.Amount))
+	assert.Equal(t, 2, len(order.PaymentLegs))
+
+	// saldo wallet langsung ditahan selama menunggu pembayaran QR code
+	currentUser := GetCurrentUserByToken(t, tokenCustomer)
+	assert.Equal(t, float32(0), currentUser.Wallet.Balance)
+
+	DoSimulatePayment(t, tokenAdmin, order.XenditTransaction.ID, "SUCCEEDED")
+
+	paidOrder := DoGetOrderById(t, tokenCustomer, order.ID)
+	assert.Equal(t, enum_state.PAID_PAYMENT, paidOrder.PaymentStatus)
+	assert.Equal(t, 2, len(paidOrder.PaymentLegs))
+	for _, leg := range paidOrder.PaymentLegs {
+		assert.Equal(t, enum_state.ORDER_PAYMENT_LEG_PAID, leg.Status)
+		if leg.Source == enum_state.ORDER_PAYMENT_LEG_SOURCE_WALLET {
+			assert.Equal(t, float32(20000), leg.Amount)
+		}
+	}
+
+	currentUser = GetCurrentUserByToken(t, tokenCustomer)
+	assert.Equal(t, float32(0), currentUser.Wallet.Balance)
+}
+
+func TestCreateOrderSplitPaymentExpiredReleasesWalletBalance(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	DoSetBalanceManually(tokenCustomer, 20000)
+
+	order := DoCreateSplitPaymentOrder(t, tokenCustomer, product)
+	DoSimulatePayment(t, tokenAdmin, order.XenditTransaction.ID, "EXPIRED")
+
+	expiredOrder := DoGetOrderById(t, tokenCustomer, order.ID)
+	assert.Equal(t, enum_state.EXPIRED_PAYMENT, expiredOrder.PaymentStatus)
+	for _, leg := range expiredOrder.PaymentLegs {
+		if leg.Source == enum_state.ORDER_PAYMENT_LEG_SOURCE_WALLET {
+			assert.Equal(t, enum_state.ORDER_PAYMENT_LEG_RELEASED, leg.Status)
+		} else {
+			assert.Equal(t, enum_state.ORDER_PAYMENT_LEG_FAILED, leg.Status)
+		}
+	}
+
+	// saldo yang ditahan dikembalikan ke wallet customer
+	currentUser := GetCurrentUserByToken(t, tokenCustomer)
+	assert.Equal(t, float32(20000), currentUser.Wallet.Balance)
+}
+
+func TestCreateOrderSplitPaymentExpiredHoldsReleaseWhenWalletFrozen(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	DoSetBalanceManually(tokenCustomer, 20000)
+
+	holdCreditsWhenFrozen := walletConfig.HoldCreditsWhenFrozen
+	walletConfig.HoldCreditsWhenFrozen = true
+	defer func() {
+		walletConfig.HoldCreditsWhenFrozen = holdCreditsWhenFrozen
+	}()
+
+	order := DoCreateSplitPaymentOrder(t, tokenCustomer, product)
+
+	// wallet dibekukan admin selama menunggu pembayaran QR code
+	customer := GetCurrentUserByToken(t, tokenCustomer)
+	err := db.Model(&entity.Wallet{}).Where("user_id = ?", customer.ID).Update("status", enum_state.INACIVE_WALLET).Error
+	assert.Nil(t, err)
+
+	DoSimulatePayment(t, tokenAdmin, order.XenditTransaction.ID, "EXPIRED")
+
+	// saldo yang ditahan belum masuk selama wallet dibekukan, dicatat sebagai credit pending
+	customer = GetCurrentUserByToken(t, tokenCustomer)
+	assert.Equal(t, float32(0), customer.Wallet.Balance)
+
+	var pendingCredits int64
+	err = db.Model(&entity.WalletTransactions{}).Where("user_id = ? AND order_id = ? AND flow_type = ? AND status = ?", customer.ID, order.ID, enum_state.WALLET_FLOW_TYPE_CREDIT, enum_state.WALLET_TRANSACTION_STATUS_PENDING).Count(&pendingCredits).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), pendingCredits)
+}
+
+func TestCreateOrderSplitPaymentWithSufficientBalance(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+	DoSetBalanceManually(tokenCustomer, 500000)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_QR_CODE,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		UseWallet:      true,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  2,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// saldo tidak berubah karena order ditolak
+	currentUser := GetCurrentUserByToken(t, tokenCustomer)
+	assert.Equal(t, float32(500000), currentUser.Wallet.Balance)
+}
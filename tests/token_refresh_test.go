@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoRefreshToken(t *testing.T, refreshToken string) (*http.Response, *model.ApiResponse[model.UserTokenResponse]) {
+	requestBody := model.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		ReturnToken:  true,
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/token/refresh", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func TestRefreshTokenRotatesTokenPair(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenPair := DoLoginAdminTokenPair(t)
+
+	response, responseBody := DoRefreshToken(t, tokenPair.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEqual(t, tokenPair.RefreshToken, responseBody.Data.RefreshToken)
+	// rotasi tidak memperpanjang masa berlaku sesi login
+	assert.True(t, responseBody.Data.RefreshTokenExpiryDate.Equal(tokenPair.RefreshTokenExpiryDate))
+
+	// access token baru bisa dipakai tanpa lookup tabel tokens
+	request := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+responseBody.Data.Token)
+
+	responseCurrent, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseCurrent.StatusCode)
+
+	// refresh token disimpan dalam bentuk hash
+	var storedTokens int64
+	err = db.Table("tokens").Where("token = ?", responseBody.Data.RefreshToken).Count(&storedTokens).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), storedTokens)
+}
+
+func TestRefreshTokenReuseRevokesTokenFamily(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenPair := DoLoginAdminTokenPair(t)
+
+	response, rotated := DoRefreshToken(t, tokenPair.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// refresh token lama dipakai ulang, seluruh family dicabut
+	response, _ = DoRefreshToken(t, tokenPair.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// refresh token terbaru dari family yang sama ikut tidak berlaku
+	response, _ = DoRefreshToken(t, rotated.Data.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestRefreshTokenInvalid(t *testing.T) {
+	ClearAll()
+
+	response, _ := DoRefreshToken(t, "not-a-refresh-token")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
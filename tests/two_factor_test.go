@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/helper/totp_helper"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoTwoFactorRequest(t *testing.T, method string, target string, token string, requestBody any) (*http.Response, []byte) {
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(method, target, strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	return response, bytes
+}
+
+// DoEnableTwoFactor mengaktifkan 2FA untuk user pemilik token, mengembalikan secret TOTP dan kode pemulihan
+func DoEnableTwoFactor(t *testing.T, token string) (string, []string) {
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/users/current/two-factor/setup", token, model.SetupTwoFactorRequest{
+		Password: "JohnDoe123#",
+	})
+	setupResponse := new(model.ApiResponse[model.TwoFactorSetupResponse])
+	err := json.Unmarshal(bytes, setupResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, setupResponse.Data.Secret)
+	assert.Contains(t, setupResponse.Data.ProvisioningURI, "otpauth://totp/")
+
+	code, err := totp_helper.GenerateCode(setupResponse.Data.Secret, time.Now())
+	assert.Nil(t, err)
+	response, bytes = DoTwoFactorRequest(t, http.MethodPost, "/api/users/current/two-factor/enable", token, model.TwoFactorCodeRequest{
+		Code: code,
+	})
+	enableResponse := new(model.ApiResponse[model.TwoFactorRecoveryCodesResponse])
+	err = json.Unmarshal(bytes, enableResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, enableResponse.Data.RecoveryCodes)
+
+	return setupResponse.Data.Secret, enableResponse.Data.RecoveryCodes
+}
+
+// DoLoginAdminTwoFactorChallenge login dengan password milik admin yang sudah memakai 2FA
+func DoLoginAdminTwoFactorChallenge(t *testing.T) *model.TwoFactorChallengeResponse {
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/users/login", "", model.LoginUserRequest{
+		Email:       "F3196813@gmail.com",
+		Password:    "JohnDoe123#",
+		ReturnToken: true,
+	})
+	responseBody := new(model.ApiResponse[model.TwoFactorChallengeResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, responseBody.Data.TwoFactorRequired)
+	assert.NotEmpty(t, responseBody.Data.ChallengeToken)
+
+	return &responseBody.Data
+}
+
+func TestEnableTwoFactor(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	_, recoveryCodes := DoEnableTwoFactor(t, token)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodGet, "/api/users/current/two-factor", token, nil)
+	responseBody := new(model.ApiResponse[model.TwoFactorStatusResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, responseBody.Data.Enabled)
+	assert.NotNil(t, responseBody.Data.EnabledAt)
+	assert.Equal(t, int64(len(recoveryCodes)), responseBody.Data.RemainingRecoveryCodes)
+}
+
+func TestLoginWithTwoFactorCode(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	secret, _ := DoEnableTwoFactor(t, token)
+
+	challenge := DoLoginAdminTwoFactorChallenge(t)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/users/login/two-factor", "", model.LoginTwoFactorRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           "000000",
+		ReturnToken:    true,
+	})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "two-factor code is not valid!", errorResponse.Error)
+
+	SkipLoginBackoff()
+	// kode langkah sekarang sudah dipakai saat enable, jadi pakai kode langkah berikutnya
+	code, err := totp_helper.GenerateCode(secret, time.Now().Add(30*time.Second))
+	assert.Nil(t, err)
+	response, bytes = DoTwoFactorRequest(t, http.MethodPost, "/api/users/login/two-factor", "", model.LoginTwoFactorRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           code,
+		ReturnToken:    true,
+	})
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
+	assert.True(t, responseBody.Data.User.TwoFactorEnabled)
+
+	// kode yang sama tidak bisa dipakai ulang
+	challenge = DoLoginAdminTwoFactorChallenge(t)
+	response, _ = DoTwoFactorRequest(t, http.MethodPost, "/api/users/login/two-factor", "", model.LoginTwoFactorRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           code,
+		ReturnToken:    true,
+	})
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLoginWithTwoFactorRecoveryCode(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	_, recoveryCodes := DoEnableTwoFactor(t, token)
+
+	challenge := DoLoginAdminTwoFactorChallenge(t)
+	response, bytes := DoTwoFactorRequest(t, http.MethodPost, "/api/users/login/two-factor", "", model.LoginTwoFactorRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           recoveryCodes[0],
+		ReturnToken:    true,
+	})
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err := json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+
+	// kode pemulihan hanya berlaku satu kali
+	challenge = DoLoginAdminTwoFactorChallenge(t)
+	response, _ = DoTwoFactorRequest(t, http.MethodPost, "/api/users/login/two-factor", "", model.LoginTwoFactorRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           recoveryCodes[0],
+		ReturnToken:    true,
+	})
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodGet, "/api/users/current/two-factor", responseBody.Data.Token, nil)
+	statusResponse := new(model.ApiResponse[model.TwoFactorStatusResponse])
+	err = json.Unmarshal(bytes, statusResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int64(len(recoveryCodes)-1), statusResponse.Data.RemainingRecoveryCodes)
+}
+
+func TestTwoFactorFreshConfirmationRequired(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	_, recoveryCodes := DoEnableTwoFactor(t, token)
+
+	// anggap konfirmasi 2FA sesi ini sudah kedaluwarsa
+	err := db.Model(&entity.UserSession{}).Where("1 = 1").Update("two_factor_verified_at", nil).Error
+	assert.Nil(t, err)
+
+	target := fmt.Sprintf("/api/wallets/%d/withdraw-approval", 999)
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, target, token, map[string]any{})
+	errorResponse := new(model.ErrorResponse[string])
+	err = json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "two-factor confirmation is required, please confirm with your two-factor code!", errorResponse.Error)
+
+	response, bytes = DoTwoFactorRequest(t, http.MethodPost, "/api/users/current/two-factor/confirm", token, model.TwoFactorCodeRequest{
+		Code: recoveryCodes[0],
+	})
+	confirmResponse := new(model.ApiResponse[model.TwoFactorConfirmResponse])
+	err = json.Unmarshal(bytes, confirmResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, confirmResponse.Data.ExpiresAt.After(confirmResponse.Data.VerifiedAt))
+
+	response, _ = DoTwoFactorRequest(t, http.MethodPatch, target, token, map[string]any{})
+	assert.NotEqual(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestTwoFactorSurvivesProfileUpdate(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	token := DoLoginAdmin(t)
+	DoEnableTwoFactor(t, token)
+
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, "/api/users/current", token, model.UpdateUserRequest{
+		FirstName: "john-test",
+		LastName:  "doe-test",
+		Email:     "F3196813@gmail.com",
+		Phone:     "99999999999",
+	})
+	updateResponse := new(model.ApiResponse[model.UserResponse])
+	err := json.Unmarshal(bytes, updateResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "john-test", updateResponse.Data.FirstName)
+	assert.Equal(t, enum_state.ADMIN, updateResponse.Data.Role)
+
+	// perubahan profil tidak boleh menonaktifkan 2FA
+	response, bytes = DoTwoFactorRequest(t, http.MethodGet, "/api/users/current/two-factor", token, nil)
+	statusResponse := new(model.ApiResponse[model.TwoFactorStatusResponse])
+	err = json.Unmarshal(bytes, statusResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, statusResponse.Data.Enabled)
+	assert.NotNil(t, statusResponse.Data.EnabledAt)
+}
+
+func TestTwoFactorRequiredForStaffMovingMoney(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoRegisterCustomer(t)
+	tokenAdmin := DoLoginAdmin(t)
+	tokenStaff := DoLoginCustomer(t)
+
+	role := DoCreateRole(t, tokenAdmin, "wallet-officer", []enum_state.Permission{enum_state.PERMISSION_WALLETS_FREEZE})
+	staffId := DoAssignStaffRoleToCustomer(t, tokenAdmin, role.ID)
+
+	// staff pemegang permission yang memindahkan uang tidak boleh lolos tanpa 2FA
+	response, bytes := DoTwoFactorRequest(t, http.MethodPatch, fmt.Sprintf("/api/admin/wallets/%d/freeze", staffId), tokenStaff, model.UpdateWalletStatusRequest{})
+	errorResponse := new(model.ErrorResponse[string])
+	err := json.Unmarshal(bytes, errorResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "two-factor authentication must be enabled to move money!", errorResponse.Error)
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoGetUserSessions(t *testing.T, token string) []model.UserSessionResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/current/sessions", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[[]model.UserSessionResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return responseBody.Data
+}
+
+func TestGetUserSessions(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	DoLoginAdminTokenPair(t)
+
+	requestBody := model.LoginUserRequest{
+		Email:       "F3196813@gmail.com",
+		Password:    "JohnDoe123#",
+		ReturnToken: true,
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "SeblakPOS/1.0")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	sessions := DoGetUserSessions(t, responseBody.Data.Token)
+	assert.Equal(t, 2, len(sessions))
+
+	totalCurrent := 0
+	for _, session := range sessions {
+		if session.IsCurrent {
+			totalCurrent++
+			assert.Equal(t, "SeblakPOS/1.0", session.UserAgent)
+		}
+	}
+	assert.Equal(t, 1, totalCurrent)
+}
+
+func TestRevokeUserSession(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	otherDevice := DoLoginAdminTokenPair(t)
+	currentDevice := DoLoginAdminTokenPair(t)
+
+	var otherSessionId string
+	for _, session := range DoGetUserSessions(t, currentDevice.Token) {
+		if !session.IsCurrent {
+			otherSessionId = session.ID
+		}
+	}
+	assert.NotEmpty(t, otherSessionId)
+
+	request := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/current/sessions/%s", otherSessionId), nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+currentDevice.Token)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// device lain tidak bisa memperpanjang sesinya lagi
+	responseRefresh, _ := DoRefreshToken(t, otherDevice.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, responseRefresh.StatusCode)
+	assert.Equal(t, 1, len(DoGetUserSessions(t, currentDevice.Token)))
+
+	// access token device lain juga langsung tidak berlaku
+	responseCurrent, _ := DoTwoFactorRequest(t, http.MethodGet, "/api/users/current", otherDevice.Token, nil)
+	assert.Equal(t, http.StatusUnauthorized, responseCurrent.StatusCode)
+
+	// sesi yang sedang dipakai harus diakhiri lewat logout
+	sessions := DoGetUserSessions(t, currentDevice.Token)
+	request = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/current/sessions/%s", sessions[0].ID), nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+currentDevice.Token)
+
+	response, err = app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestRevokeOtherUserSessions(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	firstDevice := DoLoginAdminTokenPair(t)
+	secondDevice := DoLoginAdminTokenPair(t)
+	currentDevice := DoLoginAdminTokenPair(t)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/current/sessions", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+currentDevice.Token)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	responseRefresh, _ := DoRefreshToken(t, firstDevice.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, responseRefresh.StatusCode)
+	responseRefresh, _ = DoRefreshToken(t, secondDevice.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, responseRefresh.StatusCode)
+	responseRefresh, _ = DoRefreshToken(t, currentDevice.RefreshToken)
+	assert.Equal(t, http.StatusOK, responseRefresh.StatusCode)
+}
+
+func TestUpdatePasswordRevokesOtherUserSessions(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	otherDevice := DoLoginAdminTokenPair(t)
+	currentDevice := DoLoginAdminTokenPair(t)
+
+	requestBody := model.UpdateUserPasswordRequest{
+		OldPassword:        "JohnDoe123#",
+		NewPassword:        "JohnDoe1234#",
+		NewPasswordConfirm: "JohnDoe1234#",
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPatch, "/api/users/current/password", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+currentDevice.Token)
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	responseRefresh, _ := DoRefreshToken(t, otherDevice.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, responseRefresh.StatusCode)
+	responseRefresh, _ = DoRefreshToken(t, currentDevice.RefreshToken)
+	assert.Equal(t, http.StatusOK, responseRefresh.StatusCode)
+}
@@ -163,8 +163,9 @@ func TestLogin(t *testing.T) {
 	DoRegisterAdmin(t)
 
 	requestBody := model.LoginUserRequest{
-		Email:    "F3196813@gmail.com",
-		Password: "JohnDoe123#",
+		Email:       "F3196813@gmail.com",
+		Password:    "JohnDoe123#",
+		ReturnToken: true,
 	}
 
 	DoVerificationEmail(t, requestBody.Email)
@@ -190,6 +191,62 @@ func TestLogin(t *testing.T) {
 	assert.NotNil(t, responseBody.Data.ExpiryDate)
 }
 
+func TestLoginWithCookieForBrowser(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	requestBody := model.LoginUserRequest{
+		Email:    "F3196813@gmail.com",
+		Password: "JohnDoe123#",
+	}
+
+	DoVerificationEmail(t, requestBody.Email)
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	// tanpa return_token, token hanya dikirim lewat cookie
+	responseBody := new(model.ApiResponse[model.UserTokenResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Empty(t, responseBody.Data.Token)
+
+	var accessToken *http.Cookie
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == "access_token" {
+			accessToken = cookie
+		}
+	}
+	assert.NotNil(t, accessToken)
+
+	requestCurrent := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
+	requestCurrent.Header.Set("Accept", "application/json")
+	requestCurrent.AddCookie(&http.Cookie{Name: "access_token", Value: accessToken.Value})
+
+	responseCurrent, err := app.Test(requestCurrent, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseCurrent.StatusCode)
+
+	// token tanpa skema Bearer tidak diterima
+	requestRawToken := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
+	requestRawToken.Header.Set("Accept", "application/json")
+	requestRawToken.Header.Set("Authorization", accessToken.Value)
+
+	responseRawToken, err := app.Test(requestRawToken, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, responseRawToken.StatusCode)
+}
+
 func TestLoginFailed(t *testing.T) {
 	ClearAll()
 	DoRegisterAdmin(t)
@@ -221,17 +278,13 @@ func TestLoginFailed(t *testing.T) {
 
 func TestLogout(t *testing.T) {
 	ClearAll()
-	TestLogin(t)
-
-	user := new(entity.User)
-	err := db.Preload("Token").Where("email = ?", "johndoe@email.com").First(&user).Error
-	assert.Nil(t, err)
-	assert.NotNil(t, user.Token.Token)
+	DoRegisterAdmin(t)
+	tokenPair := DoLoginAdminTokenPair(t)
 
 	request := httptest.NewRequest(http.MethodDelete, "/api/users/logout", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token.Token)
+	request.Header.Set("Authorization", "Bearer "+tokenPair.Token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -245,6 +298,14 @@ func TestLogout(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, response.StatusCode)
 	assert.True(t, responseBody.Data)
+
+	// refresh token dari sesi yang sudah logout ikut dicabut
+	responseRefresh, _ := DoRefreshToken(t, tokenPair.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, responseRefresh.StatusCode)
+
+	// access token dari sesi yang sudah logout langsung ditolak walaupun belum kedaluwarsa
+	responseCurrent, _ := DoTwoFactorRequest(t, http.MethodGet, "/api/users/current", tokenPair.Token, nil)
+	assert.Equal(t, http.StatusUnauthorized, responseCurrent.StatusCode)
 }
 
 func TestLogoutWrongAuthorization(t *testing.T) {
@@ -254,7 +315,7 @@ func TestLogoutWrongAuthorization(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/users/logout", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", fakeToken)
+	request.Header.Set("Authorization", "Bearer "+fakeToken)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -278,7 +339,7 @@ func TestGetCurrentUser(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -308,7 +369,7 @@ func TestGetCurrentUserFailed(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/users/current", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token+"adasd")
+	request.Header.Set("Authorization", "Bearer "+token+"adasd")
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -341,7 +402,7 @@ func TestChangePasswordFailedConfirmation(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/current/password", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -374,7 +435,7 @@ func TestChangePassword(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/current/password", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -407,7 +468,7 @@ func TestChangePasswordOldPasswordIsWrong(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/current/password", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -441,7 +502,7 @@ func TestChangeProfile(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/current", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -475,7 +536,7 @@ func TestChangeProfileEmailDuplicate(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/current", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", token)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response, err := app.Test(request, int(time.Second)*5)
 	assert.Nil(t, err)
@@ -882,6 +943,7 @@ func TestForgotPasswordResetPasswordSuccess(t *testing.T) {
 	requestLogin := new(model.LoginUserRequest)
 	requestLogin.Email = "F3196813@gmail.com"
 	requestLogin.Password = "Rahasia123#!"
+	requestLogin.ReturnToken = true
 
 	bodyJson, err = json.Marshal(requestLogin)
 	assert.Nil(t, err)
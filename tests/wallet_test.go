@@ -30,14 +30,10 @@ func TestWithdrawRequestByCustomerWallet(t *testing.T) {
 	customer := GetCurrentUserByToken(t, tokenCust)
 
 	requestBody := model.WithdrawWalletRequest{
-		UserId:            customer.ID,
-		Method:            enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
-		BankName:          "",
-		BankAccountNumber: "",
-		BankAccountName:   "",
-		Status:            enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING,
-		Amount:            93000,
-		Note:              "Saya mau narik duit ya",
+		UserId: customer.ID,
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 93000,
+		Note:   "Saya mau narik duit ya",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -45,7 +41,7 @@ func TestWithdrawRequestByCustomerWallet(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -78,14 +74,10 @@ func TestWithdrawRequestByCustomerAdminApproval(t *testing.T) {
 	customer := GetCurrentUserByToken(t, tokenCust)
 
 	requestBody := model.WithdrawWalletRequest{
-		UserId:            customer.ID,
-		Method:            enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
-		BankName:          "",
-		BankAccountNumber: "",
-		BankAccountName:   "",
-		Status:            enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING,
-		Amount:            93000,
-		Note:              "Saya mau narik duit ya",
+		UserId: customer.ID,
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 93000,
+		Note:   "Saya mau narik duit ya",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -93,7 +85,7 @@ func TestWithdrawRequestByCustomerAdminApproval(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -125,7 +117,7 @@ func TestWithdrawRequestByCustomerAdminApproval(t *testing.T) {
 	requestApproval := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/wallets/%d/withdraw-approval", responseBody.Data.ID), strings.NewReader(string(bodyJson)))
 	requestApproval.Header.Set("Content-Type", "application/json")
 	requestApproval.Header.Set("Accept", "application/json")
-	requestApproval.Header.Set("Authorization", tokenAdmin)
+	requestApproval.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	responseApproval, err := app.Test(requestApproval)
 	assert.Nil(t, err)
@@ -163,14 +155,10 @@ func TestWithdrawRequestByCustomerAdminRejected(t *testing.T) {
 	customer := GetCurrentUserByToken(t, tokenCust)
 
 	requestBody := model.WithdrawWalletRequest{
-		UserId:            customer.ID,
-		Method:            enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
-		BankName:          "",
-		BankAccountNumber: "",
-		BankAccountName:   "",
-		Status:            enum_state.WALLET_WITHDRAW_REQUEST_STATUS_PENDING,
-		Amount:            93000,
-		Note:              "Saya mau narik duit ya",
+		UserId: customer.ID,
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 93000,
+		Note:   "Saya mau narik duit ya",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -178,7 +166,7 @@ func TestWithdrawRequestByCustomerAdminRejected(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", tokenCust)
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -210,7 +198,7 @@ func TestWithdrawRequestByCustomerAdminRejected(t *testing.T) {
 	requestApproval := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/wallets/%d/withdraw-approval", responseBody.Data.ID), strings.NewReader(string(bodyJson)))
 	requestApproval.Header.Set("Content-Type", "application/json")
 	requestApproval.Header.Set("Accept", "application/json")
-	requestApproval.Header.Set("Authorization", tokenAdmin)
+	requestApproval.Header.Set("Authorization", "Bearer "+tokenAdmin)
 
 	responseApproval, err := app.Test(requestApproval)
 	assert.Nil(t, err)
@@ -233,3 +221,211 @@ func TestWithdrawRequestByCustomerAdminRejected(t *testing.T) {
 	customerBalance := GetCurrentUserByToken(t, tokenCust)
 	assert.Equal(t, float32(100000), customerBalance.Wallet.Balance)
 }
+
+func TestAdminWalletAdjustmentCredit(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	// set saldo wallet
+	DoSetBalanceManually(tokenCust, float32(100000))
+
+	customer := GetCurrentUserByToken(t, tokenCust)
+	tokenAdmin := DoLoginAdmin(t)
+
+	requestBody := model.WalletAdjustmentRequest{
+		Amount: 15000,
+		Reason: "Kompensasi keterlambatan pengiriman",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/wallets/%d/adjustments", customer.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.WalletAdjustmentResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.ID)
+	assert.Equal(t, float32(15000), responseBody.Data.Amount)
+	assert.Equal(t, requestBody.Reason, responseBody.Data.Reason)
+	assert.Equal(t, enum_state.WALLET_ADJUSTMENT_STATUS_APPROVED, responseBody.Data.Status)
+	assert.NotNil(t, responseBody.Data.ProcessedBy)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(115000), customerBalance.Wallet.Balance)
+}
+
+func TestAdminWalletAdjustmentDebitInsufficientBalance(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	// set saldo wallet
+	DoSetBalanceManually(tokenCust, float32(10000))
+
+	customer := GetCurrentUserByToken(t, tokenCust)
+	tokenAdmin := DoLoginAdmin(t)
+
+	requestBody := model.WalletAdjustmentRequest{
+		Amount: -20000,
+		Reason: "Koreksi top up ganda",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/wallets/%d/adjustments", customer.ID), strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(10000), customerBalance.Wallet.Balance)
+}
+
+func TestWithdrawRequestRejectedWhenWalletFrozen(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	// set saldo wallet
+	DoSetBalanceManually(tokenCust, float32(100000))
+
+	customer := GetCurrentUserByToken(t, tokenCust)
+	tokenAdmin := DoLoginAdmin(t)
+
+	requestBodyFreeze := model.UpdateWalletStatusRequest{
+		Reason: "Indikasi transaksi mencurigakan",
+	}
+
+	bodyJson, err := json.Marshal(requestBodyFreeze)
+	assert.Nil(t, err)
+	requestFreeze := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/admin/wallets/%d/freeze", customer.ID), strings.NewReader(string(bodyJson)))
+	requestFreeze.Header.Set("Content-Type", "application/json")
+	requestFreeze.Header.Set("Accept", "application/json")
+	requestFreeze.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	responseFreeze, err := app.Test(requestFreeze)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(responseFreeze.Body)
+	assert.Nil(t, err)
+
+	responseBodyFreeze := new(model.ApiResponse[model.WalletResponse])
+	err = json.Unmarshal(bytes, responseBodyFreeze)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseFreeze.StatusCode)
+	assert.Equal(t, enum_state.INACIVE_WALLET, responseBodyFreeze.Data.Status)
+	assert.Equal(t, requestBodyFreeze.Reason, responseBodyFreeze.Data.StatusReason)
+
+	requestBody := model.WithdrawWalletRequest{
+		UserId: customer.ID,
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 50000,
+		Note:   "Saya mau narik duit ya",
+	}
+
+	bodyJson, err = json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(100000), customerBalance.Wallet.Balance)
+}
+
+func TestWithdrawRequestCancelledByCustomer(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	// set saldo wallet
+	DoSetBalanceManually(tokenCust, float32(100000))
+
+	requestBody := model.WithdrawWalletRequest{
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 40000,
+		Note:   "Saya mau narik duit ya",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.WithdrawWalletResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(60000), customerBalance.Wallet.Balance)
+
+	requestCancel := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/wallets/withdraw-requests/%d/cancel", responseBody.Data.ID), nil)
+	requestCancel.Header.Set("Accept", "application/json")
+	requestCancel.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	responseCancel, err := app.Test(requestCancel)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseCancel.Body)
+	assert.Nil(t, err)
+
+	responseBodyCancel := new(model.ApiResponse[model.WithdrawWalletResponse])
+	err = json.Unmarshal(bytes, responseBodyCancel)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseCancel.StatusCode)
+	assert.Equal(t, enum_state.WALLET_WITHDRAW_REQUEST_STATUS_CANCELLED, responseBodyCancel.Data.Status)
+
+	customerBalance = GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(100000), customerBalance.Wallet.Balance)
+
+	// request yang sudah dibatalkan tidak bisa dibatalkan lagi
+	requestCancelAgain := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/wallets/withdraw-requests/%d/cancel", responseBody.Data.ID), nil)
+	requestCancelAgain.Header.Set("Accept", "application/json")
+	requestCancelAgain.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	responseCancelAgain, err := app.Test(requestCancelAgain)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, responseCancelAgain.StatusCode)
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithdrawRequestFollowsWithdrawPolicy(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+
+	// set saldo wallet
+	DoSetBalanceManually(tokenCust, float32(100000))
+
+	requestBodyPolicy := model.UpsertWithdrawPolicyRequest{
+		MinAmount: 20000,
+		FeeType:   enum_state.WITHDRAW_FEE_TYPE_FLAT,
+		FeeValue:  2500,
+	}
+
+	bodyJson, err := json.Marshal(requestBodyPolicy)
+	assert.Nil(t, err)
+	requestPolicy := httptest.NewRequest(http.MethodPut, "/api/admin/wallets/withdraw-policies/cash", strings.NewReader(string(bodyJson)))
+	requestPolicy.Header.Set("Content-Type", "application/json")
+	requestPolicy.Header.Set("Accept", "application/json")
+	requestPolicy.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	responsePolicy, err := app.Test(requestPolicy)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(responsePolicy.Body)
+	assert.Nil(t, err)
+
+	responseBodyPolicy := new(model.ApiResponse[model.WithdrawPolicyResponse])
+	err = json.Unmarshal(bytes, responseBodyPolicy)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responsePolicy.StatusCode)
+	assert.Equal(t, enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH, responseBodyPolicy.Data.Method)
+	assert.Equal(t, float32(20000), responseBodyPolicy.Data.MinAmount)
+
+	// di bawah minimal penarikan
+	requestBodyLow := model.WithdrawWalletRequest{
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 10000,
+		Note:   "Saya mau narik duit ya",
+	}
+
+	bodyJson, err = json.Marshal(requestBodyLow)
+	assert.Nil(t, err)
+	requestLow := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
+	requestLow.Header.Set("Content-Type", "application/json")
+	requestLow.Header.Set("Accept", "application/json")
+	requestLow.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	responseLow, err := app.Test(requestLow)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, responseLow.StatusCode)
+
+	requestBody := model.WithdrawWalletRequest{
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: 40000,
+		Note:   "Saya mau narik duit ya",
+	}
+
+	bodyJson, err = json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/wallets/withdraw-cust", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCust)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.WithdrawWalletResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, float32(40000), responseBody.Data.Amount)
+	assert.Equal(t, float32(2500), responseBody.Data.Fee)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(57500), customerBalance.Wallet.Balance)
+}
+
+func TestWithdrawRequestNegativeAmountRejected(t *testing.T) {
+	ClearAll()
+	DoRegisterCustomer(t)
+	tokenCust := DoLoginCustomer(t)
+	DoSetBalanceManually(tokenCust, float32(100000))
+
+	response, _ := DoTwoFactorRequest(t, http.MethodPost, "/api/wallets/withdraw-cust", tokenCust, model.WithdrawWalletRequest{
+		Method: enum_state.WALLET_WITHDRAW_REQUEST_METHOD_CASH,
+		Amount: -50000,
+		Note:   "Saya mau narik duit ya",
+	})
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	customerBalance := GetCurrentUserByToken(t, tokenCust)
+	assert.Equal(t, float32(100000), customerBalance.Wallet.Balance)
+}
+
+func TestUpsertWithdrawPolicyNegativeLimitRejected(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	response, _ := DoTwoFactorRequest(t, http.MethodPut, "/api/admin/wallets/withdraw-policies/cash", tokenAdmin, model.UpsertWithdrawPolicyRequest{
+		MinAmount:  20000,
+		FeeType:    enum_state.WITHDRAW_FEE_TYPE_FLAT,
+		FeeValue:   2500,
+		DailyLimit: -100000,
+	})
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
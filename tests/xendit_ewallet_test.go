@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func DoCreateEWalletOrder(t *testing.T, token string, productId uint64, channelCode enum_state.ChannelCode, mobileNumber string) (*http.Response, *model.ApiResponse[model.OrderResponse]) {
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_EWALLET,
+		ChannelCode:    channelCode,
+		MobileNumber:   mobileNumber,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: productId,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func TestCreateOrderWithEWalletRedirect(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	response, responseBody := DoCreateEWalletOrder(t, tokenCustomer, product.ID, enum_state.XENDIT_EWALLET_DANA_CHANNEL_CODE, "")
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, enum_state.PAYMENT_METHOD_EWALLET, responseBody.Data.PaymentMethod)
+	assert.Equal(t, enum_state.PENDING_PAYMENT, responseBody.Data.PaymentStatus)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+	assert.Equal(t, "DANA", responseBody.Data.XenditTransaction.ChannelCode)
+	assert.NotEmpty(t, responseBody.Data.XenditTransaction.CheckoutUrl)
+	assert.NotEmpty(t, responseBody.Data.XenditTransaction.DeeplinkUrl)
+	assert.Empty(t, responseBody.Data.XenditTransaction.QrString)
+}
+
+func TestCreateOrderWithOVORequireMobileNumber(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	response, _ := DoCreateEWalletOrder(t, tokenCustomer, product.ID, enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE, "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = DoCreateEWalletOrder(t, tokenCustomer, product.ID, enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE, "12345")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, responseBody := DoCreateEWalletOrder(t, tokenCustomer, product.ID, enum_state.XENDIT_EWALLET_OVO_CHANNEL_CODE, "0812-3456-7890")
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+	assert.Equal(t, "+6281234567890", responseBody.Data.XenditTransaction.MobileNumber)
+	assert.Empty(t, responseBody.Data.XenditTransaction.CheckoutUrl)
+}
+
+func TestXenditEWalletPaymentMethodExpiredCallback(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	response, responseBody := DoCreateEWalletOrder(t, tokenCustomer, product.ID, enum_state.XENDIT_EWALLET_SHOPEEPAY_CHANNEL_CODE, "")
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+
+	// callback payment_method.* berisi payment method itu sendiri, bukan payment request
+	rawBody := fmt.Sprintf(`{"event":"payment_method.expired","data":{"id":"%s","type":"EWALLET","status":"EXPIRED","metadata":{"lang":"id","time_zone":"UTC"},"updated":"2025-06-29T09:00:00Z"}}`, responseBody.Data.XenditTransaction.PaymentMethodId)
+	request := httptest.NewRequest(http.MethodPost, "/api/xendits/payment-request/notifications/callback", strings.NewReader(rawBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("webhook-id", "evt-ewallet-expired-1")
+	request.Header.Set("X-Callback-Token", viperConfig.GetString("XENDIT_TEST_CALLBACK_TOKEN"))
+	request.Host = "localhost"
+
+	responseCallback, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseCallback.StatusCode)
+
+	requestOrder := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", responseBody.Data.ID), nil)
+	requestOrder.Header.Set("Accept", "application/json")
+	requestOrder.Header.Set("Authorization", "Bearer "+tokenCustomer)
+
+	responseOrder, err := app.Test(requestOrder)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(responseOrder.Body)
+	assert.Nil(t, err)
+
+	responseBodyOrder := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBodyOrder)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseOrder.StatusCode)
+	assert.Equal(t, enum_state.EXPIRED_PAYMENT, responseBodyOrder.Data.PaymentStatus)
+	assert.Equal(t, "EXPIRED", responseBodyOrder.Data.XenditTransaction.Status)
+}
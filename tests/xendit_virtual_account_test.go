@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrderWithVirtualAccount(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT,
+		ChannelCode:    enum_state.XENDIT_VA_BCA_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, enum_state.PENDING_PAYMENT, responseBody.Data.PaymentStatus)
+	assert.NotNil(t, responseBody.Data.XenditTransaction)
+	assert.Equal(t, "BCA", responseBody.Data.XenditTransaction.ChannelCode)
+	assert.NotEmpty(t, responseBody.Data.XenditTransaction.VirtualAccountNumber)
+	assert.NotEmpty(t, responseBody.Data.XenditTransaction.VirtualAccountInstructions)
+	assert.Contains(t, responseBody.Data.XenditTransaction.VirtualAccountInstructions[1], responseBody.Data.XenditTransaction.VirtualAccountNumber)
+	// virtual account berlaku lebih lama dibanding QR code
+	assert.True(t, time.Time(responseBody.Data.XenditTransaction.ExpiresAt).After(time.Now().Add(time.Hour)))
+
+	requestSimulate := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/payment-simulator/payments/%s/simulate", responseBody.Data.XenditTransaction.ID), strings.NewReader(`{"status":"SUCCEEDED"}`))
+	requestSimulate.Header.Set("Content-Type", "application/json")
+	requestSimulate.Header.Set("Accept", "application/json")
+	requestSimulate.Header.Set("Authorization", "Bearer "+tokenAdmin)
+	requestSimulate.Host = "localhost"
+
+	responseSimulate, err := app.Test(requestSimulate, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, responseSimulate.StatusCode)
+
+	requestOrder := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/orders/%d", responseBody.Data.ID), nil)
+	requestOrder.Header.Set("Accept", "application/json")
+	requestOrder.Header.Set("Authorization", "Bearer "+tokenCustomer)
+
+	responseOrder, err := app.Test(requestOrder)
+	assert.Nil(t, err)
+
+	bytes, err = io.ReadAll(responseOrder.Body)
+	assert.Nil(t, err)
+
+	responseBodyOrder := new(model.ApiResponse[model.OrderResponse])
+	err = json.Unmarshal(bytes, responseBodyOrder)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, responseOrder.StatusCode)
+	assert.Equal(t, enum_state.PAID_PAYMENT, responseBodyOrder.Data.PaymentStatus)
+	assert.Equal(t, responseBody.Data.XenditTransaction.VirtualAccountNumber, responseBodyOrder.Data.XenditTransaction.VirtualAccountNumber)
+}
+
+func TestCreateOrderWithVirtualAccountInvalidChannelCode(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+	DoCreateApplicationSetting(t, tokenAdmin)
+	DoRegisterCustomer(t)
+	tokenCustomer := DoLoginCustomer(t)
+	product := DoCreateProduct(t, tokenAdmin, 2, 1)
+
+	requestBody := model.CreateOrderRequest{
+		PaymentGateway: enum_state.PAYMENT_GATEWAY_SIMULATOR,
+		PaymentMethod:  enum_state.PAYMENT_METHOD_VIRTUAL_ACCOUNT,
+		ChannelCode:    enum_state.XENDIT_QR_DANA_CHANNEL_CODE,
+		IsDelivery:     false,
+		OrderProducts: []model.OrderProductResponse{
+			{
+				ProductId: product.ID,
+				Quantity:  1,
+			},
+		},
+	}
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/orders?lang=id", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenCustomer)
+	request.Host = "localhost"
+
+	response, err := app.Test(request, int(time.Second)*5)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
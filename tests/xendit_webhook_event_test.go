@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seblak-bombom-restful-api/internal/entity"
+	"seblak-bombom-restful-api/internal/helper/enum_state"
+	"seblak-bombom-restful-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXenditPayoutCallbackStoredAndDeduplicated(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	rawBody := `{"event":"payout.succeeded","data":{"id":"disb-not-exists","status":"SUCCEEDED","amount":10000,"updated":"2025-06-26T09:00:00Z"}}`
+
+	// xendit bisa mengirim callback yang sama lebih dari sekali
+	for i := 0; i < 2; i++ {
+		request := httptest.NewRequest(http.MethodPost, "/api/xendits/payout-request/notifications/callback", strings.NewReader(rawBody))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json")
+		request.Header.Set("webhook-id", "evt-payout-test-1")
+		request.Header.Set("X-Callback-Token", viperConfig.GetString("XENDIT_TEST_CALLBACK_TOKEN"))
+
+		response, err := app.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/xendit/webhook-events?webhook_type=payout", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponsePagination[[]model.XenditWebhookEventResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int64(1), responseBody.TotalRealDatas)
+	assert.Equal(t, "evt-payout-test-1", responseBody.Data[0].EventId)
+	assert.Equal(t, "payout.succeeded", responseBody.Data[0].EventType)
+	assert.Equal(t, enum_state.XENDIT_WEBHOOK_EVENT_PROCESSED, responseBody.Data[0].Status)
+	assert.Equal(t, 1, responseBody.Data[0].Attempts)
+	assert.Equal(t, rawBody, responseBody.Data[0].RawBody)
+}
+
+func TestXenditCallbackRejectedWithoutToken(t *testing.T) {
+	rawBody := `{"event":"payout.succeeded","data":{"id":"disb-not-exists","status":"SUCCEEDED","amount":10000,"updated":"2025-06-26T09:00:00Z"}}`
+
+	request := httptest.NewRequest(http.MethodPost, "/api/xendits/payout-request/notifications/callback", strings.NewReader(rawBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	requestWrongToken := httptest.NewRequest(http.MethodPost, "/api/xendits/payout-request/notifications/callback", strings.NewReader(rawBody))
+	requestWrongToken.Header.Set("Content-Type", "application/json")
+	requestWrongToken.Header.Set("Accept", "application/json")
+	requestWrongToken.Header.Set("X-Callback-Token", "wrong-token")
+
+	responseWrongToken, err := app.Test(requestWrongToken)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, responseWrongToken.StatusCode)
+}
+
+func TestReplayXenditWebhookEventStuckInProcessing(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+	tokenAdmin := DoLoginAdmin(t)
+
+	rawBody := `{"event":"payout.succeeded","data":{"id":"disb-not-exists","status":"SUCCEEDED","amount":10000,"updated":"2025-06-26T09:00:00Z"}}`
+	request := httptest.NewRequest(http.MethodPost, "/api/xendits/payout-request/notifications/callback", strings.NewReader(rawBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("webhook-id", "evt-payout-stuck-1")
+	request.Header.Set("X-Callback-Token", viperConfig.GetString("XENDIT_TEST_CALLBACK_TOKEN"))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	webhookEvent := new(entity.XenditWebhookEvent)
+	err = db.Where("event_id = ?", "evt-payout-stuck-1").First(webhookEvent).Error
+	assert.Nil(t, err)
+
+	// simulasi server crash saat event masih processing
+	err = db.Model(&entity.XenditWebhookEvent{}).Where("id = ?", webhookEvent.ID).UpdateColumns(map[string]any{
+		"status":     enum_state.XENDIT_WEBHOOK_EVENT_PROCESSING,
+		"updated_at": time.Now().Add(-1 * time.Hour),
+	}).Error
+	assert.Nil(t, err)
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/xendit/webhook-events/%d/replay", webhookEvent.ID), nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", "Bearer "+tokenAdmin)
+
+	response, err = app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ApiResponse[model.XenditWebhookEventResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, enum_state.XENDIT_WEBHOOK_EVENT_PROCESSED, responseBody.Data.Status)
+	assert.Equal(t, 2, responseBody.Data.Attempts)
+}
+
+func DoSendXenditPayoutCallback(t *testing.T, eventId string, rawBody string) {
+	request := httptest.NewRequest(http.MethodPost, "/api/xendits/payout-request/notifications/callback", strings.NewReader(rawBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("webhook-id", eventId)
+	request.Header.Set("X-Callback-Token", viperConfig.GetString("XENDIT_TEST_CALLBACK_TOKEN"))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestXenditPayoutCallbackRefundsOnlyOnce(t *testing.T) {
+	ClearAll()
+	DoRegisterAdmin(t)
+
+	adminUser := new(entity.User)
+	err := db.Where("email = ?", "F3196813@gmail.com").First(adminUser).Error
+	assert.Nil(t, err)
+
+	walletBefore := new(entity.Wallet)
+	err = db.Where("user_id = ?", adminUser.ID).First(walletBefore).Error
+	assert.Nil(t, err)
+
+	now := time.Now()
+	err = db.Create(&entity.XenditPayout{
+		ID:               "disb-refund-once",
+		UserID:           adminUser.ID,
+		Amount:           10000,
+		Currency:         "IDR",
+		Status:           "ACCEPTED",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		EstimatedArrival: now,
+	}).Error
+	assert.Nil(t, err)
+
+	err = db.Create(&entity.Payout{
+		UserId:         adminUser.ID,
+		XenditPayoutId: sql.NullString{String: "disb-refund-once", Valid: true},
+		Amount:         10000,
+		Currency:       "IDR",
+		Method:         enum_state.PAYOUT_METHOD_ONLINE,
+		Status:         enum_state.PAYOUT_PENDING,
+	}).Error
+	assert.Nil(t, err)
+
+	// nominal pada body callback tidak dipakai, dan status gagal berikutnya tidak mengembalikan dana lagi
+	DoSendXenditPayoutCallback(t, "evt-payout-failed-1", `{"event":"payout.failed","data":{"id":"disb-refund-once","status":"FAILED","amount":999999,"updated":"2025-06-26T09:00:00Z"}}`)
+	DoSendXenditPayoutCallback(t, "evt-payout-cancelled-1", `{"event":"payout.cancelled","data":{"id":"disb-refund-once","status":"CANCELLED","amount":999999,"updated":"2025-06-26T09:05:00Z"}}`)
+
+	walletAfter := new(entity.Wallet)
+	err = db.Where("user_id = ?", adminUser.ID).First(walletAfter).Error
+	assert.Nil(t, err)
+	assert.Equal(t, walletBefore.Balance+10000, walletAfter.Balance)
+
+	var refundCount int64
+	err = db.Model(&entity.WalletTransactions{}).Where("reference_number = ? AND flow_type = ?", "disb-refund-once", enum_state.WALLET_FLOW_TYPE_CREDIT).Count(&refundCount).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), refundCount)
+}